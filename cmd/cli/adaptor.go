@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/ecdsa"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+)
+
+// statementFile is the content of a statement file: the statement of a hard
+// relation and the proof that its witness is known.
+type statementFile struct {
+	Statement *adaptor.Statement
+	Proof     *proofs.DLKProof
+}
+
+// witnessFile is the content of a witness file.
+type witnessFile struct {
+	Witness *big.Int
+}
+
+// runAdaptor runs the adaptor signature subcommand the arguments name.
+func runAdaptor(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing adaptor command (generate, adapt or extract)")
+	}
+
+	cmd, args := args[0], args[1:]
+
+	switch cmd {
+	case "generate":
+		return runAdaptorGenerate(args)
+	case "adapt":
+		return runAdaptorAdapt(args)
+	case "extract":
+		return runAdaptorExtract(args)
+	default:
+		return fmt.Errorf("unknown adaptor command %q (want generate, adapt or extract)", cmd)
+	}
+}
+
+// runAdaptorGenerate generates a hard relation and writes its witness and its
+// statement together with the DLK proof of the witness.
+func runAdaptorGenerate(args []string) error {
+	fs := newFlagSet("adaptor generate")
+	witPath := fs.String("witness", "", "path of the witness file to create")
+	stmtPath := fs.String("statement", "", "path of the statement file to create")
+	curveName := fs.String("curve", "secp256k1", "elliptic curve")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := required("witness", *witPath); err != nil {
+		return err
+	}
+	if err := required("statement", *stmtPath); err != nil {
+		return err
+	}
+	curve, err := curveByName(*curveName)
+	if err != nil {
+		return err
+	}
+
+	wit, stmt, err := adaptor.GenerateHardRelation(curve)
+	if err != nil {
+		return err
+	}
+	pStmt, err := proofs.GenerateDLKProof(curve, (*elliptic.Point)(stmt), (*big.Int)(wit))
+	if err != nil {
+		return err
+	}
+
+	// The witness is secret, the statement is shared with the co-signer.
+	if err := writeValue(*witPath, &witnessFile{Witness: (*big.Int)(wit)}, 0o600); err != nil {
+		return err
+	}
+
+	return writeValue(*stmtPath, &statementFile{Statement: stmt, Proof: pStmt}, 0o644)
+}
+
+// runAdaptorAdapt turns a pre-signature into a signature via the witness.
+func runAdaptorAdapt(args []string) error {
+	fs := newFlagSet("adaptor adapt")
+	preSigPath := fs.String("presignature", "", "path of the pre-signature file")
+	witPath := fs.String("witness", "", "path of the witness file")
+	curveName := fs.String("curve", "secp256k1", "elliptic curve")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := required("presignature", *preSigPath); err != nil {
+		return err
+	}
+	if err := required("witness", *witPath); err != nil {
+		return err
+	}
+	curve, err := curveByName(*curveName)
+	if err != nil {
+		return err
+	}
+
+	preSig := new(ecdsa.PreSignature)
+	if err := readValue(*preSigPath, preSig); err != nil {
+		return err
+	}
+	wit := new(witnessFile)
+	if err := readValue(*witPath, wit); err != nil {
+		return err
+	}
+
+	signature := ecdsa.Adapt(curve, adaptor.NewWitness(wit.Witness), preSig)
+
+	return printValue(signature)
+}
+
+// runAdaptorExtract extracts the witness from a pre-signature and the
+// signature that was adapted from it.
+func runAdaptorExtract(args []string) error {
+	fs := newFlagSet("adaptor extract")
+	preSigPath := fs.String("presignature", "", "path of the pre-signature file")
+	sigPath := fs.String("signature", "", "path of the signature file")
+	stmtPath := fs.String("statement", "", "path of the statement file")
+	curveName := fs.String("curve", "secp256k1", "elliptic curve")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := required("presignature", *preSigPath); err != nil {
+		return err
+	}
+	if err := required("signature", *sigPath); err != nil {
+		return err
+	}
+	if err := required("statement", *stmtPath); err != nil {
+		return err
+	}
+	curve, err := curveByName(*curveName)
+	if err != nil {
+		return err
+	}
+
+	preSig := new(ecdsa.PreSignature)
+	if err := readValue(*preSigPath, preSig); err != nil {
+		return err
+	}
+	signature := new(ecdsa.Signature)
+	if err := readValue(*sigPath, signature); err != nil {
+		return err
+	}
+	stmt := new(statementFile)
+	if err := readValue(*stmtPath, stmt); err != nil {
+		return err
+	}
+
+	wit, err := ecdsa.Extract(curve, stmt.Statement, preSig, signature)
+	if err != nil {
+		return err
+	}
+
+	return printValue(&witnessFile{Witness: (*big.Int)(wit)})
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/primefactor-io/lindell17/pkg/backup"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// recoveryKeyFile is the content of a recovery key file: the public key the
+// parties encrypt their shares to.
+type recoveryKeyFile struct {
+	Pk *keys.PublicKey
+}
+
+// recoverySecretFile is the content of a recovery secret file: the recovery
+// private key that is kept offline.
+type recoverySecretFile struct {
+	Sk *keys.PrivateKey
+}
+
+// recoveredKey is the recovered ECDSA private key and its public key.
+type recoveredKey struct {
+	PrivateKey *big.Int
+	PublicKey  string
+}
+
+// runBackup runs the backup subcommand the arguments name.
+func runBackup(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing backup command (keygen, create, verify or recover)")
+	}
+
+	cmd, args := args[0], args[1:]
+
+	switch cmd {
+	case "keygen":
+		return runBackupKeygen(args)
+	case "create":
+		return runBackupCreate(args)
+	case "verify":
+		return runBackupVerify(args)
+	case "recover":
+		return runBackupRecover(args)
+	default:
+		return fmt.Errorf("unknown backup command %q (want keygen, create, verify or recover)", cmd)
+	}
+}
+
+// runBackupKeygen generates the recovery key pair and writes the public key
+// and the private key that is meant to be kept offline.
+func runBackupKeygen(args []string) error {
+	fs := newFlagSet("backup keygen")
+	secretPath := fs.String("secret", "", "path of the recovery secret file to create")
+	pubPath := fs.String("recovery-key", "", "path of the recovery key file to create")
+	paillierBits := fs.Int("paillier-bits", 2048, "bit length of the recovery key's Paillier modulus")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := required("secret", *secretPath); err != nil {
+		return err
+	}
+	if err := required("recovery-key", *pubPath); err != nil {
+		return err
+	}
+
+	sk, pk, err := keys.GenerateKeys(*paillierBits)
+	if err != nil {
+		return err
+	}
+
+	// The private key is secret, the public key is shared with both parties.
+	if err := writeValue(*secretPath, &recoverySecretFile{Sk: sk}, 0o600); err != nil {
+		return err
+	}
+
+	return writeValue(*pubPath, &recoveryKeyFile{Pk: pk}, 0o644)
+}
+
+// runBackupCreate encrypts the party's share of the key file to the recovery
+// key and writes the backup that is handed to the other party for
+// verification.
+func runBackupCreate(args []string) error {
+	fs := newFlagSet("backup create")
+	keyPath := fs.String("key", "", "path of the key file")
+	pubPath := fs.String("recovery-key", "", "path of the recovery key file")
+	outPath := fs.String("out", "", "path of the backup file to create")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := required("key", *keyPath); err != nil {
+		return err
+	}
+	if err := required("recovery-key", *pubPath); err != nil {
+		return err
+	}
+	if err := required("out", *outPath); err != nil {
+		return err
+	}
+
+	k, err := loadKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	params, err := loadBackupParams(k, *pubPath)
+	if err != nil {
+		return err
+	}
+
+	party, x := k.share()
+	b, err := backup.Create(params, party, x, k.q())
+	if err != nil {
+		return err
+	}
+
+	return writeValue(*outPath, b, 0o644)
+}
+
+// runBackupVerify verifies the other party's backup against the key file.
+func runBackupVerify(args []string) error {
+	fs := newFlagSet("backup verify")
+	keyPath := fs.String("key", "", "path of the key file")
+	pubPath := fs.String("recovery-key", "", "path of the recovery key file")
+	backupPath := fs.String("backup", "", "path of the other party's backup file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := required("key", *keyPath); err != nil {
+		return err
+	}
+	if err := required("recovery-key", *pubPath); err != nil {
+		return err
+	}
+	if err := required("backup", *backupPath); err != nil {
+		return err
+	}
+
+	k, err := loadKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	params, err := loadBackupParams(k, *pubPath)
+	if err != nil {
+		return err
+	}
+	b := new(backup.Backup)
+	if err := readValue(*backupPath, b); err != nil {
+		return err
+	}
+
+	party, x := k.share()
+	if b.Party == party {
+		return fmt.Errorf("backup is the backup of this party's own share")
+	}
+	if err := b.VerifyPeer(params, x, k.q()); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, "valid")
+
+	return err
+}
+
+// runBackupRecover rebuilds the full ECDSA private key from both backups via
+// the recovery secret.
+func runBackupRecover(args []string) error {
+	fs := newFlagSet("backup recover")
+	secretPath := fs.String("secret", "", "path of the recovery secret file")
+	party1Path := fs.String("party1", "", "path of party 1's backup file")
+	party2Path := fs.String("party2", "", "path of party 2's backup file")
+	curveName := fs.String("curve", "secp256k1", "elliptic curve")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := required("secret", *secretPath); err != nil {
+		return err
+	}
+	if err := required("party1", *party1Path); err != nil {
+		return err
+	}
+	if err := required("party2", *party2Path); err != nil {
+		return err
+	}
+	curve, err := curveByName(*curveName)
+	if err != nil {
+		return err
+	}
+
+	secret := new(recoverySecretFile)
+	if err := readValue(*secretPath, secret); err != nil {
+		return err
+	}
+	b1 := new(backup.Backup)
+	if err := readValue(*party1Path, b1); err != nil {
+		return err
+	}
+	b2 := new(backup.Backup)
+	if err := readValue(*party2Path, b2); err != nil {
+		return err
+	}
+
+	x, err := backup.Recover(curve, secret.Sk, b1, b2)
+	if err != nil {
+		return err
+	}
+
+	return printValue(&recoveredKey{PrivateKey: x, PublicKey: encodePublicKey(curve, b1.Q, true)})
+}
+
+// loadBackupParams reads the recovery key file and returns the params the
+// key's backups are created and verified with.
+func loadBackupParams(k *key, path string) (*backup.Params, error) {
+	f := new(recoveryKeyFile)
+	if err := readValue(path, f); err != nil {
+		return nil, err
+	}
+
+	return backup.NewParams(k.curve, f.Pk), nil
+}
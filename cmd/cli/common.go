@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/observe"
+	"github.com/primefactor-io/lindell17/pkg/transport"
+	"github.com/primefactor-io/lindell17/pkg/utils"
+)
+
+// supportedCurves are the curves that can be selected via --curve.
+var supportedCurves = map[string]weierstrass.Curve{
+	"secp256k1": curves.Secp256k1,
+}
+
+// newFlagSet creates a flag set for the subcommand which reports parse errors
+// instead of exiting.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	return fs
+}
+
+// connFlags are the flags of the commands that run a protocol with the peer.
+type connFlags struct {
+	role    *string
+	listen  *string
+	connect *string
+	timeout *time.Duration
+	verbose *bool
+}
+
+// addConnFlags adds the role and connection flags to the flag set.
+func addConnFlags(fs *flag.FlagSet) *connFlags {
+	return &connFlags{
+		role:    fs.String("role", "", "party's role: party1 or party2"),
+		listen:  fs.String("listen", "", "address to accept the peer's connection on, e.g. :7000"),
+		connect: fs.String("connect", "", "address of the listening peer, e.g. 10.0.0.2:7000"),
+		timeout: fs.Duration("timeout", transport.DefaultTimeout, "time to wait for the peer"),
+		verbose: fs.Bool("verbose", false, "log the protocol events to stderr"),
+	}
+}
+
+// observer returns the observer that logs the protocol events if --verbose is
+// set and nil otherwise.
+func (f *connFlags) observer() lindell17.Observer {
+	return newObserver(*f.verbose)
+}
+
+// newObserver returns an observer that logs the protocol events to stderr if
+// verbose is set and nil otherwise.
+func newObserver(verbose bool) lindell17.Observer {
+	if !verbose {
+		return nil
+	}
+
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+
+	return observe.NewLogger(slog.New(handler))
+}
+
+// entity returns the entity of the selected role.
+func (f *connFlags) entity() (lindell17.Entity, error) {
+	switch *f.role {
+	case "party1":
+		return lindell17.Party1, nil
+	case "party2":
+		return lindell17.Party2, nil
+	default:
+		return 0, fmt.Errorf("invalid role %q (want party1 or party2)", *f.role)
+	}
+}
+
+// dial listens for or connects to the peer, depending on the flags.
+func (f *connFlags) dial() (*transport.Conn, error) {
+	var conn *transport.Conn
+	var err error
+
+	switch {
+	case *f.listen != "" && *f.connect != "":
+		return nil, fmt.Errorf("--listen and --connect are mutually exclusive")
+	case *f.listen != "":
+		fmt.Fprintf(os.Stderr, "waiting for peer on %s\n", *f.listen)
+		conn, err = transport.Listen(*f.listen)
+	case *f.connect != "":
+		conn, err = transport.Dial(*f.connect, *f.timeout)
+	default:
+		return nil, fmt.Errorf("either --listen or --connect is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return conn.WithTimeout(*f.timeout), nil
+}
+
+// hashFlags are the flags that select the hash to sign or verify.
+type hashFlags struct {
+	hash    *string
+	message *string
+}
+
+// addHashFlags adds the hash flags to the flag set.
+func addHashFlags(fs *flag.FlagSet) *hashFlags {
+	return &hashFlags{
+		hash:    fs.String("hash", "", "hex-encoded 32-byte hash"),
+		message: fs.String("message", "", "message whose SHA-256 hash is used"),
+	}
+}
+
+// value returns the selected hash.
+func (f *hashFlags) value() ([]byte, error) {
+	switch {
+	case *f.hash != "" && *f.message != "":
+		return nil, fmt.Errorf("--hash and --message are mutually exclusive")
+	case *f.hash != "":
+		hash, err := hex.DecodeString(*f.hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash: %w", err)
+		}
+		if len(hash) != utils.HashLength {
+			return nil, fmt.Errorf("invalid hash: want %d bytes, got %d", utils.HashLength, len(hash))
+		}
+		return hash, nil
+	case *f.message != "":
+		checksum := sha256.Sum256([]byte(*f.message))
+		return checksum[:], nil
+	default:
+		return nil, fmt.Errorf("either --hash or --message is required")
+	}
+}
+
+// curveByName returns the curve with the given name.
+func curveByName(name string) (weierstrass.Curve, error) {
+	curve, ok := supportedCurves[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported curve %q", name)
+	}
+
+	return curve, nil
+}
+
+// required returns an error if the flag's value is empty.
+func required(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("--%s is required", name)
+	}
+
+	return nil
+}
+
+// readJSON decodes the JSON file into the value.
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// writeJSON encodes the value as JSON and writes it to the file with the given
+// permissions.
+func writeJSON(path string, v any, perm os.FileMode) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), perm)
+}
+
+// readValue decodes the file's value that was encoded via the codec into the
+// value the pointer points to.
+func readValue(path string, ptr any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := codec.UnmarshalValue(data, ptr); err != nil {
+		return fmt.Errorf("invalid file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// writeValue encodes the value the pointer points to via the codec and
+// writes it to the file with the given permissions. Existing files aren't
+// overwritten.
+func writeValue(path string, ptr any, perm os.FileMode) error {
+	data, err := encodeValue(ptr)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// printValue encodes the value the pointer points to via the codec and prints
+// it.
+func printValue(ptr any) error {
+	data, err := encodeValue(ptr)
+	if err != nil {
+		return err
+	}
+
+	_, err = stdout.Write(data)
+
+	return err
+}
+
+// encodeValue encodes the value the pointer points to via the codec as
+// indented JSON.
+func encodeValue(ptr any) ([]byte, error) {
+	data, err := codec.MarshalValue(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
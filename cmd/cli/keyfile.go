@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// keyFileVersion is the version of the key file format.
+const keyFileVersion = 1
+
+// keyFile is the JSON representation of a party's key file. The key material
+// is the party's key generation result as encoded by the codec.
+type keyFile struct {
+	Version int             `json:"version"`
+	Role    string          `json:"role"`
+	Curve   string          `json:"curve"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// key is a party's key material that was loaded from its key file.
+type key struct {
+	role  string
+	curve weierstrass.Curve
+	km1   *kParty1.KeyMaterial
+	km2   *kParty2.KeyMaterial
+}
+
+// q returns the shared public key.
+func (k *key) q() *elliptic.Point {
+	if k.km1 != nil {
+		return k.km1.Q
+	}
+
+	return k.km2.Q
+}
+
+// share returns the party and its share of the key.
+func (k *key) share() (lindell17.Entity, *big.Int) {
+	if k.km1 != nil {
+		return lindell17.Party1, k.km1.X1
+	}
+
+	return lindell17.Party2, k.km2.X2
+}
+
+// saveKey writes the key generation result to the key file which is only
+// readable by the owner.
+func saveKey(path, role, curve string, result []byte) error {
+	f := &keyFile{
+		Version: keyFileVersion,
+		Role:    role,
+		Curve:   curve,
+		Result:  result,
+	}
+
+	return writeJSON(path, f, 0o600)
+}
+
+// loadKey reads the key material from the key file.
+func loadKey(path string) (*key, error) {
+	f := new(keyFile)
+	if err := readJSON(path, f); err != nil {
+		return nil, err
+	}
+	if f.Version != keyFileVersion {
+		return nil, fmt.Errorf("unsupported key file version %d", f.Version)
+	}
+
+	curve, err := curveByName(f.Curve)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := codec.UnmarshalResult(f.Result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+
+	k := &key{role: f.Role, curve: curve}
+
+	switch res := res.(type) {
+	case *kParty1.Result:
+		k.km1 = res.KeyMaterial
+	case *kParty2.Result:
+		k.km2 = res.KeyMaterial
+	default:
+		return nil, fmt.Errorf("invalid key file %s: not a key generation result", path)
+	}
+
+	if (k.km1 == nil || k.role != "party1") && (k.km2 == nil || k.role != "party2") {
+		return nil, fmt.Errorf("invalid key file %s: key material doesn't match role %q", path, k.role)
+	}
+
+	return k, nil
+}
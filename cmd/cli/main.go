@@ -1,7 +1,118 @@
+// Command cli runs the two-party protocols between two processes that are
+// connected via TCP, so that two operators can create a shared key and
+// co-sign from two terminals.
+//
+// Usage:
+//
+//	cli keygen  --role party1|party2 (--listen ADDR | --connect ADDR) --key FILE [--paillier-bits BITS]
+//	cli sign    --role party1|party2 (--listen ADDR | --connect ADDR) --key FILE (--hash HEX | --message TEXT)
+//	cli presign --role party1|party2 (--listen ADDR | --connect ADDR) --key FILE (--hash HEX | --message TEXT) --statement FILE
+//	cli adaptor generate --witness FILE --statement FILE
+//	cli adaptor adapt    --presignature FILE --witness FILE
+//	cli adaptor extract  --presignature FILE --signature FILE --statement FILE
+//	cli verify  (--key FILE | --pubkey HEX) (--hash HEX | --message TEXT) --signature FILE
+//	cli pubkey  --key FILE [--uncompressed]
+//	cli backup keygen  --secret FILE --recovery-key FILE [--paillier-bits BITS]
+//	cli backup create  --key FILE --recovery-key FILE --out FILE
+//	cli backup verify  --key FILE --recovery-key FILE --backup FILE
+//	cli backup recover --secret FILE --party1 FILE --party2 FILE
+//	cli serve   --cert FILE --tls-key FILE --client-ca FILE --keystore DIR [--listen ADDR]
+//	cli simulate keygen|sign|adaptor [--paillier-bits BITS] [--quiet] [(--hash HEX | --message TEXT)]
+//
+// One party listens while the other one connects, independent of their roles.
+// The key generation writes the party's key file which is then used by the
+// signing commands. Signatures and pre-signatures are printed as JSON. With
+// --verbose, the protocol commands and serve log every state transition,
+// message and proof verification to stderr (see package observe).
+//
+// The serve command runs party 2 as a long-running co-signer that keeps many
+// keys in a keystore directory and serves clients that authenticate via mutual
+// TLS (see package cosigner). Its signing policy is configured via flags, e.g.
+// --allow-destinations, --max-amount, --max-signatures and --signing-hours
+// (see package policy). Signing requests can require the approval of a quorum
+// of human approvers via --approvers, --approval-threshold and --approval-dir
+// (see package approval). With --metrics-listen, it serves Prometheus metrics
+// of its protocol runs at /metrics via plain HTTP (see package metrics).
+//
+// The backup commands back up the shares to an offline recovery key, so that
+// the shared key survives the loss of a party's host (see package backup).
+// Each party creates the backup of its share and hands it to the other party
+// which verifies it against its own key file. The recover command rebuilds the
+// full private key from both backups via the recovery secret.
+//
+// The simulate command runs both parties in this process, without a peer or
+// key files, and prints every exchanged message, the duration of every step
+// and the outputs. It's meant for demos and smoke tests.
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// stdout is where the commands print their output.
+var stdout io.Writer = os.Stdout
 
 func main() {
-	fmt.Printf("Hello %s\n", "World")
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the subcommand the arguments name.
+func run(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("missing command")
+	}
+
+	cmd, args := args[0], args[1:]
+
+	switch cmd {
+	case "keygen":
+		return runKeygen(args)
+	case "sign":
+		return runSign(args)
+	case "presign":
+		return runPresign(args)
+	case "adaptor":
+		return runAdaptor(args)
+	case "verify":
+		return runVerify(args)
+	case "pubkey":
+		return runPubkey(args)
+	case "backup":
+		return runBackup(args)
+	case "serve":
+		return runServe(args)
+	case "simulate":
+		return runSimulate(args)
+	case "help", "-h", "--help":
+		usage()
+		return nil
+	default:
+		usage()
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+// usage prints the available commands.
+func usage() {
+	fmt.Fprint(os.Stderr, `Usage: cli <command> [flags]
+
+Commands:
+  keygen   create a shared key with the peer and write the key file
+  sign     co-sign a hash with the peer
+  presign  co-sign an adaptor pre-signature with the peer
+  adaptor  generate a statement, adapt a pre-signature or extract a witness
+  verify   verify a signature
+  pubkey   print the shared public key
+  backup   back up a share to a recovery key or recover the private key
+  serve    run party 2 as a co-signer for mTLS-authenticated clients
+  simulate run both parties of a protocol in this process
+
+Run "cli <command> -h" for the command's flags.
+`)
 }
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	aParty1 "github.com/primefactor-io/lindell17/pkg/adaptor/party1"
+	aParty2 "github.com/primefactor-io/lindell17/pkg/adaptor/party2"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	sParty1 "github.com/primefactor-io/lindell17/pkg/sign/party1"
+	sParty2 "github.com/primefactor-io/lindell17/pkg/sign/party2"
+	"github.com/primefactor-io/lindell17/pkg/transport"
+)
+
+// runKeygen runs the key generation protocol with the peer and writes the
+// party's key file.
+func runKeygen(args []string) error {
+	fs := newFlagSet("keygen")
+	conn := addConnFlags(fs)
+	keyPath := fs.String("key", "", "path of the key file to create")
+	curveName := fs.String("curve", "secp256k1", "elliptic curve")
+	paillierBits := fs.Int("paillier-bits", 2048, "bit length of party 1's Paillier modulus")
+	rangeProofBits := fs.Int("range-proof-bits", 40, "statistical security parameter of the range proof")
+	nthRootProofBits := fs.Int("nth-root-proof-bits", 128, "statistical security parameter of the Nth root proof")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	entity, err := conn.entity()
+	if err != nil {
+		return err
+	}
+	if err := required("key", *keyPath); err != nil {
+		return err
+	}
+	curve, err := curveByName(*curveName)
+	if err != nil {
+		return err
+	}
+
+	// Never overwrite existing key material.
+	if _, err := os.Stat(*keyPath); !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("key file %s already exists", *keyPath)
+	}
+
+	var newParty transport.Factory
+	switch entity {
+	case lindell17.Party1:
+		params := kParty1.NewParams(curve, *rangeProofBits, *nthRootProofBits, *paillierBits).WithObserver(conn.observer())
+		newParty = func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return kParty1.NewParty1(params, outCh, resCh)
+		}
+	case lindell17.Party2:
+		params := kParty2.NewParams(curve, *rangeProofBits, *nthRootProofBits).WithObserver(conn.observer())
+		newParty = func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return kParty2.NewParty2(params, outCh, resCh)
+		}
+	}
+
+	res, err := runProtocol(conn, newParty)
+	if err != nil {
+		return err
+	}
+
+	data, err := codec.MarshalResult(res)
+	if err != nil {
+		return err
+	}
+	if err := saveKey(*keyPath, *conn.role, *curveName, data); err != nil {
+		return err
+	}
+
+	k, err := loadKey(*keyPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "key file written to %s\n", *keyPath)
+	_, err = fmt.Fprintln(stdout, encodePublicKey(k.curve, k.q(), true))
+
+	return err
+}
+
+// runSign runs the signing protocol with the peer. Party 1 prints the
+// signature.
+func runSign(args []string) error {
+	fs := newFlagSet("sign")
+	conn := addConnFlags(fs)
+	keyPath := fs.String("key", "", "path of the key file")
+	hashFlags := addHashFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	k, hash, err := loadSigningInputs(conn, *keyPath, hashFlags)
+	if err != nil {
+		return err
+	}
+
+	var newParty transport.Factory
+	if k.km1 != nil {
+		params := sParty1.NewParams(k.curve, k.km1.Sk, k.km1.Q).WithObserver(conn.observer())
+		newParty = func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return sParty1.NewParty1(params, hash, outCh, resCh)
+		}
+	} else {
+		params := sParty2.NewParams(k.curve, k.km2.Pk, k.km2.X1Enc, k.km2.X2).WithObserver(conn.observer())
+		newParty = func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return sParty2.NewParty2(params, hash, outCh, resCh)
+		}
+	}
+
+	res, err := runProtocol(conn, newParty)
+	if err != nil {
+		return err
+	}
+
+	switch res := res.(type) {
+	case *sParty1.Result:
+		return printValue(res.Signature)
+	default:
+		fmt.Fprintln(os.Stderr, "partial signature sent, party 1 holds the signature")
+		return nil
+	}
+}
+
+// runPresign runs the adaptor signature protocol with the peer. Both parties
+// print the pre-signature.
+func runPresign(args []string) error {
+	fs := newFlagSet("presign")
+	conn := addConnFlags(fs)
+	keyPath := fs.String("key", "", "path of the key file")
+	hashFlags := addHashFlags(fs)
+	stmtPath := fs.String("statement", "", "path of the statement file (see adaptor generate)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	k, hash, err := loadSigningInputs(conn, *keyPath, hashFlags)
+	if err != nil {
+		return err
+	}
+	if err := required("statement", *stmtPath); err != nil {
+		return err
+	}
+	stmt := new(statementFile)
+	if err := readValue(*stmtPath, stmt); err != nil {
+		return err
+	}
+
+	var newParty transport.Factory
+	if k.km1 != nil {
+		params := aParty1.NewParams(k.curve, k.km1.Sk, k.km1.Q).WithObserver(conn.observer())
+		newParty = func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return aParty1.NewParty1(params, hash, stmt.Statement, stmt.Proof, outCh, resCh)
+		}
+	} else {
+		params := aParty2.NewParams(k.curve, k.km2.Pk, k.km2.Q, k.km2.X1Enc, k.km2.X2).WithObserver(conn.observer())
+		newParty = func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return aParty2.NewParty2(params, hash, stmt.Statement, stmt.Proof, outCh, resCh)
+		}
+	}
+
+	res, err := runProtocol(conn, newParty)
+	if err != nil {
+		return err
+	}
+
+	switch res := res.(type) {
+	case *aParty1.Result:
+		return printValue(res.PreSignature)
+	case *aParty2.Result:
+		return printValue(res.PreSignature)
+	default:
+		return fmt.Errorf("unexpected result %T", res)
+	}
+}
+
+// loadSigningInputs validates the role and loads the key file and the hash
+// of a signing command.
+func loadSigningInputs(conn *connFlags, keyPath string, hashFlags *hashFlags) (*key, []byte, error) {
+	if _, err := conn.entity(); err != nil {
+		return nil, nil, err
+	}
+	if err := required("key", keyPath); err != nil {
+		return nil, nil, err
+	}
+
+	k, err := loadKey(keyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if k.role != *conn.role {
+		return nil, nil, fmt.Errorf("key file %s belongs to %s, not %s", keyPath, k.role, *conn.role)
+	}
+
+	hash, err := hashFlags.value()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return k, hash, nil
+}
+
+// runProtocol connects to the peer and runs the party over the connection.
+func runProtocol(conn *connFlags, newParty transport.Factory) (lindell17.Result, error) {
+	c, err := conn.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	return transport.Run(c, newParty)
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/primefactor-io/lindell17/pkg/approval"
+	"github.com/primefactor-io/lindell17/pkg/cosigner"
+	"github.com/primefactor-io/lindell17/pkg/metrics"
+	"github.com/primefactor-io/lindell17/pkg/observe"
+	"github.com/primefactor-io/lindell17/pkg/policy"
+)
+
+// runServe runs party 2 as a co-signer that serves authenticated clients via
+// HTTPS.
+func runServe(args []string) error {
+	fs := newFlagSet("serve")
+	listen := fs.String("listen", ":8443", "address to serve on")
+	certPath := fs.String("cert", "", "path of the server's PEM certificate")
+	keyPath := fs.String("tls-key", "", "path of the server's PEM private key")
+	clientCAPath := fs.String("client-ca", "", "path of the PEM certificates of the CAs that issue client certificates")
+	keystoreDir := fs.String("keystore", "", "directory of the co-signer's key files")
+	curveName := fs.String("curve", "secp256k1", "elliptic curve")
+	rangeProofBits := fs.Int("range-proof-bits", 40, "statistical security parameter of the range proof")
+	nthRootProofBits := fs.Int("nth-root-proof-bits", 128, "statistical security parameter of the Nth root proof")
+	maxSessions := fs.Int("max-sessions", cosigner.DefaultMaxSessions, "maximum number of open sessions")
+	sessionTimeout := fs.Duration("session-timeout", cosigner.DefaultSessionTimeout, "time after which an idle session expires")
+	verbose := fs.Bool("verbose", false, "log the protocol events to stderr")
+	metricsListen := fs.String("metrics-listen", "", "address to serve Prometheus metrics on via plain HTTP (disabled if empty)")
+	policyFlags := addPolicyFlags(fs)
+	approvalFlags := addApprovalFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := required("cert", *certPath); err != nil {
+		return err
+	}
+	if err := required("tls-key", *keyPath); err != nil {
+		return err
+	}
+	if err := required("client-ca", *clientCAPath); err != nil {
+		return err
+	}
+	if err := required("keystore", *keystoreDir); err != nil {
+		return err
+	}
+	curve, err := curveByName(*curveName)
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(*certPath, *keyPath)
+	if err != nil {
+		return err
+	}
+	caPEM, err := os.ReadFile(*clientCAPath)
+	if err != nil {
+		return err
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("no certificates found in %s", *clientCAPath)
+	}
+
+	if info, err := os.Stat(*keystoreDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("keystore %s isn't a directory", *keystoreDir)
+	}
+
+	engine, err := policyFlags.engine()
+	if err != nil {
+		return err
+	}
+	quorum, err := approvalFlags.quorum()
+	if err != nil {
+		return err
+	}
+
+	// Runs of sessions that expire are counted as abandoned.
+	observer := newObserver(*verbose)
+	var m *metrics.Metrics
+	if *metricsListen != "" {
+		m = metrics.NewMetrics().WithStaleAfter(*sessionTimeout)
+		observer = observe.All(observer, m)
+	}
+
+	server := cosigner.NewServer(curve, cosigner.NewDirKeystore(*keystoreDir)).
+		WithProofBits(*rangeProofBits, *nthRootProofBits).
+		WithMaxSessions(*maxSessions).
+		WithSessionTimeout(*sessionTimeout).
+		WithObserver(observer)
+	if engine != nil {
+		server.WithPolicy(engine)
+	}
+	if quorum != nil {
+		var keyIDs []string
+		if *approvalFlags.keys != "" {
+			keyIDs = strings.Split(*approvalFlags.keys, ",")
+		}
+		var clients []string
+		if *approvalFlags.clients != "" {
+			clients = strings.Split(*approvalFlags.clients, ",")
+		}
+		store := cosigner.NewDirPendingStore(*approvalFlags.dir)
+		server.WithApproval(quorum, store, keyIDs...).
+			WithApprovalTimeout(*approvalFlags.timeout).
+			WithApproverClients(clients...).
+			WithMaxPending(*approvalFlags.max, *approvalFlags.maxClient)
+	}
+
+	httpServer := &http.Server{
+		Addr:              *listen,
+		Handler:           server,
+		TLSConfig:         cosigner.ServerTLSConfig(cert, clientCAs),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 2)
+
+	if m != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", m)
+		metricsServer := &http.Server{
+			Addr:              *metricsListen,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		fmt.Fprintf(os.Stderr, "serving metrics on %s/metrics\n", *metricsListen)
+		go func() { errCh <- metricsServer.ListenAndServe() }()
+	}
+
+	fmt.Fprintf(os.Stderr, "serving co-signer on %s\n", *listen)
+	go func() { errCh <- httpServer.ListenAndServeTLS("", "") }()
+
+	return <-errCh
+}
+
+// policyFlags are the flags that configure the co-signer's signing policy.
+type policyFlags struct {
+	destinations   *string
+	maxAmount      *string
+	maxSignatures  *int
+	maxTotalAmount *string
+	window         *time.Duration
+	hours          *string
+	payloadHash    *bool
+}
+
+// addPolicyFlags adds the signing policy flags to the flag set.
+func addPolicyFlags(fs *flag.FlagSet) *policyFlags {
+	return &policyFlags{
+		destinations:   fs.String("allow-destinations", "", "comma-separated destinations that may be paid"),
+		maxAmount:      fs.String("max-amount", "", "maximum amount per signature"),
+		maxSignatures:  fs.Int("max-signatures", 0, "maximum number of signatures per key within the velocity window"),
+		maxTotalAmount: fs.String("max-total-amount", "", "maximum total amount per key within the velocity window"),
+		window:         fs.Duration("velocity-window", 24*time.Hour, "sliding window of the velocity limits"),
+		hours:          fs.String("signing-hours", "", "daily signing hours in UTC, e.g. 09:00-17:00"),
+		payloadHash:    fs.Bool("require-payload-hash", false, "require the hash to be the SHA-256 hash of the payload's data"),
+	}
+}
+
+// engine creates the signing policy the flags configure.
+// Returns nil if no rule is configured or an error if a flag is invalid.
+func (f *policyFlags) engine() (*policy.Engine, error) {
+	var rules []policy.Rule
+
+	if *f.payloadHash {
+		rules = append(rules, policy.PayloadHash(func(data []byte) []byte {
+			checksum := sha256.Sum256(data)
+			return checksum[:]
+		}))
+	}
+	if *f.destinations != "" {
+		rules = append(rules, policy.Destinations(strings.Split(*f.destinations, ",")...))
+	}
+	if *f.maxAmount != "" {
+		amount, err := parseAmount("max-amount", *f.maxAmount)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, policy.MaxAmount(amount))
+	}
+	if *f.maxSignatures > 0 || *f.maxTotalAmount != "" {
+		var total *big.Int
+		if *f.maxTotalAmount != "" {
+			var err error
+			if total, err = parseAmount("max-total-amount", *f.maxTotalAmount); err != nil {
+				return nil, err
+			}
+		}
+		rules = append(rules, policy.Velocity(*f.window, *f.maxSignatures, total))
+	}
+	if *f.hours != "" {
+		start, end, err := parseHours(*f.hours)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, policy.TimeWindow(start, end, time.UTC))
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return policy.NewEngine(rules...), nil
+}
+
+// approvalFlags are the flags that configure the quorum that approves signing
+// requests.
+type approvalFlags struct {
+	approvers *string
+	threshold *int
+	dir       *string
+	keys      *string
+	timeout   *time.Duration
+	clients   *string
+	max       *int
+	maxClient *int
+}
+
+// addApprovalFlags adds the approval flags to the flag set.
+func addApprovalFlags(fs *flag.FlagSet) *approvalFlags {
+	return &approvalFlags{
+		approvers: fs.String("approvers", "", "comma-separated approvers of the form ID=FILE with a PEM Ed25519 or ECDSA public key"),
+		threshold: fs.Int("approval-threshold", 0, "number of approvers that need to approve a signing request"),
+		dir:       fs.String("approval-dir", "", "directory of the sessions that wait for approval"),
+		keys:      fs.String("approval-keys", "", "comma-separated ids of the keys that require approval (default all)"),
+		timeout:   fs.Duration("approval-timeout", cosigner.DefaultApprovalTimeout, "time after which a session that waits for approval expires"),
+		clients:   fs.String("approval-clients", "", "comma-separated identities of the clients that list every session that waits for approval"),
+		max:       fs.Int("max-pending", cosigner.DefaultMaxPending, "maximum number of sessions that wait for approval"),
+		maxClient: fs.Int("max-pending-per-client", cosigner.DefaultMaxPendingPerOwner, "maximum number of sessions of a client that wait for approval"),
+	}
+}
+
+// quorum creates the quorum the flags configure.
+// Returns nil if no approvers are configured or an error if a flag is invalid
+// or a public key can't be read.
+func (f *approvalFlags) quorum() (*approval.Quorum, error) {
+	if *f.approvers == "" {
+		return nil, nil
+	}
+	if err := required("approval-dir", *f.dir); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(*f.dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("approval directory %s isn't a directory", *f.dir)
+	}
+
+	var approvers []*approval.Approver
+	for _, entry := range strings.Split(*f.approvers, ",") {
+		id, path, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --approvers entry %q (want ID=FILE)", entry)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM public key found in %s", path)
+		}
+		pk, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key in %s: %w", path, err)
+		}
+
+		approver, err := approval.NewApprover(id, pk)
+		if err != nil {
+			return nil, err
+		}
+		approvers = append(approvers, approver)
+	}
+
+	return approval.NewQuorum(*f.threshold, approvers...)
+}
+
+// parseAmount parses the flag's decimal amount.
+func parseAmount(name, value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid --%s %q", name, value)
+	}
+
+	return amount, nil
+}
+
+// parseHours parses signing hours of the form HH:MM-HH:MM into offsets from
+// midnight.
+func parseHours(value string) (time.Duration, time.Duration, error) {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid --signing-hours %q (want HH:MM-HH:MM)", value)
+	}
+
+	var offsets [2]time.Duration
+	for i, s := range []string{from, to} {
+		t, err := time.Parse("15:04", s)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid --signing-hours %q (want HH:MM-HH:MM)", value)
+		}
+		offsets[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+
+	return offsets[0], offsets[1], nil
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/ecdsa"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	aParty1 "github.com/primefactor-io/lindell17/pkg/adaptor/party1"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/profile"
+	sParty1 "github.com/primefactor-io/lindell17/pkg/sign/party1"
+)
+
+// simulation is a local protocol run of both parties in one process.
+type simulation struct {
+	curve            weierstrass.Curve
+	paillierBits     int
+	rangeProofBits   int
+	nthRootProofBits int
+	quiet            bool
+}
+
+// runSimulate runs party 1 and party 2 of the protocol the arguments name in
+// this process and prints the exchanged messages, the step timings and the
+// outputs.
+func runSimulate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing protocol (keygen, sign or adaptor)")
+	}
+
+	protocol, args := args[0], args[1:]
+	if protocol != "keygen" && protocol != "sign" && protocol != "adaptor" {
+		return fmt.Errorf("unknown protocol %q (want keygen, sign or adaptor)", protocol)
+	}
+
+	fs := newFlagSet("simulate " + protocol)
+	curveName := fs.String("curve", "secp256k1", "elliptic curve")
+	paillierBits := fs.Int("paillier-bits", 2048, "bit length of party 1's Paillier modulus")
+	rangeProofBits := fs.Int("range-proof-bits", 40, "statistical security parameter of the range proof")
+	nthRootProofBits := fs.Int("nth-root-proof-bits", 128, "statistical security parameter of the Nth root proof")
+	quiet := fs.Bool("quiet", false, "only print the step timings and outputs, not the messages")
+	var hashFlags *hashFlags
+	if protocol != "keygen" {
+		hashFlags = addHashFlags(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	curve, err := curveByName(*curveName)
+	if err != nil {
+		return err
+	}
+	var hash []byte
+	if hashFlags != nil {
+		if hash, err = hashFlags.value(); err != nil {
+			return err
+		}
+	}
+
+	s := &simulation{
+		curve:            curve,
+		paillierBits:     *paillierBits,
+		rangeProofBits:   *rangeProofBits,
+		nthRootProofBits: *nthRootProofBits,
+		quiet:            *quiet,
+	}
+
+	switch protocol {
+	case "keygen":
+		_, _, err = s.keygen(true)
+		return err
+	case "sign":
+		return s.sign(hash)
+	default:
+		return s.adaptor(hash)
+	}
+}
+
+// keygen simulates the key generation and prints the shared public key. The
+// run is only printed in detail if verbose is set.
+func (s *simulation) keygen(verbose bool) (*kParty1.KeyMaterial, *kParty2.KeyMaterial, error) {
+	parties := profile.Keygen(s.curve, s.rangeProofBits, s.nthRootProofBits, s.paillierBits)
+	prof, err := s.run("Keygen", parties, verbose)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	km1, km2, err := prof.KeyMaterial()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !km1.Q.Equal(km2.Q) {
+		return nil, nil, fmt.Errorf("parties derived different public keys")
+	}
+
+	fmt.Fprintf(stdout, "Shared public key: %s\n\n", encodePublicKey(s.curve, km1.Q, true))
+
+	return km1, km2, nil
+}
+
+// sign simulates a key generation and the signing of the hash and prints the
+// signature.
+func (s *simulation) sign(hash []byte) error {
+	km1, km2, err := s.keygen(false)
+	if err != nil {
+		return err
+	}
+
+	prof, err := s.run("Sign", profile.Sign(s.curve, km1, km2, hash), true)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range prof.Results {
+		if res, ok := res.(*sParty1.Result); ok {
+			return s.printSignature(km1.Q, hash, res.Signature)
+		}
+	}
+
+	return fmt.Errorf("missing signature")
+}
+
+// adaptor simulates a key generation and the adaptor signing of the hash for
+// a fresh statement, adapts the pre-signature and prints the signature.
+func (s *simulation) adaptor(hash []byte) error {
+	km1, km2, err := s.keygen(false)
+	if err != nil {
+		return err
+	}
+
+	wit, stmt, err := adaptor.GenerateHardRelation(s.curve)
+	if err != nil {
+		return err
+	}
+	pStmt, err := proofs.GenerateDLKProof(s.curve, (*elliptic.Point)(stmt), (*big.Int)(wit))
+	if err != nil {
+		return err
+	}
+
+	prof, err := s.run("Adaptor", profile.Adaptor(s.curve, km1, km2, hash, stmt, pStmt), true)
+	if err != nil {
+		return err
+	}
+
+	var preSig *ecdsa.PreSignature
+	for _, res := range prof.Results {
+		if res, ok := res.(*aParty1.Result); ok {
+			preSig = res.PreSignature
+		}
+	}
+	if preSig == nil {
+		return fmt.Errorf("missing pre-signature")
+	}
+
+	fmt.Fprintf(stdout, "Statement:         %s\n", encodePublicKey(s.curve, (*elliptic.Point)(stmt), true))
+	fmt.Fprintf(stdout, "Pre-signature:     r=%x s=%x v=%d\n", preSig.R, preSig.S, preSig.V)
+
+	signature := ecdsa.Adapt(s.curve, wit, preSig)
+	if err := s.printSignature(km1.Q, hash, signature); err != nil {
+		return err
+	}
+
+	extracted, err := ecdsa.Extract(s.curve, stmt, preSig, signature)
+	if err != nil {
+		return err
+	}
+	if (*big.Int)(extracted).Cmp((*big.Int)(wit)) != 0 {
+		return fmt.Errorf("extracted witness doesn't match the statement's witness")
+	}
+	fmt.Fprintln(stdout, "Extracted witness: matches")
+
+	return nil
+}
+
+// run runs the parties and prints every step with its duration and, if
+// verbose is set, the message it processed. Otherwise only the total duration
+// is printed.
+func (s *simulation) run(name string, parties []profile.Party, verbose bool) (*profile.Profile, error) {
+	prof, err := profile.Run(parties...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	var total time.Duration
+	for _, step := range prof.Steps {
+		total += step.Duration
+	}
+
+	if !verbose {
+		fmt.Fprintf(stdout, "== %s (%d steps, %s)\n", name, len(prof.Steps), round(total))
+		return prof, nil
+	}
+
+	fmt.Fprintf(stdout, "== %s\n\n", name)
+	for i, step := range prof.Steps {
+		fmt.Fprintf(stdout, "%2d. %-18s %10s\n", i+1, step.Name(), round(step.Duration))
+		if step.Message == nil || s.quiet {
+			continue
+		}
+
+		msg := step.Message
+		data, err := codec.MarshalMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "    ", "  "); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(stdout, "    %s -> %s, %d bytes:\n    %s\n", entityName(msg.From()), entityName(msg.To()), len(data), buf.String())
+	}
+	fmt.Fprintf(stdout, "    %-18s %10s\n\n", "Total", round(total))
+
+	return prof, nil
+}
+
+// printSignature prints the signature in DER and compact form together with
+// the public key that is recovered from it.
+func (s *simulation) printSignature(q *elliptic.Point, hash []byte, signature *ecdsa.Signature) error {
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{signature.R, signature.S})
+	if err != nil {
+		return err
+	}
+
+	size := (s.curve.N().BitLen() + 7) / 8
+	compact := make([]byte, 2*size)
+	signature.R.FillBytes(compact[:size])
+	signature.S.FillBytes(compact[size:])
+
+	pk, err := ecdsa.RecoverPublicKey(s.curve, hash, signature)
+	if err != nil {
+		return err
+	}
+	recovered := (*elliptic.Point)(pk)
+
+	match := "matches"
+	if !recovered.Equal(q) {
+		match = "DOESN'T MATCH"
+	}
+
+	fmt.Fprintf(stdout, "Hash:              %x\n", hash)
+	fmt.Fprintf(stdout, "Signature (DER):   %s\n", hex.EncodeToString(der))
+	fmt.Fprintf(stdout, "Signature (r||s):  %s\n", hex.EncodeToString(compact))
+	fmt.Fprintf(stdout, "Recovery id:       %d\n", signature.V)
+	fmt.Fprintf(stdout, "Recovered key:     %s (%s shared public key)\n", encodePublicKey(s.curve, recovered, true), match)
+
+	if !recovered.Equal(q) {
+		return fmt.Errorf("recovered public key doesn't match the shared public key")
+	}
+
+	return nil
+}
+
+// entityName returns the name of the party.
+func entityName(entity lindell17.Entity) string {
+	switch entity {
+	case lindell17.Party1:
+		return "Party1"
+	case lindell17.Party2:
+		return "Party2"
+	default:
+		return fmt.Sprintf("Entity%d", entity)
+	}
+}
+
+// round rounds the duration to a readable precision.
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
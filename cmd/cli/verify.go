@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/ecdsa"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/keys"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+)
+
+// runVerify verifies a signature against the shared public key.
+func runVerify(args []string) error {
+	fs := newFlagSet("verify")
+	keyPath := fs.String("key", "", "path of a key file")
+	pubKeyHex := fs.String("pubkey", "", "hex-encoded SEC1 public key")
+	curveName := fs.String("curve", "secp256k1", "elliptic curve (with --pubkey)")
+	hashFlags := addHashFlags(fs)
+	sigPath := fs.String("signature", "", "path of the signature file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var curve weierstrass.Curve
+	var q *elliptic.Point
+	switch {
+	case *keyPath != "" && *pubKeyHex != "":
+		return fmt.Errorf("--key and --pubkey are mutually exclusive")
+	case *keyPath != "":
+		k, err := loadKey(*keyPath)
+		if err != nil {
+			return err
+		}
+		curve, q = k.curve, k.q()
+	case *pubKeyHex != "":
+		var err error
+		if curve, err = curveByName(*curveName); err != nil {
+			return err
+		}
+		if q, err = decodePublicKey(curve, *pubKeyHex); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("either --key or --pubkey is required")
+	}
+
+	hash, err := hashFlags.value()
+	if err != nil {
+		return err
+	}
+	if err := required("signature", *sigPath); err != nil {
+		return err
+	}
+	signature := new(ecdsa.Signature)
+	if err := readValue(*sigPath, signature); err != nil {
+		return err
+	}
+
+	isValid, err := ecdsa.Verify(curve, keys.NewPublicKey(q), hash, signature)
+	if err != nil {
+		return err
+	}
+	if !isValid {
+		return fmt.Errorf("invalid signature")
+	}
+
+	_, err = fmt.Fprintln(stdout, "valid")
+
+	return err
+}
+
+// runPubkey prints the shared public key of the key file.
+func runPubkey(args []string) error {
+	fs := newFlagSet("pubkey")
+	keyPath := fs.String("key", "", "path of the key file")
+	uncompressed := fs.Bool("uncompressed", false, "print the uncompressed encoding")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := required("key", *keyPath); err != nil {
+		return err
+	}
+	k, err := loadKey(*keyPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, encodePublicKey(k.curve, k.q(), !*uncompressed))
+
+	return err
+}
+
+// encodePublicKey returns the hex-encoded SEC1 encoding of the point.
+func encodePublicKey(curve weierstrass.Curve, q *elliptic.Point, compressed bool) string {
+	size := (curve.P().BitLen() + 7) / 8
+
+	var buf []byte
+	if compressed {
+		buf = make([]byte, 1+size)
+		buf[0] = 0x02 + byte(q.Y.Bit(0))
+	} else {
+		buf = make([]byte, 1+2*size)
+		buf[0] = 0x04
+		q.Y.FillBytes(buf[1+size:])
+	}
+	q.X.FillBytes(buf[1 : 1+size])
+
+	return hex.EncodeToString(buf)
+}
+
+// decodePublicKey decodes the hex-encoded compressed or uncompressed SEC1
+// encoding of a point and checks that it's on the curve.
+func decodePublicKey(curve weierstrass.Curve, s string) (*elliptic.Point, error) {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	size := (curve.P().BitLen() + 7) / 8
+
+	var q *elliptic.Point
+	switch {
+	case len(buf) == 1+2*size && buf[0] == 0x04:
+		x := new(big.Int).SetBytes(buf[1 : 1+size])
+		y := new(big.Int).SetBytes(buf[1+size:])
+		q = elliptic.NewPoint(x, y)
+	case len(buf) == 1+size && (buf[0] == 0x02 || buf[0] == 0x03):
+		p := curve.P()
+		x := new(big.Int).SetBytes(buf[1:])
+
+		// y² = x³ + ax + b
+		y2 := new(big.Int).Exp(x, big.NewInt(3), p)
+		y2.Add(y2, new(big.Int).Mul(curve.A(), x))
+		y2.Add(y2, curve.B())
+		y2.Mod(y2, p)
+
+		y := new(big.Int).ModSqrt(y2, p)
+		if y == nil {
+			return nil, fmt.Errorf("invalid public key: not on the curve")
+		}
+		if y.Bit(0) != uint(buf[0]&1) {
+			y.Sub(p, y)
+		}
+		q = elliptic.NewPoint(x, y)
+	default:
+		return nil, fmt.Errorf("invalid public key: unsupported encoding")
+	}
+
+	if q.X.Cmp(curve.P()) >= 0 || q.Y.Cmp(curve.P()) >= 0 || !curve.IsOnCurve(q) {
+		return nil, fmt.Errorf("invalid public key: not on the curve")
+	}
+
+	return q, nil
+}
@@ -0,0 +1,180 @@
+// Command profile runs the protocols honestly and prints a table with the
+// latency of every protocol step for different Paillier key sizes.
+//
+// Usage:
+//
+//	go run ./cmd/profile -bits 1024,2048,3072 -runs 5
+package main
+
+import (
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/lindell17/pkg/profile"
+)
+
+var secp256k1 = curves.Secp256k1
+
+func main() {
+	bitsFlag := flag.String("bits", "1024,2048,3072", "comma-separated Paillier key sizes")
+	runs := flag.Int("runs", 3, "number of runs per protocol and key size")
+	rangeProofBits := flag.Int("range-proof-bits", 40, "statistical security parameter of the range proof")
+	nthRootProofBits := flag.Int("nth-root-proof-bits", 128, "statistical security parameter of the Nth root proof")
+	flag.Parse()
+
+	if err := run(*bitsFlag, *runs, *rangeProofBits, *nthRootProofBits); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run profiles every protocol for every key size and prints the table.
+func run(bitsFlag string, runs, rangeProofBits, nthRootProofBits int) error {
+	if runs < 1 {
+		return fmt.Errorf("invalid number of runs: %d", runs)
+	}
+
+	var sizes []int
+	for _, s := range strings.Split(bitsFlag, ",") {
+		bits, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("invalid key size %q: %w", s, err)
+		}
+		sizes = append(sizes, bits)
+	}
+
+	checksum := sha256.Sum256([]byte("Hello World"))
+	hash := checksum[:]
+
+	wit, stmt, err := adaptor.GenerateHardRelation(secp256k1)
+	if err != nil {
+		return err
+	}
+	pStmt, err := proofs.GenerateDLKProof(secp256k1, (*elliptic.Point)(stmt), (*big.Int)(wit))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROTOCOL\tBITS\tSTEP\tMEAN\tMIN\tMAX\t")
+
+	for _, bits := range sizes {
+		keygen := newTable("Keygen", bits)
+		var last *profile.Profile
+		for i := 0; i < runs; i++ {
+			prof, err := profile.Run(profile.Keygen(secp256k1, rangeProofBits, nthRootProofBits, bits)...)
+			if err != nil {
+				return fmt.Errorf("keygen (%d bits): %w", bits, err)
+			}
+			keygen.add(prof)
+			last = prof
+		}
+		keygen.print(w)
+
+		km1, km2, err := last.KeyMaterial()
+		if err != nil {
+			return err
+		}
+
+		dlenc, err := profile.DLEncProof(secp256k1, km1, km2)
+		if err != nil {
+			return err
+		}
+
+		protocols := []struct {
+			name    string
+			parties []profile.Party
+		}{
+			{"Sign", profile.Sign(secp256k1, km1, km2, hash)},
+			{"Adaptor", profile.Adaptor(secp256k1, km1, km2, hash, stmt, pStmt)},
+			{"DLEncProof", dlenc},
+		}
+
+		for _, protocol := range protocols {
+			t := newTable(protocol.name, bits)
+			for i := 0; i < runs; i++ {
+				prof, err := profile.Run(protocol.parties...)
+				if err != nil {
+					return fmt.Errorf("%s (%d bits): %w", strings.ToLower(protocol.name), bits, err)
+				}
+				t.add(prof)
+			}
+			t.print(w)
+		}
+	}
+
+	return w.Flush()
+}
+
+// table aggregates the step latencies of several runs of a protocol.
+type table struct {
+	protocol string
+	bits     int
+	steps    []string
+	samples  map[string][]time.Duration
+}
+
+// newTable creates a new table for the protocol and key size.
+func newTable(protocol string, bits int) *table {
+	return &table{
+		protocol: protocol,
+		bits:     bits,
+		samples:  make(map[string][]time.Duration),
+	}
+}
+
+// add adds the step latencies of the profiled run.
+func (t *table) add(prof *profile.Profile) {
+	var total time.Duration
+	for _, step := range prof.Steps {
+		name := step.Name()
+		if _, ok := t.samples[name]; !ok {
+			t.steps = append(t.steps, name)
+		}
+		t.samples[name] = append(t.samples[name], step.Duration)
+		total += step.Duration
+	}
+
+	t.samples["Total"] = append(t.samples["Total"], total)
+}
+
+// print writes a row with the mean, minimum and maximum latency per step.
+func (t *table) print(w *tabwriter.Writer) {
+	for _, name := range append(t.steps, "Total") {
+		samples := t.samples[name]
+
+		var sum time.Duration
+		lo, hi := samples[0], samples[0]
+		for _, d := range samples {
+			sum += d
+			lo = min(lo, d)
+			hi = max(hi, d)
+		}
+		mean := sum / time.Duration(len(samples))
+
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t\n", t.protocol, t.bits, name, round(mean), round(lo), round(hi))
+	}
+}
+
+// round rounds the duration to a readable precision.
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
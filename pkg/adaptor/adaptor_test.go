@@ -3,7 +3,9 @@ package adaptor_test
 import (
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -14,19 +16,30 @@ import (
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	"github.com/primefactor-io/ecc/pkg/keys"
 	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/adaptor/messages"
 	"github.com/primefactor-io/lindell17/pkg/adaptor/party1"
 	"github.com/primefactor-io/lindell17/pkg/adaptor/party2"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/policy"
+	"github.com/primefactor-io/lindell17/pkg/profile"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	pKeys "github.com/primefactor-io/paillier/pkg/keys"
 )
 
 var p1Params *party1.Params
 var p2Params *party2.Params
+var p2AllowedParams *party2.Params
+var p2DeniedParams *party2.Params
 var qShared *elliptic.Point
 var wit *adaptor.Witness
 var stmt *adaptor.Statement
 var pStmt *proofs.DLKProof
+var paillierPk *pKeys.PublicKey
+var keyMaterial1 *kParty1.KeyMaterial
+var keyMaterial2 *kParty2.KeyMaterial
 
 var secp256k1 = curves.Secp256k1
 
@@ -38,6 +51,7 @@ func TestMain(m *testing.M) {
 	pStmt, _ = proofs.GenerateDLKProof(secp256k1, (*elliptic.Point)(stmt), (*big.Int)(wit))
 
 	sk, pk, _ := pKeys.GenerateKeys(1024)
+	paillierPk = pk
 
 	x1, _ := secp256k1.GetRandomScalar(q3)
 	x1Enc, _ := cipher.Encrypt(pk, x1.Bytes())
@@ -50,6 +64,16 @@ func TestMain(m *testing.M) {
 
 	p1Params = party1.NewParams(secp256k1, sk, qShared)
 	p2Params = party2.NewParams(secp256k1, pk, qShared, x1Enc, x2)
+	keyMaterial1 = kParty1.NewKeyMaterial(x1, sk, pk, qShared)
+	keyMaterial2 = kParty2.NewKeyMaterial(x1Enc, x2, pk, qShared)
+
+	engine := policy.NewEngine(policy.Destinations("bc1qallowed"))
+	p2AllowedParams = party2.NewParams(secp256k1, pk, qShared, x1Enc, x2).
+		WithPolicy(engine, "key").
+		WithPayload(&lindell17.Payload{Destination: "bc1qallowed"})
+	p2DeniedParams = party2.NewParams(secp256k1, pk, qShared, x1Enc, x2).
+		WithPolicy(engine, "key").
+		WithPayload(&lindell17.Payload{Destination: "bc1qunknown"})
 
 	m.Run()
 }
@@ -289,6 +313,136 @@ func TestAdaptor(t *testing.T) {
 		}
 	})
 
+	t.Run("Sign - Invalid (s'' = 0 mod q)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, stmt, pStmt, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, stmt, pStmt, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if msg.MessageId() == 3 {
+						// Parse old message.
+						msg := msg.(*messages.Message3)
+
+						// Encrypt q so that s'' = 0 mod q.
+						ciphertext, _ := cipher.Encrypt(paillierPk, secp256k1.N().Bytes())
+
+						// Replace existing message.
+						msg = messages.NewMessage3(msg.SessionId(), msg.R2, msg.PR2, msg.R2Prime, msg.PK2DLEq, ciphertext)
+
+						// Inject faulty message.
+						if _, err := p1.Process(msg); err != nil {
+							errCh <- err
+						}
+
+						break
+					}
+
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, party1.ErrInvertSPPrime) {
+					t.Fatalf("want error %v, got %v", party1.ErrInvertSPPrime, err)
+				}
+
+				break coord
+			case <-resCh:
+			}
+		}
+	})
+
+	t.Run("Sign - Invalid (s' = 0)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, stmt, pStmt, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, stmt, pStmt, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if msg.MessageId() == 4 {
+						// Parse old message.
+						msg := msg.(*messages.Message4)
+
+						// Set s' to 0 so that it can't be inverted.
+						preSig := ecdsa.NewPreSignature(msg.PreSig.R, big.NewInt(0), msg.PreSig.V)
+
+						// Replace existing message.
+						msg = messages.NewMessage4(msg.SessionId(), preSig)
+
+						// Inject faulty message.
+						if _, err := p2.Process(msg); err != nil {
+							errCh <- err
+						}
+
+						break
+					}
+
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, lindell17.ErrInvalidMessage) {
+					t.Fatalf("want error %v, got %v", lindell17.ErrInvalidMessage, err)
+				}
+
+				break coord
+			case <-resCh:
+			}
+		}
+	})
+
 	t.Run("Party1 - Start - Invalid (Hash length)", func(t *testing.T) {
 		t.Parallel()
 
@@ -382,6 +536,82 @@ func TestAdaptor(t *testing.T) {
 			t.Errorf("want error %v, got %v", party2.ErrInvalidStatementDLKProof, err)
 		}
 	})
+
+	t.Run("Party2 - Start (Policy allowed)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p2 := party2.NewParty2(p2AllowedParams, hash, stmt, pStmt, outCh, resCh)
+
+		_, err := p2.Start()
+
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Party2 - Start - Invalid (Policy denied)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p2 := party2.NewParty2(p2DeniedParams, hash, stmt, pStmt, outCh, resCh)
+
+		_, err := p2.Start()
+
+		if !errors.Is(err, lindell17.ErrPolicyDenied) {
+			t.Errorf("want error %v, got %v", lindell17.ErrPolicyDenied, err)
+		}
+		if len(outCh) != 0 {
+			t.Error("expected no message to be sent")
+		}
+	})
+
+	t.Run("Process - Invalid (Foreign message type)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 4)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, stmt, pStmt, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, stmt, pStmt, outCh, resCh)
+
+		tests := []struct {
+			party lindell17.Participant
+			msg   *foreignMessage
+			want  error
+		}{
+			{p1, &foreignMessage{lindell17.Adaptor, lindell17.Party2, lindell17.Party1, 1}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.Adaptor, lindell17.Party2, lindell17.Party1, 3}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.Adaptor, lindell17.Party2, lindell17.Party1, 5}, lindell17.ErrUnknownMessage},
+			{p2, &foreignMessage{lindell17.Adaptor, lindell17.Party1, lindell17.Party2, 2}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.Adaptor, lindell17.Party1, lindell17.Party2, 4}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.Adaptor, lindell17.Party1, lindell17.Party2, 5}, lindell17.ErrUnknownMessage},
+		}
+
+		for _, tt := range tests {
+			_, err := tt.party.Process(tt.msg)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("message id %d: want error %v, got %v", tt.msg.messageId, tt.want, err)
+			}
+		}
+	})
 }
 
 type container struct {
@@ -401,3 +631,94 @@ func (c *container) add(entity lindell17.Entity, preSignature *ecdsa.PreSignatur
 
 	c.preSignatures[entity] = preSignature
 }
+
+func FuzzProcessParty1(f *testing.F) {
+	checksum := sha256.Sum256([]byte("Hello World"))
+
+	profile.Fuzz(f, lindell17.Party1, profile.Adaptor(secp256k1, keyMaterial1, keyMaterial2, checksum[:], stmt, pStmt)...)
+}
+
+func FuzzProcessParty2(f *testing.F) {
+	checksum := sha256.Sum256([]byte("Hello World"))
+
+	profile.Fuzz(f, lindell17.Party2, profile.Adaptor(secp256k1, keyMaterial1, keyMaterial2, checksum[:], stmt, pStmt)...)
+}
+
+// benchmarkPaillierBits are the Paillier key sizes the steps are benchmarked with.
+var benchmarkPaillierBits = []int{1024, 2048, 3072}
+
+func BenchmarkAdaptor(b *testing.B) {
+	for _, bits := range benchmarkPaillierBits {
+		b.Run(fmt.Sprintf("%d", bits), func(b *testing.B) {
+			km1, km2 := benchmarkKeyMaterial(b, bits)
+
+			checksum := sha256.Sum256([]byte("Hello World"))
+
+			prof, err := profile.Run(profile.Adaptor(secp256k1, km1, km2, checksum[:], stmt, pStmt)...)
+			if err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+
+			for _, step := range prof.Steps {
+				b.Run(step.Name(), func(b *testing.B) {
+					prof.Benchmark(b, step)
+				})
+			}
+		})
+	}
+}
+
+// benchmarkKeyMaterial runs key generation with a Paillier key of the given
+// size.
+func benchmarkKeyMaterial(b *testing.B, bits int) (*kParty1.KeyMaterial, *kParty2.KeyMaterial) {
+	b.Helper()
+
+	prof, err := profile.Run(profile.Keygen(secp256k1, 40, 128, bits)...)
+	if err != nil {
+		b.Fatalf("expected no error, got %v", err)
+	}
+
+	km1, km2, err := prof.KeyMaterial()
+	if err != nil {
+		b.Fatalf("expected no error, got %v", err)
+	}
+
+	return km1, km2
+}
+
+// foreignMessage is a message type that isn't part of the protocol but
+// implements the message interface.
+type foreignMessage struct {
+	protocol  lindell17.Protocol
+	from      lindell17.Entity
+	to        lindell17.Entity
+	messageId int
+}
+
+func (m *foreignMessage) To() lindell17.Entity {
+	return m.to
+}
+
+func (m *foreignMessage) From() lindell17.Entity {
+	return m.from
+}
+
+func (m *foreignMessage) Protocol() lindell17.Protocol {
+	return m.protocol
+}
+
+func (m *foreignMessage) MessageId() int {
+	return m.messageId
+}
+
+func (m *foreignMessage) SessionId() string {
+	return strings.Repeat("0", lindell17.SessionIdLength)
+}
+
+func (m *foreignMessage) IsValid() bool {
+	return true
+}
+
+func (m *foreignMessage) ValidateFor(curve weierstrass.Curve, pk *pKeys.PublicKey) error {
+	return nil
+}
@@ -2,7 +2,9 @@ package messages
 
 import (
 	"github.com/primefactor-io/commitment/pkg/hash"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message1 is the protocol's first message that is sent from party 2 to party 1.
@@ -45,7 +47,15 @@ func (m *Message1) SessionId() string {
 }
 
 func (m *Message1) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.CR2 != nil &&
 		m.CR2Prime != nil
 }
+
+func (m *Message1) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	return nil
+}
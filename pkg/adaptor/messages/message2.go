@@ -1,9 +1,13 @@
 package messages
 
 import (
+	"fmt"
+
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message2 is the protocol's second message that is sent from party 1 to party 2.
@@ -52,9 +56,24 @@ func (m *Message2) SessionId() string {
 }
 
 func (m *Message2) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.R1 != nil &&
 		m.PR1 != nil &&
 		m.R1Prime != nil &&
 		m.PK1DLEq != nil
 }
+
+func (m *Message2) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	if err := lindell17.CheckPoint(curve, m.R1); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+	if err := lindell17.CheckPoint(curve, m.R1Prime); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+
+	return nil
+}
@@ -1,10 +1,14 @@
 package messages
 
 import (
+	"fmt"
+
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
 	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message3 is the protocol's third message that is sent from party 2 to party 1.
@@ -56,10 +60,28 @@ func (m *Message3) SessionId() string {
 }
 
 func (m *Message3) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.R2 != nil &&
 		m.PR2 != nil &&
 		m.R2Prime != nil &&
 		m.PK2DLEq != nil &&
 		m.Ciphertext != nil
 }
+
+func (m *Message3) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	if err := lindell17.CheckPoint(curve, m.R2); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+	if err := lindell17.CheckPoint(curve, m.R2Prime); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+	if err := lindell17.CheckCiphertext(pk, m.Ciphertext); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+
+	return nil
+}
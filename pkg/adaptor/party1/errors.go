@@ -29,6 +29,12 @@ var (
 	ErrComputeR = fmt.Errorf("unable to compute R")
 	// ErrDecryptCiphertext is returned if the ciphertext can't be decrypted.
 	ErrDecryptCiphertext = fmt.Errorf("unable to decrypt ciphertext")
+	// ErrInvertNonceK1 is returned if the nonce k1 can't be inverted.
+	ErrInvertNonceK1 = fmt.Errorf("unable to invert nonce k1")
+	// ErrComputeSPrime is returned if s' can't be computed.
+	ErrComputeSPrime = fmt.Errorf("unable to compute s'")
+	// ErrInvertSPPrime is returned if the decrypted s'' can't be inverted.
+	ErrInvertSPPrime = fmt.Errorf("unable to invert s'' (s'' = 0 mod q)")
 	// ErrComputeU1TimesG is returned if u_1 * G can't be computed.
 	ErrComputeU1TimesG = fmt.Errorf("unable to compute u_1 * G")
 	// ErrComputeU2TimesQ is returned if u_2 * Q can't be computed.
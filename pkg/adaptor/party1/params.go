@@ -1,23 +1,99 @@
 package party1
 
 import (
+	"crypto/rand"
+	"io"
+
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/crt"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/scalar"
+	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Params is an instance of parameters party 1 uses.
 type Params struct {
-	curve   weierstrass.Curve
-	sk      *keys.PrivateKey
-	qShared *elliptic.Point
+	curve     weierstrass.Curve
+	field     *scalar.Field
+	sk        *keys.PrivateKey
+	decrypter *crt.Decrypter
+	crtErr    error
+	qShared   *elliptic.Point
+	limits    *lindell17.Limits
+	recorder  lindell17.Recorder
+	observer  lindell17.Observer
+	rand      io.Reader
 }
 
 // NewParams creates a new instance of parameters party 1 uses.
 func NewParams(curve weierstrass.Curve, sk *keys.PrivateKey, qShared *elliptic.Point) *Params {
+	// Precompute the constants of the CRT decryption once, so that every
+	// protocol run can use it. Party 1 falls back to the regular decryption if
+	// the private key's modulus can't be factored and reports the error to its
+	// observer (see lindell17.EventFallback).
+	decrypter, crtErr := crt.NewDecrypter(sk)
+
+	// Build the scalar field once, so that every protocol run can use it. It's
+	// nil if the curve's order is invalid, which fails the protocol runs.
+	field, _ := scalar.NewField(curve.N())
+
 	return &Params{
-		curve:   curve,
-		sk:      sk,
-		qShared: qShared,
+		curve:     curve,
+		field:     field,
+		sk:        sk,
+		decrypter: decrypter,
+		crtErr:    crtErr,
+		qShared:   qShared,
+		limits:    lindell17.DefaultLimits(),
+		rand:      rand.Reader,
+	}
+}
+
+// Destroy wipes the private key the params hold. The params can't be used
+// in protocol runs afterwards.
+func (p *Params) Destroy() {
+	utils.WipePaillierKey(p.sk)
+	if p.decrypter != nil {
+		p.decrypter.Destroy()
 	}
 }
+
+// WithLimits sets the resource limits party 1 enforces on incoming messages.
+// A nil value restores the default limits.
+// Returns the params to allow chaining.
+func (p *Params) WithLimits(limits *lindell17.Limits) *Params {
+	if limits == nil {
+		limits = lindell17.DefaultLimits()
+	}
+
+	p.limits = limits
+
+	return p
+}
+
+// WithRecorder sets the recorder party 1 records its transcript with.
+// Returns the params to allow chaining.
+func (p *Params) WithRecorder(recorder lindell17.Recorder) *Params {
+	p.recorder = recorder
+
+	return p
+}
+
+// WithObserver sets the observer party 1 reports the events of its
+// protocol runs to.
+// Returns the params to allow chaining.
+func (p *Params) WithObserver(observer lindell17.Observer) *Params {
+	p.observer = observer
+
+	return p
+}
+
+// WithRandomness sets the source of randomness party 1 uses.
+// Returns the params to allow chaining.
+func (p *Params) WithRandomness(reader io.Reader) *Params {
+	p.rand = reader
+
+	return p
+}
@@ -1,7 +1,10 @@
 package party1
 
 import (
+	"fmt"
+	"io"
 	"math/big"
+	"time"
 
 	"github.com/primefactor-io/commitment/pkg/hash"
 	"github.com/primefactor-io/ecc/pkg/adaptor"
@@ -10,7 +13,10 @@ import (
 	"github.com/primefactor-io/ecc/pkg/proofs"
 	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/adaptor/messages"
+	"github.com/primefactor-io/lindell17/pkg/crt"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/scalar"
 	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/keys"
@@ -19,41 +25,61 @@ import (
 // Party1 is an instance of party 1 that participates in the adaptor signature
 // protocol.
 type Party1 struct {
-	curve    weierstrass.Curve
-	sk       *keys.PrivateKey
-	qShared  *elliptic.Point
-	hash     []byte
-	stmt     *adaptor.Statement
-	pStmt    *proofs.DLKProof
-	y        *elliptic.Point
-	k1       *big.Int
-	cR2      *hash.Commitment
-	cR2Prime *hash.Commitment
-	state    lindell17.State
-	outCh    chan<- lindell17.Message
-	resCh    chan<- lindell17.Result
+	curve     weierstrass.Curve
+	field     *scalar.Field
+	sk        *keys.PrivateKey
+	decrypter *crt.Decrypter
+	crtErr    error
+	pk        *keys.PublicKey
+	qShared   *elliptic.Point
+	hash      []byte
+	stmt      *adaptor.Statement
+	pStmt     *proofs.DLKProof
+	y         *elliptic.Point
+	k1        *big.Int
+	cR2       *hash.Commitment
+	cR2Prime  *hash.Commitment
+	limits    *lindell17.Limits
+	recorder  lindell17.Recorder
+	tracer    *lindell17.Tracer
+	rand      io.Reader
+	messages  int
+	state     lindell17.State
+	outCh     chan<- lindell17.Message
+	resCh     chan<- lindell17.Result
 }
 
 // NewParty1 creates a new instance of party 1 that participates in the adaptor
 // signature protocol.
 func NewParty1(params *Params, hash []byte, stmt *adaptor.Statement, pStmt *proofs.DLKProof, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) *Party1 {
 	return &Party1{
-		curve:   params.curve,
-		sk:      params.sk,
-		qShared: params.qShared,
-		hash:    hash,
-		stmt:    stmt,
-		pStmt:   pStmt,
-		state:   lindell17.Start,
-		outCh:   outCh,
-		resCh:   resCh,
+		curve:     params.curve,
+		field:     params.field,
+		sk:        params.sk,
+		decrypter: params.decrypter,
+		crtErr:    params.crtErr,
+		pk:        keys.DerivePublicKey(params.sk),
+		qShared:   params.qShared,
+		hash:      hash,
+		stmt:      stmt,
+		pStmt:     pStmt,
+		limits:    params.limits,
+		recorder:  params.recorder,
+		tracer:    lindell17.NewTracer(params.observer, lindell17.Adaptor, lindell17.Party1),
+		rand:      params.rand,
+		state:     lindell17.Start,
+		outCh:     outCh,
+		resCh:     resCh,
 	}
 }
 
 // Start starts party 1 of the protocol.
 // Returns an error if the current state is invalid, the hash has an invalid
 // length, the statement's DLK proof is invalid or starting the protocol fails.
-func (p *Party1) Start() (bool, error) {
+func (p *Party1) Start() (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
 	// Validate state.
 	if p.state != lindell17.Start {
 		return false, lindell17.ErrInvalidState
@@ -67,7 +93,11 @@ func (p *Party1) Start() (bool, error) {
 	// Verify Statement DLK proof.
 	y := (*elliptic.Point)(p.stmt)
 	isValid, err := proofs.VerifyDLKProof(p.curve, p.pStmt, y)
-	if err != nil || !isValid {
+	p.tracer.Proof("statement DLK proof", err == nil && isValid)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidStatementDLKProof, err)
+	}
+	if !isValid {
 		return false, ErrInvalidStatementDLKProof
 	}
 
@@ -75,15 +105,25 @@ func (p *Party1) Start() (bool, error) {
 	p.y = y
 
 	// Transition to next state.
-	p.state = lindell17.Step1
+	p.setState(lindell17.Step1)
 
 	return true, nil
 }
 
 // Process processes an incoming protocol message.
-// Returns an error if the message was sent by the wrong sender, is invalid,
+// Returns an error if the session's message limit is exceeded, the message
+// can't be recorded or the message was sent by the wrong sender, is invalid,
 // unknown or not intended for the protocol / recipient.
-func (p *Party1) Process(msg lindell17.Message) (bool, error) {
+func (p *Party1) Process(msg lindell17.Message) (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
+	// Enforce the session's message limit.
+	p.messages++
+	if p.messages > p.limits.MaxMessages {
+		return false, lindell17.ErrTooManyMessages
+	}
+
 	// Check message protocol.
 	if msg.Protocol() != lindell17.Adaptor {
 		return false, lindell17.ErrWrongProtocol
@@ -99,17 +139,35 @@ func (p *Party1) Process(msg lindell17.Message) (bool, error) {
 		return false, lindell17.ErrWrongRecipient
 	}
 
+	// Record inbound message.
+	if p.recorder != nil {
+		if err := p.recorder.RecordReceived(msg); err != nil {
+			return false, fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	// Trace inbound message.
+	p.tracer.Received(msg)
+
 	// Validate message.
-	if !msg.IsValid() {
-		return false, lindell17.ErrInvalidMessage
+	if err := msg.ValidateFor(p.curve, p.pk); err != nil {
+		return false, err
 	}
 
 	// Process message.
 	switch msg.MessageId() {
 	case 1:
-		return p.step1(msg.(*messages.Message1))
+		m, ok := msg.(*messages.Message1)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step1(m)
 	case 3:
-		return p.step2(msg.(*messages.Message3))
+		m, ok := msg.(*messages.Message3)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step2(m)
 	default:
 		return false, lindell17.ErrUnknownMessage
 	}
@@ -127,33 +185,33 @@ func (p *Party1) step1(msg *messages.Message1) (bool, error) {
 	}
 
 	// Sample random partial nonce k1.
-	k1, err := p.curve.GetRandomScalar()
+	k1, err := random.Scalar(p.rand, p.curve)
 	if err != nil {
-		return false, ErrSampleNonceK1
+		return false, fmt.Errorf("%w: %w", ErrSampleNonceK1, err)
 	}
 
 	// Compute R1.
 	r1, err := p.curve.ScalarMultiply(k1, p.curve.G()) // k1 * G
 	if err != nil {
-		return false, ErrComputeR1
+		return false, fmt.Errorf("%w: %w", ErrComputeR1, err)
 	}
 
 	// Generate R1 DLK proof.
-	pR1, err := proofs.GenerateDLKProof(p.curve, r1, k1)
+	pR1, err := random.DLKProof(p.rand, p.curve, r1, k1)
 	if err != nil {
-		return false, ErrGenerateR1DLKProof
+		return false, fmt.Errorf("%w: %w", ErrGenerateR1DLKProof, err)
 	}
 
 	// Compute R1'.
 	r1Prime, err := p.curve.ScalarMultiply(k1, p.y) // k1 * Y
 	if err != nil {
-		return false, ErrComputeR1Prime
+		return false, fmt.Errorf("%w: %w", ErrComputeR1Prime, err)
 	}
 
 	// Generate DLEq proof.
-	pK1DLEq, err := proofs.GenerateDLEqProof(p.curve, p.curve.G(), r1, p.y, r1Prime, k1)
+	pK1DLEq, err := random.DLEqProof(p.rand, p.curve, p.curve.G(), r1, p.y, r1Prime, k1)
 	if err != nil {
-		return false, ErrGenerateDLEqProof
+		return false, fmt.Errorf("%w: %w", ErrGenerateDLEqProof, err)
 	}
 
 	// Store k1.
@@ -164,10 +222,12 @@ func (p *Party1) step1(msg *messages.Message1) (bool, error) {
 	p.cR2Prime = msg.CR2Prime
 
 	// Transition to next state.
-	p.state = lindell17.Step2
+	p.setState(lindell17.Step2)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage2(sid, r1, pR1, r1Prime, pK1DLEq)
+	if err := p.send(messages.NewMessage2(sid, r1, pR1, r1Prime, pK1DLEq)); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -185,80 +245,113 @@ func (p *Party1) step2(msg *messages.Message3) (bool, error) {
 
 	// Verify commitment to R2.
 	isValid := hash.Verify(p.cR2, msg.R2.X.Bytes(), msg.R2.Y.Bytes())
+	p.tracer.Proof("R2 commitment", isValid)
 	if !isValid {
 		return false, ErrInvalidR2Commitment
 	}
 
 	// Verify R2 DLK proof.
 	isValid, err := proofs.VerifyDLKProof(p.curve, msg.PR2, msg.R2)
-	if err != nil || !isValid {
+	p.tracer.Proof("R2 DLK proof", err == nil && isValid)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidR2DLKProof, err)
+	}
+	if !isValid {
 		return false, ErrInvalidR2DLKProof
 	}
 
 	// Verify commitment to R2'.
 	isValid = hash.Verify(p.cR2Prime, msg.R2Prime.X.Bytes(), msg.R2Prime.Y.Bytes())
+	p.tracer.Proof("R2' commitment", isValid)
 	if !isValid {
 		return false, ErrInvalidR2PrimeCommitment
 	}
 
 	// Verify DLEq proof.
 	isValid, err = proofs.VerifyDLEqProof(p.curve, msg.PK2DLEq, p.curve.G(), msg.R2, p.y, msg.R2Prime)
-	if err != nil || !isValid {
+	p.tracer.Proof("DLEq proof", err == nil && isValid)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidDLEqProof, err)
+	}
+	if !isValid {
 		return false, ErrInvalidDLEqProof
 	}
 
 	// Compute R.
 	rP, err := p.curve.ScalarMultiply(p.k1, msg.R2Prime) // k1 * R2' = k1 * (k2 * Y)
 	if err != nil {
-		return false, ErrComputeR
+		return false, fmt.Errorf("%w: %w", ErrComputeR, err)
 	}
 
 	// Compute r.
 	r := new(big.Int).Mod(rP.X, p.curve.N()) // R_x mod q
 
-	// Turn hash into big integer.
-	z := new(big.Int).SetBytes(p.hash)
-
 	// Compute s''.
-	plaintext, err := cipher.Decrypt(p.sk, msg.Ciphertext)
+	plaintext, err := p.decrypt(msg.Ciphertext)
 	if err != nil {
-		return false, ErrDecryptCiphertext
+		return false, fmt.Errorf("%w: %w", ErrDecryptCiphertext, err)
+	}
+
+	// Compute secret-dependent values in constant time.
+	field := p.field
+	if field == nil {
+		return false, fmt.Errorf("%w: %w", ErrComputeSPrime, scalar.ErrInvalidOrder)
 	}
-	// Turn decrypted ciphertext into big int.
-	sPPrime := new(big.Int).SetBytes(plaintext) // s''
+
+	// Turn hash into scalar.
+	z := field.NewScalar().SetBytes(p.hash)
+
+	// Turn decrypted ciphertext into scalar.
+	sPPrime := field.NewScalar().SetBytes(plaintext) // s'' mod q
+	defer sPPrime.Wipe()
+	utils.WipeBytes(plaintext)
 
 	// Compute v.
 	v := new(big.Int).And(rP.Y, big.NewInt(1)) // R_y & 1
 
 	// Compute s'.
-	in1 := new(big.Int).ModInverse(p.k1, p.curve.N()) // k1^-1 mod q
-	in2 := new(big.Int).Mul(sPPrime, in1)             // s'' * k1^-1
-	sPrime := new(big.Int).Mod(in2, p.curve.N())      // s'' * k1^-1 mod q
+	k1 := field.NewScalar().SetInt(p.k1)
+	defer k1.Wipe()
+	if k1.IsZero() == 1 {
+		return false, ErrInvertNonceK1
+	}
+	in1 := field.NewScalar().Invert(k1) // k1^-1 mod q
+	defer in1.Wipe()
+	sPrime := field.NewScalar().Mul(sPPrime, in1) // s'' * k1^-1 mod q
 
 	// Invert s''.
-	sPPrimeInv := new(big.Int).ModInverse(sPPrime, p.curve.N()) // s''^-1 mod q
+	if sPPrime.IsZero() == 1 {
+		return false, ErrInvertSPPrime
+	}
+	sPPrimeInv := field.NewScalar().Invert(sPPrime) // s''^-1 mod q
+	defer sPPrimeInv.Wipe()
 
 	// Compute u_1.
-	in3 := new(big.Int).Mul(z, sPPrimeInv)   // z * s''^-1
-	u1 := new(big.Int).Mod(in3, p.curve.N()) // z * s''^-1 mod q
+	in2 := field.NewScalar().Mul(z, sPPrimeInv) // z * s''^-1 mod q
 
 	// Compute u_2.
-	in4 := new(big.Int).Mul(r, sPPrimeInv)   // r * s''^-1
-	u2 := new(big.Int).Mod(in4, p.curve.N()) // r * s''^-1 mod q
+	in3 := field.NewScalar().Mul(field.NewScalar().SetInt(r), sPPrimeInv) // r * s''^-1 mod q
+	defer in2.Wipe()
+	defer in3.Wipe()
+
+	// The scalar multiplications need big integers.
+	u1 := in2.BigInt()
+	u2 := in3.BigInt()
+	defer utils.Wipe(u1, u2)
 
 	// Verify that R2 = (u_1 * G) + (u_2 * Q).
 	lhs := msg.R2
 	in5, err := p.curve.ScalarMultiply(u1, p.curve.G()) // u_1 * G
 	if err != nil {
-		return false, ErrComputeU1TimesG
+		return false, fmt.Errorf("%w: %w", ErrComputeU1TimesG, err)
 	}
 	in6, err := p.curve.ScalarMultiply(u2, p.qShared) // u_2 * Q
 	if err != nil {
-		return false, ErrComputeU2TimesQ
+		return false, fmt.Errorf("%w: %w", ErrComputeU2TimesQ, err)
 	}
 	rhs, err := p.curve.Add(in5, in6) // (u_1 * G) + (u_2 * Q)
 	if err != nil {
-		return false, ErrComputeU1TimesGPlusU2TimesQ
+		return false, fmt.Errorf("%w: %w", ErrComputeU1TimesGPlusU2TimesQ, err)
 	}
 
 	isValid = lhs.Equal(rhs)
@@ -267,13 +360,96 @@ func (p *Party1) step2(msg *messages.Message3) (bool, error) {
 	}
 
 	// Create pre-signature.
-	preSignature := ecdsa.NewPreSignature(r, sPrime, v)
+	preSignature := ecdsa.NewPreSignature(r, sPrime.BigInt(), v)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage4(sid, preSignature)
+	if err := p.send(messages.NewMessage4(sid, preSignature)); err != nil {
+		return false, err
+	}
 
 	// Send pre-signature over result channel.
-	p.resCh <- NewResult(sid, preSignature)
+	if err := p.sendResult(NewResult(sid, preSignature)); err != nil {
+		return false, err
+	}
+
+	// Wipe k1.
+	p.Destroy()
 
 	return true, nil
 }
+
+// decrypt decrypts the ciphertext via the Chinese Remainder Theorem if the
+// decrypter is available and via the regular decryption otherwise. The
+// decryption time and the fallback are reported to the observer.
+// Returns an error if the ciphertext can't be decrypted.
+func (p *Party1) decrypt(ciphertext cipher.Ciphertext) (cipher.Plaintext, error) {
+	defer p.tracer.Operation("paillier_decryption", time.Now())
+
+	if p.decrypter != nil {
+		return p.decrypter.Decrypt(ciphertext)
+	}
+
+	p.tracer.Fallback("paillier_decryption", p.crtErr)
+
+	return cipher.Decrypt(p.sk, ciphertext)
+}
+
+// Destroy wipes party 1's ephemeral secrets, drops its references to the
+// long-term secrets and ends the protocol run, so that further calls fail with
+// lindell17.ErrInvalidState. Party 1 destroys itself once it sent its result
+// or a call failed, so Destroy only needs to be called to cancel a run.
+func (p *Party1) Destroy() {
+	utils.Wipe(p.k1)
+
+	p.k1 = nil
+	p.sk = nil
+	p.decrypter = nil
+	p.state = lindell17.Destroyed
+	p.tracer.Destroyed()
+}
+
+// destroyOnError destroys party 1 if the call failed, since failed protocol
+// runs can't be resumed.
+func (p *Party1) destroyOnError(err *error) {
+	if *err != nil {
+		p.Destroy()
+	}
+}
+
+// setState transitions to the state and reports the transition.
+func (p *Party1) setState(state lindell17.State) {
+	p.state = state
+	p.tracer.Transition(state)
+}
+
+// send records the outbound message if a recorder is set, reports it to the
+// observer and sends it via the message channel.
+// Returns an error if the message can't be recorded.
+func (p *Party1) send(msg lindell17.Message) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordSent(msg); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Sent(msg)
+	p.outCh <- msg
+
+	return nil
+}
+
+// sendResult records the result if a recorder is set, reports it to the
+// observer and sends it via the result channel.
+// Returns an error if the result can't be recorded.
+func (p *Party1) sendResult(res lindell17.Result) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordResult(res); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Result(res)
+	p.resCh <- res
+
+	return nil
+}
@@ -1,16 +1,18 @@
 package party2
 
 import (
-	"crypto/rand"
+	"fmt"
+	"io"
 	"math/big"
 
-	"github.com/primefactor-io/commitment/pkg/hash"
 	"github.com/primefactor-io/ecc/pkg/adaptor"
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	"github.com/primefactor-io/ecc/pkg/proofs"
 	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/adaptor/messages"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/scalar"
 	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/homomorphic"
@@ -20,48 +22,69 @@ import (
 // Party2 is an instance of party 2 that participates in the adaptor signature
 // protocol.
 type Party2 struct {
-	curve   weierstrass.Curve
-	pk      *keys.PublicKey
-	qShared *elliptic.Point
-	x1Enc   cipher.Ciphertext
-	x2      *big.Int
-	hash    []byte
-	stmt    *adaptor.Statement
-	pStmt   *proofs.DLKProof
-	y       *elliptic.Point
-	k2      *big.Int
-	r2      *elliptic.Point
-	r2Prime *elliptic.Point
-	pR2     *proofs.DLKProof
-	pK2DLEq *proofs.DLEqProof
-	r1      *elliptic.Point
-	state   lindell17.State
-	outCh   chan<- lindell17.Message
-	resCh   chan<- lindell17.Result
+	curve    weierstrass.Curve
+	field    *scalar.Field
+	pk       *keys.PublicKey
+	qShared  *elliptic.Point
+	x1Enc    cipher.Ciphertext
+	x2       *big.Int
+	hash     []byte
+	stmt     *adaptor.Statement
+	pStmt    *proofs.DLKProof
+	y        *elliptic.Point
+	k2       *big.Int
+	r2       *elliptic.Point
+	r2Prime  *elliptic.Point
+	pR2      *proofs.DLKProof
+	pK2DLEq  *proofs.DLEqProof
+	r1       *elliptic.Point
+	limits   *lindell17.Limits
+	recorder lindell17.Recorder
+	tracer   *lindell17.Tracer
+	rand     io.Reader
+	policy   lindell17.Policy
+	keyID    string
+	payload  *lindell17.Payload
+	messages int
+	state    lindell17.State
+	outCh    chan<- lindell17.Message
+	resCh    chan<- lindell17.Result
 }
 
 // NewParty2 creates a new instance of party 2 that participates in the adaptor
 // signature protocol.
 func NewParty2(params *Params, hash []byte, stmt *adaptor.Statement, pStmt *proofs.DLKProof, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) *Party2 {
 	return &Party2{
-		curve:   params.curve,
-		pk:      params.pk,
-		qShared: params.qShared,
-		x1Enc:   params.x1Enc,
-		x2:      params.x2,
-		hash:    hash,
-		stmt:    stmt,
-		pStmt:   pStmt,
-		state:   lindell17.Start,
-		outCh:   outCh,
-		resCh:   resCh,
+		curve:    params.curve,
+		field:    params.field,
+		pk:       params.pk,
+		qShared:  params.qShared,
+		x1Enc:    params.x1Enc,
+		x2:       params.x2,
+		hash:     hash,
+		stmt:     stmt,
+		pStmt:    pStmt,
+		limits:   params.limits,
+		recorder: params.recorder,
+		tracer:   lindell17.NewTracer(params.observer, lindell17.Adaptor, lindell17.Party2),
+		rand:     params.rand,
+		policy:   params.policy,
+		keyID:    params.keyID,
+		payload:  params.payload,
+		state:    lindell17.Start,
+		outCh:    outCh,
+		resCh:    resCh,
 	}
 }
 
 // Start starts party 2 of the protocol.
 // Returns an error if the current state is invalid, the hash has an invalid
-// length, the statement's DLK proof is invalid or starting the protocol fails.
-func (p *Party2) Start() (bool, error) {
+// length, the statement's DLK proof is invalid, the signing policy denies the
+// request or starting the protocol fails.
+func (p *Party2) Start() (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
 	// Validate state.
 	if p.state != lindell17.Start {
 		return false, lindell17.ErrInvalidState
@@ -69,10 +92,11 @@ func (p *Party2) Start() (bool, error) {
 
 	// Generate session id.
 	bits := 128
-	sid, err := utils.GenerateSessionId(bits)
+	sid, err := utils.GenerateSessionIdFromReader(p.rand, bits)
 	if err != nil {
-		return false, lindell17.ErrGenerateSessionId
+		return false, err
 	}
+	p.tracer.SetSessionId(sid)
 
 	// Check if hash has length of 256 bits.
 	if len(p.hash) != utils.HashLength {
@@ -82,24 +106,46 @@ func (p *Party2) Start() (bool, error) {
 	// Verify Statement DLK proof.
 	y := (*elliptic.Point)(p.stmt)
 	isValid, err := proofs.VerifyDLKProof(p.curve, p.pStmt, y)
-	if err != nil || !isValid {
+	p.tracer.Proof("statement DLK proof", err == nil && isValid)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidStatementDLKProof, err)
+	}
+	if !isValid {
 		return false, ErrInvalidStatementDLKProof
 	}
 
+	// Enforce signing policy.
+	if p.policy != nil {
+		req := lindell17.NewSigningRequest(lindell17.Adaptor, p.keyID, p.hash, p.payload).WithStatement(p.stmt)
+		if err := lindell17.Enforce(p.policy, req); err != nil {
+			return false, err
+		}
+	}
+
 	// Store statement's underlying point.
 	p.y = y
 
 	// Transition to next state.
-	p.state = lindell17.Step1
+	p.setState(lindell17.Step1)
 
 	// Run step 1.
 	return p.step1(sid)
 }
 
 // Process processes an incoming protocol message.
-// Returns an error if the message was sent by the wrong sender, is invalid,
+// Returns an error if the session's message limit is exceeded, the message
+// can't be recorded or the message was sent by the wrong sender, is invalid,
 // unknown or not intended for the protocol / recipient.
-func (p *Party2) Process(msg lindell17.Message) (bool, error) {
+func (p *Party2) Process(msg lindell17.Message) (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
+	// Enforce the session's message limit.
+	p.messages++
+	if p.messages > p.limits.MaxMessages {
+		return false, lindell17.ErrTooManyMessages
+	}
+
 	// Check message protocol.
 	if msg.Protocol() != lindell17.Adaptor {
 		return false, lindell17.ErrWrongProtocol
@@ -115,17 +161,35 @@ func (p *Party2) Process(msg lindell17.Message) (bool, error) {
 		return false, lindell17.ErrWrongRecipient
 	}
 
+	// Record inbound message.
+	if p.recorder != nil {
+		if err := p.recorder.RecordReceived(msg); err != nil {
+			return false, fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	// Trace inbound message.
+	p.tracer.Received(msg)
+
 	// Validate message.
-	if !msg.IsValid() {
-		return false, lindell17.ErrInvalidMessage
+	if err := msg.ValidateFor(p.curve, p.pk); err != nil {
+		return false, err
 	}
 
 	// Process message.
 	switch msg.MessageId() {
 	case 2:
-		return p.step2(msg.(*messages.Message2))
+		m, ok := msg.(*messages.Message2)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step2(m)
 	case 4:
-		return p.step3(msg.(*messages.Message4))
+		m, ok := msg.(*messages.Message4)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step3(m)
 	default:
 		return false, lindell17.ErrUnknownMessage
 	}
@@ -141,45 +205,45 @@ func (p *Party2) step1(sessionId string) (bool, error) {
 	}
 
 	// Sample random partial nonce k2.
-	k2, err := p.curve.GetRandomScalar()
+	k2, err := random.Scalar(p.rand, p.curve)
 	if err != nil {
-		return false, ErrSampleNonceK2
+		return false, fmt.Errorf("%w: %w", ErrSampleNonceK2, err)
 	}
 
 	// Compute R2.
 	r2, err := p.curve.ScalarMultiply(k2, p.curve.G()) // k2 * G
 	if err != nil {
-		return false, ErrComputeR2
+		return false, fmt.Errorf("%w: %w", ErrComputeR2, err)
 	}
 
 	// Commit to R2.
-	cR2, err := hash.Commit(r2.X.Bytes(), r2.Y.Bytes())
+	cR2, err := random.Commit(p.rand, r2.X.Bytes(), r2.Y.Bytes())
 	if err != nil {
-		return false, ErrCommitToR2
+		return false, fmt.Errorf("%w: %w", ErrCommitToR2, err)
 	}
 
 	// Generate R2 DLK proof.
-	pR2, err := proofs.GenerateDLKProof(p.curve, r2, k2)
+	pR2, err := random.DLKProof(p.rand, p.curve, r2, k2)
 	if err != nil {
-		return false, ErrGenerateR2DLKProof
+		return false, fmt.Errorf("%w: %w", ErrGenerateR2DLKProof, err)
 	}
 
 	// Compute R2'.
 	r2Prime, err := p.curve.ScalarMultiply(k2, p.y) // k2 * Y
 	if err != nil {
-		return false, ErrComputeR2Prime
+		return false, fmt.Errorf("%w: %w", ErrComputeR2Prime, err)
 	}
 
 	// Commit to R2'.
-	cR2Prime, err := hash.Commit(r2Prime.X.Bytes(), r2Prime.Y.Bytes())
+	cR2Prime, err := random.Commit(p.rand, r2Prime.X.Bytes(), r2Prime.Y.Bytes())
 	if err != nil {
-		return false, ErrCommitToR2Prime
+		return false, fmt.Errorf("%w: %w", ErrCommitToR2Prime, err)
 	}
 
 	// Generate DLEq proof.
-	pK2DLEq, err := proofs.GenerateDLEqProof(p.curve, p.curve.G(), r2, p.y, r2Prime, k2)
+	pK2DLEq, err := random.DLEqProof(p.rand, p.curve, p.curve.G(), r2, p.y, r2Prime, k2)
 	if err != nil {
-		return false, ErrGenerateDLEqProof
+		return false, fmt.Errorf("%w: %w", ErrGenerateDLEqProof, err)
 	}
 
 	// Store k2, R2, R2', R2 DLK proof and DLEq proof.
@@ -190,10 +254,12 @@ func (p *Party2) step1(sessionId string) (bool, error) {
 	p.pK2DLEq = pK2DLEq
 
 	// Transition to next state.
-	p.state = lindell17.Step2
+	p.setState(lindell17.Step2)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage1(sessionId, cR2, cR2Prime)
+	if err := p.send(messages.NewMessage1(sessionId, cR2, cR2Prime)); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -211,20 +277,28 @@ func (p *Party2) step2(msg *messages.Message2) (bool, error) {
 
 	// Verify R1 DLK proof.
 	isValid, err := proofs.VerifyDLKProof(p.curve, msg.PR1, msg.R1)
-	if err != nil || !isValid {
+	p.tracer.Proof("R1 DLK proof", err == nil && isValid)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidR1DLKProof, err)
+	}
+	if !isValid {
 		return false, ErrInvalidR1DLKProof
 	}
 
 	// Verify DLEq proof.
 	isValid, err = proofs.VerifyDLEqProof(p.curve, msg.PK1DLEq, p.curve.G(), msg.R1, p.y, msg.R1Prime)
-	if err != nil || !isValid {
+	p.tracer.Proof("DLEq proof", err == nil && isValid)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidDLEqProof, err)
+	}
+	if !isValid {
 		return false, ErrInvalidDLEqProof
 	}
 
 	// Compute R.
 	rP, err := p.curve.ScalarMultiply(p.k2, msg.R1Prime) // k2 * R1' = k2 * (k1 * Y)
 	if err != nil {
-		return false, ErrComputeR
+		return false, fmt.Errorf("%w: %w", ErrComputeR, err)
 	}
 
 	// Compute r.
@@ -235,22 +309,37 @@ func (p *Party2) step2(msg *messages.Message2) (bool, error) {
 
 	// Sample random p from Z_q^2.
 	qq := new(big.Int).Mul(p.curve.N(), p.curve.N()) // q^2
-	randP, err := rand.Int(rand.Reader, qq)
+	randP, err := random.Int(p.rand, qq)
 	if err != nil {
-		return false, ErrSampleP
+		return false, fmt.Errorf("%w: %w", ErrSampleP, err)
+	}
+
+	// Compute secret-dependent values in constant time.
+	field := p.field
+	if field == nil {
+		return false, fmt.Errorf("%w: %w", ErrComputeC1, scalar.ErrInvalidOrder)
 	}
 
 	// Invert k2.
-	k2Inv := new(big.Int).ModInverse(p.k2, p.curve.N()) // k2^-1 mod q
+	k2 := field.NewScalar().SetInt(p.k2)
+	defer k2.Wipe()
+	if k2.IsZero() == 1 {
+		return false, ErrInvertNonceK2
+	}
+	k2Inv := field.NewScalar().Invert(k2) // k2^-1 mod q
+	defer k2Inv.Wipe()
 
 	// Compute c1.
-	in1 := new(big.Int).Mul(z, k2Inv)           // z * k2^-1
-	in2 := new(big.Int).Mod(in1, p.curve.N())   // z * k2^-1 mod q
+	in1 := field.NewScalar().Mul(field.NewScalar().SetInt(z), k2Inv) // z * k2^-1 mod q
+	defer in1.Wipe()
+	// The Paillier encryption needs big integers.
+	in2 := in1.BigInt()
 	in3 := new(big.Int).Mul(randP, p.curve.N()) // p * q
 	res := new(big.Int).Add(in2, in3)           // (z * k2^-1 mod q) + (p * q)
-	c1, nonce, err := cipher.EncryptAndReturnNonce(p.pk, res.Bytes())
+	defer utils.Wipe(randP, in2, in3, res)
+	c1, nonce, err := random.Encrypt(p.rand, p.pk, res.Bytes())
 	if err != nil {
-		return false, ErrComputeC1
+		return false, fmt.Errorf("%w: %w", ErrComputeC1, err)
 	}
 
 	// Ensure that gcd(nonce, N) = 1.
@@ -258,16 +347,23 @@ func (p *Party2) step2(msg *messages.Message2) (bool, error) {
 	if gcd.Cmp(big.NewInt(1)) != 0 {
 		return false, ErrInvalidGCD
 	}
+	defer utils.Wipe(nonce)
 
 	// Compute v.
-	in4 := new(big.Int).Mul(r, k2Inv)       // r * k2^-1
-	in5 := new(big.Int).Mul(in4, p.x2)      // r * k2^-1 * x2
-	v := new(big.Int).Mod(in5, p.curve.N()) // r * k2^-1 * x2 mod q
+	x2 := field.NewScalar().SetInt(p.x2)
+	defer x2.Wipe()
+	in4 := field.NewScalar().Mul(field.NewScalar().SetInt(r), k2Inv) // r * k2^-1 mod q
+	in5 := field.NewScalar().Mul(in4, x2)                            // r * k2^-1 * x2 mod q
+	defer in4.Wipe()
+	defer in5.Wipe()
+	// The homomorphic multiplication needs a big integer.
+	v := in5.BigInt()
+	defer utils.Wipe(v)
 
 	// Compute c2.
 	c2, err := homomorphic.MultiplyPlaintextValue(p.pk, p.x1Enc, v.Bytes())
 	if err != nil {
-		return false, ErrComputeC2
+		return false, fmt.Errorf("%w: %w", ErrComputeC2, err)
 	}
 
 	// Compute c3.
@@ -277,10 +373,12 @@ func (p *Party2) step2(msg *messages.Message2) (bool, error) {
 	p.r1 = msg.R1
 
 	// Transition to next state.
-	p.state = lindell17.Step3
+	p.setState(lindell17.Step3)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage3(sid, p.r2, p.pR2, p.r2Prime, p.pK2DLEq, c3)
+	if err := p.send(messages.NewMessage3(sid, p.r2, p.pR2, p.r2Prime, p.pK2DLEq, c3)); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -299,7 +397,7 @@ func (p *Party2) step3(msg *messages.Message4) (bool, error) {
 	// Turn hash into big integer.
 	z := new(big.Int).SetBytes(p.hash)
 
-	// Invert s'.
+	// Invert s' (which is in [1, q), see Message4.ValidateFor).
 	sPrimeInv := new(big.Int).ModInverse(msg.PreSig.S, p.curve.N()) // s'^-1 mod q
 
 	// Compute u.
@@ -313,19 +411,19 @@ func (p *Party2) step3(msg *messages.Message4) (bool, error) {
 	// Verify that k2 * R1 = (u * G) + (v * Q).
 	lhs, err := p.curve.ScalarMultiply(p.k2, p.r1) // k2 * R1
 	if err != nil {
-		return false, ErrComputeK2TimesR1
+		return false, fmt.Errorf("%w: %w", ErrComputeK2TimesR1, err)
 	}
 	in3, err := p.curve.ScalarMultiply(u, p.curve.G()) // u * G
 	if err != nil {
-		return false, ErrComputeUTimesG
+		return false, fmt.Errorf("%w: %w", ErrComputeUTimesG, err)
 	}
 	in4, err := p.curve.ScalarMultiply(v, p.qShared) // v * Q
 	if err != nil {
-		return false, ErrComputeVTimesQ
+		return false, fmt.Errorf("%w: %w", ErrComputeVTimesQ, err)
 	}
 	rhs, err := p.curve.Add(in3, in4) // (u * G) + (v * Q)
 	if err != nil {
-		return false, ErrComputeUTimesGPlusVTimesQ
+		return false, fmt.Errorf("%w: %w", ErrComputeUTimesGPlusVTimesQ, err)
 	}
 
 	isValid := lhs.Equal(rhs)
@@ -334,7 +432,71 @@ func (p *Party2) step3(msg *messages.Message4) (bool, error) {
 	}
 
 	// Send pre-signature over result channel.
-	p.resCh <- NewResult(sid, msg.PreSig)
+	if err := p.sendResult(NewResult(sid, msg.PreSig)); err != nil {
+		return false, err
+	}
+
+	// Wipe k2.
+	p.Destroy()
 
 	return true, nil
 }
+
+// Destroy wipes party 2's ephemeral secrets, drops its references to the
+// long-term secrets and ends the protocol run, so that further calls fail with
+// lindell17.ErrInvalidState. Party 2 destroys itself once it sent its result
+// or a call failed, so Destroy only needs to be called to cancel a run.
+func (p *Party2) Destroy() {
+	utils.Wipe(p.k2)
+
+	p.k2 = nil
+	p.x2 = nil
+	p.state = lindell17.Destroyed
+	p.tracer.Destroyed()
+}
+
+// destroyOnError destroys party 2 if the call failed, since failed protocol
+// runs can't be resumed.
+func (p *Party2) destroyOnError(err *error) {
+	if *err != nil {
+		p.Destroy()
+	}
+}
+
+// setState transitions to the state and reports the transition.
+func (p *Party2) setState(state lindell17.State) {
+	p.state = state
+	p.tracer.Transition(state)
+}
+
+// send records the outbound message if a recorder is set, reports it to the
+// observer and sends it via the message channel.
+// Returns an error if the message can't be recorded.
+func (p *Party2) send(msg lindell17.Message) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordSent(msg); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Sent(msg)
+	p.outCh <- msg
+
+	return nil
+}
+
+// sendResult records the result if a recorder is set, reports it to the
+// observer and sends it via the result channel.
+// Returns an error if the result can't be recorded.
+func (p *Party2) sendResult(res lindell17.Result) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordResult(res); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Result(res)
+	p.resCh <- res
+
+	return nil
+}
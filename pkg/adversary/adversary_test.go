@@ -0,0 +1,149 @@
+package adversary_test
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	aParty1 "github.com/primefactor-io/lindell17/pkg/adaptor/party1"
+	aParty2 "github.com/primefactor-io/lindell17/pkg/adaptor/party2"
+	"github.com/primefactor-io/lindell17/pkg/adversary"
+	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/prover"
+	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/verifier"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	sParty1 "github.com/primefactor-io/lindell17/pkg/sign/party1"
+	sParty2 "github.com/primefactor-io/lindell17/pkg/sign/party2"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+const paillierBits = 1024
+const rangeProofBits = 40
+const nthRootProofBits = 128
+
+var x1 *big.Int
+var x2 *big.Int
+var x1Enc cipher.Ciphertext
+var q1 *elliptic.Point
+var qShared *elliptic.Point
+var sk *keys.PrivateKey
+var pk *keys.PublicKey
+var stmt *adaptor.Statement
+var pStmt *proofs.DLKProof
+
+var secp256k1 = curves.Secp256k1
+
+func TestMain(m *testing.M) {
+	// x1 should be in the range x1 >= 0 and x1 < q / 3.
+	q3 := new(big.Int).Div(secp256k1.N(), big.NewInt(3)) // q / 3
+
+	sk, pk, _ = keys.GenerateKeys(paillierBits)
+
+	x1, _ = secp256k1.GetRandomScalar(q3)
+	x1Enc, _ = cipher.Encrypt(pk, x1.Bytes())
+	q1, _ = secp256k1.ScalarMultiply(x1, secp256k1.G())
+
+	x2, _ = secp256k1.GetRandomScalar()
+	q2, _ := secp256k1.ScalarMultiply(x2, secp256k1.G())
+
+	// Note that x1 * Q2 = x2 * Q1.
+	qShared, _ = secp256k1.ScalarMultiply(x1, q2)
+
+	var wit *adaptor.Witness
+	wit, stmt, _ = adaptor.GenerateHardRelation(secp256k1)
+	pStmt, _ = proofs.GenerateDLKProof(secp256k1, (*elliptic.Point)(stmt), (*big.Int)(wit))
+
+	m.Run()
+}
+
+func TestAdversary(t *testing.T) {
+	t.Parallel()
+
+	checksum := sha256.Sum256([]byte("Hello World"))
+	hash := checksum[:]
+
+	run := func(t *testing.T, s *adversary.Scenario, setup func(limits *lindell17.Limits, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result, h *adversary.Harness)) {
+		t.Helper()
+
+		limits := s.Limits
+		if limits == nil {
+			limits = lindell17.DefaultLimits()
+		}
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 4)
+
+		h := adversary.NewHarness(outCh, resCh)
+		setup(limits, outCh, resCh, h)
+
+		outcome, err := h.Run(s)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := s.Check(outcome); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, s := range adversary.KeygenScenarios(secp256k1) {
+		t.Run("Key Generation - "+s.Name, func(t *testing.T) {
+			t.Parallel()
+
+			run(t, s, func(limits *lindell17.Limits, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result, h *adversary.Harness) {
+				p1Params := kParty1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithLimits(limits)
+				p2Params := kParty2.NewParams(secp256k1, rangeProofBits, nthRootProofBits).WithLimits(limits)
+
+				h.WithParty(lindell17.Party1, kParty1.NewParty1(p1Params, outCh, resCh)).
+					WithParty(lindell17.Party2, kParty2.NewParty2(p2Params, outCh, resCh))
+			})
+		})
+	}
+
+	for _, s := range adversary.SignScenarios(secp256k1, pk) {
+		t.Run("Sign - "+s.Name, func(t *testing.T) {
+			t.Parallel()
+
+			run(t, s, func(limits *lindell17.Limits, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result, h *adversary.Harness) {
+				p1Params := sParty1.NewParams(secp256k1, sk, qShared).WithLimits(limits)
+				p2Params := sParty2.NewParams(secp256k1, pk, x1Enc, x2).WithLimits(limits)
+
+				h.WithParty(lindell17.Party1, sParty1.NewParty1(p1Params, hash, outCh, resCh)).
+					WithParty(lindell17.Party2, sParty2.NewParty2(p2Params, hash, outCh, resCh))
+			})
+		})
+	}
+
+	for _, s := range adversary.AdaptorScenarios(secp256k1, pk) {
+		t.Run("Adaptor - "+s.Name, func(t *testing.T) {
+			t.Parallel()
+
+			run(t, s, func(limits *lindell17.Limits, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result, h *adversary.Harness) {
+				p1Params := aParty1.NewParams(secp256k1, sk, qShared).WithLimits(limits)
+				p2Params := aParty2.NewParams(secp256k1, pk, qShared, x1Enc, x2).WithLimits(limits)
+
+				h.WithParty(lindell17.Party1, aParty1.NewParty1(p1Params, hash, stmt, pStmt, outCh, resCh)).
+					WithParty(lindell17.Party2, aParty2.NewParty2(p2Params, hash, stmt, pStmt, outCh, resCh))
+			})
+		})
+	}
+
+	for _, s := range adversary.DLEncProofScenarios(secp256k1) {
+		t.Run("DLEnc Proof - "+s.Name, func(t *testing.T) {
+			t.Parallel()
+
+			run(t, s, func(limits *lindell17.Limits, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result, h *adversary.Harness) {
+				pParams := prover.NewParams(secp256k1, sk, x1).WithLimits(limits)
+				vParams := verifier.NewParams(secp256k1, q1, pk, x1Enc).WithLimits(limits)
+
+				h.WithParty(lindell17.Prover, prover.NewProver(pParams, outCh, resCh)).
+					WithParty(lindell17.Verifier, verifier.NewVerifier(vParams, outCh, resCh))
+			})
+		})
+	}
+}
@@ -0,0 +1,23 @@
+/*
+Package adversary implements a malicious-party simulation harness that can be
+used for negative testing of the protocols.
+
+The Harness sits between the parties of a protocol run and delivers their
+messages. A declarative Scenario selects a message by its sender and message
+id and describes what the adversary does with it: it can mutate, drop,
+duplicate, reorder or replay the message. The Outcome of a run states which
+party failed with which error, so that a Scenario can assert that the honest
+party detects the attack with the expected sentinel error.
+
+KeygenScenarios, SignScenarios, AdaptorScenarios and DLEncProofScenarios
+return a scenario for every check an adversary can trigger in the parties'
+Process and step functions. Checks that only guard against local failures
+(e.g. the gcd checks on freshly sampled nonces) or that are already enforced
+by an earlier check (e.g. the embedded DLEnc proof parties' message checks)
+can't be triggered by a peer and have no scenario.
+
+Note that the protocols run in lockstep, i.e. a party only sends a message
+after it received the previous one, so a reordered message can only be
+delivered after its successors if the adversary also injects other messages.
+*/
+package adversary
@@ -0,0 +1,19 @@
+package adversary
+
+import "fmt"
+
+var (
+	// ErrStalled is reported if the protocol run can't make progress.
+	ErrStalled = fmt.Errorf("protocol run stalled")
+	// ErrRejected is reported if a party's result rejects the protocol run.
+	ErrRejected = fmt.Errorf("protocol run rejected")
+	// ErrUnknownParty is returned if a message is addressed to an unknown party.
+	ErrUnknownParty = fmt.Errorf("unknown party")
+	// ErrForgeMessage is returned if the adversary can't forge a message.
+	ErrForgeMessage = fmt.Errorf("unable to forge message")
+	// ErrNoReplayMessage is returned if the message to replay wasn't observed.
+	ErrNoReplayMessage = fmt.Errorf("message to replay wasn't observed")
+	// ErrUnexpectedOutcome is returned if a run's outcome doesn't match the
+	// scenario's expectation.
+	ErrUnexpectedOutcome = fmt.Errorf("unexpected outcome")
+)
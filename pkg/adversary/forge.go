@@ -0,0 +1,110 @@
+package adversary
+
+import (
+	"reflect"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// forged is a message whose metadata was forged by the adversary. It's
+// delivered to the recipient of the message it was derived from.
+type forged struct {
+	lindell17.Message
+	protocol  lindell17.Protocol
+	from      lindell17.Entity
+	to        lindell17.Entity
+	messageId int
+}
+
+func (f *forged) Protocol() lindell17.Protocol {
+	return f.protocol
+}
+
+func (f *forged) From() lindell17.Entity {
+	return f.from
+}
+
+func (f *forged) To() lindell17.Entity {
+	return f.to
+}
+
+func (f *forged) MessageId() int {
+	return f.messageId
+}
+
+// forge wraps the message so that its metadata can be forged.
+func forge(msg lindell17.Message) *forged {
+	if f, ok := msg.(*forged); ok {
+		cp := *f
+		return &cp
+	}
+
+	return &forged{
+		Message:   msg,
+		protocol:  msg.Protocol(),
+		from:      msg.From(),
+		to:        msg.To(),
+		messageId: msg.MessageId(),
+	}
+}
+
+// WithProtocol returns a copy of the message that claims to belong to the
+// given protocol.
+func WithProtocol(msg lindell17.Message, protocol lindell17.Protocol) lindell17.Message {
+	f := forge(msg)
+	f.protocol = protocol
+
+	return f
+}
+
+// WithSender returns a copy of the message that claims to be sent by the given
+// entity.
+func WithSender(msg lindell17.Message, from lindell17.Entity) lindell17.Message {
+	f := forge(msg)
+	f.from = from
+
+	return f
+}
+
+// WithRecipient returns a copy of the message that claims to be addressed to
+// the given entity. It's still delivered to the original recipient.
+func WithRecipient(msg lindell17.Message, to lindell17.Entity) lindell17.Message {
+	f := forge(msg)
+	f.to = to
+
+	return f
+}
+
+// WithMessageId returns a copy of the message that claims to have the given
+// message id.
+func WithMessageId(msg lindell17.Message, messageId int) lindell17.Message {
+	f := forge(msg)
+	f.messageId = messageId
+
+	return f
+}
+
+// WithSessionId returns a shallow copy of the message whose session id is
+// replaced with the given one.
+func WithSessionId(msg lindell17.Message, sid string) lindell17.Message {
+	cp := Clone(msg)
+	reflect.ValueOf(cp).Elem().FieldByName("Sid").SetString(sid)
+
+	return cp
+}
+
+// Clone returns a shallow copy of the message so that its fields can be
+// replaced without modifying the original message.
+func Clone(msg lindell17.Message) lindell17.Message {
+	v := reflect.ValueOf(msg)
+	cp := reflect.New(v.Elem().Type())
+	cp.Elem().Set(v.Elem())
+
+	return cp.Interface().(lindell17.Message)
+}
+
+// malformedSessionId returns a copy of the message whose session id doesn't
+// have the expected format.
+func malformedSessionId(msg lindell17.Message) (lindell17.Message, error) {
+	return WithSessionId(msg, msg.SessionId()[1:]), nil
+}
@@ -0,0 +1,266 @@
+package adversary
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// Action is what the adversary does with an intercepted message.
+type Action int
+
+const (
+	// Deliver delivers the message unmodified.
+	Deliver Action = iota
+	// Mutate delivers the message returned by the scenario's mutation.
+	Mutate
+	// Drop doesn't deliver the message.
+	Drop
+	// Duplicate delivers the message twice.
+	Duplicate
+	// Reorder holds the message back and delivers it after the next message
+	// (or once no other message is pending).
+	Reorder
+	// Replay delivers a previously observed message in place of the message.
+	Replay
+)
+
+// Scenario is a declarative description of an attack.
+type Scenario struct {
+	// Name is the scenario's name.
+	Name string
+	// From is the sender of the message the adversary intercepts.
+	From lindell17.Entity
+	// MessageId is the id of the message the adversary intercepts. Only the
+	// first matching message is intercepted.
+	MessageId int
+	// Action is what the adversary does with the intercepted message.
+	Action Action
+	// Mutation returns the message that's delivered in place of the
+	// intercepted one (only used by Mutate).
+	Mutation func(msg lindell17.Message) (lindell17.Message, error)
+	// ReplayId is the id of the previously observed message that's delivered
+	// in place of the intercepted one (only used by Replay).
+	ReplayId int
+	// Limits are the resource limits the honest parties should enforce (nil if
+	// the default limits should be used).
+	Limits *lindell17.Limits
+	// Reject returns true if the result rejects the protocol run (optional,
+	// for protocols which report a failure via their result).
+	Reject func(res lindell17.Result) bool
+	// Victim is the honest party that's expected to detect the attack.
+	Victim lindell17.Entity
+	// Want is the error the victim is expected to report (nil if the protocol
+	// run should succeed).
+	Want error
+}
+
+// Outcome is the outcome of a protocol run.
+type Outcome struct {
+	// Entity is the party that failed (only set if Err is set).
+	Entity lindell17.Entity
+	// Err is the error the party reported (nil if the run succeeded).
+	Err error
+	// Results are the results the parties produced.
+	Results []lindell17.Result
+}
+
+// Check checks that the outcome matches the scenario's expectation.
+// Returns an error if the outcome is unexpected.
+func (s *Scenario) Check(o *Outcome) error {
+	if s.Want == nil {
+		if o.Err != nil {
+			return fmt.Errorf("%w: expected no error, got %v (party %d)", ErrUnexpectedOutcome, o.Err, o.Entity)
+		}
+
+		return nil
+	}
+
+	if o.Err == nil {
+		return fmt.Errorf("%w: expected error %v, got none", ErrUnexpectedOutcome, s.Want)
+	}
+	if o.Entity != s.Victim || !errors.Is(o.Err, s.Want) {
+		return fmt.Errorf("%w: expected error %v (party %d), got %v (party %d)", ErrUnexpectedOutcome, s.Want, s.Victim, o.Err, o.Entity)
+	}
+
+	return nil
+}
+
+// Harness is an instance of an adversary that sits between the parties of a
+// protocol run.
+type Harness struct {
+	order   []lindell17.Entity
+	parties map[lindell17.Entity]lindell17.Participant
+	outCh   <-chan lindell17.Message
+	resCh   <-chan lindell17.Result
+}
+
+// NewHarness creates a new instance of a harness that intercepts the messages
+// and results the parties send via the given channels. The channels need to be
+// buffered so that the parties don't block when sending.
+func NewHarness(outCh <-chan lindell17.Message, resCh <-chan lindell17.Result) *Harness {
+	return &Harness{
+		parties: make(map[lindell17.Entity]lindell17.Participant),
+		outCh:   outCh,
+		resCh:   resCh,
+	}
+}
+
+// WithParty adds a freshly created party to the harness. Parties are started
+// in the order in which they're added.
+func (h *Harness) WithParty(entity lindell17.Entity, party lindell17.Participant) *Harness {
+	h.order = append(h.order, entity)
+	h.parties[entity] = party
+
+	return h
+}
+
+// Run starts the parties and delivers their messages according to the
+// scenario until a party reports an error, every party produced a result or
+// the protocol run stalls.
+// Returns an error if the adversary can't carry out the scenario.
+func (h *Harness) Run(s *Scenario) (*Outcome, error) {
+	outcome := new(Outcome)
+
+	var queue, held, seen []lindell17.Message
+	var intercepted bool
+	var dropped *lindell17.Entity
+
+	// collect moves sent messages to the queue and checks the results.
+	collect := func() bool {
+		for {
+			select {
+			case msg := <-h.outCh:
+				queue = append(queue, msg)
+			case res := <-h.resCh:
+				outcome.Results = append(outcome.Results, res)
+				if s.Reject != nil && s.Reject(res) {
+					outcome.Entity = res.From()
+					outcome.Err = ErrRejected
+
+					return false
+				}
+			default:
+				return true
+			}
+		}
+	}
+
+	for _, entity := range h.order {
+		if _, err := h.parties[entity].Start(); err != nil {
+			outcome.Entity = entity
+			outcome.Err = err
+
+			return outcome, nil
+		}
+		if !collect() {
+			return outcome, nil
+		}
+	}
+
+	for {
+		if len(outcome.Results) == len(h.parties) {
+			return outcome, nil
+		}
+
+		var msg lindell17.Message
+		switch {
+		case len(queue) > 0:
+			msg, queue = queue[0], queue[1:]
+		case len(held) > 0:
+			msg, held = held[0], held[1:]
+		default:
+			outcome.Entity = h.stalled(outcome, dropped)
+			outcome.Err = ErrStalled
+
+			return outcome, nil
+		}
+
+		deliveries := []lindell17.Message{msg}
+
+		if !intercepted && msg.From() == s.From && msg.MessageId() == s.MessageId {
+			intercepted = true
+
+			switch s.Action {
+			case Mutate:
+				forged, err := s.Mutation(msg)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrForgeMessage, err)
+				}
+				deliveries = []lindell17.Message{forged}
+			case Drop:
+				to := msg.To()
+				dropped = &to
+				deliveries = nil
+			case Duplicate:
+				deliveries = []lindell17.Message{msg, msg}
+			case Reorder:
+				held = append(held, msg)
+				deliveries = nil
+			case Replay:
+				var replayed lindell17.Message
+				for _, m := range seen {
+					if m.MessageId() == s.ReplayId {
+						replayed = m
+						break
+					}
+				}
+				if replayed == nil {
+					return nil, ErrNoReplayMessage
+				}
+				deliveries = []lindell17.Message{replayed}
+			}
+		}
+
+		for _, d := range deliveries {
+			to := recipient(d)
+			party, ok := h.parties[to]
+			if !ok {
+				return nil, fmt.Errorf("%w: %d", ErrUnknownParty, to)
+			}
+
+			seen = append(seen, d)
+
+			if _, err := party.Process(d); err != nil {
+				outcome.Entity = to
+				outcome.Err = err
+
+				return outcome, nil
+			}
+			if !collect() {
+				return outcome, nil
+			}
+		}
+	}
+}
+
+// stalled returns the party that waits for a message, which is the recipient
+// of the dropped message or otherwise the first party without a result.
+func (h *Harness) stalled(o *Outcome, dropped *lindell17.Entity) lindell17.Entity {
+	if dropped != nil {
+		return *dropped
+	}
+
+	done := make(map[lindell17.Entity]bool)
+	for _, res := range o.Results {
+		done[res.From()] = true
+	}
+	for _, entity := range h.order {
+		if !done[entity] {
+			return entity
+		}
+	}
+
+	return h.order[0]
+}
+
+// recipient returns the party the message is delivered to. Forged messages are
+// delivered to the recipient of the message they were derived from.
+func recipient(msg lindell17.Message) lindell17.Entity {
+	if f, ok := msg.(*forged); ok {
+		return recipient(f.Message)
+	}
+
+	return msg.To()
+}
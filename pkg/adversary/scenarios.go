@@ -0,0 +1,626 @@
+package adversary
+
+import (
+	"math/big"
+
+	"github.com/primefactor-io/commitment/pkg/hash"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	aMessages "github.com/primefactor-io/lindell17/pkg/adaptor/messages"
+	aParty1 "github.com/primefactor-io/lindell17/pkg/adaptor/party1"
+	aParty2 "github.com/primefactor-io/lindell17/pkg/adaptor/party2"
+	dMessages "github.com/primefactor-io/lindell17/pkg/dlenc_proof/messages"
+	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/prover"
+	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/verifier"
+	kMessages "github.com/primefactor-io/lindell17/pkg/keygen/messages"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	sMessages "github.com/primefactor-io/lindell17/pkg/sign/messages"
+	sParty1 "github.com/primefactor-io/lindell17/pkg/sign/party1"
+	sParty2 "github.com/primefactor-io/lindell17/pkg/sign/party2"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// forgedPaillierBits is the bit length of the Paillier modulus the adversary
+// generates when it replaces the honest party's Paillier public key.
+const forgedPaillierBits = 1024
+
+// KeygenScenarios returns the scenarios for every check of the key generation
+// protocol an adversary can trigger.
+func KeygenScenarios(curve weierstrass.Curve) []*Scenario {
+	var scenarios []*Scenario
+
+	scenarios = append(scenarios, processScenarios(lindell17.Keygen, lindell17.Party1, lindell17.Party2, 1, 3)...)
+	scenarios = append(scenarios, processScenarios(lindell17.Keygen, lindell17.Party2, lindell17.Party1, 2, 4)...)
+
+	scenarios = append(scenarios,
+		&Scenario{
+			Name:      "Invalid Q1 commitment",
+			From:      lindell17.Party1,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*kMessages.Message3)
+				m.Q1 = curve.G()
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   kParty2.ErrInvalidQ1Commitment,
+		},
+		&Scenario{
+			Name:      "Invalid Q1 DLK proof",
+			From:      lindell17.Party1,
+			MessageId: 1,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				proof, err := forgedDLKProof(curve)
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*kMessages.Message1)
+				m.PQ1 = proof
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   kParty2.ErrInvalidQ1DLKProof,
+		},
+		&Scenario{
+			Name:      "Paillier modulus too large",
+			From:      lindell17.Party1,
+			MessageId: 3,
+			Action:    Deliver,
+			Limits:    lindell17.NewLimits(lindell17.DefaultMaxMessages, forgedPaillierBits/2),
+			Victim:    lindell17.Party2,
+			Want:      lindell17.ErrPaillierModulusTooLarge,
+		},
+		&Scenario{
+			Name:      "Invalid Nth root proof",
+			From:      lindell17.Party1,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				_, pk, err := keys.GenerateKeys(forgedPaillierBits)
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*kMessages.Message3)
+				m.Pk = pk
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   kParty2.ErrInvalidNthRootProof,
+		},
+		&Scenario{
+			Name:      "x1 encryption not a unit",
+			From:      lindell17.Party1,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*kMessages.Message3)
+				m.X1Enc = m.Pk.N.Bytes()
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   lindell17.ErrCiphertextNotUnit,
+		},
+		&Scenario{
+			Name:      "Invalid range proof",
+			From:      lindell17.Party1,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*kMessages.Message3)
+				x1Enc, err := cipher.Encrypt(m.Pk, curve.N().Bytes())
+				if err != nil {
+					return nil, err
+				}
+				m.X1Enc = x1Enc
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   kParty2.ErrInvalidRangeProof,
+		},
+		&Scenario{
+			Name:      "Invalid DLEnc proof (Q^ commitment)",
+			From:      lindell17.Party1,
+			MessageId: 5,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				cQHat, err := hash.Commit(curve.G().X.Bytes(), curve.G().Y.Bytes())
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*kMessages.Message5)
+				m.CQHat = cQHat
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   kParty2.ErrInvalidDLEncProof,
+		},
+		&Scenario{
+			Name:      "Invalid DLEnc proof (Q^)",
+			From:      lindell17.Party1,
+			MessageId: 7,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*kMessages.Message7)
+				m.QHat = curve.G()
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   kParty2.ErrInvalidDLEncProof,
+		},
+		&Scenario{
+			Name:      "Invalid Q2 DLK proof",
+			From:      lindell17.Party2,
+			MessageId: 2,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				proof, err := forgedDLKProof(curve)
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*kMessages.Message2)
+				m.PQ2 = proof
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   kParty1.ErrInvalidQ2DLKProof,
+		},
+		&Scenario{
+			Name:      "Invalid DLEnc proof (ciphertext)",
+			From:      lindell17.Party2,
+			MessageId: 4,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				// The ciphertext 1 is an encryption of 0.
+				m := Clone(msg).(*kMessages.Message4)
+				m.Ciphertext = []byte{1}
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   kParty1.ErrInvalidDLEncProof,
+		},
+		&Scenario{
+			Name:      "Invalid DLEnc proof (a and b)",
+			From:      lindell17.Party2,
+			MessageId: 6,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*kMessages.Message6)
+				m.A = new(big.Int).Add(m.A, big.NewInt(1))
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   kParty1.ErrInvalidDLEncProof,
+		},
+	)
+
+	return scenarios
+}
+
+// SignScenarios returns the scenarios for every check of the signing protocol
+// an adversary can trigger. The Paillier public key is party 1's key.
+func SignScenarios(curve weierstrass.Curve, pk *keys.PublicKey) []*Scenario {
+	var scenarios []*Scenario
+
+	scenarios = append(scenarios, processScenarios(lindell17.Sign, lindell17.Party1, lindell17.Party2, 1, 3)...)
+	scenarios = append(scenarios, processScenarios(lindell17.Sign, lindell17.Party2, lindell17.Party1, 2, 4)...)
+
+	scenarios = append(scenarios,
+		&Scenario{
+			Name:      "Invalid R1 commitment",
+			From:      lindell17.Party1,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*sMessages.Message3)
+				m.R1 = curve.G()
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   sParty2.ErrInvalidR1Commitment,
+		},
+		&Scenario{
+			Name:      "Invalid R1 DLK proof",
+			From:      lindell17.Party1,
+			MessageId: 1,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				proof, err := forgedDLKProof(curve)
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*sMessages.Message1)
+				m.PR1 = proof
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   sParty2.ErrInvalidR1DLKProof,
+		},
+		&Scenario{
+			Name:      "Invalid R2 DLK proof",
+			From:      lindell17.Party2,
+			MessageId: 2,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				proof, err := forgedDLKProof(curve)
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*sMessages.Message2)
+				m.PR2 = proof
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   sParty1.ErrInvalidR2DLKProof,
+		},
+		&Scenario{
+			Name:      "Invalid r",
+			From:      lindell17.Party2,
+			MessageId: 4,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*sMessages.Message4)
+				m.R = new(big.Int).Mod(new(big.Int).Add(m.R, big.NewInt(1)), curve.N())
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   sParty1.ErrInvalidR,
+		},
+		&Scenario{
+			Name:      "Invalid partial signature",
+			From:      lindell17.Party2,
+			MessageId: 4,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				ciphertext, err := cipher.Encrypt(pk, big.NewInt(1).Bytes())
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*sMessages.Message4)
+				m.Ciphertext = ciphertext
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   sParty1.ErrInvalidSignature,
+		},
+	)
+
+	return scenarios
+}
+
+// AdaptorScenarios returns the scenarios for every check of the adaptor
+// signature protocol an adversary can trigger. The Paillier public key is
+// party 1's key.
+func AdaptorScenarios(curve weierstrass.Curve, pk *keys.PublicKey) []*Scenario {
+	var scenarios []*Scenario
+
+	scenarios = append(scenarios, processScenarios(lindell17.Adaptor, lindell17.Party2, lindell17.Party1, 1, 3)...)
+	scenarios = append(scenarios, processScenarios(lindell17.Adaptor, lindell17.Party1, lindell17.Party2, 2, 4)...)
+
+	scenarios = append(scenarios,
+		&Scenario{
+			Name:      "Invalid R2 commitment",
+			From:      lindell17.Party2,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*aMessages.Message3)
+				m.R2 = curve.G()
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   aParty1.ErrInvalidR2Commitment,
+		},
+		&Scenario{
+			Name:      "Invalid R2 DLK proof",
+			From:      lindell17.Party2,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				proof, err := forgedDLKProof(curve)
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*aMessages.Message3)
+				m.PR2 = proof
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   aParty1.ErrInvalidR2DLKProof,
+		},
+		&Scenario{
+			Name:      "Invalid R2' commitment",
+			From:      lindell17.Party2,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*aMessages.Message3)
+				m.R2Prime = curve.G()
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   aParty1.ErrInvalidR2PrimeCommitment,
+		},
+		&Scenario{
+			Name:      "Invalid k2 DLEq proof",
+			From:      lindell17.Party2,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				proof, err := forgedDLEqProof(curve)
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*aMessages.Message3)
+				m.PK2DLEq = proof
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   aParty1.ErrInvalidDLEqProof,
+		},
+		&Scenario{
+			Name:      "Invalid partial pre-signature",
+			From:      lindell17.Party2,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				ciphertext, err := cipher.Encrypt(pk, big.NewInt(1).Bytes())
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*aMessages.Message3)
+				m.Ciphertext = ciphertext
+				return m, nil
+			},
+			Victim: lindell17.Party1,
+			Want:   aParty1.ErrInvalidResult,
+		},
+		&Scenario{
+			Name:      "Invalid R1 DLK proof",
+			From:      lindell17.Party1,
+			MessageId: 2,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				proof, err := forgedDLKProof(curve)
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*aMessages.Message2)
+				m.PR1 = proof
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   aParty2.ErrInvalidR1DLKProof,
+		},
+		&Scenario{
+			Name:      "Invalid k1 DLEq proof",
+			From:      lindell17.Party1,
+			MessageId: 2,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				proof, err := forgedDLEqProof(curve)
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*aMessages.Message2)
+				m.PK1DLEq = proof
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   aParty2.ErrInvalidDLEqProof,
+		},
+		&Scenario{
+			Name:      "Invalid pre-signature",
+			From:      lindell17.Party1,
+			MessageId: 4,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*aMessages.Message4)
+				preSig := *m.PreSig
+				preSig.S = new(big.Int).Mod(new(big.Int).Add(preSig.S, big.NewInt(1)), curve.N())
+				m.PreSig = &preSig
+				return m, nil
+			},
+			Victim: lindell17.Party2,
+			Want:   aParty2.ErrInvalidResult,
+		},
+	)
+
+	return scenarios
+}
+
+// DLEncProofScenarios returns the scenarios for every check of the DLEnc
+// proof protocol an adversary can trigger. The prover and the verifier report
+// an invalid proof via their results, so these scenarios expect ErrRejected.
+func DLEncProofScenarios(curve weierstrass.Curve) []*Scenario {
+	var scenarios []*Scenario
+
+	scenarios = append(scenarios, processScenarios(lindell17.DLEncProof, lindell17.Verifier, lindell17.Prover, 1, 3)...)
+	scenarios = append(scenarios, processScenarios(lindell17.DLEncProof, lindell17.Prover, lindell17.Verifier, 2, 4)...)
+
+	scenarios = append(scenarios,
+		&Scenario{
+			Name:      "Invalid Q^ commitment",
+			From:      lindell17.Prover,
+			MessageId: 2,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				cQHat, err := hash.Commit(curve.G().X.Bytes(), curve.G().Y.Bytes())
+				if err != nil {
+					return nil, err
+				}
+				m := Clone(msg).(*dMessages.Message2)
+				m.CQHat = cQHat
+				return m, nil
+			},
+			Reject: rejectDLEncProof,
+			Victim: lindell17.Verifier,
+			Want:   ErrRejected,
+		},
+		&Scenario{
+			Name:      "Invalid Q^",
+			From:      lindell17.Prover,
+			MessageId: 4,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*dMessages.Message4)
+				m.QHat = curve.G()
+				return m, nil
+			},
+			Reject: rejectDLEncProof,
+			Victim: lindell17.Verifier,
+			Want:   ErrRejected,
+		},
+		&Scenario{
+			Name:      "Invalid a and b",
+			From:      lindell17.Verifier,
+			MessageId: 3,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				m := Clone(msg).(*dMessages.Message3)
+				m.B = new(big.Int).Add(m.B, big.NewInt(1))
+				return m, nil
+			},
+			Reject: rejectDLEncProof,
+			Victim: lindell17.Prover,
+			Want:   ErrRejected,
+		},
+	)
+
+	return scenarios
+}
+
+// processScenarios returns the scenarios for the checks every party runs in
+// Process as well as the scenarios for the party's state checks. The victim
+// receives the messages with the ids first and later from the peer.
+func processScenarios(protocol lindell17.Protocol, peer, victim lindell17.Entity, first, later int) []*Scenario {
+	other := lindell17.Protocol((int(protocol) + 1) % 4)
+
+	return []*Scenario{
+		{
+			Name:      "Wrong protocol",
+			From:      peer,
+			MessageId: first,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				return WithProtocol(msg, other), nil
+			},
+			Victim: victim,
+			Want:   lindell17.ErrWrongProtocol,
+		},
+		{
+			Name:      "Wrong sender",
+			From:      peer,
+			MessageId: first,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				return WithSender(msg, victim), nil
+			},
+			Victim: victim,
+			Want:   lindell17.ErrWrongSender,
+		},
+		{
+			Name:      "Wrong recipient",
+			From:      peer,
+			MessageId: first,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				return WithRecipient(msg, peer), nil
+			},
+			Victim: victim,
+			Want:   lindell17.ErrWrongRecipient,
+		},
+		{
+			Name:      "Unknown message",
+			From:      peer,
+			MessageId: first,
+			Action:    Mutate,
+			Mutation: func(msg lindell17.Message) (lindell17.Message, error) {
+				return WithMessageId(msg, 0), nil
+			},
+			Victim: victim,
+			Want:   lindell17.ErrUnknownMessage,
+		},
+		{
+			Name:      "Malformed session id",
+			From:      peer,
+			MessageId: first,
+			Action:    Mutate,
+			Mutation:  malformedSessionId,
+			Victim:    victim,
+			Want:      lindell17.ErrInvalidMessage,
+		},
+		{
+			Name:      "Too many messages",
+			From:      peer,
+			MessageId: first,
+			Action:    Duplicate,
+			Limits:    lindell17.NewLimits(1, lindell17.DefaultMaxPaillierBits),
+			Victim:    victim,
+			Want:      lindell17.ErrTooManyMessages,
+		},
+		{
+			Name:      "Duplicated message",
+			From:      peer,
+			MessageId: first,
+			Action:    Duplicate,
+			Victim:    victim,
+			Want:      lindell17.ErrInvalidState,
+		},
+		{
+			Name:      "Replayed message",
+			From:      peer,
+			MessageId: later,
+			Action:    Replay,
+			ReplayId:  first,
+			Victim:    victim,
+			Want:      lindell17.ErrInvalidState,
+		},
+		{
+			Name:      "Dropped message",
+			From:      peer,
+			MessageId: first,
+			Action:    Drop,
+			Victim:    victim,
+			Want:      ErrStalled,
+		},
+		{
+			Name:      "Reordered message",
+			From:      peer,
+			MessageId: first,
+			Action:    Reorder,
+			Victim:    victim,
+		},
+	}
+}
+
+// forgedDLKProof returns a valid DLK proof for the generator which doesn't
+// match any point of the protocol run.
+func forgedDLKProof(curve weierstrass.Curve) (*proofs.DLKProof, error) {
+	return proofs.GenerateDLKProof(curve, curve.G(), big.NewInt(1))
+}
+
+// forgedDLEqProof returns a valid DLEq proof for the generator which doesn't
+// match any points of the protocol run.
+func forgedDLEqProof(curve weierstrass.Curve) (*proofs.DLEqProof, error) {
+	g := curve.G()
+
+	return proofs.GenerateDLEqProof(curve, g, g, g, g, big.NewInt(1))
+}
+
+// rejectDLEncProof returns true if the DLEnc proof prover's or verifier's
+// result rejects the proof.
+func rejectDLEncProof(res lindell17.Result) bool {
+	switch res := res.(type) {
+	case *prover.Result:
+		return !res.IsValid
+	case *verifier.Result:
+		return !res.IsValid
+	default:
+		return false
+	}
+}
@@ -0,0 +1,176 @@
+package approval
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// domain separates approval digests from other signed data.
+const domain = "lindell17/approval/v1"
+
+// Approver is an instance of a person who approves signing requests with
+// their own key.
+type Approver struct {
+	// ID identifies the approver.
+	ID string
+	// PublicKey is the approver's ed25519.PublicKey or *ecdsa.PublicKey.
+	PublicKey crypto.PublicKey
+}
+
+// NewApprover creates a new instance of an approver.
+// Returns an error if the id is empty or the key type isn't supported.
+func NewApprover(id string, pk crypto.PublicKey) (*Approver, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: empty id", ErrInvalidApprover)
+	}
+
+	switch pk.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey:
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvalidApprover, pk)
+	}
+
+	return &Approver{
+		ID:        id,
+		PublicKey: pk,
+	}, nil
+}
+
+// Quorum is an instance of a set of approvers of which a threshold needs to
+// approve a request.
+type Quorum struct {
+	threshold int
+	approvers map[string]*Approver
+}
+
+// NewQuorum creates a new instance of a quorum that requires threshold
+// approvals of the given approvers.
+// Returns an error if the threshold is out of range or an id is used twice.
+func NewQuorum(threshold int, approvers ...*Approver) (*Quorum, error) {
+	if threshold < 1 || threshold > len(approvers) {
+		return nil, fmt.Errorf("%w: %d of %d", ErrInvalidThreshold, threshold, len(approvers))
+	}
+
+	q := &Quorum{
+		threshold: threshold,
+		approvers: make(map[string]*Approver),
+	}
+	for _, approver := range approvers {
+		if _, ok := q.approvers[approver.ID]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateApprover, approver.ID)
+		}
+		q.approvers[approver.ID] = approver
+	}
+
+	return q, nil
+}
+
+// Threshold returns the number of approvals the quorum requires.
+func (q *Quorum) Threshold() int {
+	return q.threshold
+}
+
+// Verify checks the approver's signature of the digest.
+// Returns an error if the approver isn't part of the quorum or the signature
+// is invalid.
+func (q *Quorum) Verify(approverID string, digest, signature []byte) error {
+	approver, ok := q.approvers[approverID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownApprover, approverID)
+	}
+
+	var isValid bool
+	switch pk := approver.PublicKey.(type) {
+	case ed25519.PublicKey:
+		isValid = ed25519.Verify(pk, digest, signature)
+	case *ecdsa.PublicKey:
+		isValid = ecdsa.VerifyASN1(pk, digest, signature)
+	}
+	if !isValid {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, approverID)
+	}
+
+	return nil
+}
+
+// Count returns the number of valid approvals of the digest. Approvals of
+// unknown approvers and invalid signatures aren't counted.
+func (q *Quorum) Count(digest []byte, approvals map[string][]byte) int {
+	count := 0
+	for approverID, signature := range approvals {
+		if q.Verify(approverID, digest, signature) == nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Approved checks if the threshold of valid approvals of the digest is met.
+func (q *Quorum) Approved(digest []byte, approvals map[string][]byte) bool {
+	return q.Count(digest, approvals) >= q.threshold
+}
+
+// Digest computes the digest approvers sign to approve the request in the
+// given session.
+func Digest(sessionID string, req *lindell17.SigningRequest) []byte {
+	h := sha256.New()
+
+	write(h, []byte(domain))
+	write(h, []byte(sessionID))
+	write(h, []byte{byte(req.Protocol)})
+	write(h, []byte(req.KeyID))
+	write(h, req.Hash)
+
+	if req.Payload == nil {
+		write(h, []byte{0})
+	} else {
+		write(h, []byte{1})
+		write(h, []byte(req.Payload.Destination))
+		if req.Payload.Amount == nil {
+			write(h, nil)
+		} else {
+			write(h, []byte(req.Payload.Amount.String()))
+		}
+		write(h, req.Payload.Data)
+	}
+
+	if req.Statement == nil {
+		write(h, []byte{0})
+	} else {
+		write(h, []byte{1})
+		write(h, req.Statement.X.Bytes())
+		write(h, req.Statement.Y.Bytes())
+	}
+
+	return h.Sum(nil)
+}
+
+// Sign signs the digest with the approver's Ed25519 or ECDSA private key.
+// Returns an error if the key type isn't supported or signing fails.
+func Sign(signer crypto.Signer, digest []byte) ([]byte, error) {
+	switch signer.Public().(type) {
+	case ed25519.PublicKey:
+		return signer.Sign(rand.Reader, digest, crypto.Hash(0))
+	case *ecdsa.PublicKey:
+		return signer.Sign(rand.Reader, digest, crypto.SHA256)
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvalidApprover, signer.Public())
+	}
+}
+
+// write writes the length-prefixed data to the hash.
+func write(h hash.Hash, data []byte) {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	h.Write(length[:])
+	h.Write(data)
+}
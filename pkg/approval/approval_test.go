@@ -0,0 +1,165 @@
+package approval_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/lindell17/pkg/approval"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+func TestApproval(t *testing.T) {
+	t.Parallel()
+
+	edPk, edSk, _ := ed25519.GenerateKey(rand.Reader)
+	ecSk, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherPk, otherSk, _ := ed25519.GenerateKey(rand.Reader)
+
+	carol, _ := approval.NewApprover("carol", edPk)
+	dave, _ := approval.NewApprover("dave", &ecSk.PublicKey)
+	erin, _ := approval.NewApprover("erin", otherPk)
+
+	checksum := sha256.Sum256([]byte("Hello World"))
+	payload := &lindell17.Payload{Destination: "bc1qdest", Amount: big.NewInt(1000)}
+	req := lindell17.NewSigningRequest(lindell17.Sign, "key", checksum[:], payload)
+	digest := approval.Digest("session", req)
+
+	t.Run("Quorum (threshold)", func(t *testing.T) {
+		t.Parallel()
+
+		quorum, err := approval.NewQuorum(2, carol, dave, erin)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		edSig, err := approval.Sign(edSk, digest)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		ecSig, err := approval.Sign(ecSk, digest)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if err := quorum.Verify("carol", digest, edSig); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		if err := quorum.Verify("dave", digest, ecSig); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+
+		if quorum.Approved(digest, map[string][]byte{"carol": edSig}) {
+			t.Error("expected a single approval not to meet the threshold")
+		}
+		if !quorum.Approved(digest, map[string][]byte{"carol": edSig, "dave": ecSig}) {
+			t.Error("expected two approvals to meet the threshold")
+		}
+
+		// Invalid approvals aren't counted.
+		approvals := map[string][]byte{"carol": edSig, "erin": edSig, "mallory": ecSig}
+		if count := quorum.Count(digest, approvals); count != 1 {
+			t.Errorf("want count %d, got %d", 1, count)
+		}
+	})
+
+	t.Run("Digest (binding)", func(t *testing.T) {
+		t.Parallel()
+
+		statement := adaptor.NewStatement(curves.Secp256k1.G())
+
+		other := func(modify func(*lindell17.SigningRequest) string) []byte {
+			clone := *req
+			amount := new(big.Int).Set(payload.Amount)
+			clone.Payload = &lindell17.Payload{Destination: payload.Destination, Amount: amount}
+			return approval.Digest(modify(&clone), &clone)
+		}
+
+		digests := [][]byte{
+			other(func(r *lindell17.SigningRequest) string { return "other session" }),
+			other(func(r *lindell17.SigningRequest) string { r.Protocol = lindell17.Adaptor; return "session" }),
+			other(func(r *lindell17.SigningRequest) string { r.KeyID = "other key"; return "session" }),
+			other(func(r *lindell17.SigningRequest) string { r.Hash = make([]byte, 32); return "session" }),
+			other(func(r *lindell17.SigningRequest) string { r.Payload.Destination = "bc1qother"; return "session" }),
+			other(func(r *lindell17.SigningRequest) string { r.Payload.Amount.SetInt64(1001); return "session" }),
+			other(func(r *lindell17.SigningRequest) string { r.Payload = nil; return "session" }),
+			other(func(r *lindell17.SigningRequest) string { r.Statement = statement; return "session" }),
+		}
+		for i, d := range digests {
+			if string(d) == string(digest) {
+				t.Errorf("expected digest %d to differ", i)
+			}
+		}
+
+		if string(other(func(*lindell17.SigningRequest) string { return "session" })) != string(digest) {
+			t.Error("expected digest of the same request to match")
+		}
+
+		// The statement of an adaptor signature request is bound as well.
+		adaptorReq := lindell17.NewSigningRequest(lindell17.Adaptor, "key", checksum[:], nil).WithStatement(statement)
+		doubled, err := curves.Secp256k1.Double(curves.Secp256k1.G())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		otherStatement := adaptor.NewStatement(doubled)
+		otherReq := lindell17.NewSigningRequest(lindell17.Adaptor, "key", checksum[:], nil).WithStatement(otherStatement)
+		if string(approval.Digest("session", adaptorReq)) == string(approval.Digest("session", otherReq)) {
+			t.Error("expected digests of different statements to differ")
+		}
+	})
+
+	t.Run("Quorum - Invalid (threshold)", func(t *testing.T) {
+		t.Parallel()
+
+		for _, threshold := range []int{0, 3} {
+			if _, err := approval.NewQuorum(threshold, carol, dave); !errors.Is(err, approval.ErrInvalidThreshold) {
+				t.Errorf("want error %v, got %v", approval.ErrInvalidThreshold, err)
+			}
+		}
+	})
+
+	t.Run("Quorum - Invalid (duplicate approver)", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := approval.NewQuorum(1, carol, carol); !errors.Is(err, approval.ErrDuplicateApprover) {
+			t.Errorf("want error %v, got %v", approval.ErrDuplicateApprover, err)
+		}
+	})
+
+	t.Run("Approver - Invalid (key type)", func(t *testing.T) {
+		t.Parallel()
+
+		rsaSk, _ := rsa.GenerateKey(rand.Reader, 1024)
+		if _, err := approval.NewApprover("frank", &rsaSk.PublicKey); !errors.Is(err, approval.ErrInvalidApprover) {
+			t.Errorf("want error %v, got %v", approval.ErrInvalidApprover, err)
+		}
+		if _, err := approval.NewApprover("", edPk); !errors.Is(err, approval.ErrInvalidApprover) {
+			t.Errorf("want error %v, got %v", approval.ErrInvalidApprover, err)
+		}
+		if _, err := approval.Sign(rsaSk, digest); !errors.Is(err, approval.ErrInvalidApprover) {
+			t.Errorf("want error %v, got %v", approval.ErrInvalidApprover, err)
+		}
+	})
+
+	t.Run("Verify - Invalid (signature)", func(t *testing.T) {
+		t.Parallel()
+
+		quorum, _ := approval.NewQuorum(1, carol, dave)
+		sig, _ := approval.Sign(otherSk, digest)
+
+		if err := quorum.Verify("carol", digest, sig); !errors.Is(err, approval.ErrInvalidSignature) {
+			t.Errorf("want error %v, got %v", approval.ErrInvalidSignature, err)
+		}
+		if err := quorum.Verify("erin", digest, sig); !errors.Is(err, approval.ErrUnknownApprover) {
+			t.Errorf("want error %v, got %v", approval.ErrUnknownApprover, err)
+		}
+	})
+}
@@ -0,0 +1,16 @@
+/*
+Package approval implements quorums of human approvers that need to approve a
+signing request before party 2 contributes to the signature.
+
+A Quorum consists of M approvers, each identified by an id and an Ed25519 or
+ECDSA public key, and a threshold N. An approver approves a request by signing
+its digest (see Digest and Sign). The digest binds the approval to the signing
+session, the protocol, the key, the hash, the payload and the statement of an
+adaptor signature, so that an approval can't be replayed for another session
+or request. Approvers should compute the digest from the request they reviewed
+rather than trusting a digest they are given.
+
+The co-signer (see package cosigner) holds sessions that require approval
+until N valid approvals arrived or the session expired.
+*/
+package approval
@@ -0,0 +1,16 @@
+package approval
+
+import "fmt"
+
+var (
+	// ErrInvalidThreshold is returned if the threshold isn't in the range [1, M].
+	ErrInvalidThreshold = fmt.Errorf("invalid threshold")
+	// ErrInvalidApprover is returned if an approver's id is empty or its key type isn't supported.
+	ErrInvalidApprover = fmt.Errorf("invalid approver")
+	// ErrDuplicateApprover is returned if two approvers have the same id.
+	ErrDuplicateApprover = fmt.Errorf("duplicate approver")
+	// ErrUnknownApprover is returned if an approval was made by an approver that isn't part of the quorum.
+	ErrUnknownApprover = fmt.Errorf("unknown approver")
+	// ErrInvalidSignature is returned if an approval's signature is invalid.
+	ErrInvalidSignature = fmt.Errorf("invalid approval signature")
+)
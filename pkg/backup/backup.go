@@ -0,0 +1,153 @@
+package backup
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/utils"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+)
+
+// maskBits is the number of bits the proof's masks are longer than the order,
+// which is the statistical security with which alpha + x hides x.
+const maskBits = 80
+
+// Backup is an instance of a party's backup of its share.
+type Backup struct {
+	// Party is the party whose share is backed up.
+	Party lindell17.Entity
+	// Share is the public share (Q1 = x1 * G or Q2 = x2 * G).
+	Share *elliptic.Point
+	// Q is the shared public key the share belongs to.
+	Q *elliptic.Point
+	// Ciphertext is the share encrypted to the recovery key.
+	Ciphertext cipher.Ciphertext
+	// Proof proves that the ciphertext decrypts to the discrete logarithm of
+	// the public share.
+	Proof *Proof
+}
+
+// Create creates the backup of the party's share x of the shared public key q
+// by encrypting it to the recovery key.
+// Returns an error if the party, the recovery key, the number of rounds or the
+// share is invalid or the backup can't be created.
+func Create(params *Params, party lindell17.Entity, x *big.Int, q *elliptic.Point) (*Backup, error) {
+	curve := params.curve
+
+	if party != lindell17.Party1 && party != lindell17.Party2 {
+		return nil, ErrInvalidParty
+	}
+	if err := checkRecoveryKey(params); err != nil {
+		return nil, err
+	}
+	if params.rounds < MinRounds {
+		return nil, ErrInvalidRounds
+	}
+	if err := lindell17.CheckScalar(x, big.NewInt(1), curve.N()); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidShare, err)
+	}
+	if err := lindell17.CheckPoint(curve, q); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMismatchedKey, err)
+	}
+
+	// Compute the public share.
+	share, err := curve.ScalarMultiply(x, curve.G()) // x * G
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidShare, err)
+	}
+
+	// Encrypt the share.
+	ciphertext, nonce, err := random.Encrypt(params.rand, params.pk, x.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrEncryptShare, err)
+	}
+	defer utils.Wipe(nonce)
+
+	b := &Backup{
+		Party:      party,
+		Share:      share,
+		Q:          q,
+		Ciphertext: ciphertext,
+	}
+
+	// Prove that the ciphertext decrypts to the discrete logarithm of the share.
+	proof, err := prove(params, b, x, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGenerateProof, err)
+	}
+	b.Proof = proof
+
+	return b, nil
+}
+
+// Verify verifies that the backup is well-formed and that its ciphertext
+// decrypts to the discrete logarithm of its public share.
+// Returns an error if the number of rounds, the backup or its proof is invalid.
+func (b *Backup) Verify(params *Params) error {
+	curve := params.curve
+
+	if err := checkRecoveryKey(params); err != nil {
+		return err
+	}
+	if params.rounds < MinRounds {
+		return ErrInvalidRounds
+	}
+	if b.Party != lindell17.Party1 && b.Party != lindell17.Party2 {
+		return ErrInvalidParty
+	}
+	if err := lindell17.CheckPoint(curve, b.Share); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+	if err := lindell17.CheckPoint(curve, b.Q); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+	if err := lindell17.CheckCiphertext(params.pk, b.Ciphertext); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+
+	return verify(params, b)
+}
+
+// VerifyPeer verifies the backup of the other party (see Verify) and that it
+// belongs to the shared public key q, i.e. that x * Share = Q where x is the
+// verifying party's own share.
+// Returns an error if the backup is invalid or belongs to another key.
+func (b *Backup) VerifyPeer(params *Params, x *big.Int, q *elliptic.Point) error {
+	if err := b.Verify(params); err != nil {
+		return err
+	}
+
+	if !b.Q.Equal(q) {
+		return ErrMismatchedKey
+	}
+
+	// x2 * Q1 = x1 * Q2 = Q.
+	result, err := params.curve.ScalarMultiply(x, b.Share)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMismatchedKey, err)
+	}
+	if !result.Equal(q) {
+		return ErrMismatchedKey
+	}
+
+	return nil
+}
+
+// checkRecoveryKey checks that the recovery key is well-formed and large
+// enough to encrypt the proof's values, which have to be less than N / 2 to be
+// decrypted as signed values.
+// Returns an error if the recovery key is invalid.
+func checkRecoveryKey(params *Params) error {
+	if err := lindell17.CheckPaillierKey(params.pk); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRecoveryKey, err)
+	}
+
+	if params.pk.N.BitLen() <= params.curve.N().BitLen()+maskBits+2 {
+		return ErrInvalidRecoveryKey
+	}
+
+	return nil
+}
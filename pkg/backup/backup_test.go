@@ -0,0 +1,266 @@
+package backup_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/lindell17/pkg/backup"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+const paillierBits = 1024
+
+var secp256k1 = curves.Secp256k1
+
+var sk *keys.PrivateKey
+var params *backup.Params
+
+var x1, x2 *big.Int
+var q *elliptic.Point
+
+func TestMain(m *testing.M) {
+	var pk *keys.PublicKey
+	sk, pk, _ = keys.GenerateKeys(paillierBits)
+	params = backup.NewParams(secp256k1, pk).WithRounds(backup.MinRounds)
+
+	x1, _ = secp256k1.GetRandomScalar()
+	x2, _ = secp256k1.GetRandomScalar()
+	q2, _ := secp256k1.ScalarMultiply(x2, secp256k1.G())
+
+	// Q = x1 * Q2 = x1 * x2 * G.
+	q, _ = secp256k1.ScalarMultiply(x1, q2)
+
+	m.Run()
+}
+
+func TestBackup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Create / Verify / Recover (valid)", func(t *testing.T) {
+		t.Parallel()
+
+		b1, err := backup.Create(params, lindell17.Party1, x1, q)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		b2, err := backup.Create(params, lindell17.Party2, x2, q)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// Each party verifies the other party's backup.
+		if err := b1.VerifyPeer(params, x2, q); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := b2.VerifyPeer(params, x1, q); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// The order of the backups doesn't matter.
+		for _, backups := range [][2]*backup.Backup{{b1, b2}, {b2, b1}} {
+			x, err := backup.Recover(secp256k1, sk, backups[0], backups[1])
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			want := new(big.Int).Mul(x1, x2)
+			want.Mod(want, secp256k1.N())
+			if x.Cmp(want) != 0 {
+				t.Fatal("Recovery failed (x = x1 * x2 mod n)")
+			}
+		}
+	})
+
+	t.Run("Verify (default rounds, codec)", func(t *testing.T) {
+		t.Parallel()
+
+		params := backup.NewParams(secp256k1, keys.DerivePublicKey(sk))
+
+		b, err := backup.Create(params, lindell17.Party2, x2, q)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(b.Proof.Rounds) != backup.DefaultRounds {
+			t.Fatalf("want %d rounds, got %d", backup.DefaultRounds, len(b.Proof.Rounds))
+		}
+
+		data, err := codec.MarshalValue(b)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		decoded := new(backup.Backup)
+		if err := codec.UnmarshalValue(data, decoded); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if err := decoded.VerifyPeer(params, x1, q); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Verify - Invalid (Ciphertext of another share)", func(t *testing.T) {
+		t.Parallel()
+
+		b, _ := backup.Create(params, lindell17.Party1, x1, q)
+
+		// Replace the ciphertext with the encryption of x1 + 1.
+		other := new(big.Int).Add(x1, big.NewInt(1))
+		b.Ciphertext, _ = cipher.Encrypt(keys.DerivePublicKey(sk), other.Bytes())
+
+		err := b.Verify(params)
+
+		if !errors.Is(err, backup.ErrInvalidProof) {
+			t.Errorf("want error %v, got %v", backup.ErrInvalidProof, err)
+		}
+	})
+
+	t.Run("Verify - Invalid (Tampered round)", func(t *testing.T) {
+		t.Parallel()
+
+		b, _ := backup.Create(params, lindell17.Party1, x1, q)
+
+		b.Proof.Rounds[0].Value.Add(b.Proof.Rounds[0].Value, big.NewInt(1))
+
+		err := b.Verify(params)
+
+		if !errors.Is(err, backup.ErrInvalidProof) {
+			t.Errorf("want error %v, got %v", backup.ErrInvalidProof, err)
+		}
+	})
+
+	t.Run("Verify - Invalid (Rounds)", func(t *testing.T) {
+		t.Parallel()
+
+		b, _ := backup.Create(params, lindell17.Party1, x1, q)
+
+		b.Proof.Rounds = b.Proof.Rounds[1:]
+
+		err := b.Verify(params)
+
+		if !errors.Is(err, backup.ErrInvalidProof) {
+			t.Errorf("want error %v, got %v", backup.ErrInvalidProof, err)
+		}
+	})
+
+	t.Run("Verify - Invalid (Rounds below minimum)", func(t *testing.T) {
+		t.Parallel()
+
+		b, _ := backup.Create(params, lindell17.Party1, x1, q)
+		b.Proof = &backup.Proof{}
+
+		// An empty proof has to be rejected even if it has the expected rounds.
+		err := b.Verify(backup.NewParams(secp256k1, keys.DerivePublicKey(sk)).WithRounds(0))
+
+		if !errors.Is(err, backup.ErrInvalidRounds) {
+			t.Errorf("want error %v, got %v", backup.ErrInvalidRounds, err)
+		}
+	})
+
+	t.Run("VerifyPeer - Invalid (Other key)", func(t *testing.T) {
+		t.Parallel()
+
+		b, _ := backup.Create(params, lindell17.Party1, x1, q)
+
+		other, _ := secp256k1.GetRandomScalar()
+		err := b.VerifyPeer(params, other, q)
+
+		if !errors.Is(err, backup.ErrMismatchedKey) {
+			t.Errorf("want error %v, got %v", backup.ErrMismatchedKey, err)
+		}
+	})
+
+	t.Run("Recover - Invalid (Same party)", func(t *testing.T) {
+		t.Parallel()
+
+		b, _ := backup.Create(params, lindell17.Party1, x1, q)
+
+		_, err := backup.Recover(secp256k1, sk, b, b)
+
+		if !errors.Is(err, backup.ErrInvalidParty) {
+			t.Errorf("want error %v, got %v", backup.ErrInvalidParty, err)
+		}
+	})
+
+	t.Run("Recover - Invalid (Other key)", func(t *testing.T) {
+		t.Parallel()
+
+		other, _ := secp256k1.GetRandomScalar()
+		b1, _ := backup.Create(params, lindell17.Party1, x1, q)
+		b2, _ := backup.Create(params, lindell17.Party2, other, q)
+
+		_, err := backup.Recover(secp256k1, sk, b1, b2)
+
+		if !errors.Is(err, backup.ErrMismatchedKey) {
+			t.Errorf("want error %v, got %v", backup.ErrMismatchedKey, err)
+		}
+	})
+
+	t.Run("Recover - Invalid (Missing backup)", func(t *testing.T) {
+		t.Parallel()
+
+		b, _ := backup.Create(params, lindell17.Party1, x1, q)
+
+		_, err := backup.Recover(secp256k1, sk, b, nil)
+
+		if !errors.Is(err, backup.ErrInvalidBackup) {
+			t.Errorf("want error %v, got %v", backup.ErrInvalidBackup, err)
+		}
+	})
+
+	t.Run("Recover - Invalid (Ciphertext)", func(t *testing.T) {
+		t.Parallel()
+
+		b1, _ := backup.Create(params, lindell17.Party1, x1, q)
+		b2, _ := backup.Create(params, lindell17.Party2, x2, q)
+
+		// A ciphertext that isn't less than N^2 can't be decrypted.
+		b2.Ciphertext = keys.DerivePublicKey(sk).NN.Bytes()
+
+		_, err := backup.Recover(secp256k1, sk, b1, b2)
+
+		if !errors.Is(err, backup.ErrInvalidBackup) {
+			t.Errorf("want error %v, got %v", backup.ErrInvalidBackup, err)
+		}
+	})
+
+	t.Run("Create - Invalid (Recovery key too small)", func(t *testing.T) {
+		t.Parallel()
+
+		_, pk, _ := keys.GenerateKeys(256)
+		params := backup.NewParams(secp256k1, pk)
+
+		_, err := backup.Create(params, lindell17.Party1, x1, q)
+
+		if !errors.Is(err, backup.ErrInvalidRecoveryKey) {
+			t.Errorf("want error %v, got %v", backup.ErrInvalidRecoveryKey, err)
+		}
+	})
+
+	t.Run("Create - Invalid (Rounds below minimum)", func(t *testing.T) {
+		t.Parallel()
+
+		params := backup.NewParams(secp256k1, keys.DerivePublicKey(sk)).WithRounds(backup.MinRounds - 1)
+
+		_, err := backup.Create(params, lindell17.Party1, x1, q)
+
+		if !errors.Is(err, backup.ErrInvalidRounds) {
+			t.Errorf("want error %v, got %v", backup.ErrInvalidRounds, err)
+		}
+	})
+
+	t.Run("Create - Invalid (Party)", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := backup.Create(params, lindell17.Prover, x1, q)
+
+		if !errors.Is(err, backup.ErrInvalidParty) {
+			t.Errorf("want error %v, got %v", backup.ErrInvalidParty, err)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+/*
+Package backup implements the backup and recovery of the key shares via
+verifiable encryption.
+
+Party 1 alone can't sign, so the shared key is lost if party 2's host is lost
+(and vice versa). Each party can back up its share (x1 or x2) by encrypting it
+to an offline recovery key, i.e. a Paillier public key whose private key is
+kept offline (see Create). The backup contains the party's public share (Q1 or
+Q2), the shared public key Q and a non-interactive proof that the ciphertext
+decrypts to the discrete logarithm of the public share. The other party checks
+the backup via VerifyPeer without learning the share, since it checks the proof
+and that its own share combines the public share to Q (x2 * Q1 = x1 * Q2 = Q).
+
+The proof is a cut-and-choose proof that is made non-interactive via the
+Fiat-Shamir heuristic. In every round, the prover encrypts a random mask alpha
+(A = Enc(alpha)) and commits to it on the curve (B = alpha * G). Depending on
+the round's challenge bit, it either opens alpha or alpha + x, which is
+consistent with A * Enc(x) and B + Q_i. A prover that encrypted another value
+than the discrete logarithm can answer at most one of the challenges, so it
+succeeds with probability 2^-rounds (see DefaultRounds and MinRounds). The mask is 80 bits
+longer than the order, so that alpha + x statistically hides x.
+
+The recovery tool decrypts both backups via the recovery private key and
+rebuilds the full ECDSA private key x = x1 * x2 mod n (see Recover).
+*/
+package backup
@@ -0,0 +1,30 @@
+package backup
+
+import "fmt"
+
+var (
+	// ErrInvalidParty is returned if the party isn't party 1 or party 2.
+	ErrInvalidParty = fmt.Errorf("invalid party (want party 1 or party 2)")
+	// ErrInvalidRecoveryKey is returned if the recovery key is malformed or too
+	// small to encrypt the proof's values.
+	ErrInvalidRecoveryKey = fmt.Errorf("invalid recovery key")
+	// ErrInvalidRounds is returned if the number of proof rounds is less than
+	// MinRounds.
+	ErrInvalidRounds = fmt.Errorf("invalid number of proof rounds")
+	// ErrInvalidShare is returned if the share isn't a valid scalar or doesn't
+	// match its public share.
+	ErrInvalidShare = fmt.Errorf("invalid share")
+	// ErrEncryptShare is returned if the share can't be encrypted.
+	ErrEncryptShare = fmt.Errorf("unable to encrypt share")
+	// ErrGenerateProof is returned if the proof can't be generated.
+	ErrGenerateProof = fmt.Errorf("unable to generate proof")
+	// ErrInvalidBackup is returned if the backup is malformed.
+	ErrInvalidBackup = fmt.Errorf("invalid backup")
+	// ErrInvalidProof is returned if the backup's proof is invalid.
+	ErrInvalidProof = fmt.Errorf("invalid backup proof")
+	// ErrMismatchedKey is returned if the share doesn't belong to the shared
+	// public key.
+	ErrMismatchedKey = fmt.Errorf("share doesn't match the shared public key")
+	// ErrDecryptShare is returned if the share can't be decrypted.
+	ErrDecryptShare = fmt.Errorf("unable to decrypt share")
+)
@@ -0,0 +1,55 @@
+package backup
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// DefaultRounds is the default number of rounds of the proof. A cheating
+// prover succeeds with probability 2^-rounds and can try many challenges
+// offline, so the rounds need to match the security level of the curve.
+const DefaultRounds = 128
+
+// MinRounds is the minimum number of rounds of the proof. Backups aren't
+// created or verified with fewer rounds.
+const MinRounds = 128
+
+// Params is an instance of parameters that backups are created and verified
+// with.
+type Params struct {
+	curve  weierstrass.Curve
+	pk     *keys.PublicKey
+	rounds int
+	rand   io.Reader
+}
+
+// NewParams creates a new instance of parameters that backups are created and
+// verified with. pk is the public key of the offline recovery key.
+func NewParams(curve weierstrass.Curve, pk *keys.PublicKey) *Params {
+	return &Params{
+		curve:  curve,
+		pk:     pk,
+		rounds: DefaultRounds,
+		rand:   rand.Reader,
+	}
+}
+
+// WithRounds sets the number of rounds of the proof, which can't be less than
+// MinRounds. Proofs with another number of rounds are rejected.
+// Returns the params to allow chaining.
+func (p *Params) WithRounds(rounds int) *Params {
+	p.rounds = rounds
+
+	return p
+}
+
+// WithRandomness sets the source of randomness backups are created with.
+// Returns the params to allow chaining.
+func (p *Params) WithRandomness(reader io.Reader) *Params {
+	p.rand = reader
+
+	return p
+}
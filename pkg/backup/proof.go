@@ -0,0 +1,212 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/utils"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	pUtils "github.com/primefactor-io/paillier/pkg/utils"
+)
+
+// Proof is an instance of the proof that a backup's ciphertext decrypts to
+// the discrete logarithm of its public share.
+type Proof struct {
+	// Rounds are the proof's rounds.
+	Rounds []*Round
+}
+
+// Round is an instance of one of the proof's rounds.
+type Round struct {
+	// Ciphertext is the encryption of the mask (A = Enc(alpha, rho)).
+	Ciphertext cipher.Ciphertext
+	// Point is the commitment to the mask (B = alpha * G).
+	Point *elliptic.Point
+	// Value is alpha if the round's challenge bit is 0 and alpha + x otherwise.
+	Value *big.Int
+	// Nonce is rho if the round's challenge bit is 0 and rho * r mod N
+	// otherwise, where r is the nonce of the share's encryption.
+	Nonce *big.Int
+}
+
+// prove generates the proof that the backup's ciphertext, which was encrypted
+// with the nonce r, decrypts to x.
+// Returns an error if the proof can't be generated.
+func prove(params *Params, b *Backup, x, r *big.Int) (*Proof, error) {
+	curve := params.curve
+	pk := params.pk
+
+	// alpha is sampled from [0, n * 2^80).
+	maskBound := new(big.Int).Lsh(curve.N(), maskBits)
+
+	alphas := make([]*big.Int, params.rounds)
+	rhos := make([]*big.Int, params.rounds)
+	defer utils.Wipe(alphas...)
+	defer utils.Wipe(rhos...)
+
+	proof := &Proof{Rounds: make([]*Round, params.rounds)}
+	for i := range proof.Rounds {
+		alpha, err := random.Int(params.rand, maskBound)
+		if err != nil {
+			return nil, err
+		}
+		alphas[i] = alpha
+
+		// Compute A.
+		ciphertext, rho, err := random.Encrypt(params.rand, pk, alpha.Bytes()) // Enc(alpha, rho)
+		if err != nil {
+			return nil, err
+		}
+		rhos[i] = rho
+
+		// Compute B.
+		point, err := curve.ScalarMultiply(new(big.Int).Mod(alpha, curve.N()), curve.G()) // alpha * G
+		if err != nil {
+			return nil, err
+		}
+
+		proof.Rounds[i] = &Round{Ciphertext: ciphertext, Point: point}
+	}
+
+	// Compute the challenge bits.
+	bits, err := challenge(params, b, proof)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, round := range proof.Rounds {
+		if bits[i] == 0 {
+			round.Value = new(big.Int).Set(alphas[i])
+			round.Nonce = new(big.Int).Set(rhos[i])
+			continue
+		}
+
+		round.Value = new(big.Int).Add(alphas[i], x) // alpha + x
+		in1 := new(big.Int).Mul(rhos[i], r)          // rho * r
+		round.Nonce = in1.Mod(in1, pk.N)             // rho * r mod N
+	}
+
+	return proof, nil
+}
+
+// verify verifies the proof of the backup whose values were checked.
+// Returns an error if the proof is invalid.
+func verify(params *Params, b *Backup) error {
+	curve := params.curve
+	pk := params.pk
+
+	if b.Proof == nil || len(b.Proof.Rounds) != params.rounds {
+		return ErrInvalidProof
+	}
+	for _, round := range b.Proof.Rounds {
+		if round == nil {
+			return ErrInvalidProof
+		}
+		if err := lindell17.CheckCiphertext(pk, round.Ciphertext); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+		}
+		if err := lindell17.CheckPoint(curve, round.Point); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+		}
+	}
+
+	// Compute the challenge bits.
+	bits, err := challenge(params, b, b.Proof)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+
+	c := new(big.Int).SetBytes(b.Ciphertext)
+	maskBound := new(big.Int).Lsh(curve.N(), maskBits)
+	valueBound := new(big.Int).Add(maskBound, curve.N())
+
+	for i, round := range b.Proof.Rounds {
+		a := new(big.Int).SetBytes(round.Ciphertext)
+
+		// alpha < n * 2^80 and alpha + x < n * 2^80 + n, so that the decrypted
+		// value can't wrap around N.
+		wantCiphertext, wantPoint := a, round.Point
+		bound := maskBound
+		if bits[i] == 1 {
+			in1 := new(big.Int).Mul(a, c)                    // A * Enc(x)
+			wantCiphertext = in1.Mod(in1, pk.NN)             // A * Enc(x) mod N^2
+			wantPoint, err = curve.Add(round.Point, b.Share) // B + Q_i
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+			}
+			bound = valueBound
+		}
+
+		if err := lindell17.CheckScalar(round.Value, big.NewInt(0), bound); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+		}
+		if err := lindell17.CheckScalar(round.Nonce, big.NewInt(1), pk.N); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+		}
+
+		// Check that Enc(value, nonce) = A or A * Enc(x).
+		ciphertext, err := cipher.EncryptWithCustomNonce(pk, round.Nonce, round.Value.Bytes())
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+		}
+		if new(big.Int).SetBytes(ciphertext).Cmp(wantCiphertext) != 0 {
+			return ErrInvalidProof
+		}
+
+		// Check that value * G = B or B + Q_i.
+		point, err := curve.ScalarMultiply(new(big.Int).Mod(round.Value, curve.N()), curve.G())
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+		}
+		if !point.Equal(wantPoint) {
+			return ErrInvalidProof
+		}
+	}
+
+	return nil
+}
+
+// challenge derives the proof's challenge bits from the hash of the statement
+// and the rounds' commitments.
+// Returns an error if the challenge can't be derived.
+func challenge(params *Params, b *Backup, proof *Proof) ([]byte, error) {
+	h := sha256.New()
+	write := func(bz []byte) {
+		// Prefix every value with its length, so that the encoding is unambiguous.
+		binary.Write(h, binary.BigEndian, uint32(len(bz)))
+		h.Write(bz)
+	}
+
+	write([]byte("BACKUP"))
+	write(params.curve.N().Bytes())
+	write(params.pk.N.Bytes())
+	write([]byte{byte(b.Party)})
+	for _, point := range []*elliptic.Point{b.Share, b.Q} {
+		write(point.X.Bytes())
+		write(point.Y.Bytes())
+	}
+	write(b.Ciphertext)
+	for _, round := range proof.Rounds {
+		write(round.Ciphertext)
+		write(round.Point.X.Bytes())
+		write(round.Point.Y.Bytes())
+	}
+
+	// Expand the hash to whole bytes, so that no bit is masked.
+	bz, err := pUtils.GenerateRandomBytesSeeded(h.Sum(nil), 8*((len(proof.Rounds)+7)/8))
+	if err != nil {
+		return nil, err
+	}
+
+	bits := make([]byte, len(proof.Rounds))
+	for i := range bits {
+		bits[i] = (bz[i/8] >> (i % 8)) & 1
+	}
+
+	return bits, nil
+}
@@ -0,0 +1,91 @@
+package backup
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/utils"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// Recover rebuilds the full ECDSA private key x = x1 * x2 mod n of the shared
+// public key Q from the backups of both parties (in any order) via the
+// recovery private key sk.
+// Returns an error if a backup is malformed, the backups don't belong to both
+// parties of the same key or a share can't be decrypted.
+func Recover(curve weierstrass.Curve, sk *keys.PrivateKey, b1, b2 *Backup) (*big.Int, error) {
+	if b1 == nil || b2 == nil {
+		return nil, ErrInvalidBackup
+	}
+	if b1.Party == lindell17.Party2 && b2.Party == lindell17.Party1 {
+		b1, b2 = b2, b1
+	}
+	if b1.Party != lindell17.Party1 || b2.Party != lindell17.Party2 {
+		return nil, ErrInvalidParty
+	}
+	if b1.Q == nil || b2.Q == nil || !b1.Q.Equal(b2.Q) {
+		return nil, ErrMismatchedKey
+	}
+
+	x1, err := decryptShare(curve, sk, b1)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.Wipe(x1)
+
+	x2, err := decryptShare(curve, sk, b2)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.Wipe(x2)
+
+	// Compute x.
+	in1 := new(big.Int).Mul(x1, x2)       // x1 * x2
+	x := new(big.Int).Mod(in1, curve.N()) // x1 * x2 mod n
+	utils.Wipe(in1)
+
+	// Ensure that x * G = Q.
+	q, err := curve.ScalarMultiply(x, curve.G())
+	if err != nil || !q.Equal(b1.Q) {
+		utils.Wipe(x)
+		return nil, ErrMismatchedKey
+	}
+
+	return x, nil
+}
+
+// decryptShare decrypts the backup's share and checks that it's the discrete
+// logarithm of the backup's public share.
+// Returns an error if the ciphertext is invalid or the share can't be
+// decrypted or doesn't match.
+func decryptShare(curve weierstrass.Curve, sk *keys.PrivateKey, b *Backup) (*big.Int, error) {
+	if err := lindell17.CheckCiphertext(keys.DerivePublicKey(sk), b.Ciphertext); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+
+	plaintext, err := cipher.Decrypt(sk, b.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryptShare, err)
+	}
+	m := new(big.Int).SetBytes(plaintext)
+	utils.WipeBytes(plaintext)
+
+	// The proof only guarantees that the plaintext, as a signed value in
+	// (-N / 2, N / 2), is congruent to the share modulo n.
+	half := new(big.Int).Rsh(sk.N, 1)
+	if m.Cmp(half) > 0 {
+		m.Sub(m, sk.N)
+	}
+	x := m.Mod(m, curve.N())
+
+	share, err := curve.ScalarMultiply(x, curve.G())
+	if err != nil || !share.Equal(b.Share) {
+		utils.Wipe(x)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidShare, b.Party)
+	}
+
+	return x, nil
+}
@@ -0,0 +1,572 @@
+package batchsign_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/ecc/pkg/ecdsa"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/keys"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/batchsign/messages"
+	"github.com/primefactor-io/lindell17/pkg/batchsign/party1"
+	"github.com/primefactor-io/lindell17/pkg/batchsign/party2"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/policy"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	pKeys "github.com/primefactor-io/paillier/pkg/keys"
+)
+
+var p1Params *party1.Params
+var p2Params *party2.Params
+var p2AllowedParams *party2.Params
+var p2DeniedParams *party2.Params
+var qShared *elliptic.Point
+var paillierPk *pKeys.PublicKey
+
+var secp256k1 = curves.Secp256k1
+
+func TestMain(m *testing.M) {
+	// x1 should be in the range x1 >= 0 and x1 < q / 3.
+	q3 := new(big.Int).Div(secp256k1.N(), big.NewInt(3)) // q / 3
+
+	sk, pk, _ := pKeys.GenerateKeys(1024)
+	paillierPk = pk
+
+	x1, _ := secp256k1.GetRandomScalar(q3)
+	x1Enc, _ := cipher.Encrypt(pk, x1.Bytes())
+
+	x2, _ := secp256k1.GetRandomScalar()
+	q2, _ := secp256k1.ScalarMultiply(x2, secp256k1.G())
+
+	// Note that x1 * Q2 = x2 * Q1.
+	qShared, _ = secp256k1.ScalarMultiply(x1, q2)
+
+	p1Params = party1.NewParams(secp256k1, sk, qShared)
+	p2Params = party2.NewParams(secp256k1, pk, x1Enc, x2)
+
+	engine := policy.NewEngine(policy.Destinations("bc1qallowed"))
+	allowed := &lindell17.Payload{Destination: "bc1qallowed"}
+	denied := &lindell17.Payload{Destination: "bc1qunknown"}
+	p2AllowedParams = party2.NewParams(secp256k1, pk, x1Enc, x2).
+		WithPolicy(engine, "key").
+		WithPayloads([]*lindell17.Payload{allowed, allowed})
+	p2DeniedParams = party2.NewParams(secp256k1, pk, x1Enc, x2).
+		WithPolicy(engine, "key").
+		WithPayloads([]*lindell17.Payload{allowed, denied})
+
+	m.Run()
+}
+
+func TestBatchSign(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Batch Sign (valid)", func(t *testing.T) {
+		t.Parallel()
+
+		hashes := newHashes(3)
+
+		res, err := run(t, hashes, hashes, nil)
+
+		if err.p1 != nil || err.p2 != nil {
+			t.Fatalf("expected no error, got %v / %v", err.p1, err.p2)
+		}
+
+		verifySignatures(t, hashes, res.p1.Signatures, -1)
+
+		for i := range hashes {
+			if res.p2.R[i] == nil || res.p2.Ciphertexts[i] == nil {
+				t.Fatalf("Partial signature %d missing", i)
+			}
+		}
+	})
+
+	t.Run("Batch Sign - Invalid (R2 DLK proof of one item)", func(t *testing.T) {
+		t.Parallel()
+
+		hashes := newHashes(3)
+
+		res, err := run(t, hashes, hashes, func(msg lindell17.Message) lindell17.Message {
+			if m, ok := msg.(*messages.Message2); ok {
+				// Use the proof of another item.
+				m.PR2[1] = m.PR2[0]
+			}
+			return msg
+		})
+
+		checkBatchError(t, err.p1, 1, party1.ErrInvalidR2DLKProof)
+		checkBatchError(t, err.p2, 1, party2.ErrItemAborted)
+		verifySignatures(t, hashes, res.p1.Signatures, 1)
+
+		if res.p2.R[1] != nil || res.p2.Ciphertexts[1] != nil {
+			t.Fatal("Partial signature of aborted item wasn't omitted")
+		}
+	})
+
+	t.Run("Batch Sign - Invalid (R1 commitment of one item)", func(t *testing.T) {
+		t.Parallel()
+
+		hashes := newHashes(3)
+
+		res, err := run(t, hashes, hashes, func(msg lindell17.Message) lindell17.Message {
+			if m, ok := msg.(*messages.Message3); ok {
+				m.R1[2] = secp256k1.G()
+			}
+			return msg
+		})
+
+		checkBatchError(t, err.p2, 2, party2.ErrInvalidR1Commitment)
+		checkBatchError(t, err.p1, 2, party1.ErrItemAborted)
+		verifySignatures(t, hashes, res.p1.Signatures, 2)
+	})
+
+	t.Run("Batch Sign - Invalid (Forged ciphertext of one item)", func(t *testing.T) {
+		t.Parallel()
+
+		hashes := newHashes(3)
+
+		res, err := run(t, hashes, hashes, func(msg lindell17.Message) lindell17.Message {
+			if m, ok := msg.(*messages.Message4); ok {
+				m.Ciphertexts[1], _ = cipher.Encrypt(paillierPk, big.NewInt(1).Bytes())
+			}
+			return msg
+		})
+
+		if err.p2 != nil {
+			t.Fatalf("expected no error, got %v", err.p2)
+		}
+
+		checkBatchAborted(t, err.p1, res.p1)
+	})
+
+	t.Run("Batch Sign - Invalid (Zero s' of one item)", func(t *testing.T) {
+		t.Parallel()
+
+		hashes := newHashes(3)
+
+		res, err := run(t, hashes, hashes, func(msg lindell17.Message) lindell17.Message {
+			if m, ok := msg.(*messages.Message4); ok {
+				m.Ciphertexts[0], _ = cipher.Encrypt(paillierPk, secp256k1.N().Bytes())
+			}
+			return msg
+		})
+
+		if err.p2 != nil {
+			t.Fatalf("expected no error, got %v", err.p2)
+		}
+
+		checkBatchAborted(t, err.p1, res.p1)
+	})
+
+	t.Run("Batch Sign - Invalid (Incomplete item)", func(t *testing.T) {
+		t.Parallel()
+
+		hashes := newHashes(2)
+
+		res, err := run(t, hashes, hashes, func(msg lindell17.Message) lindell17.Message {
+			if m, ok := msg.(*messages.Message4); ok {
+				m.R[1] = nil
+			}
+			return msg
+		})
+
+		if !errors.Is(err.p1, lindell17.ErrInvalidMessage) {
+			t.Errorf("want error %v, got %v", lindell17.ErrInvalidMessage, err.p1)
+		}
+		if res.p1 != nil {
+			t.Fatal("Party 1 sent a result")
+		}
+	})
+
+	t.Run("Batch Sign - Invalid (Batch size)", func(t *testing.T) {
+		t.Parallel()
+
+		hashes := newHashes(3)
+
+		_, err := run(t, hashes, hashes[:2], nil)
+
+		if !errors.Is(err.p2, party2.ErrInvalidBatchSize) {
+			t.Errorf("want error %v, got %v", party2.ErrInvalidBatchSize, err.p2)
+		}
+	})
+
+	t.Run("Party1 - Start - Invalid (Empty batch)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		p1 := party1.NewParty1(p1Params, nil, outCh, resCh)
+
+		_, err := p1.Start()
+
+		if !errors.Is(err, party1.ErrEmptyBatch) {
+			t.Errorf("want error %v, got %v", party1.ErrEmptyBatch, err)
+		}
+	})
+
+	t.Run("Party1 - Start - Invalid (Hash length)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		hashes := newHashes(2)
+		hashes[1] = []byte("Hello World")
+
+		p1 := party1.NewParty1(p1Params, hashes, outCh, resCh)
+
+		_, err := p1.Start()
+
+		if !errors.Is(err, party1.ErrInvalidHashLength) {
+			t.Errorf("want error %v, got %v", party1.ErrInvalidHashLength, err)
+		}
+
+		var itemErr *lindell17.ItemError
+		if !errors.As(err, &itemErr) || itemErr.Index != 1 {
+			t.Errorf("want error for item 1, got %v", err)
+		}
+	})
+
+	t.Run("Party2 - Start - Invalid (Hash length)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		hashes := newHashes(2)
+		hashes[0] = []byte("Hello World")
+
+		p2 := party2.NewParty2(p2Params, hashes, outCh, resCh)
+
+		_, err := p2.Start()
+
+		if !errors.Is(err, party2.ErrInvalidHashLength) {
+			t.Errorf("want error %v, got %v", party2.ErrInvalidHashLength, err)
+		}
+	})
+
+	t.Run("Party2 - Start (Policy allowed)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		p2 := party2.NewParty2(p2AllowedParams, newHashes(2), outCh, resCh)
+
+		_, err := p2.Start()
+
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Party2 - Start - Invalid (Policy denied one item)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		p2 := party2.NewParty2(p2DeniedParams, newHashes(2), outCh, resCh)
+
+		_, err := p2.Start()
+
+		if !errors.Is(err, lindell17.ErrPolicyDenied) {
+			t.Errorf("want error %v, got %v", lindell17.ErrPolicyDenied, err)
+		}
+
+		var itemErr *lindell17.ItemError
+		if !errors.As(err, &itemErr) || itemErr.Index != 1 {
+			t.Errorf("want error for item 1, got %v", err)
+		}
+		if len(outCh) != 0 {
+			t.Error("expected no message to be sent")
+		}
+	})
+
+	t.Run("Party2 - Start - Invalid (Policy velocity)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		// The batch's items exceed the velocity limit together.
+		engine := policy.NewEngine(policy.Velocity(time.Hour, 2, big.NewInt(100)))
+		payload := &lindell17.Payload{Destination: "bc1qallowed", Amount: big.NewInt(40)}
+		params := party2.NewParams(secp256k1, paillierPk, nil, nil).
+			WithPolicy(engine, "key").
+			WithPayloads([]*lindell17.Payload{payload, payload, payload})
+
+		p2 := party2.NewParty2(params, newHashes(3), outCh, resCh)
+
+		_, err := p2.Start()
+
+		var itemErr *lindell17.ItemError
+		if !errors.Is(err, lindell17.ErrPolicyDenied) || !errors.As(err, &itemErr) || itemErr.Index != 2 {
+			t.Errorf("want error %v for item 2, got %v", lindell17.ErrPolicyDenied, err)
+		}
+
+		// The denied batch's items aren't counted.
+		params = party2.NewParams(secp256k1, paillierPk, nil, nil).
+			WithPolicy(engine, "key").
+			WithPayloads([]*lindell17.Payload{payload, payload})
+
+		p2 = party2.NewParty2(params, newHashes(2), outCh, resCh)
+
+		if _, err := p2.Start(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Party2 - Start - Invalid (Payload count)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		p2 := party2.NewParty2(p2AllowedParams, newHashes(3), outCh, resCh)
+
+		_, err := p2.Start()
+
+		if !errors.Is(err, party2.ErrInvalidPayloadCount) {
+			t.Errorf("want error %v, got %v", party2.ErrInvalidPayloadCount, err)
+		}
+	})
+
+	t.Run("Process - Invalid (Foreign message type)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 4)
+
+		hashes := newHashes(1)
+
+		p1 := party1.NewParty1(p1Params, hashes, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hashes, outCh, resCh)
+
+		tests := []struct {
+			party lindell17.Participant
+			msg   *foreignMessage
+			want  error
+		}{
+			{p1, &foreignMessage{lindell17.BatchSign, lindell17.Party2, lindell17.Party1, 2}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.BatchSign, lindell17.Party2, lindell17.Party1, 4}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.BatchSign, lindell17.Party2, lindell17.Party1, 5}, lindell17.ErrUnknownMessage},
+			{p2, &foreignMessage{lindell17.BatchSign, lindell17.Party1, lindell17.Party2, 1}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.BatchSign, lindell17.Party1, lindell17.Party2, 3}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.BatchSign, lindell17.Party1, lindell17.Party2, 5}, lindell17.ErrUnknownMessage},
+		}
+
+		for _, tt := range tests {
+			_, err := tt.party.Process(tt.msg)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("message id %d: want error %v, got %v", tt.msg.messageId, tt.want, err)
+			}
+		}
+	})
+}
+
+// results are the results of both parties.
+type results struct {
+	p1 *party1.Result
+	p2 *party2.Result
+}
+
+// partyErrors are the first errors both parties returned.
+type partyErrors struct {
+	p1 error
+	p2 error
+}
+
+// run runs the protocol with the given hashes and returns the parties'
+// results and errors. Every message passes the codec and the mutation (if
+// set) before it's delivered.
+func run(t *testing.T, hashes1, hashes2 [][]byte, mutate func(lindell17.Message) lindell17.Message) (results, partyErrors) {
+	t.Helper()
+
+	outCh := make(chan lindell17.Message, 2)
+	resCh := make(chan lindell17.Result, 2)
+
+	p1 := party1.NewParty1(p1Params, hashes1, outCh, resCh)
+	p2 := party2.NewParty2(p2Params, hashes2, outCh, resCh)
+
+	var res results
+	var errs partyErrors
+
+	if _, err := p1.Start(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := p2.Start(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for {
+		select {
+		case msg := <-outCh:
+			data, err := codec.MarshalMessage(msg)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			msg, err = codec.UnmarshalMessage(data)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if mutate != nil {
+				msg = mutate(msg)
+			}
+
+			switch msg.To() {
+			case lindell17.Party1:
+				if _, err := p1.Process(msg); err != nil && errs.p1 == nil {
+					errs.p1 = err
+				}
+			case lindell17.Party2:
+				if _, err := p2.Process(msg); err != nil && errs.p2 == nil {
+					errs.p2 = err
+				}
+			}
+		case result := <-resCh:
+			data, err := codec.MarshalResult(result)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			result, err = codec.UnmarshalResult(data)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			switch result.From() {
+			case lindell17.Party1:
+				res.p1 = result.(*party1.Result)
+			case lindell17.Party2:
+				res.p2 = result.(*party2.Result)
+			}
+		default:
+			return res, errs
+		}
+	}
+}
+
+// newHashes creates the given number of distinct hashes.
+func newHashes(n int) [][]byte {
+	hashes := make([][]byte, n)
+	for i := range hashes {
+		checksum := sha256.Sum256(fmt.Appendf(nil, "Hello World %d", i))
+		hashes[i] = checksum[:]
+	}
+
+	return hashes
+}
+
+// verifySignatures checks that every signature except the one of the failed
+// item (-1 if no item failed) is a valid signature of its hash.
+func verifySignatures(t *testing.T, hashes [][]byte, signatures []*ecdsa.Signature, failed int) {
+	t.Helper()
+
+	if len(signatures) != len(hashes) {
+		t.Fatalf("want %d signatures, got %d", len(hashes), len(signatures))
+	}
+
+	pk := (*keys.PublicKey)(qShared)
+	for i, signature := range signatures {
+		if i == failed {
+			if signature != nil {
+				t.Fatalf("Signature of failed item %d wasn't omitted", i)
+			}
+			continue
+		}
+
+		if signature == nil {
+			t.Fatalf("Signature %d missing", i)
+		}
+
+		isValid, _ := ecdsa.Verify(secp256k1, pk, hashes[i], signature)
+		if !isValid {
+			t.Fatalf("Signature %d verification failed", i)
+		}
+	}
+}
+
+// checkBatchError checks that the error is a batch error that reports the
+// wanted error for the item with the given index only.
+func checkBatchError(t *testing.T, err error, index int, want error) {
+	t.Helper()
+
+	var batchErr *lindell17.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("want batch error, got %v", err)
+	}
+
+	if len(batchErr.Errors) != 1 || batchErr.Errors[0].Index != index {
+		t.Fatalf("want error for item %d only, got %v", index, err)
+	}
+
+	if !errors.Is(err, want) {
+		t.Fatalf("want error %v, got %v", want, err)
+	}
+}
+
+// checkBatchAborted checks that party 1 aborted the whole batch with an error
+// that doesn't name an item and didn't output any signatures.
+func checkBatchAborted(t *testing.T, err error, res *party1.Result) {
+	t.Helper()
+
+	if !errors.Is(err, party1.ErrBatchAborted) {
+		t.Fatalf("want error %v, got %v", party1.ErrBatchAborted, err)
+	}
+
+	var batchErr *lindell17.BatchError
+	if errors.As(err, &batchErr) {
+		t.Fatalf("want error without item, got %v", err)
+	}
+
+	if res != nil {
+		t.Fatal("Signatures of aborted batch weren't omitted")
+	}
+}
+
+// foreignMessage is a message type that isn't part of the protocol but
+// implements the message interface.
+type foreignMessage struct {
+	protocol  lindell17.Protocol
+	from      lindell17.Entity
+	to        lindell17.Entity
+	messageId int
+}
+
+func (m *foreignMessage) To() lindell17.Entity {
+	return m.to
+}
+
+func (m *foreignMessage) From() lindell17.Entity {
+	return m.from
+}
+
+func (m *foreignMessage) Protocol() lindell17.Protocol {
+	return m.protocol
+}
+
+func (m *foreignMessage) MessageId() int {
+	return m.messageId
+}
+
+func (m *foreignMessage) SessionId() string {
+	return strings.Repeat("0", lindell17.SessionIdLength)
+}
+
+func (m *foreignMessage) IsValid() bool {
+	return true
+}
+
+func (m *foreignMessage) ValidateFor(curve weierstrass.Curve, pk *pKeys.PublicKey) error {
+	return nil
+}
@@ -0,0 +1,34 @@
+/*
+Package batchsign implements a batched version of the interactive signing
+protocol (see package sign) which signs many hashes with the same key in a
+single protocol run.
+
+Both parties are given the same list of hashes. The protocol's four messages
+carry one value per hash instead of a single one, i.e. vectors of commitments,
+DLK proofs, R values and ciphertexts. Every item uses its own nonces, so the
+items are independent signing runs that share the message exchange.
+
+Party 2 can be given a signing policy (see lindell17.Policy) and the payloads
+the hashes were computed from. It evaluates the policy on every item before it
+starts and denies the whole batch if the policy denies any of its items.
+Policies that implement lindell17.BatchPolicy (e.g. policy.Engine) only record
+the items of allowed batches, so a denied batch doesn't use up velocity
+limits.
+
+An item either results in a valid signature or fails as a whole. A party that
+detects an invalid value for an item before party 1 decrypts anything (i.e. an
+invalid commitment, DLK proof or r value) aborts this item, sends nil in its
+place from then on and continues with the batch's other items. The results
+hold nil for failed items, and after sending its result a party returns a
+*lindell17.BatchError that reports the error of every failed item.
+
+Failures after party 1 decrypted a partial signature (i.e. an undecryptable
+ciphertext, s' = 0 mod q or an invalid signature) depend on party 1's secret
+key. Reporting them per item would let a malicious party 2 learn about the key
+from which items fail, so party 1 aborts the whole batch instead: it outputs no
+signatures, wipes the nonces of every item and returns a single error that
+doesn't name the item. Errors that aren't caused by an item's values (e.g.
+malformed messages, a batch size mismatch or a failure of a party's own
+computations) abort the whole protocol run as well.
+*/
+package batchsign
@@ -0,0 +1,76 @@
+package messages
+
+import (
+	"github.com/primefactor-io/commitment/pkg/hash"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// Message1 is the protocol's first message that is sent from party 1 to party 2.
+type Message1 struct {
+	// Sid is the session id.
+	Sid string
+	// CR1 are the commitments to the items' R1 values.
+	CR1 []*hash.Commitment
+	// PR1 are the discrete logarithm knowledge proofs for the items' R1 values.
+	PR1 []*proofs.DLKProof
+}
+
+// NewMessage1 creates a new instance of the protocol's first message.
+func NewMessage1(sid string, cR1 []*hash.Commitment, pR1 []*proofs.DLKProof) *Message1 {
+	return &Message1{
+		Sid: sid,
+		CR1: cR1,
+		PR1: pR1,
+	}
+}
+
+func (m *Message1) To() lindell17.Entity {
+	return lindell17.Party2
+}
+
+func (m *Message1) From() lindell17.Entity {
+	return lindell17.Party1
+}
+
+func (m *Message1) Protocol() lindell17.Protocol {
+	return lindell17.BatchSign
+}
+
+func (m *Message1) MessageId() int {
+	return 1
+}
+
+func (m *Message1) SessionId() string {
+	return m.Sid
+}
+
+func (m *Message1) BatchSize() int {
+	return len(m.CR1)
+}
+
+func (m *Message1) IsValid() bool {
+	if !lindell17.IsValidSessionId(m.Sid) ||
+		len(m.CR1) == 0 ||
+		len(m.PR1) != len(m.CR1) {
+		return false
+	}
+
+	for i := range m.CR1 {
+		if m.CR1[i] == nil || m.PR1[i] == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *Message1) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	return nil
+}
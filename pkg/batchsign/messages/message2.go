@@ -0,0 +1,84 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// Message2 is the protocol's second message that is sent form party 2 to party 1.
+type Message2 struct {
+	// Sid is the session id.
+	Sid string
+	// R2 are the items' R2 values.
+	R2 []*elliptic.Point
+	// PR2 are the discrete logarithm knowledge proofs for the items' R2 values.
+	PR2 []*proofs.DLKProof
+}
+
+// NewMessage2 creates a new instance of the protocol's second message.
+func NewMessage2(sid string, r2 []*elliptic.Point, pR2 []*proofs.DLKProof) *Message2 {
+	return &Message2{
+		Sid: sid,
+		R2:  r2,
+		PR2: pR2,
+	}
+}
+
+func (m *Message2) To() lindell17.Entity {
+	return lindell17.Party1
+}
+
+func (m *Message2) From() lindell17.Entity {
+	return lindell17.Party2
+}
+
+func (m *Message2) Protocol() lindell17.Protocol {
+	return lindell17.BatchSign
+}
+
+func (m *Message2) MessageId() int {
+	return 2
+}
+
+func (m *Message2) SessionId() string {
+	return m.Sid
+}
+
+func (m *Message2) BatchSize() int {
+	return len(m.R2)
+}
+
+func (m *Message2) IsValid() bool {
+	if !lindell17.IsValidSessionId(m.Sid) ||
+		len(m.R2) == 0 ||
+		len(m.PR2) != len(m.R2) {
+		return false
+	}
+
+	for i := range m.R2 {
+		if m.R2[i] == nil || m.PR2[i] == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *Message2) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	for i, r2 := range m.R2 {
+		if err := lindell17.CheckPoint(curve, r2); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, lindell17.NewItemError(i, err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// Message3 is the protocol's third message that is sent from party 1 to party 2.
+type Message3 struct {
+	// Sid is the session id.
+	Sid string
+	// R1 are the items' R1 values. The values of items party 1 aborted are nil.
+	R1 []*elliptic.Point
+}
+
+// NewMessage3 creates a new instance of the protocol's third message.
+func NewMessage3(sid string, r1 []*elliptic.Point) *Message3 {
+	return &Message3{
+		Sid: sid,
+		R1:  r1,
+	}
+}
+
+func (m *Message3) To() lindell17.Entity {
+	return lindell17.Party2
+}
+
+func (m *Message3) From() lindell17.Entity {
+	return lindell17.Party1
+}
+
+func (m *Message3) Protocol() lindell17.Protocol {
+	return lindell17.BatchSign
+}
+
+func (m *Message3) MessageId() int {
+	return 3
+}
+
+func (m *Message3) SessionId() string {
+	return m.Sid
+}
+
+func (m *Message3) BatchSize() int {
+	return len(m.R1)
+}
+
+func (m *Message3) IsValid() bool {
+	return lindell17.IsValidSessionId(m.Sid) &&
+		len(m.R1) != 0
+}
+
+func (m *Message3) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	for i, r1 := range m.R1 {
+		if r1 == nil {
+			continue
+		}
+		if err := lindell17.CheckPoint(curve, r1); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, lindell17.NewItemError(i, err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package messages
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// Message4 is the protocol's fourth message that is sent from part 2 to party 1.
+type Message4 struct {
+	// Sid is the session id.
+	Sid string
+	// R are the signatures' r values. The values of aborted items are nil.
+	R []*big.Int
+	// Ciphertexts are the encryptions of k2^-1 * (z + (r * x1 * x2)) + (p * q)
+	// for every item. The values of aborted items are nil.
+	Ciphertexts []cipher.Ciphertext
+}
+
+// NewMessage4 creates a new instance of the protocol's fourth message.
+func NewMessage4(sid string, r []*big.Int, ciphertexts []cipher.Ciphertext) *Message4 {
+	return &Message4{
+		Sid:         sid,
+		R:           r,
+		Ciphertexts: ciphertexts,
+	}
+}
+
+func (m *Message4) To() lindell17.Entity {
+	return lindell17.Party1
+}
+
+func (m *Message4) From() lindell17.Entity {
+	return lindell17.Party2
+}
+
+func (m *Message4) Protocol() lindell17.Protocol {
+	return lindell17.BatchSign
+}
+
+func (m *Message4) MessageId() int {
+	return 4
+}
+
+func (m *Message4) SessionId() string {
+	return m.Sid
+}
+
+func (m *Message4) BatchSize() int {
+	return len(m.R)
+}
+
+func (m *Message4) IsValid() bool {
+	if !lindell17.IsValidSessionId(m.Sid) ||
+		len(m.R) == 0 ||
+		len(m.Ciphertexts) != len(m.R) {
+		return false
+	}
+
+	// An item's values are either both set or both missing.
+	for i := range m.R {
+		if (m.R[i] == nil) != (m.Ciphertexts[i] == nil) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *Message4) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	for i := range m.R {
+		if m.R[i] == nil {
+			continue
+		}
+		if err := lindell17.CheckScalar(m.R[i], big.NewInt(1), curve.N()); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, lindell17.NewItemError(i, err))
+		}
+		if err := lindell17.CheckCiphertext(pk, m.Ciphertexts[i]); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, lindell17.NewItemError(i, err))
+		}
+	}
+
+	return nil
+}
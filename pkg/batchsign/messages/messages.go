@@ -0,0 +1,11 @@
+package messages
+
+import "github.com/primefactor-io/lindell17/pkg/lindell17"
+
+// Message is an interface that all messages of the batch signing protocol
+// implement.
+type Message interface {
+	lindell17.Message
+	// BatchSize returns the number of items the message carries values for.
+	BatchSize() int
+}
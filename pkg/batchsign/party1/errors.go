@@ -0,0 +1,41 @@
+package party1
+
+import "fmt"
+
+var (
+	// ErrEmptyBatch is returned if the batch doesn't contain any hash.
+	ErrEmptyBatch = fmt.Errorf("empty batch")
+	// ErrInvalidBatchSize is returned if a message's batch size doesn't match the number of hashes.
+	ErrInvalidBatchSize = fmt.Errorf("invalid batch size")
+	// ErrInvalidHashLength is returned if the hash length is invalid.
+	ErrInvalidHashLength = fmt.Errorf("invalid hash length")
+	// ErrSampleNonceK1 is returned if the random nonce k1 can't be sampled.
+	ErrSampleNonceK1 = fmt.Errorf("unable to sample random nonce k1")
+	// ErrComputeR1 is returned if R1 can't be computed.
+	ErrComputeR1 = fmt.Errorf("unable to compute R1")
+	// ErrCommitToR1 is returned if the commitment to R1 can't be computed.
+	ErrCommitToR1 = fmt.Errorf("unable to commit to R1")
+	// ErrGenerateR1DLKProof is returned if the R1 DLK proof can't be generated.
+	ErrGenerateR1DLKProof = fmt.Errorf("unable to generate R1 DLK proof")
+	// ErrInvalidR2DLKProof is returned if the R2 DLK proof is invalid.
+	ErrInvalidR2DLKProof = fmt.Errorf("invalid R2 DLK proof")
+	// ErrItemAborted is returned if party 2 aborted an item.
+	ErrItemAborted = fmt.Errorf("item aborted by party 2")
+	// ErrComputeR is returned if R can't be computed.
+	ErrComputeR = fmt.Errorf("unable to compute R")
+	// ErrInvalidR is returned if the recomputed r value doesn't match the partial signature's r value.
+	ErrInvalidR = fmt.Errorf("recomputed r doesn't equal r of partial signature")
+	// ErrDecryptCiphertext is returned if the ciphertext can't be decrypted.
+	ErrDecryptCiphertext = fmt.Errorf("unable to decrypt ciphertext")
+	// ErrZeroSPrime is returned if the decrypted s' is 0 mod q.
+	ErrZeroSPrime = fmt.Errorf("invalid ciphertext (s' = 0 mod q)")
+	// ErrInvertNonceK1 is returned if the nonce k1 can't be inverted.
+	ErrInvertNonceK1 = fmt.Errorf("unable to invert nonce k1")
+	// ErrComputeS is returned if s can't be computed.
+	ErrComputeS = fmt.Errorf("unable to compute s")
+	// ErrInvalidSignature is returned if the signature is invalid.
+	ErrInvalidSignature = fmt.Errorf("invalid signature")
+	// ErrBatchAborted is returned if an item's partial signature is invalid.
+	// It doesn't name the item, since the failure depends on the secret key.
+	ErrBatchAborted = fmt.Errorf("batch aborted (invalid partial signature)")
+)
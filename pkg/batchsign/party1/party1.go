@@ -0,0 +1,523 @@
+package party1
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/primefactor-io/commitment/pkg/hash"
+	"github.com/primefactor-io/ecc/pkg/ecdsa"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/keys"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/batchsign/messages"
+	"github.com/primefactor-io/lindell17/pkg/crt"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/scalar"
+	"github.com/primefactor-io/lindell17/pkg/utils"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	pKeys "github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// Party1 is an instance of party 1 that participates in the batch signing
+// protocol.
+type Party1 struct {
+	curve     weierstrass.Curve
+	field     *scalar.Field
+	sk        *pKeys.PrivateKey
+	decrypter *crt.Decrypter
+	crtErr    error
+	pk        *pKeys.PublicKey
+	qShared   *elliptic.Point
+	hashes    [][]byte
+	k1        []*big.Int
+	r1        []*elliptic.Point
+	r2        []*elliptic.Point
+	errs      []error
+	limits    *lindell17.Limits
+	recorder  lindell17.Recorder
+	tracer    *lindell17.Tracer
+	rand      io.Reader
+	messages  int
+	state     lindell17.State
+	outCh     chan<- lindell17.Message
+	resCh     chan<- lindell17.Result
+}
+
+// NewParty1 creates a new instance of party 1 that participates in the batch
+// signing protocol.
+func NewParty1(params *Params, hashes [][]byte, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) *Party1 {
+	return &Party1{
+		curve:     params.curve,
+		field:     params.field,
+		sk:        params.sk,
+		decrypter: params.decrypter,
+		crtErr:    params.crtErr,
+		pk:        pKeys.DerivePublicKey(params.sk),
+		qShared:   params.qShared,
+		hashes:    hashes,
+		errs:      make([]error, len(hashes)),
+		limits:    params.limits,
+		recorder:  params.recorder,
+		tracer:    lindell17.NewTracer(params.observer, lindell17.BatchSign, lindell17.Party1),
+		rand:      params.rand,
+		state:     lindell17.Start,
+		outCh:     outCh,
+		resCh:     resCh,
+	}
+}
+
+// Start starts party 1 of the protocol.
+// Returns an error if the current state is invalid, the batch is empty, a hash
+// has an invalid length or starting the protocol fails.
+func (p *Party1) Start() (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
+	// Validate state.
+	if p.state != lindell17.Start {
+		return false, lindell17.ErrInvalidState
+	}
+
+	// Generate session id.
+	bits := 128
+	sid, err := utils.GenerateSessionIdFromReader(p.rand, bits)
+	if err != nil {
+		return false, err
+	}
+	p.tracer.SetSessionId(sid)
+
+	// Check if the batch contains hashes.
+	if len(p.hashes) == 0 {
+		return false, ErrEmptyBatch
+	}
+
+	// Check if hashes have length of 256 bits.
+	for i, hash := range p.hashes {
+		if len(hash) != utils.HashLength {
+			return false, lindell17.NewItemError(i, ErrInvalidHashLength)
+		}
+	}
+
+	// Transition to next state.
+	p.setState(lindell17.Step1)
+
+	// Run step 1.
+	return p.step1(sid)
+}
+
+// Process processes an incoming protocol message.
+// Returns an error if the session's message limit is exceeded, the message
+// can't be recorded or the message was sent by the wrong sender, is invalid,
+// unknown, not intended for the protocol / recipient or carries values for a
+// different number of items.
+// After sending its result, party 1 returns a *lindell17.BatchError if any
+// item failed.
+func (p *Party1) Process(msg lindell17.Message) (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
+	// Enforce the session's message limit.
+	p.messages++
+	if p.messages > p.limits.MaxMessages {
+		return false, lindell17.ErrTooManyMessages
+	}
+
+	// Check message protocol.
+	if msg.Protocol() != lindell17.BatchSign {
+		return false, lindell17.ErrWrongProtocol
+	}
+
+	// Check message sender.
+	if msg.From() != lindell17.Party2 {
+		return false, lindell17.ErrWrongSender
+	}
+
+	// Check message recipient.
+	if msg.To() != lindell17.Party1 {
+		return false, lindell17.ErrWrongRecipient
+	}
+
+	// Record inbound message.
+	if p.recorder != nil {
+		if err := p.recorder.RecordReceived(msg); err != nil {
+			return false, fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	// Trace inbound message.
+	p.tracer.Received(msg)
+
+	// Check batch size before validating the message's items.
+	if m, ok := msg.(messages.Message); ok && m.BatchSize() != len(p.hashes) {
+		return false, ErrInvalidBatchSize
+	}
+
+	// Validate message.
+	if err := msg.ValidateFor(p.curve, p.pk); err != nil {
+		return false, err
+	}
+
+	// Process message.
+	switch msg.MessageId() {
+	case 2:
+		m, ok := msg.(*messages.Message2)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step2(m)
+	case 4:
+		m, ok := msg.(*messages.Message4)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step3(m)
+	default:
+		return false, lindell17.ErrUnknownMessage
+	}
+}
+
+// step1 runs party 1's first step of the protocol.
+// Returns an error if the current state is invalid or the step can't be run
+// properly.
+func (p *Party1) step1(sessionId string) (bool, error) {
+	// Validate state.
+	if p.state != lindell17.Step1 {
+		return false, lindell17.ErrInvalidState
+	}
+
+	n := len(p.hashes)
+	k1s := make([]*big.Int, n)
+	r1s := make([]*elliptic.Point, n)
+	cR1s := make([]*hash.Commitment, n)
+	pR1s := make([]*proofs.DLKProof, n)
+
+	for i := range n {
+		// Sample random partial nonce k1.
+		k1, err := random.Scalar(p.rand, p.curve)
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrSampleNonceK1, err)
+		}
+
+		// Compute R1.
+		r1, err := p.curve.ScalarMultiply(k1, p.curve.G()) // k1 * G
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrComputeR1, err)
+		}
+
+		// Commit to R1.
+		cR1, err := random.Commit(p.rand, r1.X.Bytes(), r1.Y.Bytes())
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrCommitToR1, err)
+		}
+
+		// Generate R1 DLK proof.
+		pR1, err := random.DLKProof(p.rand, p.curve, r1, k1)
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrGenerateR1DLKProof, err)
+		}
+
+		k1s[i] = k1
+		r1s[i] = r1
+		cR1s[i] = cR1
+		pR1s[i] = pR1
+	}
+
+	// Store k1 and R1 values.
+	p.k1 = k1s
+	p.r1 = r1s
+
+	// Transition to next state.
+	p.setState(lindell17.Step2)
+
+	// Send outbound message.
+	if err := p.send(messages.NewMessage1(sessionId, cR1s, pR1s)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// step2 runs party 1's second step of the protocol. Items whose R2 DLK proof
+// is invalid are aborted.
+// Returns an error if the current state is invalid or the step can't be run
+// properly.
+func (p *Party1) step2(msg *messages.Message2) (bool, error) {
+	sid := msg.SessionId()
+
+	// Validate state.
+	if p.state != lindell17.Step2 {
+		return false, lindell17.ErrInvalidState
+	}
+
+	r1s := make([]*elliptic.Point, len(p.hashes))
+
+	for i := range p.hashes {
+		// Verify R2 DLK proof.
+		isValid, err := proofs.VerifyDLKProof(p.curve, msg.PR2[i], msg.R2[i])
+		p.tracer.Proof("R2 DLK proof", err == nil && isValid)
+		if err != nil {
+			p.errs[i] = fmt.Errorf("%w: %w", ErrInvalidR2DLKProof, err)
+			continue
+		}
+		if !isValid {
+			p.errs[i] = ErrInvalidR2DLKProof
+			continue
+		}
+
+		// Fetch R1.
+		r1s[i] = p.r1[i]
+	}
+
+	// Store R2 values.
+	p.r2 = msg.R2
+
+	// Transition to next state.
+	p.setState(lindell17.Step3)
+
+	// Send outbound message.
+	if err := p.send(messages.NewMessage3(sid, r1s)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// step3 runs party 1's third step of the protocol.
+// Returns an error if the current state is invalid, the step can't be run
+// properly or any item failed.
+func (p *Party1) step3(msg *messages.Message4) (bool, error) {
+	sid := msg.SessionId()
+
+	// Validate state.
+	if p.state != lindell17.Step3 {
+		return false, lindell17.ErrInvalidState
+	}
+
+	// Check the partial signatures' r values before anything is decrypted.
+	rPoints := make([]*elliptic.Point, len(p.hashes))
+	for i := range p.hashes {
+		// Skip items that were aborted before.
+		if p.errs[i] != nil {
+			continue
+		}
+
+		if msg.R[i] == nil {
+			p.errs[i] = ErrItemAborted
+			continue
+		}
+
+		rP, err := p.deriveR(i, msg.R[i])
+		if err != nil {
+			p.errs[i] = err
+			continue
+		}
+
+		rPoints[i] = rP
+	}
+
+	// Sign every remaining item. A failure after decryption depends on the
+	// secret key, so it aborts the whole batch without naming the item.
+	// Every item is processed regardless, so that the failure isn't revealed
+	// by the time the run takes either.
+	signatures := make([]*ecdsa.Signature, len(p.hashes))
+	failed := false
+	for i, rP := range rPoints {
+		if rP == nil {
+			continue
+		}
+
+		signature, err := p.sign(i, rP, msg.Ciphertexts[i])
+		if err != nil {
+			failed = true
+			continue
+		}
+
+		signatures[i] = signature
+	}
+	if failed {
+		return false, ErrBatchAborted
+	}
+
+	// Send signatures over result channel.
+	if err := p.sendResult(NewResult(sid, signatures)); err != nil {
+		return false, err
+	}
+
+	// Wipe the nonces.
+	p.Destroy()
+
+	// Report failed items.
+	if batchErr := lindell17.NewBatchError(p.errs); batchErr != nil {
+		return false, batchErr
+	}
+
+	return true, nil
+}
+
+// deriveR derives the shared point R of the item with the given index and
+// checks it against the r value of party 2's partial signature.
+// Returns an error if R can't be derived or the r values don't match.
+func (p *Party1) deriveR(i int, rPartial *big.Int) (*elliptic.Point, error) {
+	// Derive shared point R.
+	rP, err := p.curve.ScalarMultiply(p.k1[i], p.r2[i]) // k1 * R2
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrComputeR, err)
+	}
+
+	// Compute r.
+	r := new(big.Int).Mod(rP.X, p.curve.N()) // R_x mod q
+
+	// Check if r of partial signature equals r.
+	if rPartial.Cmp(r) != 0 {
+		return nil, ErrInvalidR
+	}
+
+	return rP, nil
+}
+
+// sign computes the signature of the item with the given index from the shared
+// point R and the partial signature party 2 sent.
+// Returns an error if the signature can't be computed or is invalid.
+func (p *Party1) sign(i int, rP *elliptic.Point, ciphertext cipher.Ciphertext) (*ecdsa.Signature, error) {
+	// Compute r.
+	r := new(big.Int).Mod(rP.X, p.curve.N()) // R_x mod q
+
+	// Compute s.
+	sPrime, err := p.decrypt(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryptCiphertext, err)
+	}
+
+	// Compute v.
+	v := int(rP.Y.Bit(0)) // R_y & 1
+
+	// Compute secret-dependent values in constant time.
+	field := p.field
+	if field == nil {
+		return nil, fmt.Errorf("%w: %w", ErrComputeS, scalar.ErrInvalidOrder)
+	}
+
+	// Turn decrypted ciphertext into scalar.
+	in1 := field.NewScalar().SetBytes(sPrime) // s' mod q
+	defer in1.Wipe()
+	utils.WipeBytes(sPrime)
+
+	// Ensure that s' isn't 0 mod q (which would result in s = 0).
+	if in1.IsZero() == 1 {
+		return nil, ErrZeroSPrime
+	}
+
+	// Invert k1.
+	k1 := field.NewScalar().SetInt(p.k1[i])
+	defer k1.Wipe()
+	if k1.IsZero() == 1 {
+		return nil, ErrInvertNonceK1
+	}
+	in2 := field.NewScalar().Invert(k1) // k1^-1 mod q
+	defer in2.Wipe()
+
+	s1 := field.NewScalar().Mul(in1, in2) // s' * k1^-1 mod q
+
+	// s = min(s1, q - s1).
+	// Ensures that s is always smaller than half of the curve. v is inverted if
+	// s1 is negated. Both happen without branching on s1.
+	s := field.NewScalar()
+	v ^= s.SetLowS(s1)
+	s1.Wipe()
+
+	// Create signature.
+	signature := ecdsa.NewSignature(r, s.BigInt(), big.NewInt(int64(v)))
+
+	// Verify signature.
+	pk := (*keys.PublicKey)(p.qShared)
+	isValid, err := ecdsa.Verify(p.curve, pk, p.hashes[i], signature)
+	p.tracer.Proof("signature", err == nil && isValid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if !isValid {
+		return nil, ErrInvalidSignature
+	}
+
+	return signature, nil
+}
+
+// decrypt decrypts the ciphertext via the Chinese Remainder Theorem if the
+// decrypter is available and via the regular decryption otherwise. The
+// decryption time and the fallback are reported to the observer.
+// Returns an error if the ciphertext can't be decrypted.
+func (p *Party1) decrypt(ciphertext cipher.Ciphertext) (cipher.Plaintext, error) {
+	defer p.tracer.Operation("paillier_decryption", time.Now())
+
+	if p.decrypter != nil {
+		return p.decrypter.Decrypt(ciphertext)
+	}
+
+	p.tracer.Fallback("paillier_decryption", p.crtErr)
+
+	return cipher.Decrypt(p.sk, ciphertext)
+}
+
+// Destroy wipes party 1's ephemeral secrets, drops its references to the
+// long-term secrets and ends the protocol run, so that further calls fail with
+// lindell17.ErrInvalidState. Party 1 destroys itself once it sent its result
+// or a call failed, so Destroy only needs to be called to cancel a run.
+func (p *Party1) Destroy() {
+	utils.Wipe(p.k1...)
+
+	p.k1 = nil
+	p.sk = nil
+	p.decrypter = nil
+	p.state = lindell17.Destroyed
+	p.tracer.Destroyed()
+}
+
+// destroyOnError destroys party 1 if the call failed, since failed protocol
+// runs can't be resumed.
+func (p *Party1) destroyOnError(err *error) {
+	if *err != nil {
+		p.Destroy()
+	}
+}
+
+// setState transitions to the state and reports the transition.
+func (p *Party1) setState(state lindell17.State) {
+	p.state = state
+	p.tracer.Transition(state)
+}
+
+// send records the outbound message if a recorder is set, reports it to the
+// observer and sends it via the message channel.
+// Returns an error if the message can't be recorded.
+func (p *Party1) send(msg lindell17.Message) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordSent(msg); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Sent(msg)
+	p.outCh <- msg
+
+	return nil
+}
+
+// sendResult records the result if a recorder is set, reports it to the
+// observer and sends it via the result channel.
+// Returns an error if the result can't be recorded.
+func (p *Party1) sendResult(res lindell17.Result) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordResult(res); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Result(res)
+	p.resCh <- res
+
+	return nil
+}
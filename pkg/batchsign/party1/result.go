@@ -0,0 +1,36 @@
+package party1
+
+import (
+	"github.com/primefactor-io/ecc/pkg/ecdsa"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// Result is the result that party 1 computed.
+type Result struct {
+	// Sid is the session id.
+	Sid string
+	// Signatures are the full ECDSA signatures of the batch's hashes in the
+	// order of the hashes. The signatures of items that were aborted before
+	// decryption are nil.
+	Signatures []*ecdsa.Signature
+}
+
+// NewResult creates a new instance of a result that party 1 computed.
+func NewResult(sid string, signatures []*ecdsa.Signature) *Result {
+	return &Result{
+		Sid:        sid,
+		Signatures: signatures,
+	}
+}
+
+func (r *Result) From() lindell17.Entity {
+	return lindell17.Party1
+}
+
+func (r *Result) Protocol() lindell17.Protocol {
+	return lindell17.BatchSign
+}
+
+func (r *Result) SessionId() string {
+	return r.Sid
+}
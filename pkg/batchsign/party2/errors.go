@@ -0,0 +1,38 @@
+package party2
+
+import "fmt"
+
+var (
+	// ErrEmptyBatch is returned if the batch doesn't contain any hash.
+	ErrEmptyBatch = fmt.Errorf("empty batch")
+	// ErrInvalidBatchSize is returned if a message's batch size doesn't match the number of hashes.
+	ErrInvalidBatchSize = fmt.Errorf("invalid batch size")
+	// ErrInvalidHashLength is returned if the hash length is invalid.
+	ErrInvalidHashLength = fmt.Errorf("invalid hash length")
+	// ErrInvalidPayloadCount is returned if the number of payloads doesn't match the number of hashes.
+	ErrInvalidPayloadCount = fmt.Errorf("invalid number of payloads")
+	// ErrSampleNonceK2 is returned if the random nonce k2 can't be sampled.
+	ErrSampleNonceK2 = fmt.Errorf("unable to sample random nonce k2")
+	// ErrComputeR2 is returned if R2 can't be computed.
+	ErrComputeR2 = fmt.Errorf("unable to compute R2")
+	// ErrGenerateR2DLKProof is returned if the R2 DLK proof can't be generated.
+	ErrGenerateR2DLKProof = fmt.Errorf("unable to generate R2 DLK proof")
+	// ErrItemAborted is returned if party 1 aborted an item.
+	ErrItemAborted = fmt.Errorf("item aborted by party 1")
+	// ErrInvalidR1Commitment is returned if the R1 commitment is invalid.
+	ErrInvalidR1Commitment = fmt.Errorf("invalid R1 commitment")
+	// ErrInvalidR1DLKProof is returned if the R1 DLK proof is invalid.
+	ErrInvalidR1DLKProof = fmt.Errorf("invalid R1 DLK proof")
+	// ErrComputeR is returned if R can't be computed.
+	ErrComputeR = fmt.Errorf("unable to compute R")
+	// ErrSampleP is returned if p can't be sampled.
+	ErrSampleP = fmt.Errorf("unable to sample random p")
+	// ErrInvertNonceK2 is returned if the nonce k2 can't be inverted.
+	ErrInvertNonceK2 = fmt.Errorf("unable to invert nonce k2")
+	// ErrComputeC1 is returned if c1 can't be computed.
+	ErrComputeC1 = fmt.Errorf("unable to compute c1")
+	// ErrInvalidGCD is returned if the GCD is invalid.
+	ErrInvalidGCD = fmt.Errorf("invalid gcd (gcd(nonce, N) != 1)")
+	// ErrComputeC2 is returned if c2 can't be computed.
+	ErrComputeC2 = fmt.Errorf("unable to compute c2")
+)
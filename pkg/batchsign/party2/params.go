@@ -0,0 +1,110 @@
+package party2
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/scalar"
+	"github.com/primefactor-io/lindell17/pkg/utils"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// Params is an instance of parameters party 2 uses.
+type Params struct {
+	curve    weierstrass.Curve
+	field    *scalar.Field
+	pk       *keys.PublicKey
+	x1Enc    cipher.Ciphertext
+	x2       *big.Int
+	limits   *lindell17.Limits
+	recorder lindell17.Recorder
+	observer lindell17.Observer
+	rand     io.Reader
+	policy   lindell17.Policy
+	keyID    string
+	payloads []*lindell17.Payload
+}
+
+// NewParams creates a new instance of parameters party 2 uses.
+func NewParams(curve weierstrass.Curve, pk *keys.PublicKey, x1Enc cipher.Ciphertext, x2 *big.Int) *Params {
+	// Build the scalar field once, so that every protocol run can use it. It's
+	// nil if the curve's order is invalid, which fails the protocol runs.
+	field, _ := scalar.NewField(curve.N())
+
+	return &Params{
+		curve:  curve,
+		field:  field,
+		pk:     pk,
+		x1Enc:  x1Enc,
+		x2:     x2,
+		limits: lindell17.DefaultLimits(),
+		rand:   rand.Reader,
+	}
+}
+
+// Destroy wipes the key share the params hold. The params can't be used in
+// protocol runs afterwards.
+func (p *Params) Destroy() {
+	utils.Wipe(p.x2)
+}
+
+// WithLimits sets the resource limits party 2 enforces on incoming messages.
+// A nil value restores the default limits.
+// Returns the params to allow chaining.
+func (p *Params) WithLimits(limits *lindell17.Limits) *Params {
+	if limits == nil {
+		limits = lindell17.DefaultLimits()
+	}
+
+	p.limits = limits
+
+	return p
+}
+
+// WithRecorder sets the recorder party 2 records its transcript with.
+// Returns the params to allow chaining.
+func (p *Params) WithRecorder(recorder lindell17.Recorder) *Params {
+	p.recorder = recorder
+
+	return p
+}
+
+// WithObserver sets the observer party 2 reports the events of its
+// protocol runs to.
+// Returns the params to allow chaining.
+func (p *Params) WithObserver(observer lindell17.Observer) *Params {
+	p.observer = observer
+
+	return p
+}
+
+// WithRandomness sets the source of randomness party 2 uses.
+// Returns the params to allow chaining.
+func (p *Params) WithRandomness(reader io.Reader) *Params {
+	p.rand = reader
+
+	return p
+}
+
+// WithPolicy sets the signing policy party 2 evaluates on every item before it
+// contributes to the signatures and the id of the key the policy sees.
+// Returns the params to allow chaining.
+func (p *Params) WithPolicy(policy lindell17.Policy, keyID string) *Params {
+	p.policy = policy
+	p.keyID = keyID
+
+	return p
+}
+
+// WithPayloads sets the structured data the hashes were computed from (one
+// per hash), which the signing policy evaluates.
+// Returns the params to allow chaining.
+func (p *Params) WithPayloads(payloads []*lindell17.Payload) *Params {
+	p.payloads = payloads
+
+	return p
+}
@@ -0,0 +1,462 @@
+package party2
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/primefactor-io/commitment/pkg/hash"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/batchsign/messages"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/scalar"
+	"github.com/primefactor-io/lindell17/pkg/utils"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/homomorphic"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// Party2 is an instance of party 2 that participates in the batch signing
+// protocol.
+type Party2 struct {
+	curve    weierstrass.Curve
+	field    *scalar.Field
+	pk       *keys.PublicKey
+	x1Enc    cipher.Ciphertext
+	x2       *big.Int
+	hashes   [][]byte
+	k2       []*big.Int
+	cR1      []*hash.Commitment
+	pR1      []*proofs.DLKProof
+	errs     []error
+	limits   *lindell17.Limits
+	recorder lindell17.Recorder
+	tracer   *lindell17.Tracer
+	rand     io.Reader
+	policy   lindell17.Policy
+	keyID    string
+	payloads []*lindell17.Payload
+	messages int
+	state    lindell17.State
+	outCh    chan<- lindell17.Message
+	resCh    chan<- lindell17.Result
+}
+
+// NewParty2 creates a new instance of party 2 that participates in the batch
+// signing protocol.
+func NewParty2(params *Params, hashes [][]byte, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) *Party2 {
+	return &Party2{
+		curve:    params.curve,
+		field:    params.field,
+		pk:       params.pk,
+		x1Enc:    params.x1Enc,
+		x2:       params.x2,
+		hashes:   hashes,
+		errs:     make([]error, len(hashes)),
+		limits:   params.limits,
+		recorder: params.recorder,
+		tracer:   lindell17.NewTracer(params.observer, lindell17.BatchSign, lindell17.Party2),
+		rand:     params.rand,
+		policy:   params.policy,
+		keyID:    params.keyID,
+		payloads: params.payloads,
+		state:    lindell17.Start,
+		outCh:    outCh,
+		resCh:    resCh,
+	}
+}
+
+// Start starts party 2 of the protocol.
+// Returns an error if the current state is invalid, the batch is empty, a hash
+// has an invalid length, the number of payloads doesn't match, the signing
+// policy denies any item or starting the protocol fails.
+func (p *Party2) Start() (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
+	// Validate state.
+	if p.state != lindell17.Start {
+		return false, lindell17.ErrInvalidState
+	}
+
+	// Check if the batch contains hashes.
+	if len(p.hashes) == 0 {
+		return false, ErrEmptyBatch
+	}
+
+	// Check if hashes have length of 256 bits.
+	for i, hash := range p.hashes {
+		if len(hash) != utils.HashLength {
+			return false, lindell17.NewItemError(i, ErrInvalidHashLength)
+		}
+	}
+
+	// Enforce signing policy on every item. The batch is denied as a whole if
+	// the policy denies any of its items.
+	if p.policy != nil {
+		if p.payloads != nil && len(p.payloads) != len(p.hashes) {
+			return false, ErrInvalidPayloadCount
+		}
+		reqs := make([]*lindell17.SigningRequest, len(p.hashes))
+		for i, hash := range p.hashes {
+			var payload *lindell17.Payload
+			if p.payloads != nil {
+				payload = p.payloads[i]
+			}
+			reqs[i] = lindell17.NewSigningRequest(lindell17.BatchSign, p.keyID, hash, payload)
+		}
+		if err := lindell17.EnforceBatch(p.policy, reqs); err != nil {
+			return false, err
+		}
+	}
+
+	// Transition to next state.
+	p.setState(lindell17.Step1)
+
+	return true, nil
+}
+
+// Process processes an incoming protocol message.
+// Returns an error if the session's message limit is exceeded, the message
+// can't be recorded or the message was sent by the wrong sender, is invalid,
+// unknown, not intended for the protocol / recipient or carries values for a
+// different number of items.
+// After sending its result, party 2 returns a *lindell17.BatchError if any
+// item failed.
+func (p *Party2) Process(msg lindell17.Message) (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
+	// Enforce the session's message limit.
+	p.messages++
+	if p.messages > p.limits.MaxMessages {
+		return false, lindell17.ErrTooManyMessages
+	}
+
+	// Check message protocol.
+	if msg.Protocol() != lindell17.BatchSign {
+		return false, lindell17.ErrWrongProtocol
+	}
+
+	// Check message sender.
+	if msg.From() != lindell17.Party1 {
+		return false, lindell17.ErrWrongSender
+	}
+
+	// Check message recipient.
+	if msg.To() != lindell17.Party2 {
+		return false, lindell17.ErrWrongRecipient
+	}
+
+	// Record inbound message.
+	if p.recorder != nil {
+		if err := p.recorder.RecordReceived(msg); err != nil {
+			return false, fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	// Trace inbound message.
+	p.tracer.Received(msg)
+
+	// Check batch size before validating the message's items.
+	if m, ok := msg.(messages.Message); ok && m.BatchSize() != len(p.hashes) {
+		return false, ErrInvalidBatchSize
+	}
+
+	// Validate message.
+	if err := msg.ValidateFor(p.curve, p.pk); err != nil {
+		return false, err
+	}
+
+	// Process message.
+	switch msg.MessageId() {
+	case 1:
+		m, ok := msg.(*messages.Message1)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step1(m)
+	case 3:
+		m, ok := msg.(*messages.Message3)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step2(m)
+	default:
+		return false, lindell17.ErrUnknownMessage
+	}
+}
+
+// step1 runs party 2's first step of the protocol.
+// Returns an error if the current state is invalid or the step can't be run
+// properly.
+func (p *Party2) step1(msg *messages.Message1) (bool, error) {
+	sid := msg.SessionId()
+
+	// Validate state.
+	if p.state != lindell17.Step1 {
+		return false, lindell17.ErrInvalidState
+	}
+
+	n := len(p.hashes)
+	k2s := make([]*big.Int, n)
+	r2s := make([]*elliptic.Point, n)
+	pR2s := make([]*proofs.DLKProof, n)
+
+	for i := range n {
+		// Sample random partial nonce k2.
+		k2, err := random.Scalar(p.rand, p.curve)
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrSampleNonceK2, err)
+		}
+
+		// Compute R2.
+		r2, err := p.curve.ScalarMultiply(k2, p.curve.G()) // k2 * G
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrComputeR2, err)
+		}
+
+		// Generate R2 DLK proof.
+		pR2, err := random.DLKProof(p.rand, p.curve, r2, k2)
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrGenerateR2DLKProof, err)
+		}
+
+		k2s[i] = k2
+		r2s[i] = r2
+		pR2s[i] = pR2
+	}
+
+	// Store commitments to R1 and R1 DLK proofs.
+	p.cR1 = msg.CR1
+	p.pR1 = msg.PR1
+
+	// Store k2 values.
+	p.k2 = k2s
+
+	// Transition to next state.
+	p.setState(lindell17.Step2)
+
+	// Send outbound message.
+	if err := p.send(messages.NewMessage2(sid, r2s, pR2s)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// step2 runs party 2's second step of the protocol. Items whose R1 value
+// doesn't match its commitment or DLK proof are aborted.
+// Returns an error if the current state is invalid, the step can't be run
+// properly or any item failed.
+func (p *Party2) step2(msg *messages.Message3) (bool, error) {
+	sid := msg.SessionId()
+
+	// Validate state.
+	if p.state != lindell17.Step2 {
+		return false, lindell17.ErrInvalidState
+	}
+
+	rs := make([]*big.Int, len(p.hashes))
+	c3s := make([]cipher.Ciphertext, len(p.hashes))
+
+	for i, r1 := range msg.R1 {
+		if r1 == nil {
+			p.errs[i] = ErrItemAborted
+			continue
+		}
+
+		// Verify commitment to R1.
+		isValid := hash.Verify(p.cR1[i], r1.X.Bytes(), r1.Y.Bytes())
+		p.tracer.Proof("R1 commitment", isValid)
+		if !isValid {
+			p.errs[i] = ErrInvalidR1Commitment
+			continue
+		}
+
+		// Verify R1 DLK proof.
+		isValid, err := proofs.VerifyDLKProof(p.curve, p.pR1[i], r1)
+		p.tracer.Proof("R1 DLK proof", err == nil && isValid)
+		if err != nil {
+			p.errs[i] = fmt.Errorf("%w: %w", ErrInvalidR1DLKProof, err)
+			continue
+		}
+		if !isValid {
+			p.errs[i] = ErrInvalidR1DLKProof
+			continue
+		}
+
+		// Party 2's own computations don't depend on party 1's behavior, so
+		// their failure aborts the whole batch.
+		r, c3, err := p.partialSign(i, r1)
+		if err != nil {
+			return false, lindell17.NewItemError(i, err)
+		}
+
+		rs[i] = r
+		c3s[i] = c3
+	}
+
+	// Send outbound message.
+	if err := p.send(messages.NewMessage4(sid, rs, c3s)); err != nil {
+		return false, err
+	}
+
+	// Send partial signatures over result channel.
+	if err := p.sendResult(NewResult(sid, rs, c3s)); err != nil {
+		return false, err
+	}
+
+	// Wipe the nonces.
+	p.Destroy()
+
+	// Report failed items.
+	if batchErr := lindell17.NewBatchError(p.errs); batchErr != nil {
+		return false, batchErr
+	}
+
+	return true, nil
+}
+
+// partialSign computes the r value and the encrypted partial signature of the
+// item with the given index.
+// Returns an error if the partial signature can't be computed.
+func (p *Party2) partialSign(i int, r1 *elliptic.Point) (*big.Int, cipher.Ciphertext, error) {
+	// Derive shared point R.
+	rP, err := p.curve.ScalarMultiply(p.k2[i], r1) // k2 * R1
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrComputeR, err)
+	}
+
+	// Compute r.
+	r := new(big.Int).Mod(rP.X, p.curve.N()) // R_x mod q
+
+	// Sample random p from Z_q^2.
+	qq := new(big.Int).Mul(p.curve.N(), p.curve.N()) // q^2
+	randP, err := random.Int(p.rand, qq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrSampleP, err)
+	}
+
+	// Turn hash into big integer.
+	z := new(big.Int).SetBytes(p.hashes[i])
+
+	// Compute secret-dependent values in constant time.
+	field := p.field
+	if field == nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrComputeC1, scalar.ErrInvalidOrder)
+	}
+
+	// Invert k2.
+	k2 := field.NewScalar().SetInt(p.k2[i])
+	defer k2.Wipe()
+	if k2.IsZero() == 1 {
+		return nil, nil, ErrInvertNonceK2
+	}
+	k2Inv := field.NewScalar().Invert(k2) // k2^-1 mod q
+	defer k2Inv.Wipe()
+
+	// Compute c1.
+	in1 := field.NewScalar().Mul(field.NewScalar().SetInt(z), k2Inv) // z * k2^-1 mod q
+	defer in1.Wipe()
+	// The Paillier encryption needs big integers.
+	in2 := in1.BigInt()
+	in3 := new(big.Int).Mul(randP, p.curve.N()) // p * q
+	res := new(big.Int).Add(in2, in3)           // (z * k2^-1 mod q) + (p * q)
+	defer utils.Wipe(randP, in2, in3, res)
+	c1, nonce, err := random.Encrypt(p.rand, p.pk, res.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrComputeC1, err)
+	}
+
+	// Ensure that gcd(nonce, N) = 1.
+	gcd, _, _ := utils.ExtendedEuclidean(nonce, p.pk.N)
+	if gcd.Cmp(big.NewInt(1)) != 0 {
+		return nil, nil, ErrInvalidGCD
+	}
+	defer utils.Wipe(nonce)
+
+	// Compute v.
+	x2 := field.NewScalar().SetInt(p.x2)
+	defer x2.Wipe()
+	in4 := field.NewScalar().Mul(field.NewScalar().SetInt(r), k2Inv) // r * k2^-1 mod q
+	in5 := field.NewScalar().Mul(in4, x2)                            // r * k2^-1 * x2 mod q
+	defer in4.Wipe()
+	defer in5.Wipe()
+	// The homomorphic multiplication needs a big integer.
+	v := in5.BigInt()
+	defer utils.Wipe(v)
+
+	// Compute c2.
+	c2, err := homomorphic.MultiplyPlaintextValue(p.pk, p.x1Enc, v.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrComputeC2, err)
+	}
+
+	// Compute c3.
+	c3 := homomorphic.AddPlaintextValues(p.pk, c1, c2)
+
+	return r, c3, nil
+}
+
+// Destroy wipes party 2's ephemeral secrets, drops its references to the
+// long-term secrets and ends the protocol run, so that further calls fail with
+// lindell17.ErrInvalidState. Party 2 destroys itself once it sent its result
+// or a call failed, so Destroy only needs to be called to cancel a run.
+func (p *Party2) Destroy() {
+	utils.Wipe(p.k2...)
+
+	p.k2 = nil
+	p.x2 = nil
+	p.state = lindell17.Destroyed
+	p.tracer.Destroyed()
+}
+
+// destroyOnError destroys party 2 if the call failed, since failed protocol
+// runs can't be resumed.
+func (p *Party2) destroyOnError(err *error) {
+	if *err != nil {
+		p.Destroy()
+	}
+}
+
+// setState transitions to the state and reports the transition.
+func (p *Party2) setState(state lindell17.State) {
+	p.state = state
+	p.tracer.Transition(state)
+}
+
+// send records the outbound message if a recorder is set, reports it to the
+// observer and sends it via the message channel.
+// Returns an error if the message can't be recorded.
+func (p *Party2) send(msg lindell17.Message) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordSent(msg); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Sent(msg)
+	p.outCh <- msg
+
+	return nil
+}
+
+// sendResult records the result if a recorder is set, reports it to the
+// observer and sends it via the result channel.
+// Returns an error if the result can't be recorded.
+func (p *Party2) sendResult(res lindell17.Result) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordResult(res); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Result(res)
+	p.resCh <- res
+
+	return nil
+}
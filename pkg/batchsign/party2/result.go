@@ -0,0 +1,41 @@
+package party2
+
+import (
+	"math/big"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+)
+
+// Result is the result that party 2 computed.
+type Result struct {
+	// Sid is the session id.
+	Sid string
+	// R are the signatures' r values in the order of the hashes. The values of
+	// failed items are nil.
+	R []*big.Int
+	// Ciphertexts are the encryptions of k2^-1 * (z + (r * x1 * x2)) + (p * q)
+	// in the order of the hashes. The values of failed items are nil.
+	Ciphertexts []cipher.Ciphertext
+}
+
+// NewResult creates a new instance of a result that party 2 computed.
+func NewResult(sid string, r []*big.Int, ciphertexts []cipher.Ciphertext) *Result {
+	return &Result{
+		Sid:         sid,
+		R:           r,
+		Ciphertexts: ciphertexts,
+	}
+}
+
+func (r *Result) From() lindell17.Entity {
+	return lindell17.Party2
+}
+
+func (r *Result) Protocol() lindell17.Protocol {
+	return lindell17.BatchSign
+}
+
+func (r *Result) SessionId() string {
+	return r.Sid
+}
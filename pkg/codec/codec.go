@@ -0,0 +1,184 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// messageEnvelope is the encoding of a message together with the information
+// that's necessary to decode it.
+type messageEnvelope struct {
+	Protocol  lindell17.Protocol `json:"protocol"`
+	MessageId int                `json:"id"`
+	Payload   json.RawMessage    `json:"payload"`
+}
+
+// resultEnvelope is the encoding of a result together with the information
+// that's necessary to decode it.
+type resultEnvelope struct {
+	Protocol lindell17.Protocol `json:"protocol"`
+	From     lindell17.Entity   `json:"from"`
+	Payload  json.RawMessage    `json:"payload"`
+}
+
+// MarshalMessage encodes the message.
+// Returns an error if the message type is unknown or can't be encoded.
+func MarshalMessage(msg lindell17.Message) ([]byte, error) {
+	if msg == nil || reflect.ValueOf(msg).IsNil() {
+		return nil, ErrEncode
+	}
+
+	key := messageKey{msg.Protocol(), msg.MessageId()}
+	if messageTypes[key] != reflect.TypeOf(msg).Elem() {
+		return nil, ErrUnknownType
+	}
+
+	payload, err := marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := messageEnvelope{
+		Protocol:  msg.Protocol(),
+		MessageId: msg.MessageId(),
+		Payload:   payload,
+	}
+
+	return json.Marshal(envelope)
+}
+
+// UnmarshalMessage decodes a message that was encoded via MarshalMessage.
+// Returns an error if the message type is unknown or can't be decoded.
+func UnmarshalMessage(data []byte) (lindell17.Message, error) {
+	var envelope messageEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	typ, ok := messageTypes[messageKey{envelope.Protocol, envelope.MessageId}]
+	if !ok {
+		return nil, ErrUnknownType
+	}
+
+	value, err := unmarshal(envelope.Payload, typ)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(lindell17.Message), nil
+}
+
+// MarshalResult encodes the result.
+// Returns an error if the result type is unknown or can't be encoded.
+func MarshalResult(res lindell17.Result) ([]byte, error) {
+	if res == nil || reflect.ValueOf(res).IsNil() {
+		return nil, ErrEncode
+	}
+
+	key := resultKey{res.Protocol(), res.From()}
+	if resultTypes[key] != reflect.TypeOf(res).Elem() {
+		return nil, ErrUnknownType
+	}
+
+	payload, err := marshal(res)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := resultEnvelope{
+		Protocol: res.Protocol(),
+		From:     res.From(),
+		Payload:  payload,
+	}
+
+	return json.Marshal(envelope)
+}
+
+// UnmarshalResult decodes a result that was encoded via MarshalResult.
+// Returns an error if the result type is unknown or can't be decoded.
+func UnmarshalResult(data []byte) (lindell17.Result, error) {
+	var envelope resultEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	typ, ok := resultTypes[resultKey{envelope.Protocol, envelope.From}]
+	if !ok {
+		return nil, ErrUnknownType
+	}
+
+	value, err := unmarshal(envelope.Payload, typ)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(lindell17.Result), nil
+}
+
+// MarshalValue encodes the value the pointer points to, e.g. a proof or a
+// signature. Other than messages and results, values aren't wrapped in an
+// envelope, so their type needs to be known when they're decoded.
+// Returns an error if the value can't be encoded.
+func MarshalValue(ptr any) ([]byte, error) {
+	v := reflect.ValueOf(ptr)
+	if ptr == nil || v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil, ErrEncode
+	}
+
+	return marshal(ptr)
+}
+
+// UnmarshalValue decodes a value that was encoded via MarshalValue into the
+// value the pointer points to.
+// Returns an error if the data doesn't match the value's type.
+func UnmarshalValue(data []byte, ptr any) error {
+	v := reflect.ValueOf(ptr)
+	if ptr == nil || v.Kind() != reflect.Pointer || v.IsNil() {
+		return ErrDecode
+	}
+
+	value, err := unmarshal(data, v.Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	v.Elem().Set(reflect.ValueOf(value).Elem())
+
+	return nil
+}
+
+// marshal encodes the value the pointer points to.
+// Returns an error if the value can't be encoded.
+func marshal(ptr any) ([]byte, error) {
+	tree, err := encodeValue(reflect.ValueOf(ptr).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrEncode, err)
+	}
+
+	return bz, nil
+}
+
+// unmarshal decodes the data into a new value of the given type and returns a
+// pointer to it.
+// Returns an error if the data can't be decoded.
+func unmarshal(data []byte, typ reflect.Type) (any, error) {
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	ptr := reflect.New(typ)
+	if err := decodeValue(tree, ptr.Elem()); err != nil {
+		return nil, err
+	}
+
+	return ptr.Interface(), nil
+}
@@ -0,0 +1,224 @@
+package codec_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/schnorr"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	"github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+const paillierBits = 1024
+const rangeProofBits = 40
+const nthRootProofBits = 128
+
+var secp256k1 = curves.Secp256k1
+
+func TestCodec(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Key Generation (round trip)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		p1Params := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits)
+		p2Params := party2.NewParams(secp256k1, rangeProofBits, nthRootProofBits)
+
+		p1 := party1.NewParty1(p1Params, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+		var counter int
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				// Only deliver messages that went through the codec.
+				data, err := codec.MarshalMessage(msg)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				decoded, err := codec.UnmarshalMessage(data)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+
+				switch decoded.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(decoded); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(decoded); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				t.Fatalf("expected no error, got %v", err)
+			case result := <-resCh:
+				counter++
+
+				data, err := codec.MarshalResult(result)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				decoded, err := codec.UnmarshalResult(data)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				reencoded, _ := codec.MarshalResult(decoded)
+
+				if !bytes.Equal(data, reencoded) {
+					t.Fatal("Result round trip failed")
+				}
+
+				if counter == 2 {
+					break coord
+				}
+			}
+		}
+	})
+
+	t.Run("MarshalValue / UnmarshalValue (round trip)", func(t *testing.T) {
+		t.Parallel()
+
+		x, _ := secp256k1.GetRandomScalar()
+		point, _ := secp256k1.ScalarMultiply(x, secp256k1.G())
+		proof, _ := proofs.GenerateDLKProof(secp256k1, point, x)
+
+		data, err := codec.MarshalValue(proof)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		decoded := new(proofs.DLKProof)
+		if err := codec.UnmarshalValue(data, decoded); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		isValid, _ := proofs.VerifyDLKProof(secp256k1, decoded, point)
+		if !isValid {
+			t.Fatal("Decoded proof doesn't verify")
+		}
+	})
+
+	t.Run("UnmarshalValue - Invalid (Not a pointer)", func(t *testing.T) {
+		t.Parallel()
+
+		err := codec.UnmarshalValue([]byte(`{}`), proofs.DLKProof{})
+		if !errors.Is(err, codec.ErrDecode) {
+			t.Fatalf("want error %v, got %v", codec.ErrDecode, err)
+		}
+	})
+
+	t.Run("UnmarshalValue - Invalid (Unknown field)", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"e":"01","s":"02","x":"03"}`)
+
+		err := codec.UnmarshalValue(data, new(proofs.DLKProof))
+		if !errors.Is(err, codec.ErrDecode) {
+			t.Fatalf("want error %v, got %v", codec.ErrDecode, err)
+		}
+	})
+
+	t.Run("UnmarshalValue - Invalid (Missing field)", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"e":"01"}`)
+
+		err := codec.UnmarshalValue(data, new(proofs.DLKProof))
+		if !errors.Is(err, codec.ErrDecode) {
+			t.Fatalf("want error %v, got %v", codec.ErrDecode, err)
+		}
+	})
+
+	t.Run("UnmarshalMessage - Invalid (Unknown field)", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"protocol":2,"id":4,"payload":{"Sid":"00","R":"01","Ciphertext":"00","Extra":"00"}}`)
+
+		_, err := codec.UnmarshalMessage(data)
+		if !errors.Is(err, codec.ErrDecode) {
+			t.Fatalf("want error %v, got %v", codec.ErrDecode, err)
+		}
+	})
+
+	t.Run("UnmarshalMessage - Invalid (Missing field)", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"protocol":2,"id":4,"payload":{"Sid":"00","R":"01"}}`)
+
+		_, err := codec.UnmarshalMessage(data)
+		if !errors.Is(err, codec.ErrDecode) {
+			t.Fatalf("want error %v, got %v", codec.ErrDecode, err)
+		}
+	})
+
+	t.Run("MarshalValue - Invalid (Unsupported type)", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := codec.MarshalValue(new(schnorr.Signature))
+		if !errors.Is(err, codec.ErrUnsupportedType) {
+			t.Fatalf("want error %v, got %v", codec.ErrUnsupportedType, err)
+		}
+	})
+
+	t.Run("UnmarshalMessage - Invalid (Unknown type)", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"protocol":42,"id":1,"payload":{}}`)
+
+		_, err := codec.UnmarshalMessage(data)
+		if !errors.Is(err, codec.ErrUnknownType) {
+			t.Fatalf("want error %v, got %v", codec.ErrUnknownType, err)
+		}
+	})
+
+	t.Run("UnmarshalMessage - Invalid (Malformed payload)", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"protocol":2,"id":4,"payload":{"Sid":"00","R":"xyz","Ciphertext":"00"}}`)
+
+		_, err := codec.UnmarshalMessage(data)
+		if !errors.Is(err, codec.ErrDecode) {
+			t.Fatalf("want error %v, got %v", codec.ErrDecode, err)
+		}
+	})
+}
+
+func FuzzUnmarshalMessage(f *testing.F) {
+	f.Add([]byte(`{"protocol":2,"id":4,"payload":{"Sid":"00","R":"01","Ciphertext":"00"}}`))
+	f.Add([]byte(`{"protocol":1,"id":3,"payload":{"Sid":"00","Q1":{"X":"01","Y":"02"}}}`))
+	f.Add([]byte(`{"protocol":0,"id":1,"payload":{"CRandVals":{"hash":"00","nonce":"00"}}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg, err := codec.UnmarshalMessage(data)
+		if err != nil {
+			return
+		}
+
+		// Decoded messages need to be encodable and safe to validate.
+		if _, err := codec.MarshalMessage(msg); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		msg.ValidateFor(secp256k1, nil)
+	})
+}
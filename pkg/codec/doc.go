@@ -0,0 +1,22 @@
+/*
+Package codec implements a canonical JSON encoding for protocol messages and
+results so that they can be persisted or sent over the wire.
+
+Types of this module are encoded via their exported fields and may not have
+unexported ones. Types of other modules (e.g. points, Paillier keys, proofs
+and commitments) are encoded via an explicit schema that is registered per
+type and decodes values via the library's constructors. Decoding rejects
+objects with unknown or missing fields. Big integers, byte slices and byte
+arrays are encoded as hex strings and object fields are encoded in a
+deterministic (sorted) order, so equal values always result in equal
+encodings.
+
+The libraries' proofs and commitments don't export accessors for their fields
+and the Range proof can't be constructed outside of its package, so their
+schemas access the unexported fields by name via package opaque. The access
+fails with ErrUnsupportedType if a library's layout changes.
+
+MarshalValue and UnmarshalValue encode other values (e.g. proofs or
+signatures) the same way, but without an envelope that identifies their type.
+*/
+package codec
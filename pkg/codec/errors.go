@@ -0,0 +1,14 @@
+package codec
+
+import "fmt"
+
+var (
+	// ErrUnknownType is returned if the message or result type isn't registered.
+	ErrUnknownType = fmt.Errorf("unknown message or result type")
+	// ErrUnsupportedType is returned if a value's type can't be encoded.
+	ErrUnsupportedType = fmt.Errorf("unsupported type")
+	// ErrEncode is returned if a value can't be encoded.
+	ErrEncode = fmt.Errorf("unable to encode value")
+	// ErrDecode is returned if a value can't be decoded.
+	ErrDecode = fmt.Errorf("unable to decode value")
+)
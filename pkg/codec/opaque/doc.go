@@ -0,0 +1,14 @@
+/*
+Package opaque reads and writes the unexported fields of the libraries'
+commitment and proof types.
+
+The types have constructors, but neither export their fields nor accessors for
+them, and the Range proof's constructor takes unexported types so that it can't
+be called outside of its package. The codec needs to read the fields to encode
+the values and random needs to build Range proofs from their parts, so both use
+this package, which is the only place that accesses unexported fields. Every
+field is looked up by name and checked against the type it's expected to have,
+so a change of a library's layout results in ErrLayout rather than in a misread
+value.
+*/
+package opaque
@@ -0,0 +1,9 @@
+package opaque
+
+import "fmt"
+
+var (
+	// ErrLayout is returned if a library type's layout doesn't match the
+	// expected one.
+	ErrLayout = fmt.Errorf("unexpected type layout")
+)
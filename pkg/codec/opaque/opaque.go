@@ -0,0 +1,182 @@
+package opaque
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"unsafe"
+
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	pProofs "github.com/primefactor-io/paillier/pkg/proofs"
+)
+
+// RangeProofPair is a pair of Range proof data. J is 0 if both values and
+// nonces are revealed and 1 or 2 if only the first or second pair is.
+type RangeProofPair struct {
+	J              int
+	W1, R1, W2, R2 *big.Int
+}
+
+// RangeCiphertextPair is a pair of Range proof ciphertexts.
+type RangeCiphertextPair struct {
+	C1, C2 cipher.Ciphertext
+}
+
+// Read reads the unexported fields of the struct x points to into the targets
+// (pointers), which are keyed by field name.
+// Returns an error if a field doesn't exist or if its type doesn't match.
+func Read(x any, targets map[string]any) error {
+	v := reflect.ValueOf(x).Elem()
+
+	for name, target := range targets {
+		field, err := unexported(v, name)
+		if err != nil {
+			return err
+		}
+
+		dst := reflect.ValueOf(target).Elem()
+		if field.Type() != dst.Type() {
+			return layoutError(v.Type(), name)
+		}
+		dst.Set(field)
+	}
+
+	return nil
+}
+
+// Write sets the unexported fields of the struct x points to to the values,
+// which are keyed by field name.
+// Returns an error if a field doesn't exist or if its type doesn't match.
+func Write(x any, values map[string]any) error {
+	v := reflect.ValueOf(x).Elem()
+
+	for name, value := range values {
+		field, err := unexported(v, name)
+		if err != nil {
+			return err
+		}
+
+		src := reflect.ValueOf(value)
+		if field.Type() != src.Type() {
+			return layoutError(v.Type(), name)
+		}
+		field.Set(src)
+	}
+
+	return nil
+}
+
+// ReadRangeProof returns the proof and ciphertext pairs of the Range proof.
+// Returns an error if the Range proof's layout doesn't match.
+func ReadRangeProof(x *pProofs.RangeProof) ([]RangeProofPair, []RangeCiphertextPair, error) {
+	v := reflect.ValueOf(x).Elem()
+
+	proofs, err := slice(v, "proofPairs")
+	if err != nil {
+		return nil, nil, err
+	}
+	ciphertexts, err := slice(v, "ciphertextPairs")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var proofPairs []RangeProofPair
+	if !proofs.IsNil() {
+		proofPairs = make([]RangeProofPair, proofs.Len())
+	}
+	for i := range proofPairs {
+		pair := &proofPairs[i]
+		targets := map[string]any{"j": &pair.J, "w1": &pair.W1, "r1": &pair.R1, "w2": &pair.W2, "r2": &pair.R2}
+		if err := Read(proofs.Index(i).Addr().Interface(), targets); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var ciphertextPairs []RangeCiphertextPair
+	if !ciphertexts.IsNil() {
+		ciphertextPairs = make([]RangeCiphertextPair, ciphertexts.Len())
+	}
+	for i := range ciphertextPairs {
+		pair := &ciphertextPairs[i]
+		targets := map[string]any{"c1": &pair.C1, "c2": &pair.C2}
+		if err := Read(ciphertexts.Index(i).Addr().Interface(), targets); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return proofPairs, ciphertextPairs, nil
+}
+
+// NewRangeProof creates a Range proof from its proof and ciphertext pairs.
+// Returns an error if the Range proof's layout doesn't match.
+func NewRangeProof(proofPairs []RangeProofPair, ciphertextPairs []RangeCiphertextPair) (*pProofs.RangeProof, error) {
+	x := new(pProofs.RangeProof)
+	v := reflect.ValueOf(x).Elem()
+
+	proofs, err := slice(v, "proofPairs")
+	if err != nil {
+		return nil, err
+	}
+	ciphertexts, err := slice(v, "ciphertextPairs")
+	if err != nil {
+		return nil, err
+	}
+
+	if proofPairs != nil {
+		proofs.Set(reflect.MakeSlice(proofs.Type(), len(proofPairs), len(proofPairs)))
+	}
+	for i, pair := range proofPairs {
+		values := map[string]any{"j": pair.J, "w1": pair.W1, "r1": pair.R1, "w2": pair.W2, "r2": pair.R2}
+		if err := Write(proofs.Index(i).Addr().Interface(), values); err != nil {
+			return nil, err
+		}
+	}
+
+	if ciphertextPairs != nil {
+		ciphertexts.Set(reflect.MakeSlice(ciphertexts.Type(), len(ciphertextPairs), len(ciphertextPairs)))
+	}
+	for i, pair := range ciphertextPairs {
+		values := map[string]any{"c1": pair.C1, "c2": pair.C2}
+		if err := Write(ciphertexts.Index(i).Addr().Interface(), values); err != nil {
+			return nil, err
+		}
+	}
+
+	return x, nil
+}
+
+// unexported returns the unexported field of the struct value v so that it can
+// be read and set. v needs to be addressable.
+// Returns an error if the field doesn't exist.
+func unexported(v reflect.Value, name string) (reflect.Value, error) {
+	if v.Kind() != reflect.Struct || !v.CanAddr() {
+		return reflect.Value{}, layoutError(v.Type(), name)
+	}
+
+	field := v.FieldByName(name)
+	if !field.IsValid() || field.CanInterface() {
+		return reflect.Value{}, layoutError(v.Type(), name)
+	}
+
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem(), nil
+}
+
+// slice returns the unexported slice of structs with the given name.
+// Returns an error if the field doesn't exist or isn't a slice of structs.
+func slice(v reflect.Value, name string) (reflect.Value, error) {
+	field, err := unexported(v, name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if field.Kind() != reflect.Slice || field.Type().Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, layoutError(v.Type(), name)
+	}
+
+	return field, nil
+}
+
+// layoutError returns the error for a type whose layout doesn't match.
+func layoutError(t reflect.Type, name string) error {
+	return fmt.Errorf("%w: %v (unexpected field %s)", ErrLayout, t, name)
+}
@@ -0,0 +1,94 @@
+package codec
+
+import (
+	"reflect"
+
+	adaptorMessages "github.com/primefactor-io/lindell17/pkg/adaptor/messages"
+	adaptorParty1 "github.com/primefactor-io/lindell17/pkg/adaptor/party1"
+	adaptorParty2 "github.com/primefactor-io/lindell17/pkg/adaptor/party2"
+	batchMessages "github.com/primefactor-io/lindell17/pkg/batchsign/messages"
+	batchParty1 "github.com/primefactor-io/lindell17/pkg/batchsign/party1"
+	batchParty2 "github.com/primefactor-io/lindell17/pkg/batchsign/party2"
+	dlencMessages "github.com/primefactor-io/lindell17/pkg/dlenc_proof/messages"
+	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/prover"
+	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/verifier"
+	keygenMessages "github.com/primefactor-io/lindell17/pkg/keygen/messages"
+	keygenParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	keygenParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	signMessages "github.com/primefactor-io/lindell17/pkg/sign/messages"
+	signParty1 "github.com/primefactor-io/lindell17/pkg/sign/party1"
+	signParty2 "github.com/primefactor-io/lindell17/pkg/sign/party2"
+)
+
+// messageKey identifies a message type.
+type messageKey struct {
+	protocol  lindell17.Protocol
+	messageId int
+}
+
+// resultKey identifies a result type.
+type resultKey struct {
+	protocol lindell17.Protocol
+	from     lindell17.Entity
+}
+
+var messageTypes = make(map[messageKey]reflect.Type)
+var resultTypes = make(map[resultKey]reflect.Type)
+
+func init() {
+	registerMessages(
+		&dlencMessages.Message1{},
+		&dlencMessages.Message2{},
+		&dlencMessages.Message3{},
+		&dlencMessages.Message4{},
+		&keygenMessages.Message1{},
+		&keygenMessages.Message2{},
+		&keygenMessages.Message3{},
+		&keygenMessages.Message4{},
+		&keygenMessages.Message5{},
+		&keygenMessages.Message6{},
+		&keygenMessages.Message7{},
+		&signMessages.Message1{},
+		&signMessages.Message2{},
+		&signMessages.Message3{},
+		&signMessages.Message4{},
+		&adaptorMessages.Message1{},
+		&adaptorMessages.Message2{},
+		&adaptorMessages.Message3{},
+		&adaptorMessages.Message4{},
+		&batchMessages.Message1{},
+		&batchMessages.Message2{},
+		&batchMessages.Message3{},
+		&batchMessages.Message4{},
+	)
+
+	registerResults(
+		&prover.Result{},
+		&verifier.Result{},
+		&keygenParty1.Result{},
+		&keygenParty2.Result{},
+		&signParty1.Result{},
+		&signParty2.Result{},
+		&adaptorParty1.Result{},
+		&adaptorParty2.Result{},
+		&batchParty1.Result{},
+		&batchParty2.Result{},
+	)
+}
+
+// registerMessages registers the message types so that they can be decoded.
+func registerMessages(msgs ...lindell17.Message) {
+	for _, msg := range msgs {
+		key := messageKey{msg.Protocol(), msg.MessageId()}
+		messageTypes[key] = reflect.TypeOf(msg).Elem()
+	}
+}
+
+// registerResults registers the result types so that they can be decoded.
+func registerResults(results ...lindell17.Result) {
+	for _, res := range results {
+		key := resultKey{res.Protocol(), res.From()}
+		resultTypes[key] = reflect.TypeOf(res).Elem()
+	}
+}
@@ -0,0 +1,327 @@
+package codec
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/primefactor-io/commitment/pkg/hash"
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/ecdsa"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/schnorr"
+	"github.com/primefactor-io/lindell17/pkg/codec/opaque"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+	pProofs "github.com/primefactor-io/paillier/pkg/proofs"
+)
+
+// schema encodes and decodes a type that isn't declared in this module as an
+// object with a fixed set of fields.
+type schema struct {
+	encode func(v reflect.Value) (any, error)
+	decode func(tree any) (reflect.Value, error)
+}
+
+var schemas = make(map[reflect.Type]schema)
+
+// register registers the schema of the type T. The encode function returns
+// the values of T's fields by name. The decode function builds a T from the
+// fields it decoded via decodeFields.
+func register[T any](encode func(x *T) (map[string]any, error), decode func(tree any) (*T, error)) {
+	schemas[reflect.TypeFor[T]()] = schema{
+		encode: func(v reflect.Value) (any, error) {
+			// Copy the value so that it's addressable.
+			x := reflect.New(v.Type())
+			x.Elem().Set(v)
+
+			values, err := encode(x.Interface().(*T))
+			if err != nil {
+				return nil, err
+			}
+
+			tree := make(map[string]any, len(values))
+			for name, value := range values {
+				encoded, err := encodeValue(reflect.ValueOf(value))
+				if err != nil {
+					return nil, err
+				}
+				tree[name] = encoded
+			}
+
+			return tree, nil
+		},
+		decode: func(tree any) (reflect.Value, error) {
+			x, err := decode(tree)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			return reflect.ValueOf(x).Elem(), nil
+		},
+	}
+}
+
+// decodeFields decodes the object in the tree into the targets (pointers),
+// which are keyed by field name.
+// Returns an error if the tree isn't an object, if it has unknown fields or if
+// fields are missing.
+func decodeFields[T any](tree any, targets map[string]any) error {
+	values := make(map[string]reflect.Value, len(targets))
+	for name, target := range targets {
+		values[name] = reflect.ValueOf(target).Elem()
+	}
+
+	return decodeObject(tree, reflect.TypeFor[T](), values)
+}
+
+// rangeProofPair is the encoding of a pair of Range proof data.
+type rangeProofPair struct {
+	j              int
+	w1, r1, w2, r2 *big.Int
+}
+
+// rangeCiphertextPair is the encoding of a pair of Range proof ciphertexts.
+type rangeCiphertextPair struct {
+	c1, c2 cipher.Ciphertext
+}
+
+func init() {
+	register(
+		func(x *elliptic.Point) (map[string]any, error) {
+			return map[string]any{"X": x.X, "Y": x.Y}, nil
+		},
+		func(tree any) (*elliptic.Point, error) {
+			var x, y *big.Int
+			if err := decodeFields[elliptic.Point](tree, map[string]any{"X": &x, "Y": &y}); err != nil {
+				return nil, err
+			}
+			return elliptic.NewPoint(x, y), nil
+		},
+	)
+
+	register(
+		func(x *adaptor.Statement) (map[string]any, error) {
+			return map[string]any{"X": x.X, "Y": x.Y}, nil
+		},
+		func(tree any) (*adaptor.Statement, error) {
+			var x, y *big.Int
+			if err := decodeFields[adaptor.Statement](tree, map[string]any{"X": &x, "Y": &y}); err != nil {
+				return nil, err
+			}
+			return adaptor.NewStatement(elliptic.NewPoint(x, y)), nil
+		},
+	)
+
+	register(
+		func(x *ecdsa.Signature) (map[string]any, error) {
+			return map[string]any{"R": x.R, "S": x.S, "V": x.V}, nil
+		},
+		func(tree any) (*ecdsa.Signature, error) {
+			var r, s, v *big.Int
+			if err := decodeFields[ecdsa.Signature](tree, map[string]any{"R": &r, "S": &s, "V": &v}); err != nil {
+				return nil, err
+			}
+			return ecdsa.NewSignature(r, s, v), nil
+		},
+	)
+
+	register(
+		func(x *ecdsa.PreSignature) (map[string]any, error) {
+			return map[string]any{"R": x.R, "S": x.S, "V": x.V}, nil
+		},
+		func(tree any) (*ecdsa.PreSignature, error) {
+			var r, s, v *big.Int
+			if err := decodeFields[ecdsa.PreSignature](tree, map[string]any{"R": &r, "S": &s, "V": &v}); err != nil {
+				return nil, err
+			}
+			return ecdsa.NewPreSignature(r, s, v), nil
+		},
+	)
+
+	register(
+		func(x *keys.PublicKey) (map[string]any, error) {
+			return map[string]any{"N": x.N, "G": x.G, "NN": x.NN}, nil
+		},
+		func(tree any) (*keys.PublicKey, error) {
+			var n, g, nn *big.Int
+			if err := decodeFields[keys.PublicKey](tree, map[string]any{"N": &n, "G": &g, "NN": &nn}); err != nil {
+				return nil, err
+			}
+			return keys.NewPublicKey(n, g, nn), nil
+		},
+	)
+
+	register(
+		func(x *keys.PrivateKey) (map[string]any, error) {
+			return map[string]any{"N": x.N, "PhiN": x.PhiN, "Mu": x.Mu, "NN": x.NN}, nil
+		},
+		func(tree any) (*keys.PrivateKey, error) {
+			var n, phiN, mu, nn *big.Int
+			if err := decodeFields[keys.PrivateKey](tree, map[string]any{"N": &n, "PhiN": &phiN, "Mu": &mu, "NN": &nn}); err != nil {
+				return nil, err
+			}
+			return keys.NewPrivateKey(n, phiN, mu, nn), nil
+		},
+	)
+
+	register(
+		func(x *hash.Commitment) (map[string]any, error) {
+			var h, nonce [32]byte
+			if err := readOpaque(x, map[string]any{"hash": &h, "nonce": &nonce}); err != nil {
+				return nil, err
+			}
+			return map[string]any{"hash": h, "nonce": nonce}, nil
+		},
+		func(tree any) (*hash.Commitment, error) {
+			var h, nonce [32]byte
+			if err := decodeFields[hash.Commitment](tree, map[string]any{"hash": &h, "nonce": &nonce}); err != nil {
+				return nil, err
+			}
+			return hash.NewCommitment(h, nonce), nil
+		},
+	)
+
+	register(
+		func(x *proofs.DLKProof) (map[string]any, error) {
+			var e, s *big.Int
+			if err := readOpaque(x, map[string]any{"e": &e, "s": &s}); err != nil {
+				return nil, err
+			}
+			return map[string]any{"e": e, "s": s}, nil
+		},
+		func(tree any) (*proofs.DLKProof, error) {
+			var e, s *big.Int
+			if err := decodeFields[proofs.DLKProof](tree, map[string]any{"e": &e, "s": &s}); err != nil {
+				return nil, err
+			}
+			return proofs.NewDLKProof(schnorr.NewSignature(e, s)), nil
+		},
+	)
+
+	register(
+		func(x *proofs.DLEqProof) (map[string]any, error) {
+			var b, c *big.Int
+			if err := readOpaque(x, map[string]any{"b": &b, "c": &c}); err != nil {
+				return nil, err
+			}
+			return map[string]any{"b": b, "c": c}, nil
+		},
+		func(tree any) (*proofs.DLEqProof, error) {
+			var b, c *big.Int
+			if err := decodeFields[proofs.DLEqProof](tree, map[string]any{"b": &b, "c": &c}); err != nil {
+				return nil, err
+			}
+			return proofs.NewDLEqProof(b, c), nil
+		},
+	)
+
+	register(
+		func(x *pProofs.NthRootProof) (map[string]any, error) {
+			var u, a, z *big.Int
+			if err := readOpaque(x, map[string]any{"u": &u, "a": &a, "z": &z}); err != nil {
+				return nil, err
+			}
+			return map[string]any{"u": u, "a": a, "z": z}, nil
+		},
+		func(tree any) (*pProofs.NthRootProof, error) {
+			var u, a, z *big.Int
+			if err := decodeFields[pProofs.NthRootProof](tree, map[string]any{"u": &u, "a": &a, "z": &z}); err != nil {
+				return nil, err
+			}
+			return pProofs.NewNthRootProof(u, a, z), nil
+		},
+	)
+
+	register(
+		func(x *rangeProofPair) (map[string]any, error) {
+			return map[string]any{"j": x.j, "w1": x.w1, "r1": x.r1, "w2": x.w2, "r2": x.r2}, nil
+		},
+		func(tree any) (*rangeProofPair, error) {
+			x := new(rangeProofPair)
+			if err := decodeFields[rangeProofPair](tree, map[string]any{"j": &x.j, "w1": &x.w1, "r1": &x.r1, "w2": &x.w2, "r2": &x.r2}); err != nil {
+				return nil, err
+			}
+			return x, nil
+		},
+	)
+
+	register(
+		func(x *rangeCiphertextPair) (map[string]any, error) {
+			return map[string]any{"c1": x.c1, "c2": x.c2}, nil
+		},
+		func(tree any) (*rangeCiphertextPair, error) {
+			x := new(rangeCiphertextPair)
+			if err := decodeFields[rangeCiphertextPair](tree, map[string]any{"c1": &x.c1, "c2": &x.c2}); err != nil {
+				return nil, err
+			}
+			return x, nil
+		},
+	)
+
+	register(
+		func(x *pProofs.RangeProof) (map[string]any, error) {
+			pairs, cPairs, err := opaque.ReadRangeProof(x)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrUnsupportedType, err)
+			}
+
+			var proofPairs []rangeProofPair
+			if pairs != nil {
+				proofPairs = make([]rangeProofPair, len(pairs))
+			}
+			for i, pair := range pairs {
+				proofPairs[i] = rangeProofPair{pair.J, pair.W1, pair.R1, pair.W2, pair.R2}
+			}
+			var ciphertextPairs []rangeCiphertextPair
+			if cPairs != nil {
+				ciphertextPairs = make([]rangeCiphertextPair, len(cPairs))
+			}
+			for i, pair := range cPairs {
+				ciphertextPairs[i] = rangeCiphertextPair{pair.C1, pair.C2}
+			}
+
+			return map[string]any{"proofPairs": proofPairs, "ciphertextPairs": ciphertextPairs}, nil
+		},
+		func(tree any) (*pProofs.RangeProof, error) {
+			var proofPairs []rangeProofPair
+			var ciphertextPairs []rangeCiphertextPair
+			if err := decodeFields[pProofs.RangeProof](tree, map[string]any{"proofPairs": &proofPairs, "ciphertextPairs": &ciphertextPairs}); err != nil {
+				return nil, err
+			}
+
+			var pairs []opaque.RangeProofPair
+			if proofPairs != nil {
+				pairs = make([]opaque.RangeProofPair, len(proofPairs))
+			}
+			for i, pair := range proofPairs {
+				pairs[i] = opaque.RangeProofPair{J: pair.j, W1: pair.w1, R1: pair.r1, W2: pair.w2, R2: pair.r2}
+			}
+			var cPairs []opaque.RangeCiphertextPair
+			if ciphertextPairs != nil {
+				cPairs = make([]opaque.RangeCiphertextPair, len(ciphertextPairs))
+			}
+			for i, pair := range ciphertextPairs {
+				cPairs[i] = opaque.RangeCiphertextPair{C1: pair.c1, C2: pair.c2}
+			}
+
+			x, err := opaque.NewRangeProof(pairs, cPairs)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrUnsupportedType, err)
+			}
+			return x, nil
+		},
+	)
+}
+
+// readOpaque reads the unexported fields of the library value x points to into
+// the targets (pointers), which are keyed by field name.
+// Returns an error if the library's layout doesn't match the schema.
+func readOpaque(x any, targets map[string]any) error {
+	if err := opaque.Read(x, targets); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnsupportedType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,245 @@
+package codec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// modulePath is the path of the module whose types are encoded via their
+// exported fields. Types of other modules need a schema.
+const modulePath = "github.com/primefactor-io/lindell17"
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// encodeValue turns the value into a tree of maps, slices, strings, numbers
+// and booleans that can be encoded as JSON.
+// Returns an error if the value's type is unsupported.
+func encodeValue(v reflect.Value) (any, error) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem() == bigIntType {
+			return v.Interface().(*big.Int).Text(16), nil
+		}
+		return encodeValue(v.Elem())
+	case reflect.Struct:
+		if s, ok := schemas[v.Type()]; ok {
+			return s.encode(v)
+		}
+		if err := checkStruct(v.Type()); err != nil {
+			return nil, err
+		}
+		tree := make(map[string]any, v.NumField())
+		for i := range v.NumField() {
+			value, err := encodeValue(v.Field(i))
+			if err != nil {
+				return nil, err
+			}
+			tree[v.Type().Field(i).Name] = value
+		}
+		return tree, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return hex.EncodeToString(v.Bytes()), nil
+		}
+		tree := make([]any, v.Len())
+		for i := range v.Len() {
+			value, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			tree[i] = value
+		}
+		return tree, nil
+	case reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, v.Type())
+		}
+		bz := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(bz), v)
+		return hex.EncodeToString(bz), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, v.Type())
+	}
+}
+
+// decodeValue sets the value to the one encoded in the tree.
+// Returns an error if the tree doesn't match the value's type.
+func decodeValue(tree any, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if tree == nil {
+			return nil
+		}
+		if v.Type().Elem() == bigIntType {
+			s, ok := tree.(string)
+			if !ok {
+				return fmt.Errorf("%w: want hex string for %v", ErrDecode, v.Type())
+			}
+			x, ok := new(big.Int).SetString(s, 16)
+			if !ok {
+				return fmt.Errorf("%w: invalid integer %q", ErrDecode, s)
+			}
+			v.Set(reflect.ValueOf(x))
+			return nil
+		}
+		ptr := reflect.New(v.Type().Elem())
+		if err := decodeValue(tree, ptr.Elem()); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	case reflect.Struct:
+		if s, ok := schemas[v.Type()]; ok {
+			decoded, err := s.decode(tree)
+			if err != nil {
+				return err
+			}
+			v.Set(decoded)
+			return nil
+		}
+		if err := checkStruct(v.Type()); err != nil {
+			return err
+		}
+		targets := make(map[string]reflect.Value, v.NumField())
+		for i := range v.NumField() {
+			targets[v.Type().Field(i).Name] = v.Field(i)
+		}
+		return decodeObject(tree, v.Type(), targets)
+	case reflect.Slice:
+		if tree == nil {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			bz, err := decodeHex(tree)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(bz)
+			return nil
+		}
+		items, ok := tree.([]any)
+		if !ok {
+			return fmt.Errorf("%w: want array for %v", ErrDecode, v.Type())
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	case reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("%w: %v", ErrUnsupportedType, v.Type())
+		}
+		bz, err := decodeHex(tree)
+		if err != nil {
+			return err
+		}
+		if len(bz) != v.Len() {
+			return fmt.Errorf("%w: want %d bytes for %v", ErrDecode, v.Len(), v.Type())
+		}
+		reflect.Copy(v, reflect.ValueOf(bz))
+		return nil
+	case reflect.String:
+		s, ok := tree.(string)
+		if !ok {
+			return fmt.Errorf("%w: want string for %v", ErrDecode, v.Type())
+		}
+		v.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, ok := tree.(bool)
+		if !ok {
+			return fmt.Errorf("%w: want boolean for %v", ErrDecode, v.Type())
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, ok := tree.(float64)
+		if !ok || f != float64(int64(f)) || v.OverflowInt(int64(f)) {
+			return fmt.Errorf("%w: want integer for %v", ErrDecode, v.Type())
+		}
+		v.SetInt(int64(f))
+		return nil
+	default:
+		return fmt.Errorf("%w: %v", ErrUnsupportedType, v.Type())
+	}
+}
+
+// decodeHex decodes the hex string in the tree.
+// Returns an error if the tree isn't a valid hex string.
+func decodeHex(tree any) ([]byte, error) {
+	s, ok := tree.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: want hex string", ErrDecode)
+	}
+
+	bz, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	return bz, nil
+}
+
+// checkStruct checks that the struct type can be encoded via its fields, i.e.
+// that it's declared in this module and only has exported fields.
+// Returns an error if the type needs a schema.
+func checkStruct(t reflect.Type) error {
+	if t.PkgPath() != modulePath && !strings.HasPrefix(t.PkgPath(), modulePath+"/") {
+		return fmt.Errorf("%w: %v (no schema)", ErrUnsupportedType, t)
+	}
+	for i := range t.NumField() {
+		if !t.Field(i).IsExported() {
+			return fmt.Errorf("%w: %v (unexported field %s)", ErrUnsupportedType, t, t.Field(i).Name)
+		}
+	}
+
+	return nil
+}
+
+// decodeObject decodes the object in the tree into the targets, which are
+// keyed by field name.
+// Returns an error if the tree isn't an object, if it has unknown fields or if
+// fields are missing.
+func decodeObject(tree any, t reflect.Type, targets map[string]reflect.Value) error {
+	fields, ok := tree.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: want object for %v", ErrDecode, t)
+	}
+
+	for name := range fields {
+		if _, ok := targets[name]; !ok {
+			return fmt.Errorf("%w: unknown field %q for %v", ErrDecode, name, t)
+		}
+	}
+
+	for name, target := range targets {
+		value, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("%w: missing field %q for %v", ErrDecode, name, t)
+		}
+		if err := decodeValue(value, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
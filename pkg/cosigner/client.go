@@ -0,0 +1,247 @@
+package cosigner
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/lindell17/pkg/approval"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/transport"
+)
+
+// Client is an instance of a client that runs party 1 of the protocols with
+// a co-signer.
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	pollInterval time.Duration
+}
+
+// DefaultPollInterval is the default interval in which the client checks if
+// a pending session was approved.
+const DefaultPollInterval = time.Second
+
+// NewClient creates a new instance of a client for the co-signer at the given
+// base URL (e.g. https://cosigner:8443). The HTTP client needs to present the
+// client's certificate (see ClientTLSConfig).
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{
+		baseURL:      baseURL,
+		httpClient:   httpClient,
+		pollInterval: DefaultPollInterval,
+	}
+}
+
+// WithPollInterval sets the interval in which the client checks if a pending
+// session was approved.
+// Returns the client to allow chaining.
+func (c *Client) WithPollInterval(interval time.Duration) *Client {
+	c.pollInterval = interval
+
+	return c
+}
+
+// Run opens a session for the request and runs the party the factory creates
+// with the co-signer's party 2 until both parties finished.
+// If the co-signer requires approval, Run waits until the session is approved
+// or expired.
+// Returns the party's result and, for key generation, the id of the key the
+// co-signer stored, or an error if a request fails or the party fails. The
+// session is closed if the party fails.
+func (c *Client) Run(req *Request, newParty transport.Factory) (lindell17.Result, string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp := new(sessionResponse)
+	if err := c.do(http.MethodPost, "/v1/sessions", "application/json", body, resp); err != nil {
+		return nil, "", err
+	}
+	sessionID := resp.SessionID
+	inbox := resp.Messages
+	done := resp.Done
+	keyID := resp.KeyID
+	pending := resp.Pending
+
+	outCh := make(chan lindell17.Message, channelSize)
+	resCh := make(chan lindell17.Result, channelSize)
+	party := newParty(outCh, resCh)
+
+	var res lindell17.Result
+	_, err = party.Start()
+
+	for {
+		var outbox []lindell17.Message
+	drain:
+		for {
+			select {
+			case msg := <-outCh:
+				outbox = append(outbox, msg)
+			case r := <-resCh:
+				res = r
+			default:
+				break drain
+			}
+		}
+
+		if err != nil {
+			if !done {
+				c.do(http.MethodDelete, "/v1/sessions/"+sessionID, "", nil, nil)
+			}
+			return nil, "", fmt.Errorf("%w: %w", ErrRunProtocol, err)
+		}
+
+		for _, msg := range outbox {
+			data, err := codec.MarshalMessage(msg)
+			if err != nil {
+				return nil, "", err
+			}
+
+			resp := new(sessionResponse)
+			if err := c.do(http.MethodPost, "/v1/sessions/"+sessionID+"/messages", "application/json", data, resp); err != nil {
+				return nil, "", err
+			}
+			inbox = append(inbox, resp.Messages...)
+			done = resp.Done
+			if resp.KeyID != "" {
+				keyID = resp.KeyID
+			}
+		}
+
+		if res != nil && done {
+			return res, keyID, nil
+		}
+		if len(inbox) == 0 && pending {
+			resp, err := c.await(sessionID)
+			if err != nil {
+				return nil, "", err
+			}
+			inbox = resp.Messages
+			done = resp.Done
+			pending = false
+		}
+		if len(inbox) == 0 {
+			return nil, "", ErrStalled
+		}
+
+		var msg lindell17.Message
+		msg, err = codec.UnmarshalMessage(inbox[0])
+		inbox = inbox[1:]
+		if err == nil {
+			_, err = party.Process(msg)
+		}
+	}
+}
+
+// await polls the pending session until it's approved.
+// Returns party 2's response once the session is resumed or an error if a
+// request fails or the session expired.
+func (c *Client) await(sessionID string) (*sessionResponse, error) {
+	for {
+		resp := new(sessionResponse)
+		if err := c.do(http.MethodPost, "/v1/sessions/"+sessionID+"/resume", "", nil, resp); err != nil {
+			return nil, err
+		}
+		if !resp.Pending {
+			return resp, nil
+		}
+
+		time.Sleep(c.pollInterval)
+	}
+}
+
+// PendingApprovals returns the sessions that wait for approval.
+// Returns an error if the request fails.
+func (c *Client) PendingApprovals() ([]*PendingApproval, error) {
+	var list []*PendingApproval
+	if err := c.do(http.MethodGet, "/v1/approvals", "", nil, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Approve approves the pending session as the approver with the given id. The
+// digest is computed from the session's request, so the caller needs to have
+// reviewed the request.
+// Returns an error if signing fails or the co-signer rejects the approval.
+func (c *Client) Approve(p *PendingApproval, approverID string, signer crypto.Signer) error {
+	signature, err := approval.Sign(signer, p.Digest())
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(&approvalRequest{
+		Approver:  approverID,
+		Signature: hex.EncodeToString(signature),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.do(http.MethodPost, "/v1/approvals/"+p.SessionID, "application/json", body, nil)
+}
+
+// PublicKey returns the shared public key of the client's key with the given
+// id.
+// Returns an error if the request fails.
+func (c *Client) PublicKey(keyID string) (*elliptic.Point, error) {
+	resp := new(keyResponse)
+	if err := c.do(http.MethodGet, "/v1/keys/"+keyID, "", nil, resp); err != nil {
+		return nil, err
+	}
+
+	q := new(elliptic.Point)
+	if err := codec.UnmarshalValue(resp.PublicKey, q); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// do sends the request and decodes the JSON response into the value (if not
+// nil).
+// Returns an error if the request fails or the co-signer rejects it.
+func (c *Client) do(method, path, contentType string, body []byte, v any) error {
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRequest, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRequest, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		e := new(errorResponse)
+		if json.Unmarshal(data, e) != nil || e.Error == "" {
+			e.Error = http.StatusText(resp.StatusCode)
+		}
+		return fmt.Errorf("%w: %s (status %d)", ErrRequest, e.Error, resp.StatusCode)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.Unmarshal(data, v)
+}
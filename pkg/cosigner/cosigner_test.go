@@ -0,0 +1,712 @@
+package cosigner_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/curves"
+	eccEcdsa "github.com/primefactor-io/ecc/pkg/ecdsa"
+	eccElliptic "github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/keys"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	aParty1 "github.com/primefactor-io/lindell17/pkg/adaptor/party1"
+	"github.com/primefactor-io/lindell17/pkg/approval"
+	"github.com/primefactor-io/lindell17/pkg/cosigner"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/policy"
+	sParty1 "github.com/primefactor-io/lindell17/pkg/sign/party1"
+)
+
+var secp256k1 = curves.Secp256k1
+
+var caPool *x509.CertPool
+var serverCert tls.Certificate
+var aliceCert tls.Certificate
+var bobCert tls.Certificate
+
+var hash []byte
+
+func TestMain(m *testing.M) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, _ := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	ca, _ := x509.ParseCertificate(caDER)
+
+	caPool = x509.NewCertPool()
+	caPool.AddCert(ca)
+
+	// issue issues a certificate for the common name.
+	issue := func(serial int64, cn string, usage x509.ExtKeyUsage) tls.Certificate {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: cn},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		}
+		der, _ := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+
+		return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	}
+
+	serverCert = issue(2, "cosigner", x509.ExtKeyUsageServerAuth)
+	aliceCert = issue(3, "alice", x509.ExtKeyUsageClientAuth)
+	bobCert = issue(4, "bob", x509.ExtKeyUsageClientAuth)
+
+	checksum := sha256.Sum256([]byte("Hello World"))
+	hash = checksum[:]
+
+	os.Exit(m.Run())
+}
+
+// newServer starts a co-signer with mutual TLS.
+func newServer(t *testing.T, server http.Handler) *httptest.Server {
+	ts := httptest.NewUnstartedServer(server)
+	ts.TLS = cosigner.ServerTLSConfig(serverCert, caPool)
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+// newClient creates a client that authenticates with the certificate.
+func newClient(ts *httptest.Server, cert tls.Certificate) *cosigner.Client {
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: cosigner.ClientTLSConfig(cert, caPool),
+		},
+	}
+
+	return cosigner.NewClient(ts.URL, httpClient)
+}
+
+// keygen runs a key generation with the co-signer.
+func keygen(t *testing.T, client *cosigner.Client) (*kParty1.KeyMaterial, string) {
+	t.Helper()
+
+	params := kParty1.NewParams(secp256k1, 40, 128, 1024)
+	res, keyID, err := client.Run(cosigner.NewKeygenRequest(), func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+		return kParty1.NewParty1(params, outCh, resCh)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return res.(*kParty1.Result).KeyMaterial, keyID
+}
+
+// signFactory returns a factory of party 1 of a signing run.
+func signFactory(km *kParty1.KeyMaterial) func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+	params := sParty1.NewParams(secp256k1, km.Sk, km.Q)
+
+	return func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+		return sParty1.NewParty1(params, hash, outCh, resCh)
+	}
+}
+
+func TestCosigner(t *testing.T) {
+	t.Parallel()
+
+	server := cosigner.NewServer(secp256k1, cosigner.NewMemoryKeystore())
+	ts := newServer(t, server)
+	alice := newClient(ts, aliceCert)
+	bob := newClient(ts, bobCert)
+
+	km, keyID := keygen(t, alice)
+
+	t.Run("Keygen / Sign (valid)", func(t *testing.T) {
+		t.Parallel()
+
+		q, err := alice.PublicKey(keyID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !q.Equal(km.Q) {
+			t.Error("expected co-signer's public key to match party 1's public key")
+		}
+
+		res, _, err := alice.Run(cosigner.NewSignRequest(keyID, hash), signFactory(km))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		signature := res.(*sParty1.Result).Signature
+		isValid, _ := eccEcdsa.Verify(secp256k1, keys.NewPublicKey(km.Q), hash, signature)
+		if !isValid {
+			t.Error("expected signature to be valid")
+		}
+	})
+
+	t.Run("Adaptor (valid)", func(t *testing.T) {
+		t.Parallel()
+
+		wit, stmt, _ := adaptor.GenerateHardRelation(secp256k1)
+		pStmt, _ := proofs.GenerateDLKProof(secp256k1, (*eccElliptic.Point)(stmt), (*big.Int)(wit))
+
+		params := aParty1.NewParams(secp256k1, km.Sk, km.Q)
+		req := cosigner.NewAdaptorRequest(keyID, hash, stmt, pStmt)
+		res, _, err := alice.Run(req, func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return aParty1.NewParty1(params, hash, stmt, pStmt, outCh, resCh)
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		signature := eccEcdsa.Adapt(secp256k1, wit, res.(*aParty1.Result).PreSignature)
+		isValid, _ := eccEcdsa.Verify(secp256k1, keys.NewPublicKey(km.Q), hash, signature)
+		if !isValid {
+			t.Error("expected adapted signature to be valid")
+		}
+	})
+
+	t.Run("Sign - Invalid (other client's key)", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := bob.Run(cosigner.NewSignRequest(keyID, hash), signFactory(km))
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), cosigner.ErrUnknownKey.Error()) {
+			t.Errorf("want error %v, got %v", cosigner.ErrUnknownKey, err)
+		}
+
+		if _, err := bob.PublicKey(keyID); !errors.Is(err, cosigner.ErrRequest) {
+			t.Errorf("want error %v, got %v", cosigner.ErrRequest, err)
+		}
+	})
+
+	t.Run("Sign - Invalid (unknown key)", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := alice.Run(cosigner.NewSignRequest("00112233445566778899aabbccddeeff", hash), signFactory(km))
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), "status 404") {
+			t.Errorf("want error %v, got %v", cosigner.ErrUnknownKey, err)
+		}
+	})
+
+	t.Run("Sign - Invalid (hash length)", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := alice.Run(cosigner.NewSignRequest(keyID, hash[:16]), signFactory(km))
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), cosigner.ErrRunProtocol.Error()) {
+			t.Errorf("want error %v, got %v", cosigner.ErrRunProtocol, err)
+		}
+	})
+
+	t.Run("Adaptor - Invalid (missing statement)", func(t *testing.T) {
+		t.Parallel()
+
+		req := cosigner.NewAdaptorRequest(keyID, hash, nil, nil)
+		_, _, err := alice.Run(req, signFactory(km))
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), "status 400") {
+			t.Errorf("want error %v, got %v", cosigner.ErrInvalidRequest, err)
+		}
+	})
+
+	t.Run("Refresh - Invalid (unsupported)", func(t *testing.T) {
+		t.Parallel()
+
+		req := &cosigner.Request{Protocol: cosigner.Refresh, KeyID: keyID}
+		_, _, err := alice.Run(req, signFactory(km))
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), "status 501") {
+			t.Errorf("want error %v, got %v", cosigner.ErrUnsupportedProtocol, err)
+		}
+	})
+
+	t.Run("Invalid (no client certificate)", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(ts, tls.Certificate{})
+		_, _, err := client.Run(cosigner.NewSignRequest(keyID, hash), signFactory(km))
+		if !errors.Is(err, cosigner.ErrRequest) {
+			t.Errorf("want error %v, got %v", cosigner.ErrRequest, err)
+		}
+	})
+
+	t.Run("Invalid (no TLS)", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/keys/"+keyID, nil)
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("want status %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+}
+
+func TestCosignerSessions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Sessions - Invalid (too many sessions)", func(t *testing.T) {
+		t.Parallel()
+
+		server := cosigner.NewServer(secp256k1, cosigner.NewMemoryKeystore()).WithMaxSessions(0)
+		ts := newServer(t, server)
+		alice := newClient(ts, aliceCert)
+
+		params := kParty1.NewParams(secp256k1, 40, 128, 1024)
+		_, _, err := alice.Run(cosigner.NewKeygenRequest(), func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return kParty1.NewParty1(params, outCh, resCh)
+		})
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), cosigner.ErrTooManySessions.Error()) {
+			t.Errorf("want error %v, got %v", cosigner.ErrTooManySessions, err)
+		}
+	})
+
+	t.Run("Sessions - Invalid (expired)", func(t *testing.T) {
+		t.Parallel()
+
+		server := cosigner.NewServer(secp256k1, cosigner.NewMemoryKeystore()).WithSessionTimeout(time.Nanosecond)
+		ts := newServer(t, server)
+		alice := newClient(ts, aliceCert)
+
+		params := kParty1.NewParams(secp256k1, 40, 128, 1024)
+		_, _, err := alice.Run(cosigner.NewKeygenRequest(), func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return kParty1.NewParty1(params, outCh, resCh)
+		})
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), cosigner.ErrUnknownSession.Error()) {
+			t.Errorf("want error %v, got %v", cosigner.ErrUnknownSession, err)
+		}
+	})
+}
+
+func TestKeystore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DirKeystore (round trip)", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		ks := cosigner.NewDirKeystore(dir)
+		dirServer := cosigner.NewServer(secp256k1, ks)
+		dirTs := newServer(t, dirServer)
+		alice := newClient(dirTs, aliceCert)
+
+		km, keyID := keygen(t, alice)
+
+		key, err := ks.Get(keyID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if key.Owner != "alice" || !key.KeyMaterial.Q.Equal(km.Q) {
+			t.Error("expected stored key to match the generated key")
+		}
+
+		info, err := os.Stat(dir + "/" + keyID + ".json")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Errorf("want mode %o, got %o", 0o600, info.Mode().Perm())
+		}
+
+		if err := ks.Put(key); !errors.Is(err, cosigner.ErrKeyExists) {
+			t.Errorf("want error %v, got %v", cosigner.ErrKeyExists, err)
+		}
+
+		if _, _, err := alice.Run(cosigner.NewSignRequest(keyID, hash), signFactory(km)); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("DirKeystore - Invalid (key id)", func(t *testing.T) {
+		t.Parallel()
+
+		ks := cosigner.NewDirKeystore(t.TempDir())
+
+		if _, err := ks.Get("../../etc/passwd"); !errors.Is(err, cosigner.ErrUnknownKey) {
+			t.Errorf("want error %v, got %v", cosigner.ErrUnknownKey, err)
+		}
+		if err := ks.Put(&cosigner.Key{ID: "../key"}); !errors.Is(err, cosigner.ErrInvalidKeyID) {
+			t.Errorf("want error %v, got %v", cosigner.ErrInvalidKeyID, err)
+		}
+	})
+
+	t.Run("MemoryKeystore - Invalid (unknown key)", func(t *testing.T) {
+		t.Parallel()
+
+		ks := cosigner.NewMemoryKeystore()
+
+		if _, err := ks.Get("00112233445566778899aabbccddeeff"); !errors.Is(err, cosigner.ErrUnknownKey) {
+			t.Errorf("want error %v, got %v", cosigner.ErrUnknownKey, err)
+		}
+	})
+}
+
+func TestCosignerPolicy(t *testing.T) {
+	t.Parallel()
+
+	engine := policy.NewEngine(policy.Destinations("bc1qallowed"))
+	server := cosigner.NewServer(secp256k1, cosigner.NewMemoryKeystore()).WithPolicy(engine)
+	ts := newServer(t, server)
+	alice := newClient(ts, aliceCert)
+
+	km, keyID := keygen(t, alice)
+
+	t.Run("Sign (policy allowed)", func(t *testing.T) {
+		t.Parallel()
+
+		req := cosigner.NewSignRequest(keyID, hash).WithPayload(&lindell17.Payload{
+			Destination: "bc1qallowed",
+			Amount:      big.NewInt(1000),
+			Data:        []byte("Hello World"),
+		})
+		if _, _, err := alice.Run(req, signFactory(km)); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Sign - Invalid (policy denied)", func(t *testing.T) {
+		t.Parallel()
+
+		req := cosigner.NewSignRequest(keyID, hash).WithPayload(&lindell17.Payload{Destination: "bc1qunknown"})
+		_, _, err := alice.Run(req, signFactory(km))
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), "status 403") || !strings.Contains(err.Error(), "bc1qunknown") {
+			t.Errorf("want error %v, got %v", lindell17.ErrPolicyDenied, err)
+		}
+	})
+
+	t.Run("Sign - Invalid (policy without payload)", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := alice.Run(cosigner.NewSignRequest(keyID, hash), signFactory(km))
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), "status 403") {
+			t.Errorf("want error %v, got %v", lindell17.ErrPolicyDenied, err)
+		}
+	})
+}
+
+// approver is an approver together with its private key.
+type approver struct {
+	id     string
+	signer crypto.Signer
+}
+
+// newApprovers generates approvers with an Ed25519, an ECDSA and another
+// Ed25519 key and a quorum that requires two of them.
+func newApprovers(t *testing.T) ([]approver, *approval.Quorum) {
+	t.Helper()
+
+	_, carolKey, _ := ed25519.GenerateKey(rand.Reader)
+	daveKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, erinKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	approvers := []approver{{"carol", carolKey}, {"dave", daveKey}, {"erin", erinKey}}
+	var members []*approval.Approver
+	for _, a := range approvers {
+		member, err := approval.NewApprover(a.id, a.signer.Public())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		members = append(members, member)
+	}
+
+	quorum, err := approval.NewQuorum(2, members...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return approvers, quorum
+}
+
+// handler is a co-signer that can be replaced to simulate a restart.
+type handler struct {
+	server atomic.Pointer[cosigner.Server]
+}
+
+// ServeHTTP passes the request to the current co-signer.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.server.Load().ServeHTTP(w, r)
+}
+
+// run is the outcome of a client's protocol run.
+type run struct {
+	res lindell17.Result
+	err error
+}
+
+// runAsync runs the request in the background, since the client blocks until
+// the session is approved.
+func runAsync(client *cosigner.Client, req *cosigner.Request, newParty func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant) <-chan run {
+	ch := make(chan run, 1)
+	go func() {
+		res, _, err := client.Run(req, newParty)
+		ch <- run{res, err}
+	}()
+
+	return ch
+}
+
+// awaitPending waits until the co-signer lists a pending session.
+func awaitPending(t *testing.T, client *cosigner.Client) *cosigner.PendingApproval {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		list, err := client.PendingApprovals()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(list) > 0 {
+			return list[0]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected a pending session")
+
+	return nil
+}
+
+func TestCosignerApproval(t *testing.T) {
+	t.Parallel()
+
+	approvers, quorum := newApprovers(t)
+
+	t.Run("Sign (approved across a restart)", func(t *testing.T) {
+		t.Parallel()
+
+		ks := cosigner.NewMemoryKeystore()
+		store := cosigner.NewDirPendingStore(t.TempDir())
+		h := new(handler)
+		h.server.Store(cosigner.NewServer(secp256k1, ks))
+		ts := newServer(t, h)
+		alice := newClient(ts, aliceCert).WithPollInterval(10 * time.Millisecond)
+		bob := newClient(ts, bobCert)
+
+		km, keyID := keygen(t, alice)
+		h.server.Store(cosigner.NewServer(secp256k1, ks).WithApproval(quorum, store).WithApproverClients("bob"))
+
+		payload := &lindell17.Payload{Destination: "bc1qdest", Amount: big.NewInt(1000)}
+		ch := runAsync(alice, cosigner.NewSignRequest(keyID, hash).WithPayload(payload), signFactory(km))
+
+		p := awaitPending(t, bob)
+		if p.Owner != "alice" || p.Request.KeyID != keyID || p.Request.Payload.Destination != "bc1qdest" || p.Threshold != 2 {
+			t.Errorf("expected pending session to show the request, got %+v", p)
+		}
+
+		if err := bob.Approve(p, approvers[0].id, approvers[0].signer); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// The pending session survives the restart of the co-signer.
+		h.server.Store(cosigner.NewServer(secp256k1, ks).WithApproval(quorum, store).WithApproverClients("bob"))
+
+		select {
+		case r := <-ch:
+			t.Fatalf("expected session to wait for approval, got %v", r.err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		if err := bob.Approve(p, approvers[1].id, approvers[1].signer); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		r := <-ch
+		if r.err != nil {
+			t.Fatalf("expected no error, got %v", r.err)
+		}
+		signature := r.res.(*sParty1.Result).Signature
+		isValid, _ := eccEcdsa.Verify(secp256k1, keys.NewPublicKey(km.Q), hash, signature)
+		if !isValid {
+			t.Error("expected signature to be valid")
+		}
+
+		if list, _ := bob.PendingApprovals(); len(list) != 0 {
+			t.Errorf("expected no pending sessions, got %d", len(list))
+		}
+	})
+
+	t.Run("Adaptor (approved)", func(t *testing.T) {
+		t.Parallel()
+
+		server := cosigner.NewServer(secp256k1, cosigner.NewMemoryKeystore()).
+			WithApproval(quorum, cosigner.NewMemoryPendingStore())
+		ts := newServer(t, server)
+		alice := newClient(ts, aliceCert).WithPollInterval(10 * time.Millisecond)
+
+		km, keyID := keygen(t, alice)
+
+		wit, stmt, _ := adaptor.GenerateHardRelation(secp256k1)
+		pStmt, _ := proofs.GenerateDLKProof(secp256k1, (*eccElliptic.Point)(stmt), (*big.Int)(wit))
+
+		params := aParty1.NewParams(secp256k1, km.Sk, km.Q)
+		req := cosigner.NewAdaptorRequest(keyID, hash, stmt, pStmt)
+		ch := runAsync(alice, req, func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return aParty1.NewParty1(params, hash, stmt, pStmt, outCh, resCh)
+		})
+
+		p := awaitPending(t, alice)
+		for _, a := range []approver{approvers[0], approvers[2]} {
+			if err := alice.Approve(p, a.id, a.signer); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+
+		r := <-ch
+		if r.err != nil {
+			t.Fatalf("expected no error, got %v", r.err)
+		}
+		signature := eccEcdsa.Adapt(secp256k1, wit, r.res.(*aParty1.Result).PreSignature)
+		isValid, _ := eccEcdsa.Verify(secp256k1, keys.NewPublicKey(km.Q), hash, signature)
+		if !isValid {
+			t.Error("expected adapted signature to be valid")
+		}
+	})
+
+	t.Run("Sign (key without approval)", func(t *testing.T) {
+		t.Parallel()
+
+		server := cosigner.NewServer(secp256k1, cosigner.NewMemoryKeystore()).
+			WithApproval(quorum, cosigner.NewMemoryPendingStore(), "00112233445566778899aabbccddeeff")
+		ts := newServer(t, server)
+		alice := newClient(ts, aliceCert)
+
+		km, keyID := keygen(t, alice)
+
+		if _, _, err := alice.Run(cosigner.NewSignRequest(keyID, hash), signFactory(km)); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Sign - Invalid (approvals / expired)", func(t *testing.T) {
+		t.Parallel()
+
+		server := cosigner.NewServer(secp256k1, cosigner.NewMemoryKeystore()).
+			WithApproval(quorum, cosigner.NewMemoryPendingStore()).
+			WithApprovalTimeout(time.Second)
+		ts := newServer(t, server)
+		alice := newClient(ts, aliceCert).WithPollInterval(10 * time.Millisecond)
+
+		km, keyID := keygen(t, alice)
+		ch := runAsync(alice, cosigner.NewSignRequest(keyID, hash), signFactory(km))
+		p := awaitPending(t, alice)
+
+		// An approver that isn't part of the quorum.
+		_, malloryKey, _ := ed25519.GenerateKey(rand.Reader)
+		err := alice.Approve(p, "mallory", malloryKey)
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), approval.ErrUnknownApprover.Error()) {
+			t.Errorf("want error %v, got %v", approval.ErrUnknownApprover, err)
+		}
+
+		// A signature by another approver's key.
+		err = alice.Approve(p, approvers[0].id, approvers[2].signer)
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), "status 403") {
+			t.Errorf("want error %v, got %v", approval.ErrInvalidSignature, err)
+		}
+
+		// An approval of another hash.
+		tampered := *p
+		tampered.Request = cosigner.NewSignRequest(keyID, make([]byte, len(hash)))
+		err = alice.Approve(&tampered, approvers[0].id, approvers[0].signer)
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), approval.ErrInvalidSignature.Error()) {
+			t.Errorf("want error %v, got %v", approval.ErrInvalidSignature, err)
+		}
+
+		// A single approval doesn't meet the threshold.
+		if err := alice.Approve(p, approvers[1].id, approvers[1].signer); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		r := <-ch
+		if !errors.Is(r.err, cosigner.ErrRequest) || !strings.Contains(r.err.Error(), cosigner.ErrApprovalExpired.Error()) {
+			t.Errorf("want error %v, got %v", cosigner.ErrApprovalExpired, r.err)
+		}
+	})
+
+	t.Run("Sign - Invalid (listed to other clients)", func(t *testing.T) {
+		t.Parallel()
+
+		server := cosigner.NewServer(secp256k1, cosigner.NewMemoryKeystore()).
+			WithApproval(quorum, cosigner.NewMemoryPendingStore()).
+			WithApprovalTimeout(time.Second)
+		ts := newServer(t, server)
+		alice := newClient(ts, aliceCert).WithPollInterval(10 * time.Millisecond)
+		bob := newClient(ts, bobCert)
+
+		km, keyID := keygen(t, alice)
+		ch := runAsync(alice, cosigner.NewSignRequest(keyID, hash), signFactory(km))
+		awaitPending(t, alice)
+
+		// Bob isn't an approver client, so alice's session isn't listed.
+		list, err := bob.PendingApprovals()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(list) != 0 {
+			t.Errorf("expected no pending sessions, got %d", len(list))
+		}
+
+		<-ch
+	})
+
+	t.Run("Sign - Invalid (too many pending)", func(t *testing.T) {
+		t.Parallel()
+
+		// newCosigner creates a co-signer with the given maximum numbers of
+		// pending sessions in total and per client.
+		newCosigner := func(maxPending, maxPerOwner int) (*cosigner.Client, *cosigner.Client) {
+			server := cosigner.NewServer(secp256k1, cosigner.NewMemoryKeystore()).
+				WithApproval(quorum, cosigner.NewMemoryPendingStore()).
+				WithApprovalTimeout(time.Second).
+				WithMaxPending(maxPending, maxPerOwner)
+			ts := newServer(t, server)
+
+			return newClient(ts, aliceCert).WithPollInterval(10 * time.Millisecond), newClient(ts, bobCert)
+		}
+
+		// Alice reached the maximum number of her pending sessions.
+		alice, _ := newCosigner(2, 1)
+		km, keyID := keygen(t, alice)
+		ch1 := runAsync(alice, cosigner.NewSignRequest(keyID, hash), signFactory(km))
+		awaitPending(t, alice)
+
+		_, _, err := alice.Run(cosigner.NewSignRequest(keyID, hash), signFactory(km))
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), "status 429") {
+			t.Errorf("want error %v, got %v", cosigner.ErrTooManyPending, err)
+		}
+
+		// Alice's session reached the maximum number of pending sessions.
+		alice, bob := newCosigner(1, 2)
+		km, keyID = keygen(t, alice)
+		kmBob, keyIDBob := keygen(t, bob)
+		ch2 := runAsync(alice, cosigner.NewSignRequest(keyID, hash), signFactory(km))
+		awaitPending(t, alice)
+
+		_, _, err = bob.Run(cosigner.NewSignRequest(keyIDBob, hash), signFactory(kmBob))
+		if !errors.Is(err, cosigner.ErrRequest) || !strings.Contains(err.Error(), cosigner.ErrTooManyPending.Error()) {
+			t.Errorf("want error %v, got %v", cosigner.ErrTooManyPending, err)
+		}
+
+		<-ch1
+		<-ch2
+	})
+}
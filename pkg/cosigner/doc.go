@@ -0,0 +1,55 @@
+/*
+Package cosigner implements a co-signer that runs party 2 of the key
+generation, signing and adaptor signature protocols as a long-running service,
+together with a client that runs party 1 against it.
+
+The co-signer (see Server) is an http.Handler. It keeps party 2's key material
+in a Keystore and runs a fresh party 2 per session. Sessions are driven by the
+client, so that every protocol step is a single request:
+
+	POST   /v1/sessions               open a session (see Request) and start party 2
+	POST   /v1/sessions/{id}/messages let party 2 process a codec-encoded message
+	POST   /v1/sessions/{id}/resume   resume a session once it's approved
+	DELETE /v1/sessions/{id}          abort a session
+	GET    /v1/keys/{id}              return the shared public key of a key
+	GET    /v1/approvals              list the sessions that wait for approval
+	POST   /v1/approvals/{id}         approve a session
+
+Every session response contains the messages party 2 emitted (encoded via the
+codec, see package codec) and whether party 2 finished. A finished key
+generation stores party 2's key material under a new key id that is returned
+together with the shared public key. Party 2's key material never leaves the
+co-signer. Sessions are closed once party 2 finished or failed and expire
+after a period of inactivity.
+
+Clients are authenticated via mutual TLS (see ServerTLSConfig and
+ClientTLSConfig). A client's identity is the common name of its certificate
+and a key can only be used by the client that created it. Other clients get
+the same response as for a key that doesn't exist.
+
+Party 2 evaluates the co-signer's signing policy (see Server.WithPolicy and
+package policy) before it contributes to a signature. The policy sees the key
+id, the hash and the request's optional payload. Denied requests are rejected
+with the policy's reason.
+
+Signing and adaptor signature requests can require the approval of a quorum
+of human approvers (see Server.WithApproval and package approval). Such a
+session is held as pending before party 2 is created: the client's first
+message is stored with the request and party 2 only processes it and
+responds once N of the M approvers signed the session's digest with their own
+Ed25519 or ECDSA keys. A pending session only consists of public data, so it's
+kept in a PendingStore that survives a restart of the co-signer. It expires
+if it isn't approved in time. The number of pending sessions is capped in total and per client
+(see Server.WithMaxPending), further requests are rejected with
+ErrTooManyPending. Clients poll the session via the resume endpoint (see
+Client.Run). Approvers list the pending sessions via a client whose identity
+is configured as an approver client (see Server.WithApproverClients), other
+clients only list their own sessions (see Client.PendingApprovals). Approvers
+submit their signatures via any authenticated client (see Client.Approve). An
+approval is authorized by the approver's signature, not by the client that
+submits it.
+
+Note that there's no key refresh protocol yet, so requests for refresh
+sessions are rejected with ErrUnsupportedProtocol.
+*/
+package cosigner
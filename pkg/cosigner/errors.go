@@ -0,0 +1,34 @@
+package cosigner
+
+import "fmt"
+
+var (
+	// ErrUnauthenticated is returned if the client didn't present a verified certificate.
+	ErrUnauthenticated = fmt.Errorf("client isn't authenticated")
+	// ErrInvalidRequest is returned if a request can't be decoded or lacks a field.
+	ErrInvalidRequest = fmt.Errorf("invalid request")
+	// ErrUnsupportedProtocol is returned if a session is requested for a protocol the co-signer doesn't run.
+	ErrUnsupportedProtocol = fmt.Errorf("unsupported protocol")
+	// ErrUnknownKey is returned if a key doesn't exist or belongs to another client.
+	ErrUnknownKey = fmt.Errorf("unknown key")
+	// ErrInvalidKeyID is returned if a key id isn't a hex-encoded 16-byte value.
+	ErrInvalidKeyID = fmt.Errorf("invalid key id")
+	// ErrKeyExists is returned if a key with the same id is already stored.
+	ErrKeyExists = fmt.Errorf("key already exists")
+	// ErrUnknownSession is returned if a session doesn't exist, expired or belongs to another client.
+	ErrUnknownSession = fmt.Errorf("unknown session")
+	// ErrTooManySessions is returned if the maximum number of open sessions is reached.
+	ErrTooManySessions = fmt.Errorf("too many sessions")
+	// ErrTooManyPending is returned if the maximum number of sessions that wait for approval is reached.
+	ErrTooManyPending = fmt.Errorf("too many sessions pending approval")
+	// ErrPendingApproval is returned if a message is sent to a session that waits for approval and already holds a message.
+	ErrPendingApproval = fmt.Errorf("session is pending approval")
+	// ErrApprovalExpired is returned if a session expired before it was approved.
+	ErrApprovalExpired = fmt.Errorf("session expired before it was approved")
+	// ErrRunProtocol is returned if party 2 fails during the protocol run.
+	ErrRunProtocol = fmt.Errorf("unable to run protocol")
+	// ErrRequest is returned if the co-signer rejects a client's request.
+	ErrRequest = fmt.Errorf("request failed")
+	// ErrStalled is returned if neither party has a message to process before the protocol run finished.
+	ErrStalled = fmt.Errorf("protocol run stalled")
+)
@@ -0,0 +1,211 @@
+package cosigner
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+)
+
+// keyIDLength is the length of a key id in bytes.
+const keyIDLength = 16
+
+// Key is an instance of party 2's key material that the co-signer stores on
+// behalf of a client.
+type Key struct {
+	// ID is the key's id.
+	ID string
+	// Owner is the identity of the client that created the key.
+	Owner string
+	// KeyMaterial is party 2's key material.
+	KeyMaterial *kParty2.KeyMaterial
+}
+
+// NewKey creates a new instance of a key with a random id.
+// Returns an error if the id can't be generated.
+func NewKey(owner string, keyMaterial *kParty2.KeyMaterial) (*Key, error) {
+	id, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		ID:          id,
+		Owner:       owner,
+		KeyMaterial: keyMaterial,
+	}, nil
+}
+
+// Keystore is an interface that stores of the co-signer's keys need to
+// implement. Implementations need to be safe for concurrent use.
+type Keystore interface {
+	// Put stores the key. It returns ErrKeyExists if a key with the same id
+	// is already stored.
+	Put(key *Key) error
+	// Get returns the key with the given id. It returns ErrUnknownKey if no
+	// such key is stored.
+	Get(id string) (*Key, error)
+}
+
+// MemoryKeystore is an instance of a keystore that keeps the keys in memory.
+type MemoryKeystore struct {
+	mu   sync.RWMutex
+	keys map[string]*Key
+}
+
+// NewMemoryKeystore creates a new instance of an empty in-memory keystore.
+func NewMemoryKeystore() *MemoryKeystore {
+	return &MemoryKeystore{
+		keys: make(map[string]*Key),
+	}
+}
+
+// Put stores the key.
+// Returns an error if the key id is invalid or a key with the same id exists.
+func (m *MemoryKeystore) Put(key *Key) error {
+	if err := checkKeyID(key.ID); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.keys[key.ID]; ok {
+		return ErrKeyExists
+	}
+	m.keys[key.ID] = key
+
+	return nil
+}
+
+// Get returns the key with the given id.
+// Returns an error if no such key is stored.
+func (m *MemoryKeystore) Get(id string) (*Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key, ok := m.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+// keyFile is the JSON representation of a key in a DirKeystore.
+type keyFile struct {
+	Owner       string          `json:"owner"`
+	KeyMaterial json.RawMessage `json:"key_material"`
+}
+
+// DirKeystore is an instance of a keystore that stores every key in a file
+// of a directory. The files are only readable by the owner.
+type DirKeystore struct {
+	dir string
+}
+
+// NewDirKeystore creates a new instance of a keystore that stores the keys in
+// the given directory, which needs to exist.
+func NewDirKeystore(dir string) *DirKeystore {
+	return &DirKeystore{
+		dir: dir,
+	}
+}
+
+// Put writes the key to its file.
+// Returns an error if the key id is invalid, a key with the same id exists or
+// the file can't be written.
+func (d *DirKeystore) Put(key *Key) error {
+	if err := checkKeyID(key.ID); err != nil {
+		return err
+	}
+
+	km, err := codec.MarshalValue(key.KeyMaterial)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(&keyFile{Owner: key.Owner, KeyMaterial: km})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(d.path(key.ID), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return ErrKeyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Get reads the key with the given id from its file.
+// Returns an error if the key id is invalid, no such key is stored or the
+// file can't be read.
+func (d *DirKeystore) Get(id string) (*Key, error) {
+	if err := checkKeyID(id); err != nil {
+		return nil, ErrUnknownKey
+	}
+
+	data, err := os.ReadFile(d.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUnknownKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := new(keyFile)
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", id, err)
+	}
+	km := new(kParty2.KeyMaterial)
+	if err := codec.UnmarshalValue(f.KeyMaterial, km); err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", id, err)
+	}
+
+	return &Key{
+		ID:          id,
+		Owner:       f.Owner,
+		KeyMaterial: km,
+	}, nil
+}
+
+// path returns the path of the key's file.
+func (d *DirKeystore) path(id string) string {
+	return filepath.Join(d.dir, id+".json")
+}
+
+// checkKeyID checks that the key id is a lowercase hex-encoded value of the
+// expected length, so that it can be used as a file name.
+func checkKeyID(id string) error {
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) != keyIDLength || hex.EncodeToString(b) != id {
+		return ErrInvalidKeyID
+	}
+
+	return nil
+}
+
+// randomID generates a random hex-encoded id for keys and sessions.
+func randomID() (string, error) {
+	b := make([]byte, keyIDLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,286 @@
+package cosigner
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/primefactor-io/lindell17/pkg/approval"
+)
+
+// Pending is an instance of a session that waits for the approval of a
+// quorum. Party 2 isn't created before the session is approved, so a pending
+// session only holds public data and can be stored persistently.
+type Pending struct {
+	// ID is the session's id.
+	ID string
+	// Owner is the identity of the client that opened the session.
+	Owner string
+	// Request is the client's request.
+	Request *Request
+	// Message is the client's codec-encoded first message (if any), which
+	// party 2 processes once the session is approved.
+	Message json.RawMessage
+	// ExpiresAt is the time at which the session expires if it isn't
+	// approved.
+	ExpiresAt time.Time
+	// Approvals are the approvers' signatures of the session's digest by
+	// approver id.
+	Approvals map[string][]byte
+}
+
+// Digest returns the digest approvers sign to approve the session.
+func (p *Pending) Digest() []byte {
+	return approval.Digest(p.ID, p.Request.signingRequest())
+}
+
+// expired checks if the session expired at the given time.
+func (p *Pending) expired(now time.Time) bool {
+	return now.After(p.ExpiresAt)
+}
+
+// PendingStore is an interface that stores of the co-signer's pending
+// sessions need to implement. Implementations need to be safe for concurrent
+// use.
+type PendingStore interface {
+	// Put stores the session or replaces the stored session with the same id.
+	Put(p *Pending) error
+	// Get returns the session with the given id. It returns
+	// ErrUnknownSession if no such session is stored.
+	Get(id string) (*Pending, error)
+	// Delete removes the session with the given id (if stored).
+	Delete(id string) error
+	// List returns all stored sessions.
+	List() ([]*Pending, error)
+}
+
+// MemoryPendingStore is an instance of a pending session store that keeps
+// the sessions in memory.
+type MemoryPendingStore struct {
+	mu      sync.RWMutex
+	pending map[string]*Pending
+}
+
+// NewMemoryPendingStore creates a new instance of an empty in-memory pending
+// session store.
+func NewMemoryPendingStore() *MemoryPendingStore {
+	return &MemoryPendingStore{
+		pending: make(map[string]*Pending),
+	}
+}
+
+// Put stores the session.
+// Returns an error if the session id is invalid.
+func (m *MemoryPendingStore) Put(p *Pending) error {
+	if err := checkKeyID(p.ID); err != nil {
+		return ErrUnknownSession
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pending[p.ID] = p
+
+	return nil
+}
+
+// Get returns the session with the given id.
+// Returns an error if no such session is stored.
+func (m *MemoryPendingStore) Get(id string) (*Pending, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.pending[id]
+	if !ok {
+		return nil, ErrUnknownSession
+	}
+
+	return p, nil
+}
+
+// Delete removes the session with the given id.
+func (m *MemoryPendingStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.pending, id)
+
+	return nil
+}
+
+// List returns all stored sessions ordered by id.
+func (m *MemoryPendingStore) List() ([]*Pending, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := make([]*Pending, 0, len(m.pending))
+	for _, p := range m.pending {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+
+	return list, nil
+}
+
+// pendingFile is the JSON representation of a pending session in a
+// DirPendingStore. The signatures are hex-encoded.
+type pendingFile struct {
+	Owner     string            `json:"owner"`
+	Request   *Request          `json:"request"`
+	Message   json.RawMessage   `json:"message,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	Approvals map[string]string `json:"approvals"`
+}
+
+// DirPendingStore is an instance of a pending session store that stores every
+// session in a file of a directory, so that pending sessions survive a
+// restart of the co-signer.
+type DirPendingStore struct {
+	dir string
+}
+
+// NewDirPendingStore creates a new instance of a pending session store that
+// stores the sessions in the given directory, which needs to exist.
+func NewDirPendingStore(dir string) *DirPendingStore {
+	return &DirPendingStore{
+		dir: dir,
+	}
+}
+
+// Put writes the session to its file. The file is replaced atomically.
+// Returns an error if the session id is invalid or the file can't be written.
+func (d *DirPendingStore) Put(p *Pending) error {
+	if err := checkKeyID(p.ID); err != nil {
+		return ErrUnknownSession
+	}
+
+	f := &pendingFile{
+		Owner:     p.Owner,
+		Request:   p.Request,
+		Message:   p.Message,
+		ExpiresAt: p.ExpiresAt,
+		Approvals: make(map[string]string, len(p.Approvals)),
+	}
+	for approverID, signature := range p.Approvals {
+		f.Approvals[approverID] = hex.EncodeToString(signature)
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(d.dir, p.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), d.path(p.ID))
+}
+
+// Get reads the session with the given id from its file.
+// Returns an error if the session id is invalid, no such session is stored or
+// the file can't be read.
+func (d *DirPendingStore) Get(id string) (*Pending, error) {
+	if err := checkKeyID(id); err != nil {
+		return nil, ErrUnknownSession
+	}
+
+	data, err := os.ReadFile(d.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUnknownSession
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	f := new(pendingFile)
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("invalid pending session file %s: %w", id, err)
+	}
+	if f.Request == nil {
+		return nil, fmt.Errorf("invalid pending session file %s: missing request", id)
+	}
+
+	p := &Pending{
+		ID:        id,
+		Owner:     f.Owner,
+		Request:   f.Request,
+		Message:   f.Message,
+		ExpiresAt: f.ExpiresAt,
+		Approvals: make(map[string][]byte, len(f.Approvals)),
+	}
+	for approverID, signature := range f.Approvals {
+		sig, err := hex.DecodeString(signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pending session file %s: %w", id, err)
+		}
+		p.Approvals[approverID] = sig
+	}
+
+	return p, nil
+}
+
+// Delete removes the file of the session with the given id.
+// Returns an error if the file exists but can't be removed.
+func (d *DirPendingStore) Delete(id string) error {
+	if err := checkKeyID(id); err != nil {
+		return nil
+	}
+
+	err := os.Remove(d.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// List reads all sessions of the directory ordered by id.
+// Returns an error if the directory or a file can't be read.
+func (d *DirPendingStore) List() ([]*Pending, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*Pending
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || checkKeyID(id) != nil {
+			continue
+		}
+
+		p, err := d.Get(id)
+		if errors.Is(err, ErrUnknownSession) {
+			// The session was deleted in the meantime.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+
+	return list, nil
+}
+
+// path returns the path of the session's file.
+func (d *DirPendingStore) path(id string) string {
+	return filepath.Join(d.dir, id+".json")
+}
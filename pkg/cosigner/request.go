@@ -0,0 +1,266 @@
+package cosigner
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/lindell17/pkg/approval"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// Protocol is the name of a protocol a session runs.
+type Protocol string
+
+const (
+	// Keygen is the key generation protocol.
+	Keygen Protocol = "keygen"
+	// Sign is the signing protocol.
+	Sign Protocol = "sign"
+	// Adaptor is the adaptor signature (pre-signing) protocol.
+	Adaptor Protocol = "adaptor"
+	// Refresh is the key refresh protocol, which isn't implemented yet.
+	Refresh Protocol = "refresh"
+)
+
+// Request is an instance of a request to open a session in which the
+// co-signer runs party 2 of a protocol.
+type Request struct {
+	// Protocol is the protocol to run.
+	Protocol Protocol
+	// KeyID is the id of the key to sign with (sign and adaptor only).
+	KeyID string
+	// Hash is the hash to sign (sign and adaptor only).
+	Hash []byte
+	// Statement is the adaptor statement (adaptor only).
+	Statement *adaptor.Statement
+	// StatementProof is the proof of knowledge of the statement's witness
+	// (adaptor only).
+	StatementProof *proofs.DLKProof
+	// Payload is the optional structured data the hash was computed from,
+	// which the co-signer's signing policy evaluates (sign and adaptor only).
+	Payload *lindell17.Payload
+}
+
+// NewKeygenRequest creates a new instance of a request to generate a key.
+func NewKeygenRequest() *Request {
+	return &Request{
+		Protocol: Keygen,
+	}
+}
+
+// NewSignRequest creates a new instance of a request to sign the hash with
+// the key.
+func NewSignRequest(keyID string, hash []byte) *Request {
+	return &Request{
+		Protocol: Sign,
+		KeyID:    keyID,
+		Hash:     hash,
+	}
+}
+
+// WithPayload sets the structured data the hash was computed from.
+// Returns the request to allow chaining.
+func (r *Request) WithPayload(payload *lindell17.Payload) *Request {
+	r.Payload = payload
+
+	return r
+}
+
+// NewAdaptorRequest creates a new instance of a request to pre-sign the hash
+// with the key for the statement.
+func NewAdaptorRequest(keyID string, hash []byte, stmt *adaptor.Statement, pStmt *proofs.DLKProof) *Request {
+	return &Request{
+		Protocol:       Adaptor,
+		KeyID:          keyID,
+		Hash:           hash,
+		Statement:      stmt,
+		StatementProof: pStmt,
+	}
+}
+
+// signingRequest returns the request in the form signing policies and
+// approvers evaluate.
+func (r *Request) signingRequest() *lindell17.SigningRequest {
+	var protocol lindell17.Protocol
+	switch r.Protocol {
+	case Sign:
+		protocol = lindell17.Sign
+	case Adaptor:
+		protocol = lindell17.Adaptor
+	}
+
+	return lindell17.NewSigningRequest(protocol, r.KeyID, r.Hash, r.Payload).WithStatement(r.Statement)
+}
+
+// PendingApproval is an instance of a session that waits for the approval of
+// a quorum as it's listed to approvers.
+type PendingApproval struct {
+	// SessionID is the session's id.
+	SessionID string `json:"session_id"`
+	// Owner is the identity of the client that opened the session.
+	Owner string `json:"owner"`
+	// Request is the client's request.
+	Request *Request `json:"request"`
+	// ApprovedBy are the ids of the approvers that approved the session.
+	ApprovedBy []string `json:"approved_by"`
+	// Threshold is the number of approvals the session requires.
+	Threshold int `json:"threshold"`
+	// ExpiresAt is the time at which the session expires if it isn't
+	// approved.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Digest computes the digest approvers sign to approve the session from the
+// session's id and request.
+func (p *PendingApproval) Digest() []byte {
+	return approval.Digest(p.SessionID, p.Request.signingRequest())
+}
+
+// statement is the codec representation of an adaptor statement together
+// with its proof.
+type statement struct {
+	Statement *adaptor.Statement
+	Proof     *proofs.DLKProof
+}
+
+// payload is the JSON representation of a payload. The amount is a decimal
+// integer and the data is hex-encoded.
+type payload struct {
+	Destination string `json:"destination,omitempty"`
+	Amount      string `json:"amount,omitempty"`
+	Data        string `json:"data,omitempty"`
+}
+
+// sessionRequest is the JSON representation of a request.
+type sessionRequest struct {
+	Protocol  Protocol        `json:"protocol"`
+	KeyID     string          `json:"key_id,omitempty"`
+	Hash      string          `json:"hash,omitempty"`
+	Statement json.RawMessage `json:"statement,omitempty"`
+	Payload   *payload        `json:"payload,omitempty"`
+}
+
+// sessionResponse is the JSON representation of the co-signer's response to
+// a request or message. Messages are encoded via the codec.
+type sessionResponse struct {
+	SessionID string            `json:"session_id"`
+	Messages  []json.RawMessage `json:"messages"`
+	Done      bool              `json:"done"`
+	KeyID     string            `json:"key_id,omitempty"`
+	PublicKey json.RawMessage   `json:"public_key,omitempty"`
+	Pending   bool              `json:"pending,omitempty"`
+	Approval  *approvalStatus   `json:"approval,omitempty"`
+}
+
+// approvalStatus is the JSON representation of the approval state of a
+// pending session.
+type approvalStatus struct {
+	Approvals int       `json:"approvals"`
+	Threshold int       `json:"threshold"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// approvalRequest is the JSON representation of an approver's approval. The
+// signature is hex-encoded.
+type approvalRequest struct {
+	Approver  string `json:"approver"`
+	Signature string `json:"signature"`
+}
+
+// keyResponse is the JSON representation of a stored key's public data.
+type keyResponse struct {
+	KeyID     string          `json:"key_id"`
+	PublicKey json.RawMessage `json:"public_key"`
+}
+
+// errorResponse is the JSON representation of a rejected request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// MarshalJSON encodes the request.
+func (r *Request) MarshalJSON() ([]byte, error) {
+	req := &sessionRequest{
+		Protocol: r.Protocol,
+		KeyID:    r.KeyID,
+	}
+	if r.Hash != nil {
+		req.Hash = hex.EncodeToString(r.Hash)
+	}
+	if r.Statement != nil || r.StatementProof != nil {
+		data, err := codec.MarshalValue(&statement{Statement: r.Statement, Proof: r.StatementProof})
+		if err != nil {
+			return nil, err
+		}
+		req.Statement = data
+	}
+	if r.Payload != nil {
+		req.Payload = &payload{
+			Destination: r.Payload.Destination,
+			Data:        hex.EncodeToString(r.Payload.Data),
+		}
+		if r.Payload.Amount != nil {
+			req.Payload.Amount = r.Payload.Amount.String()
+		}
+	}
+
+	return json.Marshal(req)
+}
+
+// UnmarshalJSON decodes the request.
+func (r *Request) UnmarshalJSON(data []byte) error {
+	req := new(sessionRequest)
+	if err := json.Unmarshal(data, req); err != nil {
+		return err
+	}
+
+	r.Protocol = req.Protocol
+	r.KeyID = req.KeyID
+	r.Hash = nil
+	r.Statement = nil
+	r.StatementProof = nil
+	r.Payload = nil
+
+	if req.Hash != "" {
+		hash, err := hex.DecodeString(req.Hash)
+		if err != nil {
+			return fmt.Errorf("invalid hash: %w", err)
+		}
+		r.Hash = hash
+	}
+	if len(req.Statement) != 0 {
+		stmt := new(statement)
+		if err := codec.UnmarshalValue(req.Statement, stmt); err != nil {
+			return fmt.Errorf("invalid statement: %w", err)
+		}
+		r.Statement = stmt.Statement
+		r.StatementProof = stmt.Proof
+	}
+	if req.Payload != nil {
+		r.Payload = &lindell17.Payload{
+			Destination: req.Payload.Destination,
+		}
+		if req.Payload.Amount != "" {
+			amount, ok := new(big.Int).SetString(req.Payload.Amount, 10)
+			if !ok {
+				return fmt.Errorf("invalid amount %q", req.Payload.Amount)
+			}
+			r.Payload.Amount = amount
+		}
+		if req.Payload.Data != "" {
+			data, err := hex.DecodeString(req.Payload.Data)
+			if err != nil {
+				return fmt.Errorf("invalid payload data: %w", err)
+			}
+			r.Payload.Data = data
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,927 @@
+package cosigner
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	aParty2 "github.com/primefactor-io/lindell17/pkg/adaptor/party2"
+	"github.com/primefactor-io/lindell17/pkg/approval"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	sParty2 "github.com/primefactor-io/lindell17/pkg/sign/party2"
+	"github.com/primefactor-io/lindell17/pkg/transport"
+)
+
+// DefaultSessionTimeout is the default time after which an idle session
+// expires.
+const DefaultSessionTimeout = 10 * time.Minute
+
+// DefaultMaxSessions is the default maximum number of open sessions.
+const DefaultMaxSessions = 64
+
+// DefaultApprovalTimeout is the default time after which a session that
+// waits for approval expires.
+const DefaultApprovalTimeout = time.Hour
+
+// DefaultMaxPending is the default maximum number of sessions that wait for
+// approval.
+const DefaultMaxPending = 256
+
+// DefaultMaxPendingPerOwner is the default maximum number of sessions of a
+// single client that wait for approval.
+const DefaultMaxPendingPerOwner = 16
+
+// channelSize is the capacity of the channels party 2 sends via. A single
+// call sends at most one message and one result.
+const channelSize = 4
+
+// Server is an instance of a co-signer that runs party 2 of the protocols
+// for authenticated clients via HTTP. It's safe for concurrent use.
+type Server struct {
+	curve            weierstrass.Curve
+	keystore         Keystore
+	rangeProofBits   int
+	nthRootProofBits int
+	limits           *lindell17.Limits
+	policy           lindell17.Policy
+	observer         lindell17.Observer
+	sessionTimeout   time.Duration
+	maxSessions      int
+	maxMessageSize   int64
+	quorum           *approval.Quorum
+	pending          PendingStore
+	approvalKeys     map[string]bool
+	approvalTimeout  time.Duration
+	approverClients  map[string]bool
+	maxPending       int
+	maxPendingOwner  int
+	mux              *http.ServeMux
+	mu               sync.Mutex
+	sessions         map[string]*session
+	// pendingMu serializes the updates of pending sessions.
+	pendingMu sync.Mutex
+}
+
+// NewServer creates a new instance of a co-signer that stores the keys it
+// generates in the given keystore.
+func NewServer(curve weierstrass.Curve, keystore Keystore) *Server {
+	s := &Server{
+		curve:            curve,
+		keystore:         keystore,
+		rangeProofBits:   40,
+		nthRootProofBits: 128,
+		limits:           lindell17.DefaultLimits(),
+		sessionTimeout:   DefaultSessionTimeout,
+		maxSessions:      DefaultMaxSessions,
+		maxMessageSize:   transport.DefaultMaxMessageSize,
+		approvalTimeout:  DefaultApprovalTimeout,
+		maxPending:       DefaultMaxPending,
+		maxPendingOwner:  DefaultMaxPendingPerOwner,
+		mux:              http.NewServeMux(),
+		sessions:         make(map[string]*session),
+	}
+
+	s.mux.HandleFunc("POST /v1/sessions", s.handleOpen)
+	s.mux.HandleFunc("POST /v1/sessions/{id}/messages", s.handleMessage)
+	s.mux.HandleFunc("POST /v1/sessions/{id}/resume", s.handleResume)
+	s.mux.HandleFunc("DELETE /v1/sessions/{id}", s.handleAbort)
+	s.mux.HandleFunc("GET /v1/keys/{id}", s.handleKey)
+	s.mux.HandleFunc("GET /v1/approvals", s.handleApprovals)
+	s.mux.HandleFunc("POST /v1/approvals/{id}", s.handleApprove)
+
+	return s
+}
+
+// WithProofBits sets the statistical security parameters of the range proof
+// and the Nth root proof party 2 expects during key generation.
+// Returns the server to allow chaining.
+func (s *Server) WithProofBits(rangeProofBits, nthRootProofBits int) *Server {
+	s.rangeProofBits = rangeProofBits
+	s.nthRootProofBits = nthRootProofBits
+
+	return s
+}
+
+// WithLimits sets the resource limits party 2 enforces on incoming messages.
+// A nil value restores the default limits.
+// Returns the server to allow chaining.
+func (s *Server) WithLimits(limits *lindell17.Limits) *Server {
+	if limits == nil {
+		limits = lindell17.DefaultLimits()
+	}
+
+	s.limits = limits
+
+	return s
+}
+
+// WithPolicy sets the signing policy party 2 evaluates before it contributes
+// to a signature. The policy sees the key id and the request's payload.
+// Returns the server to allow chaining.
+func (s *Server) WithPolicy(policy lindell17.Policy) *Server {
+	s.policy = policy
+
+	return s
+}
+
+// WithObserver sets the observer party 2 reports the events of its protocol
+// runs to.
+// Returns the server to allow chaining.
+func (s *Server) WithObserver(observer lindell17.Observer) *Server {
+	s.observer = observer
+
+	return s
+}
+
+// WithApproval requires the approval of the quorum before party 2
+// contributes to a signature with one of the given keys (or any key if none
+// are given). Sessions that wait for approval are kept in the given store.
+// Returns the server to allow chaining.
+func (s *Server) WithApproval(quorum *approval.Quorum, store PendingStore, keyIDs ...string) *Server {
+	s.quorum = quorum
+	s.pending = store
+	s.approvalKeys = nil
+	if len(keyIDs) > 0 {
+		s.approvalKeys = make(map[string]bool, len(keyIDs))
+		for _, keyID := range keyIDs {
+			s.approvalKeys[keyID] = true
+		}
+	}
+
+	return s
+}
+
+// WithApprovalTimeout sets the time after which a session that waits for
+// approval expires.
+// Returns the server to allow chaining.
+func (s *Server) WithApprovalTimeout(timeout time.Duration) *Server {
+	s.approvalTimeout = timeout
+
+	return s
+}
+
+// WithApproverClients sets the identities of the clients that list every
+// session that waits for approval (e.g. the clients the approvers use). Other
+// clients only list their own sessions.
+// Returns the server to allow chaining.
+func (s *Server) WithApproverClients(identities ...string) *Server {
+	s.approverClients = make(map[string]bool, len(identities))
+	for _, identity := range identities {
+		s.approverClients[identity] = true
+	}
+
+	return s
+}
+
+// WithMaxPending sets the maximum number of sessions that wait for approval,
+// in total and per client.
+// Returns the server to allow chaining.
+func (s *Server) WithMaxPending(maxPending, maxPerOwner int) *Server {
+	s.maxPending = maxPending
+	s.maxPendingOwner = maxPerOwner
+
+	return s
+}
+
+// WithSessionTimeout sets the time after which an idle session expires.
+// Returns the server to allow chaining.
+func (s *Server) WithSessionTimeout(timeout time.Duration) *Server {
+	s.sessionTimeout = timeout
+
+	return s
+}
+
+// WithMaxSessions sets the maximum number of open sessions.
+// Returns the server to allow chaining.
+func (s *Server) WithMaxSessions(maxSessions int) *Server {
+	s.maxSessions = maxSessions
+
+	return s
+}
+
+// WithMaxMessageSize sets the maximum size of a request body in bytes.
+// Returns the server to allow chaining.
+func (s *Server) WithMaxMessageSize(size int) *Server {
+	s.maxMessageSize = int64(size)
+
+	return s
+}
+
+// ServeHTTP handles the client's request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleOpen opens a session and starts party 2.
+func (s *Server) handleOpen(w http.ResponseWriter, r *http.Request) {
+	owner, err := authenticate(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	req := new(Request)
+	if err := s.decode(w, r, req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	id, err := randomID()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// The session is created before it's held for approval, so that invalid
+	// requests are rejected right away.
+	sess, err := s.newSession(id, owner, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if s.requiresApproval(req) {
+		s.hold(w, &Pending{
+			ID:        id,
+			Owner:     owner,
+			Request:   req,
+			ExpiresAt: time.Now().Add(s.approvalTimeout),
+			Approvals: make(map[string][]byte),
+		})
+		return
+	}
+
+	if err := s.addSession(sess); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.step(w, sess, sess.party.Start)
+}
+
+// handleMessage lets party 2 process the client's message.
+func (s *Server) handleMessage(w http.ResponseWriter, r *http.Request) {
+	owner, err := authenticate(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxMessageSize)
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: %w", ErrInvalidRequest, err))
+		return
+	}
+	msg, err := codec.UnmarshalMessage(data)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: %w", ErrInvalidRequest, err))
+		return
+	}
+
+	id := r.PathValue("id")
+	sess, err := s.session(owner, id)
+	if errors.Is(err, ErrUnknownSession) && s.pending != nil {
+		s.holdMessage(w, owner, id, data)
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.step(w, sess, func() (bool, error) {
+		return sess.party.Process(msg)
+	})
+}
+
+// handleAbort closes the client's session.
+func (s *Server) handleAbort(w http.ResponseWriter, r *http.Request) {
+	owner, err := authenticate(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	id := r.PathValue("id")
+	sess, err := s.session(owner, id)
+	if errors.Is(err, ErrUnknownSession) && s.pending != nil {
+		err = s.abortPending(owner, id)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if sess != nil {
+		s.removeSession(sess.id)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleResume starts party 2 of the client's pending session once it's
+// approved and lets it process the held message. Responds with the approval
+// state as long as the session isn't approved.
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	owner, err := authenticate(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	p, approved, err := s.takeApproved(owner, r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !approved {
+		writeJSON(w, http.StatusAccepted, s.pendingResponse(p))
+		return
+	}
+
+	var msg lindell17.Message
+	if p.Message != nil {
+		if msg, err = codec.UnmarshalMessage(p.Message); err != nil {
+			writeError(w, fmt.Errorf("%w: %w", ErrInvalidRequest, err))
+			return
+		}
+	}
+
+	sess, err := s.newSession(p.ID, owner, p.Request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.addSession(sess); err != nil {
+		// The session stays approved, so that the client can resume it once
+		// other sessions were closed.
+		s.pendingMu.Lock()
+		s.pending.Put(p)
+		s.pendingMu.Unlock()
+		writeError(w, err)
+		return
+	}
+
+	s.step(w, sess, func() (bool, error) {
+		done, err := sess.party.Start()
+		if err != nil || msg == nil {
+			return done, err
+		}
+		return sess.party.Process(msg)
+	})
+}
+
+// handleApprovals lists the sessions that wait for approval. Approver clients
+// list every session, other clients only their own. Approvers need to review
+// the requests and compute the digests they sign themselves.
+func (s *Server) handleApprovals(w http.ResponseWriter, r *http.Request) {
+	owner, err := authenticate(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	list := make([]*PendingApproval, 0)
+	if s.pending != nil {
+		s.pendingMu.Lock()
+		defer s.pendingMu.Unlock()
+
+		pending, err := s.pending.List()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		now := time.Now()
+		for _, p := range pending {
+			if p.expired(now) {
+				s.pending.Delete(p.ID)
+				continue
+			}
+			if p.Owner != owner && !s.approverClients[owner] {
+				continue
+			}
+			list = append(list, s.pendingApproval(p))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// handleApprove adds an approver's approval to a pending session. The
+// approval is authorized by the approver's signature, not by the client that
+// submits it.
+func (s *Server) handleApprove(w http.ResponseWriter, r *http.Request) {
+	if _, err := authenticate(r); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	req := new(approvalRequest)
+	if err := s.decode(w, r, req); err != nil {
+		writeError(w, err)
+		return
+	}
+	signature, err := hex.DecodeString(req.Signature)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: invalid signature: %w", ErrInvalidRequest, err))
+		return
+	}
+
+	if s.pending == nil {
+		writeError(w, ErrUnknownSession)
+		return
+	}
+
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	p, err := s.livePending(r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := s.quorum.Verify(req.Approver, p.Digest(), signature); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	p.Approvals[req.Approver] = signature
+	if err := s.pending.Put(p); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.pendingApproval(p))
+}
+
+// handleKey returns the public data of the client's key.
+func (s *Server) handleKey(w http.ResponseWriter, r *http.Request) {
+	owner, err := authenticate(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	key, err := s.key(owner, r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	q, err := codec.MarshalValue(key.KeyMaterial.Q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &keyResponse{KeyID: key.ID, PublicKey: q})
+}
+
+// newSession creates a session with the given id and a fresh party 2 for the
+// request.
+// Returns an error if the request is invalid or the key isn't the client's.
+func (s *Server) newSession(id, owner string, req *Request) (*session, error) {
+	sess := &session{
+		id:    id,
+		owner: owner,
+		outCh: make(chan lindell17.Message, channelSize),
+		resCh: make(chan lindell17.Result, channelSize),
+	}
+
+	switch req.Protocol {
+	case Keygen:
+		params := kParty2.NewParams(s.curve, s.rangeProofBits, s.nthRootProofBits).WithLimits(s.limits).WithObserver(s.observer)
+		sess.party = kParty2.NewParty2(params, sess.outCh, sess.resCh)
+	case Sign:
+		key, err := s.key(owner, req.KeyID)
+		if err != nil {
+			return nil, err
+		}
+		km := key.KeyMaterial
+		params := sParty2.NewParams(s.curve, km.Pk, km.X1Enc, km.X2).
+			WithLimits(s.limits).
+			WithObserver(s.observer).
+			WithPayload(req.Payload)
+		if s.policy != nil {
+			params.WithPolicy(s.policy, key.ID)
+		}
+		sess.party = sParty2.NewParty2(params, req.Hash, sess.outCh, sess.resCh)
+	case Adaptor:
+		if req.Statement == nil || req.StatementProof == nil {
+			return nil, fmt.Errorf("%w: missing statement", ErrInvalidRequest)
+		}
+		key, err := s.key(owner, req.KeyID)
+		if err != nil {
+			return nil, err
+		}
+		km := key.KeyMaterial
+		params := aParty2.NewParams(s.curve, km.Pk, km.Q, km.X1Enc, km.X2).
+			WithLimits(s.limits).
+			WithObserver(s.observer).
+			WithPayload(req.Payload)
+		if s.policy != nil {
+			params.WithPolicy(s.policy, key.ID)
+		}
+		sess.party = aParty2.NewParty2(params, req.Hash, req.Statement, req.StatementProof, sess.outCh, sess.resCh)
+	case Refresh:
+		// There's no key refresh protocol yet, so keys can't be refreshed.
+		return nil, fmt.Errorf("%w: %q isn't implemented", ErrUnsupportedProtocol, req.Protocol)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedProtocol, req.Protocol)
+	}
+
+	return sess, nil
+}
+
+// requiresApproval checks if the request needs to be approved by the quorum
+// before party 2 is started.
+func (s *Server) requiresApproval(req *Request) bool {
+	if s.quorum == nil || (req.Protocol != Sign && req.Protocol != Adaptor) {
+		return false
+	}
+
+	return s.approvalKeys == nil || s.approvalKeys[req.KeyID]
+}
+
+// hold stores the pending session and responds with its approval state.
+// Responds with ErrTooManyPending if the maximum number of pending sessions
+// in total or of the client is reached.
+func (s *Server) hold(w http.ResponseWriter, p *Pending) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	pending, err := s.pending.List()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var total, owned int
+	now := time.Now()
+	for _, other := range pending {
+		if other.expired(now) {
+			s.pending.Delete(other.ID)
+			continue
+		}
+		total++
+		if other.Owner == p.Owner {
+			owned++
+		}
+	}
+	if total >= s.maxPending || owned >= s.maxPendingOwner {
+		writeError(w, ErrTooManyPending)
+		return
+	}
+
+	if err := s.pending.Put(p); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, s.pendingResponse(p))
+}
+
+// holdMessage stores the client's first message in its pending session until
+// the session is approved.
+func (s *Server) holdMessage(w http.ResponseWriter, owner, id string, data []byte) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	p, err := s.livePending(id)
+	if err == nil && p.Owner != owner {
+		err = ErrUnknownSession
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if p.Message != nil {
+		writeError(w, ErrPendingApproval)
+		return
+	}
+
+	p.Message = data
+	if err := s.pending.Put(p); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, s.pendingResponse(p))
+}
+
+// takeApproved returns the client's pending session and whether it's
+// approved. An approved session is removed from the store, so that it can
+// only be resumed once.
+// Returns an error if the session doesn't exist, expired or belongs to
+// another client.
+func (s *Server) takeApproved(owner, id string) (*Pending, bool, error) {
+	if s.pending == nil {
+		return nil, false, ErrUnknownSession
+	}
+
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	p, err := s.livePending(id)
+	if err != nil {
+		return nil, false, err
+	}
+	if p.Owner != owner {
+		return nil, false, ErrUnknownSession
+	}
+
+	if !s.quorum.Approved(p.Digest(), p.Approvals) {
+		return p, false, nil
+	}
+	if err := s.pending.Delete(p.ID); err != nil {
+		return nil, false, err
+	}
+
+	return p, true, nil
+}
+
+// abortPending removes the client's pending session.
+// Returns an error if the session doesn't exist or belongs to another client.
+func (s *Server) abortPending(owner, id string) error {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	p, err := s.pending.Get(id)
+	if err != nil {
+		return err
+	}
+	if p.Owner != owner {
+		return ErrUnknownSession
+	}
+
+	return s.pending.Delete(id)
+}
+
+// livePending returns the pending session with the given id. Expired sessions
+// are removed. The caller needs to hold pendingMu.
+// Returns an error if the session doesn't exist or expired.
+func (s *Server) livePending(id string) (*Pending, error) {
+	p, err := s.pending.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if p.expired(time.Now()) {
+		s.pending.Delete(id)
+		return nil, ErrApprovalExpired
+	}
+
+	return p, nil
+}
+
+// pendingResponse returns the response to a request or message of a pending
+// session.
+func (s *Server) pendingResponse(p *Pending) *sessionResponse {
+	return &sessionResponse{
+		SessionID: p.ID,
+		Messages:  []json.RawMessage{},
+		Pending:   true,
+		Approval: &approvalStatus{
+			Approvals: s.quorum.Count(p.Digest(), p.Approvals),
+			Threshold: s.quorum.Threshold(),
+			ExpiresAt: p.ExpiresAt,
+		},
+	}
+}
+
+// pendingApproval returns the pending session as it's listed to approvers.
+func (s *Server) pendingApproval(p *Pending) *PendingApproval {
+	digest := p.Digest()
+	approvedBy := make([]string, 0, len(p.Approvals))
+	for approverID, signature := range p.Approvals {
+		if s.quorum.Verify(approverID, digest, signature) == nil {
+			approvedBy = append(approvedBy, approverID)
+		}
+	}
+	sort.Strings(approvedBy)
+
+	return &PendingApproval{
+		SessionID:  p.ID,
+		Owner:      p.Owner,
+		Request:    p.Request,
+		ApprovedBy: approvedBy,
+		Threshold:  s.quorum.Threshold(),
+		ExpiresAt:  p.ExpiresAt,
+	}
+}
+
+// key returns the client's key with the given id.
+// Returns an error if the key doesn't exist or belongs to another client.
+func (s *Server) key(owner, id string) (*Key, error) {
+	key, err := s.keystore.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if key.Owner != owner {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+// step runs the party's Start or Process call and responds with the messages
+// party 2 emitted. The session is closed once party 2 emitted its result or
+// failed. The key material of a key generation run is stored as a new key.
+func (s *Server) step(w http.ResponseWriter, sess *session, call func() (bool, error)) {
+	msgs, res, err := sess.step(call)
+	if err != nil {
+		s.removeSession(sess.id)
+		writeError(w, fmt.Errorf("%w: %w", ErrRunProtocol, err))
+		return
+	}
+
+	resp := &sessionResponse{
+		SessionID: sess.id,
+		Messages:  make([]json.RawMessage, 0, len(msgs)),
+		Done:      res != nil,
+	}
+	for _, msg := range msgs {
+		data, err := codec.MarshalMessage(msg)
+		if err != nil {
+			s.removeSession(sess.id)
+			writeError(w, err)
+			return
+		}
+		resp.Messages = append(resp.Messages, data)
+	}
+
+	if res != nil {
+		s.removeSession(sess.id)
+
+		// Party 2's key material never leaves the co-signer, only the id and
+		// the public key are sent to the client.
+		if res, ok := res.(*kParty2.Result); ok {
+			key, err := NewKey(sess.owner, res.KeyMaterial)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			if err := s.keystore.Put(key); err != nil {
+				writeError(w, err)
+				return
+			}
+
+			q, err := codec.MarshalValue(res.KeyMaterial.Q)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			resp.KeyID = key.ID
+			resp.PublicKey = q
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// addSession adds the session after removing the expired ones.
+// Returns an error if the maximum number of sessions is reached.
+func (s *Server) addSession(sess *session) error {
+	// The parties of expired sessions are destroyed without holding the lock.
+	var expired []*session
+	defer func() { destroySessions(expired) }()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, other := range s.sessions {
+		if other.expired(now) {
+			delete(s.sessions, id)
+			expired = append(expired, other)
+		}
+	}
+
+	if len(s.sessions) >= s.maxSessions {
+		return ErrTooManySessions
+	}
+
+	sess.touch(now.Add(s.sessionTimeout))
+	s.sessions[sess.id] = sess
+
+	return nil
+}
+
+// session returns the client's open session with the given id and extends
+// its expiry.
+// Returns an error if the session doesn't exist, expired or belongs to
+// another client.
+func (s *Server) session(owner, id string) (*session, error) {
+	// The party of an expired session is destroyed without holding the lock.
+	var expired []*session
+	defer func() { destroySessions(expired) }()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	sess, ok := s.sessions[id]
+	if !ok || sess.owner != owner {
+		return nil, ErrUnknownSession
+	}
+	if sess.expired(now) {
+		delete(s.sessions, id)
+		expired = append(expired, sess)
+		return nil, ErrUnknownSession
+	}
+
+	sess.touch(now.Add(s.sessionTimeout))
+
+	return sess, nil
+}
+
+// removeSession removes the session with the given id and destroys its
+// party.
+func (s *Server) removeSession(id string) {
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	if ok {
+		sess.destroy()
+	}
+}
+
+// decode decodes the JSON request body into the value.
+// Returns an error if the body is too large or can't be decoded.
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, v any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxMessageSize)
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	return nil
+}
+
+// authenticate returns the identity of the client, which is the common name
+// of its verified certificate.
+// Returns an error if the client didn't present a verified certificate.
+func authenticate(r *http.Request) (string, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", ErrUnauthenticated
+	}
+
+	owner := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	if owner == "" {
+		return "", ErrUnauthenticated
+	}
+
+	return owner, nil
+}
+
+// writeError responds with the error's status code and message. The details
+// of internal errors aren't sent to the client.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		status = http.StatusUnauthorized
+	case errors.Is(err, ErrInvalidRequest), errors.Is(err, ErrInvalidKeyID):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrUnknownKey), errors.Is(err, ErrUnknownSession):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrPendingApproval):
+		status = http.StatusConflict
+	case errors.Is(err, ErrApprovalExpired):
+		status = http.StatusGone
+	case errors.Is(err, approval.ErrUnknownApprover), errors.Is(err, approval.ErrInvalidSignature):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrTooManySessions), errors.Is(err, ErrTooManyPending):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, ErrUnsupportedProtocol):
+		status = http.StatusNotImplemented
+	case errors.Is(err, lindell17.ErrPolicyDenied):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrRunProtocol):
+		status = http.StatusUnprocessableEntity
+	}
+
+	msg := err.Error()
+	if status == http.StatusInternalServerError {
+		msg = http.StatusText(status)
+	}
+
+	writeJSON(w, status, &errorResponse{Error: msg})
+}
+
+// writeJSON responds with the value encoded as JSON.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
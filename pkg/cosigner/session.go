@@ -0,0 +1,70 @@
+package cosigner
+
+import (
+	"sync"
+	"time"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// session is an instance of a protocol run of party 2 for a client.
+type session struct {
+	id    string
+	owner string
+	party lindell17.Participant
+	outCh chan lindell17.Message
+	resCh chan lindell17.Result
+	// mu serializes the party's calls.
+	mu sync.Mutex
+	// expiresAt is guarded by the server's mutex.
+	expiresAt time.Time
+}
+
+// step runs the party's Start or Process call.
+// Returns the messages and the result the party emitted or the party's error.
+func (s *session) step(call func() (bool, error)) ([]lindell17.Message, lindell17.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := call()
+
+	var msgs []lindell17.Message
+	var res lindell17.Result
+	for {
+		select {
+		case msg := <-s.outCh:
+			msgs = append(msgs, msg)
+		case r := <-s.resCh:
+			res = r
+		default:
+			return msgs, res, err
+		}
+	}
+}
+
+// destroy wipes the party's secrets once its current call returned.
+func (s *session) destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.party.(lindell17.Destroyer); ok {
+		d.Destroy()
+	}
+}
+
+// destroySessions destroys the parties of the sessions.
+func destroySessions(sessions []*session) {
+	for _, sess := range sessions {
+		sess.destroy()
+	}
+}
+
+// touch sets the time at which the session expires.
+func (s *session) touch(expiresAt time.Time) {
+	s.expiresAt = expiresAt
+}
+
+// expired checks if the session expired at the given time.
+func (s *session) expired(now time.Time) bool {
+	return now.After(s.expiresAt)
+}
@@ -0,0 +1,29 @@
+package cosigner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+)
+
+// ServerTLSConfig creates a TLS configuration for the co-signer that requires
+// every client to present a certificate that was issued by one of the given
+// certificate authorities.
+func ServerTLSConfig(cert tls.Certificate, clientCAs *x509.CertPool) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS13,
+	}
+}
+
+// ClientTLSConfig creates a TLS configuration for a client that authenticates
+// with the given certificate and trusts the co-signer's certificate if it was
+// issued by one of the given certificate authorities.
+func ClientTLSConfig(cert tls.Certificate, rootCAs *x509.CertPool) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS13,
+	}
+}
@@ -0,0 +1,173 @@
+package crt
+
+import (
+	"math/big"
+
+	"github.com/primefactor-io/lindell17/pkg/utils"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// Decrypter is an instance of a Paillier decrypter that decrypts via the
+// Chinese Remainder Theorem. It's safe for concurrent use.
+type Decrypter struct {
+	nn *big.Int
+	p  *prime
+	q  *prime
+	// qInv is q^-1 mod p.
+	qInv *big.Int
+}
+
+// prime holds the precomputed constants of the decryption modulo p^2 for one
+// of the prime factors p.
+type prime struct {
+	p *big.Int
+	// pp is p^2.
+	pp *big.Int
+	// pMinusOne is p - 1.
+	pMinusOne *big.Int
+	// h is L_p(g^(p-1) mod p^2)^-1 mod p.
+	h *big.Int
+}
+
+// NewDecrypter creates a new instance of a decrypter for the private key.
+// Returns an error if the private key's modulus can't be factored.
+func NewDecrypter(sk *keys.PrivateKey) (*Decrypter, error) {
+	p, q, err := Factor(sk)
+	if err != nil {
+		return nil, err
+	}
+
+	g := new(big.Int).Add(sk.N, big.NewInt(1)) // n + 1
+
+	pc, err := newPrime(p, g)
+	if err != nil {
+		return nil, err
+	}
+	qc, err := newPrime(q, g)
+	if err != nil {
+		return nil, err
+	}
+
+	qInv := new(big.Int).ModInverse(q, p) // q^-1 mod p
+	if qInv == nil {
+		return nil, ErrFactorModulus
+	}
+
+	return &Decrypter{
+		nn:   new(big.Int).Set(sk.NN),
+		p:    pc,
+		q:    qc,
+		qInv: qInv,
+	}, nil
+}
+
+// Factor recovers the prime factors p and q (p > q) of the private key's
+// modulus N from N and phi(N).
+// Returns an error if phi(N) doesn't belong to a product of two primes.
+func Factor(sk *keys.PrivateKey) (*big.Int, *big.Int, error) {
+	if sk == nil || sk.N == nil || sk.PhiN == nil || sk.N.Sign() <= 0 || sk.PhiN.Sign() <= 0 {
+		return nil, nil, ErrFactorModulus
+	}
+
+	// p + q = N - phi(N) + 1.
+	s := new(big.Int).Sub(sk.N, sk.PhiN)
+	s.Add(s, big.NewInt(1))
+
+	// p - q = sqrt((p + q)^2 - 4N).
+	disc := new(big.Int).Mul(s, s)
+	disc.Sub(disc, new(big.Int).Lsh(sk.N, 2))
+	if disc.Sign() <= 0 {
+		return nil, nil, ErrFactorModulus
+	}
+	d := new(big.Int).Sqrt(disc)
+	if new(big.Int).Mul(d, d).Cmp(disc) != 0 {
+		return nil, nil, ErrFactorModulus
+	}
+
+	p := new(big.Int).Add(s, d)
+	p.Rsh(p, 1) // (s + d) / 2
+	q := new(big.Int).Sub(s, d)
+	q.Rsh(q, 1) // (s - d) / 2
+
+	if q.Cmp(big.NewInt(1)) <= 0 || new(big.Int).Mul(p, q).Cmp(sk.N) != 0 {
+		return nil, nil, ErrFactorModulus
+	}
+
+	return p, q, nil
+}
+
+// Decrypt decrypts the ciphertext. It's the equivalent of cipher.Decrypt.
+// Returns an error if the decrypter was destroyed or the ciphertext is too
+// long.
+func (d *Decrypter) Decrypt(ciphertext cipher.Ciphertext) (cipher.Plaintext, error) {
+	if d.p == nil {
+		return nil, ErrDestroyed
+	}
+
+	c := new(big.Int).SetBytes(ciphertext)
+	if c.Cmp(d.nn) >= 1 {
+		return nil, cipher.ErrCiphertextTooLong
+	}
+
+	mp := d.p.decrypt(c)
+	mq := d.q.decrypt(c)
+
+	// m = mq + q * ((mp - mq) * q^-1 mod p).
+	m := new(big.Int).Sub(mp, mq)
+	m.Mul(m, d.qInv)
+	m.Mod(m, d.p.p)
+	m.Mul(m, d.q.p)
+	m.Add(m, mq)
+
+	return m.Bytes(), nil
+}
+
+// Destroy wipes the prime factors and the constants derived from them. The
+// decrypter can't be used afterwards and must not be in use concurrently.
+func (d *Decrypter) Destroy() {
+	for _, pc := range []*prime{d.p, d.q} {
+		if pc != nil {
+			utils.Wipe(pc.p, pc.pp, pc.pMinusOne, pc.h)
+		}
+	}
+	utils.Wipe(d.qInv)
+
+	d.p = nil
+	d.q = nil
+	d.qInv = nil
+}
+
+// newPrime precomputes the decryption constants for the prime factor p and
+// the generator g.
+// Returns an error if the constants can't be computed.
+func newPrime(p, g *big.Int) (*prime, error) {
+	pc := &prime{
+		p:         p,
+		pp:        new(big.Int).Mul(p, p),
+		pMinusOne: new(big.Int).Sub(p, big.NewInt(1)),
+	}
+
+	gp := new(big.Int).Exp(g, pc.pMinusOne, pc.pp) // g^(p-1) mod p^2
+	h := new(big.Int).ModInverse(pc.l(gp), p)      // L_p(g^(p-1) mod p^2)^-1 mod p
+	if h == nil {
+		return nil, ErrFactorModulus
+	}
+	pc.h = h
+
+	return pc, nil
+}
+
+// decrypt decrypts the ciphertext modulo p.
+func (pc *prime) decrypt(c *big.Int) *big.Int {
+	x := new(big.Int).Exp(c, pc.pMinusOne, pc.pp) // c^(p-1) mod p^2
+	m := pc.l(x)                                  // L_p(c^(p-1) mod p^2)
+	m.Mul(m, pc.h)                                // L_p(c^(p-1) mod p^2) * h_p
+	return m.Mod(m, pc.p)                         // L_p(c^(p-1) mod p^2) * h_p mod p
+}
+
+// l computes L_p(x) = (x - 1) / p.
+func (pc *prime) l(x *big.Int) *big.Int {
+	res := new(big.Int).Sub(x, big.NewInt(1))
+	return res.Div(res, pc.p)
+}
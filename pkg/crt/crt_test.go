@@ -0,0 +1,140 @@
+package crt_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lindell17/pkg/crt"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+const paillierBits = 1024
+
+var sk *keys.PrivateKey
+var pk *keys.PublicKey
+
+func TestMain(m *testing.M) {
+	sk, pk, _ = keys.GenerateKeys(paillierBits)
+
+	m.Run()
+}
+
+func TestCRT(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Factor", func(t *testing.T) {
+		t.Parallel()
+
+		p, q, err := crt.Factor(sk)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if new(big.Int).Mul(p, q).Cmp(sk.N) != 0 {
+			t.Fatal("Factors don't multiply to N")
+		}
+		if !p.ProbablyPrime(20) || !q.ProbablyPrime(20) {
+			t.Fatal("Factors aren't prime")
+		}
+	})
+
+	t.Run("Decrypt (equals cipher.Decrypt)", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := crt.NewDecrypter(sk)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		nMinusOne := new(big.Int).Sub(pk.N, big.NewInt(1))
+		plaintexts := []*big.Int{big.NewInt(0), big.NewInt(1), nMinusOne}
+		for range 16 {
+			m, _ := rand.Int(rand.Reader, pk.N)
+			plaintexts = append(plaintexts, m)
+		}
+
+		for _, m := range plaintexts {
+			ciphertext, _ := cipher.Encrypt(pk, m.Bytes())
+
+			want, _ := cipher.Decrypt(sk, ciphertext)
+			got, err := d.Decrypt(ciphertext)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Fatalf("want plaintext %x, got %x", []byte(want), []byte(got))
+			}
+		}
+	})
+
+	t.Run("Decrypt - Invalid (Ciphertext too long)", func(t *testing.T) {
+		t.Parallel()
+
+		d, _ := crt.NewDecrypter(sk)
+
+		ciphertext := new(big.Int).Add(sk.NN, big.NewInt(1)).Bytes()
+		_, err := d.Decrypt(ciphertext)
+
+		if !errors.Is(err, cipher.ErrCiphertextTooLong) {
+			t.Errorf("want error %v, got %v", cipher.ErrCiphertextTooLong, err)
+		}
+	})
+
+	t.Run("Decrypt - Invalid (Destroyed)", func(t *testing.T) {
+		t.Parallel()
+
+		d, _ := crt.NewDecrypter(sk)
+		d.Destroy()
+
+		ciphertext, _ := cipher.Encrypt(pk, []byte{0x1})
+		_, err := d.Decrypt(ciphertext)
+
+		if !errors.Is(err, crt.ErrDestroyed) {
+			t.Errorf("want error %v, got %v", crt.ErrDestroyed, err)
+		}
+	})
+
+	t.Run("NewDecrypter - Invalid (phi(N))", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := *sk
+		invalid.PhiN = new(big.Int).Sub(sk.PhiN, big.NewInt(2))
+
+		_, err := crt.NewDecrypter(&invalid)
+
+		if !errors.Is(err, crt.ErrFactorModulus) {
+			t.Errorf("want error %v, got %v", crt.ErrFactorModulus, err)
+		}
+	})
+}
+
+func BenchmarkDecrypt(b *testing.B) {
+	for _, bits := range []int{1024, 2048, 3072} {
+		sk, pk, _ := keys.GenerateKeys(bits)
+		m, _ := rand.Int(rand.Reader, pk.N)
+		ciphertext, _ := cipher.Encrypt(pk, m.Bytes())
+
+		d, err := crt.NewDecrypter(sk)
+		if err != nil {
+			b.Fatalf("expected no error, got %v", err)
+		}
+
+		b.Run(fmt.Sprintf("%d/cipher.Decrypt", bits), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				cipher.Decrypt(sk, ciphertext)
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d/CRT", bits), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				d.Decrypt(ciphertext)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+/*
+Package crt implements Paillier decryption via the Chinese Remainder Theorem.
+
+A Paillier private key stores N and phi(N) from which the prime factors p and q
+of N can be recovered (p + q = N - phi(N) + 1). A Decrypter recovers them once,
+precomputes the constants of the decryption modulo p^2 and q^2 and combines the
+two half-size results via the Chinese Remainder Theorem. Exponentiation with
+half-size moduli and exponents is several times faster than the exponentiation
+modulo N^2 cipher.Decrypt uses.
+
+The decrypted plaintext equals the one cipher.Decrypt returns for every
+ciphertext that is a unit in Z*_{N^2} (see lindell17.CheckCiphertext).
+*/
+package crt
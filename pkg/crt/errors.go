@@ -0,0 +1,11 @@
+package crt
+
+import "fmt"
+
+var (
+	// ErrFactorModulus is returned if the modulus can't be factored with the
+	// private key's phi(N).
+	ErrFactorModulus = fmt.Errorf("unable to factor modulus")
+	// ErrDestroyed is returned if the decrypter was destroyed.
+	ErrDestroyed = fmt.Errorf("decrypter destroyed")
+)
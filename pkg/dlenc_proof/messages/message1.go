@@ -1,9 +1,13 @@
 package messages
 
 import (
+	"fmt"
+
 	"github.com/primefactor-io/commitment/pkg/hash"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
 	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message1 is the protocol's first message that is sent from the verifier to
@@ -47,7 +51,19 @@ func (m *Message1) SessionId() string {
 }
 
 func (m *Message1) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.CRandVals != nil &&
 		m.Ciphertext != nil
 }
+
+func (m *Message1) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	if err := lindell17.CheckCiphertext(pk, m.Ciphertext); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+
+	return nil
+}
@@ -2,7 +2,9 @@ package messages
 
 import (
 	"github.com/primefactor-io/commitment/pkg/hash"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message2 is the protocol's second message that is sent from the prover to the
@@ -43,6 +45,14 @@ func (m *Message2) SessionId() string {
 }
 
 func (m *Message2) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.CQHat != nil
 }
+
+func (m *Message2) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	return nil
+}
@@ -1,9 +1,12 @@
 package messages
 
 import (
+	"fmt"
 	"math/big"
 
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message3 is the protocol's third message that is sent from the verifier to
@@ -47,7 +50,24 @@ func (m *Message3) SessionId() string {
 }
 
 func (m *Message3) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.A != nil &&
 		m.B != nil
 }
+
+func (m *Message3) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	qq := new(big.Int).Mul(curve.N(), curve.N()) // q^2
+
+	if err := lindell17.CheckScalar(m.A, big.NewInt(0), curve.N()); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+	if err := lindell17.CheckScalar(m.B, big.NewInt(0), qq); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+
+	return nil
+}
@@ -1,8 +1,12 @@
 package messages
 
 import (
+	"fmt"
+
 	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message4 is the protocol's fourth message that is sent from the prover to the
@@ -43,6 +47,18 @@ func (m *Message4) SessionId() string {
 }
 
 func (m *Message4) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.QHat != nil
 }
+
+func (m *Message4) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	if err := lindell17.CheckPoint(curve, m.QHat); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+
+	return nil
+}
@@ -1,16 +1,23 @@
 package dlencproof_test
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 
 	"github.com/primefactor-io/ecc/pkg/curves"
 	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/prover"
 	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/verifier"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/profile"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/keys"
 )
@@ -170,6 +177,42 @@ func TestDLEncProof(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("Process - Invalid (Foreign message type)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 4)
+
+		x1Enc, _ := cipher.Encrypt(pk, x1.Bytes())
+
+		pParams := prover.NewParams(secp256k1, sk, x1)
+		vParams := verifier.NewParams(secp256k1, q1, pk, x1Enc)
+
+		p1 := prover.NewProver(pParams, outCh, resCh)
+		p2 := verifier.NewVerifier(vParams, outCh, resCh)
+
+		tests := []struct {
+			party lindell17.Participant
+			msg   *foreignMessage
+			want  error
+		}{
+			{p1, &foreignMessage{lindell17.DLEncProof, lindell17.Verifier, lindell17.Prover, 1}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.DLEncProof, lindell17.Verifier, lindell17.Prover, 3}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.DLEncProof, lindell17.Verifier, lindell17.Prover, 5}, lindell17.ErrUnknownMessage},
+			{p2, &foreignMessage{lindell17.DLEncProof, lindell17.Prover, lindell17.Verifier, 2}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.DLEncProof, lindell17.Prover, lindell17.Verifier, 4}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.DLEncProof, lindell17.Prover, lindell17.Verifier, 5}, lindell17.ErrUnknownMessage},
+		}
+
+		for _, tt := range tests {
+			_, err := tt.party.Process(tt.msg)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("message id %d: want error %v, got %v", tt.msg.messageId, tt.want, err)
+			}
+		}
+	})
 }
 
 type container struct {
@@ -189,3 +232,113 @@ func (c *container) add(entity lindell17.Entity, result bool) {
 
 	c.results[entity] = result
 }
+
+func FuzzProcessProver(f *testing.F) {
+	profile.Fuzz(f, lindell17.Prover, dlEncProofParties(f)...)
+}
+
+func FuzzProcessVerifier(f *testing.F) {
+	profile.Fuzz(f, lindell17.Verifier, dlEncProofParties(f)...)
+}
+
+// dlEncProofParties returns the parties of a DLEnc proof run for the shared
+// key material.
+func dlEncProofParties(f *testing.F) []profile.Party {
+	f.Helper()
+
+	x1Enc, err := cipher.Encrypt(pk, x1.Bytes())
+	if err != nil {
+		f.Fatalf("expected no error, got %v", err)
+	}
+
+	km1 := kParty1.NewKeyMaterial(x1, sk, pk, q1)
+	km2 := kParty2.NewKeyMaterial(x1Enc, nil, pk, q1)
+	parties, err := profile.DLEncProof(secp256k1, km1, km2)
+	if err != nil {
+		f.Fatalf("expected no error, got %v", err)
+	}
+
+	return parties
+}
+
+// benchmarkPaillierBits are the Paillier key sizes the steps are benchmarked with.
+var benchmarkPaillierBits = []int{1024, 2048, 3072}
+
+func BenchmarkDLEncProof(b *testing.B) {
+	for _, bits := range benchmarkPaillierBits {
+		b.Run(fmt.Sprintf("%d", bits), func(b *testing.B) {
+			km1, km2 := benchmarkKeyMaterial(b, bits)
+
+			parties, err := profile.DLEncProof(secp256k1, km1, km2)
+			if err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+
+			prof, err := profile.Run(parties...)
+			if err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+
+			for _, step := range prof.Steps {
+				b.Run(step.Name(), func(b *testing.B) {
+					prof.Benchmark(b, step)
+				})
+			}
+		})
+	}
+}
+
+// benchmarkKeyMaterial runs key generation with a Paillier key of the given
+// size.
+func benchmarkKeyMaterial(b *testing.B, bits int) (*kParty1.KeyMaterial, *kParty2.KeyMaterial) {
+	b.Helper()
+
+	prof, err := profile.Run(profile.Keygen(secp256k1, 40, 128, bits)...)
+	if err != nil {
+		b.Fatalf("expected no error, got %v", err)
+	}
+
+	km1, km2, err := prof.KeyMaterial()
+	if err != nil {
+		b.Fatalf("expected no error, got %v", err)
+	}
+
+	return km1, km2
+}
+
+// foreignMessage is a message type that isn't part of the protocol but
+// implements the message interface.
+type foreignMessage struct {
+	protocol  lindell17.Protocol
+	from      lindell17.Entity
+	to        lindell17.Entity
+	messageId int
+}
+
+func (m *foreignMessage) To() lindell17.Entity {
+	return m.to
+}
+
+func (m *foreignMessage) From() lindell17.Entity {
+	return m.from
+}
+
+func (m *foreignMessage) Protocol() lindell17.Protocol {
+	return m.protocol
+}
+
+func (m *foreignMessage) MessageId() int {
+	return m.messageId
+}
+
+func (m *foreignMessage) SessionId() string {
+	return strings.Repeat("0", lindell17.SessionIdLength)
+}
+
+func (m *foreignMessage) IsValid() bool {
+	return true
+}
+
+func (m *foreignMessage) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	return nil
+}
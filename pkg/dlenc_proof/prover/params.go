@@ -1,24 +1,52 @@
 package prover
 
 import (
+	"crypto/rand"
+	"io"
 	"math/big"
 
 	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
 	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Params is an instance of parameters for a DLEnc proof prover.
 type Params struct {
-	curve weierstrass.Curve
-	sk    *keys.PrivateKey
-	x1    *big.Int
+	curve  weierstrass.Curve
+	sk     *keys.PrivateKey
+	x1     *big.Int
+	limits *lindell17.Limits
+	rand   io.Reader
 }
 
 // NewParams creates a new instance of parameters for a DLEnc proof prover.
 func NewParams(curve weierstrass.Curve, sk *keys.PrivateKey, x1 *big.Int) *Params {
 	return &Params{
-		curve: curve,
-		sk:    sk,
-		x1:    x1,
+		curve:  curve,
+		sk:     sk,
+		x1:     x1,
+		limits: lindell17.DefaultLimits(),
+		rand:   rand.Reader,
 	}
 }
+
+// WithLimits sets the resource limits the prover enforces on incoming messages.
+// A nil value restores the default limits.
+// Returns the params to allow chaining.
+func (p *Params) WithLimits(limits *lindell17.Limits) *Params {
+	if limits == nil {
+		limits = lindell17.DefaultLimits()
+	}
+
+	p.limits = limits
+
+	return p
+}
+
+// WithRandomness sets the source of randomness the prover uses.
+// Returns the params to allow chaining.
+func (p *Params) WithRandomness(reader io.Reader) *Params {
+	p.rand = reader
+
+	return p
+}
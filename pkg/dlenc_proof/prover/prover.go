@@ -1,6 +1,8 @@
 package prover
 
 import (
+	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/primefactor-io/commitment/pkg/hash"
@@ -8,6 +10,8 @@ import (
 	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/messages"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/keys"
 )
@@ -16,10 +20,14 @@ import (
 type Prover struct {
 	curve     weierstrass.Curve
 	sk        *keys.PrivateKey
+	pk        *keys.PublicKey
 	x1        *big.Int
 	alpha     *big.Int
 	qHat      *elliptic.Point
 	cRandVals *hash.Commitment
+	limits    *lindell17.Limits
+	rand      io.Reader
+	messages  int
 	state     lindell17.State
 	outCh     chan<- lindell17.Message
 	resCh     chan<- lindell17.Result
@@ -28,19 +36,24 @@ type Prover struct {
 // NewProver creates a new instance of a DLEnc proof prover.
 func NewProver(params *Params, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) *Prover {
 	return &Prover{
-		curve: params.curve,
-		sk:    params.sk,
-		x1:    params.x1,
-		state: lindell17.Start,
-		outCh: outCh,
-		resCh: resCh,
+		curve:  params.curve,
+		sk:     params.sk,
+		pk:     keys.DerivePublicKey(params.sk),
+		x1:     params.x1,
+		limits: params.limits,
+		rand:   params.rand,
+		state:  lindell17.Start,
+		outCh:  outCh,
+		resCh:  resCh,
 	}
 }
 
 // Start starts the prover part of the protocol.
 // Returns an error if the current state is invalid or starting the protocol
 // fails.
-func (p *Prover) Start() (bool, error) {
+func (p *Prover) Start() (ok bool, err error) {
+	defer p.destroyOnError(&err)
+
 	// Validate state.
 	if p.state != lindell17.Start {
 		return false, lindell17.ErrInvalidState
@@ -53,9 +66,18 @@ func (p *Prover) Start() (bool, error) {
 }
 
 // Process processes an incoming protocol message.
-// Returns an error if the message was sent by the wrong sender, is invalid,
-// unknown or not intended for the protocol / recipient.
-func (p *Prover) Process(msg lindell17.Message) (bool, error) {
+// Returns an error if the session's message limit is exceeded or the message
+// was sent by the wrong sender, is invalid, unknown or not intended for the
+// protocol / recipient.
+func (p *Prover) Process(msg lindell17.Message) (ok bool, err error) {
+	defer p.destroyOnError(&err)
+
+	// Enforce the session's message limit.
+	p.messages++
+	if p.messages > p.limits.MaxMessages {
+		return false, lindell17.ErrTooManyMessages
+	}
+
 	// Check message protocol.
 	if msg.Protocol() != lindell17.DLEncProof {
 		return false, lindell17.ErrWrongProtocol
@@ -72,16 +94,24 @@ func (p *Prover) Process(msg lindell17.Message) (bool, error) {
 	}
 
 	// Validate message.
-	if !msg.IsValid() {
-		return false, lindell17.ErrInvalidMessage
+	if err := msg.ValidateFor(p.curve, p.pk); err != nil {
+		return false, err
 	}
 
 	// Process message.
 	switch msg.MessageId() {
 	case 1:
-		return p.step1(msg.(*messages.Message1))
+		m, ok := msg.(*messages.Message1)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step1(m)
 	case 3:
-		return p.step2(msg.(*messages.Message3))
+		m, ok := msg.(*messages.Message3)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step2(m)
 	default:
 		return false, lindell17.ErrUnknownMessage
 	}
@@ -101,22 +131,23 @@ func (p *Prover) step1(msg *messages.Message1) (bool, error) {
 	// Decrypt ciphertext.
 	plaintext, err := cipher.Decrypt(p.sk, msg.Ciphertext)
 	if err != nil {
-		return false, ErrDecryptCiphertext
+		return false, fmt.Errorf("%w: %w", ErrDecryptCiphertext, err)
 	}
 
 	// Turn decrypted result into big int to obtain alpha.
 	alpha := new(big.Int).SetBytes(plaintext)
+	utils.WipeBytes(plaintext)
 
 	// Compute Q^.
 	qHat, err := p.curve.ScalarMultiply(alpha, p.curve.G()) // alpha * G
 	if err != nil {
-		return false, ErrComputeQHat
+		return false, fmt.Errorf("%w: %w", ErrComputeQHat, err)
 	}
 
 	// Commit to Q^.
-	cQHat, err := hash.Commit(qHat.X.Bytes(), qHat.Y.Bytes())
+	cQHat, err := random.Commit(p.rand, qHat.X.Bytes(), qHat.Y.Bytes())
 	if err != nil {
-		return false, ErrCommitToQHat
+		return false, fmt.Errorf("%w: %w", ErrCommitToQHat, err)
 	}
 
 	// Store commitment to a and b.
@@ -156,6 +187,7 @@ func (p *Prover) step2(msg *messages.Message3) (bool, error) {
 
 	// Check if alpha values match.
 	isEqual := p.alpha.Cmp(alpha) == 0
+	utils.Wipe(in1, alpha)
 
 	// Compute final result.
 	result := isValid && isEqual
@@ -169,5 +201,29 @@ func (p *Prover) step2(msg *messages.Message3) (bool, error) {
 	// Send result.
 	p.resCh <- NewResult(sid, result)
 
+	// Wipe alpha.
+	p.Destroy()
+
 	return true, nil
 }
+
+// Destroy wipes the prover's ephemeral secrets, drops its references to the
+// long-term secrets and ends the protocol run, so that further calls fail with
+// lindell17.ErrInvalidState. The prover destroys itself once it sent its
+// result or a call failed, so Destroy only needs to be called to cancel a run.
+func (p *Prover) Destroy() {
+	utils.Wipe(p.alpha)
+
+	p.alpha = nil
+	p.sk = nil
+	p.x1 = nil
+	p.state = lindell17.Destroyed
+}
+
+// destroyOnError destroys the prover if the call failed, since failed
+// protocol runs can't be resumed.
+func (p *Prover) destroyOnError(err *error) {
+	if *err != nil {
+		p.Destroy()
+	}
+}
@@ -1,26 +1,55 @@
 package verifier
 
 import (
+	"crypto/rand"
+	"io"
+
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Params is an instance of parameters for a DLEnc proof verifier.
 type Params struct {
-	curve weierstrass.Curve
-	q1    *elliptic.Point
-	pk    *keys.PublicKey
-	x1Enc cipher.Ciphertext
+	curve  weierstrass.Curve
+	q1     *elliptic.Point
+	pk     *keys.PublicKey
+	x1Enc  cipher.Ciphertext
+	limits *lindell17.Limits
+	rand   io.Reader
 }
 
 // NewParams creates a new instance of parameters for a DLEnc proof verifier.
 func NewParams(curve weierstrass.Curve, q1 *elliptic.Point, pk *keys.PublicKey, x1Enc cipher.Ciphertext) *Params {
 	return &Params{
-		curve: curve,
-		q1:    q1,
-		pk:    pk,
-		x1Enc: x1Enc,
+		curve:  curve,
+		q1:     q1,
+		pk:     pk,
+		x1Enc:  x1Enc,
+		limits: lindell17.DefaultLimits(),
+		rand:   rand.Reader,
 	}
 }
+
+// WithLimits sets the resource limits the verifier enforces on incoming messages.
+// A nil value restores the default limits.
+// Returns the params to allow chaining.
+func (p *Params) WithLimits(limits *lindell17.Limits) *Params {
+	if limits == nil {
+		limits = lindell17.DefaultLimits()
+	}
+
+	p.limits = limits
+
+	return p
+}
+
+// WithRandomness sets the source of randomness the verifier uses.
+// Returns the params to allow chaining.
+func (p *Params) WithRandomness(reader io.Reader) *Params {
+	p.rand = reader
+
+	return p
+}
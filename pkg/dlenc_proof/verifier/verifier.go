@@ -1,7 +1,8 @@
 package verifier
 
 import (
-	"crypto/rand"
+	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/primefactor-io/commitment/pkg/hash"
@@ -9,6 +10,7 @@ import (
 	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/messages"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
 	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/homomorphic"
@@ -17,35 +19,42 @@ import (
 
 // Verifier is an instance of a DLEnc proof verifier.
 type Verifier struct {
-	curve weierstrass.Curve
-	q1    *elliptic.Point
-	pk    *keys.PublicKey
-	x1Enc cipher.Ciphertext
-	a     *big.Int
-	b     *big.Int
-	cQHat *hash.Commitment
-	state lindell17.State
-	outCh chan<- lindell17.Message
-	resCh chan<- lindell17.Result
+	curve    weierstrass.Curve
+	q1       *elliptic.Point
+	pk       *keys.PublicKey
+	x1Enc    cipher.Ciphertext
+	a        *big.Int
+	b        *big.Int
+	cQHat    *hash.Commitment
+	limits   *lindell17.Limits
+	rand     io.Reader
+	messages int
+	state    lindell17.State
+	outCh    chan<- lindell17.Message
+	resCh    chan<- lindell17.Result
 }
 
 // NewVerifier creates a new instance of a DLEnc proof verifier.
 func NewVerifier(params *Params, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) *Verifier {
 	return &Verifier{
-		curve: params.curve,
-		q1:    params.q1,
-		pk:    params.pk,
-		x1Enc: params.x1Enc,
-		state: lindell17.Start,
-		outCh: outCh,
-		resCh: resCh,
+		curve:  params.curve,
+		q1:     params.q1,
+		pk:     params.pk,
+		x1Enc:  params.x1Enc,
+		limits: params.limits,
+		rand:   params.rand,
+		state:  lindell17.Start,
+		outCh:  outCh,
+		resCh:  resCh,
 	}
 }
 
 // Start starts the verifier part of the protocol.
 // Returns an error if the current state is invalid or starting the protocol
 // fails.
-func (v *Verifier) Start() (bool, error) {
+func (v *Verifier) Start() (ok bool, err error) {
+	defer v.destroyOnError(&err)
+
 	// Validate state.
 	if v.state != lindell17.Start {
 		return false, lindell17.ErrInvalidState
@@ -53,9 +62,9 @@ func (v *Verifier) Start() (bool, error) {
 
 	// Generate session id.
 	bits := 128
-	sid, err := utils.GenerateSessionId(bits)
+	sid, err := utils.GenerateSessionIdFromReader(v.rand, bits)
 	if err != nil {
-		return false, lindell17.ErrGenerateSessionId
+		return false, err
 	}
 
 	// Transition to next state.
@@ -66,9 +75,18 @@ func (v *Verifier) Start() (bool, error) {
 }
 
 // Process processes an incoming protocol message.
-// Returns an error if the message was sent by the wrong sender, is invalid,
-// unknown or not intended for the protocol / recipient.
-func (v *Verifier) Process(msg lindell17.Message) (bool, error) {
+// Returns an error if the session's message limit is exceeded or the message
+// was sent by the wrong sender, is invalid, unknown or not intended for the
+// protocol / recipient.
+func (v *Verifier) Process(msg lindell17.Message) (ok bool, err error) {
+	defer v.destroyOnError(&err)
+
+	// Enforce the session's message limit.
+	v.messages++
+	if v.messages > v.limits.MaxMessages {
+		return false, lindell17.ErrTooManyMessages
+	}
+
 	// Check message protocol.
 	if msg.Protocol() != lindell17.DLEncProof {
 		return false, lindell17.ErrWrongProtocol
@@ -85,16 +103,24 @@ func (v *Verifier) Process(msg lindell17.Message) (bool, error) {
 	}
 
 	// Validate message.
-	if !msg.IsValid() {
-		return false, lindell17.ErrInvalidMessage
+	if err := msg.ValidateFor(v.curve, v.pk); err != nil {
+		return false, err
 	}
 
 	// Process message.
 	switch msg.MessageId() {
 	case 2:
-		return v.step2(msg.(*messages.Message2))
+		m, ok := msg.(*messages.Message2)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return v.step2(m)
 	case 4:
-		return v.step3(msg.(*messages.Message4))
+		m, ok := msg.(*messages.Message4)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return v.step3(m)
 	default:
 		return false, lindell17.ErrUnknownMessage
 	}
@@ -112,27 +138,27 @@ func (v *Verifier) step1(sessionId string) (bool, error) {
 	qq := new(big.Int).Mul(v.curve.N(), v.curve.N()) // q^2
 
 	// Sample random a from Z_q.
-	a, err := rand.Int(rand.Reader, v.curve.N())
+	a, err := random.Int(v.rand, v.curve.N())
 	if err != nil {
-		return false, ErrSampleA
+		return false, fmt.Errorf("%w: %w", ErrSampleA, err)
 	}
 
 	// Sample random b from Z_q^2.
-	b, err := rand.Int(rand.Reader, qq)
+	b, err := random.Int(v.rand, qq)
 	if err != nil {
-		return false, ErrSampleB
+		return false, fmt.Errorf("%w: %w", ErrSampleB, err)
 	}
 
 	// Commit to a and b.
-	cRandVals, err := hash.Commit(a.Bytes(), b.Bytes())
+	cRandVals, err := random.Commit(v.rand, a.Bytes(), b.Bytes())
 	if err != nil {
-		return false, ErrCommitToAAndB
+		return false, fmt.Errorf("%w: %w", ErrCommitToAAndB, err)
 	}
 
 	// Encrypt b.
-	in1, r, err := cipher.EncryptAndReturnNonce(v.pk, b.Bytes())
+	in1, r, err := random.Encrypt(v.rand, v.pk, b.Bytes())
 	if err != nil {
-		return false, ErrEncryptB
+		return false, fmt.Errorf("%w: %w", ErrEncryptB, err)
 	}
 
 	// Ensure that gcd(r, N) = 1.
@@ -144,7 +170,7 @@ func (v *Verifier) step1(sessionId string) (bool, error) {
 	// Multiply plaintext of x1Enc (which is the discrete logarithm x1) with a.
 	in2, err := homomorphic.MultiplyPlaintextValue(v.pk, v.x1Enc, a.Bytes())
 	if err != nil {
-		return false, ErrMultiplyPlaintextValue
+		return false, fmt.Errorf("%w: %w", ErrMultiplyPlaintextValue, err)
 	}
 
 	// Add the two underlying plaintext values.
@@ -177,9 +203,10 @@ func (v *Verifier) step2(msg *messages.Message2) (bool, error) {
 	// Store Q^ commitment.
 	v.cQHat = msg.CQHat
 
-	// Fetch values a and b.
-	a := v.a
-	b := v.b
+	// Fetch copies of values a and b, so that wiping them doesn't alter the
+	// message.
+	a := new(big.Int).Set(v.a)
+	b := new(big.Int).Set(v.b)
 
 	// Transition to next state.
 	v.state = lindell17.Step3
@@ -208,15 +235,15 @@ func (v *Verifier) step3(msg *messages.Message4) (bool, error) {
 	// Compute Q'.
 	in1, err := v.curve.ScalarMultiply(v.a, v.q1) // a * Q1
 	if err != nil {
-		return false, ErrComputeATimesQ1
+		return false, fmt.Errorf("%w: %w", ErrComputeATimesQ1, err)
 	}
 	in2, err := v.curve.ScalarMultiply(v.b, v.curve.G()) // b * G
 	if err != nil {
-		return false, ErrComputeBTimesG
+		return false, fmt.Errorf("%w: %w", ErrComputeBTimesG, err)
 	}
 	qPrime, err := v.curve.Add(in1, in2) // (a * Q1) + (b * G)
 	if err != nil {
-		return false, ErrComputeATimesQ1PlusBTimesG
+		return false, fmt.Errorf("%w: %w", ErrComputeATimesQ1PlusBTimesG, err)
 	}
 
 	// Check if Q^ equals Q'.
@@ -228,5 +255,28 @@ func (v *Verifier) step3(msg *messages.Message4) (bool, error) {
 	// Send result.
 	v.resCh <- NewResult(sid, result)
 
+	// Wipe a and b.
+	v.Destroy()
+
 	return true, nil
 }
+
+// Destroy wipes the verifier's ephemeral secrets and ends the protocol run, so
+// that further calls fail with lindell17.ErrInvalidState. The verifier
+// destroys itself once it sent its result or a call failed, so Destroy only
+// needs to be called to cancel a run.
+func (v *Verifier) Destroy() {
+	utils.Wipe(v.a, v.b)
+
+	v.a = nil
+	v.b = nil
+	v.state = lindell17.Destroyed
+}
+
+// destroyOnError destroys the verifier if the call failed, since failed
+// protocol runs can't be resumed.
+func (v *Verifier) destroyOnError(err *error) {
+	if *err != nil {
+		v.Destroy()
+	}
+}
@@ -1,5 +1,24 @@
 /*
 Package keygen implements the interactive key generation protocol as described
 in section "Protocol 3.1" of the paper https://eprint.iacr.org/2017/552.pdf.
+
+Party 1 generates its Paillier key pair during the protocol run unless it's
+given a lindell17.PaillierKeyProvider (see package keypool) that provides
+pre-generated key pairs.
+
+Both parties can be given multiple workers (see WithWorkers) to generate and
+verify the Nth root and range proofs concurrently. Once a proof fails, the
+proofs that haven't been started yet are skipped and the party returns the
+first error right away. The proofs that are already running can't be
+interrupted, they finish in the background and their results are discarded.
+Party 2 reports the outcome of its checks to the observer once they returned,
+so only the failed check is reported if one failed. Party 1's proofs draw their randomness from forks of its source of
+randomness (see random.Fork), so its results don't depend on the number of
+workers and are reproducible with a deterministic source of randomness.
+
+Both parties wipe their secrets once they sent their result or a call failed
+(see Destroy). The key material owns the key shares afterwards. Its secrets are
+redacted when it's formatted or encoded as JSON and can be wiped via its
+Destroy method.
 */
 package keygen
@@ -1,22 +1,33 @@
 package keygen_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 
 	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/codec"
 	"github.com/primefactor-io/lindell17/pkg/keygen/messages"
 	"github.com/primefactor-io/lindell17/pkg/keygen/party1"
 	"github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/keypool"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/profile"
+	"github.com/primefactor-io/lindell17/pkg/random"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 const paillierBits = 1024
+const rangeProofBits = 40
+const nthRootProofBits = 128
 
 var p1Params *party1.Params
 var p2Params *party2.Params
@@ -24,9 +35,6 @@ var p2Params *party2.Params
 var secp256k1 = curves.Secp256k1
 
 func TestMain(m *testing.M) {
-	rangeProofBits := 40
-	nthRootProofBits := 128
-
 	p1Params = party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits)
 	p2Params = party2.NewParams(secp256k1, rangeProofBits, nthRootProofBits)
 
@@ -108,6 +116,395 @@ func TestKeygen(t *testing.T) {
 		}
 	})
 
+	t.Run("Key Generation (deterministic randomness)", func(t *testing.T) {
+		t.Parallel()
+
+		var keyMaterials [2]*party1.KeyMaterial
+		var x1Encs [2]cipher.Ciphertext
+		var encodings [2][][]byte
+
+		for i := range 2 {
+			errCh := make(chan error, 2)
+			outCh := make(chan lindell17.Message, 2)
+			resCh := make(chan lindell17.Result, 2)
+
+			// Both runs use the same seeds.
+			rand1 := random.NewSeededReader([]byte("party 1"))
+			rand2 := random.NewSeededReader([]byte("party 2"))
+
+			// The runs use different numbers of workers.
+			params1 := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithRandomness(rand1).WithWorkers(i + 1)
+			params2 := party2.NewParams(secp256k1, rangeProofBits, nthRootProofBits).WithRandomness(rand2).WithWorkers(i + 1)
+
+			p1 := party1.NewParty1(params1, outCh, resCh)
+			p2 := party2.NewParty2(params2, outCh, resCh)
+
+			if _, err := p1.Start(); err != nil {
+				errCh <- err
+			}
+
+			if _, err := p2.Start(); err != nil {
+				errCh <- err
+			}
+
+			var counter int
+
+		coord:
+			for {
+				select {
+				case msg := <-outCh:
+					bz, err := codec.MarshalMessage(msg)
+					if err != nil {
+						t.Fatalf("expected no error, got %v", err)
+					}
+					encodings[i] = append(encodings[i], bz)
+
+					switch msg.To() {
+					case lindell17.Party1:
+						if _, err := p1.Process(msg); err != nil {
+							errCh <- err
+						}
+					case lindell17.Party2:
+						if _, err := p2.Process(msg); err != nil {
+							errCh <- err
+						}
+					}
+				case err := <-errCh:
+					t.Fatalf("expected no error, got %v", err)
+				case result := <-resCh:
+					counter++
+
+					switch result.From() {
+					case lindell17.Party1:
+						keyMaterials[i] = result.(*party1.Result).KeyMaterial
+					case lindell17.Party2:
+						x1Encs[i] = result.(*party2.Result).KeyMaterial.X1Enc
+					}
+
+					if counter == 2 {
+						break coord
+					}
+				}
+			}
+		}
+
+		if keyMaterials[0].X1.Cmp(keyMaterials[1].X1) != 0 {
+			t.Fatal("Key generation isn't deterministic (x1)")
+		}
+		if keyMaterials[0].Sk.Equal(keyMaterials[1].Sk) != true {
+			t.Fatal("Key generation isn't deterministic (Paillier sk)")
+		}
+		if keyMaterials[0].Q.Equal(keyMaterials[1].Q) != true {
+			t.Fatal("Key generation isn't deterministic (Q)")
+		}
+		if !bytes.Equal(x1Encs[0], x1Encs[1]) {
+			t.Fatal("Key generation isn't deterministic (x1 encryption)")
+		}
+		if len(encodings[0]) != len(encodings[1]) {
+			t.Fatal("Key generation isn't deterministic (message count)")
+		}
+		for j := range encodings[0] {
+			if !bytes.Equal(encodings[0][j], encodings[1][j]) {
+				t.Fatalf("Key generation isn't deterministic (message %d)", j+1)
+			}
+		}
+	})
+
+	t.Run("Key Generation (key provider)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		sk, _, _ := keys.GenerateKeys(paillierBits)
+		provider := keypool.NewList(sk)
+
+		params1 := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithKeyProvider(provider)
+
+		p1 := party1.NewParty1(params1, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+		var counter int32
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				t.Fatalf("expected no error, got %v", err)
+			case result := <-resCh:
+				atomic.AddInt32(&counter, 1)
+
+				if result.From() == lindell17.Party2 {
+					res := result.(*party2.Result)
+					if res.KeyMaterial.Pk.N.Cmp(sk.N) != 0 {
+						t.Fatal("Key generation didn't use the provided Paillier key")
+					}
+				}
+
+				if atomic.LoadInt32(&counter) == 2 {
+					break coord
+				}
+			}
+		}
+
+		if provider.Len() != 0 {
+			t.Fatal("Provided Paillier key wasn't taken")
+		}
+	})
+
+	t.Run("Key Generation - Invalid (No provided key)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		// The provider only has a key of the wrong size.
+		sk, _, _ := keys.GenerateKeys(paillierBits / 2)
+		provider := keypool.NewList(sk)
+
+		params1 := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithKeyProvider(provider)
+
+		p1 := party1.NewParty1(params1, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, party1.ErrProvidePaillierKeys) {
+					t.Fatalf("want error %v, got %v", party1.ErrProvidePaillierKeys, err)
+				}
+
+				break coord
+			case <-resCh:
+			}
+		}
+	})
+
+	t.Run("Key Generation - Invalid (Provided key size)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		// The provider ignores the requested key size.
+		sk, pk, _ := keys.GenerateKeys(paillierBits / 2)
+		provider := &staticKeyProvider{sk: sk, pk: pk}
+
+		params1 := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithKeyProvider(provider)
+
+		p1 := party1.NewParty1(params1, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, party1.ErrInvalidPaillierKeys) {
+					t.Fatalf("want error %v, got %v", party1.ErrInvalidPaillierKeys, err)
+				}
+
+				break coord
+			case <-resCh:
+			}
+		}
+	})
+
+	t.Run("Key Generation (workers)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		params1 := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithWorkers(4)
+		params2 := party2.NewParams(secp256k1, rangeProofBits, nthRootProofBits).WithWorkers(4)
+
+		p1 := party1.NewParty1(params1, outCh, resCh)
+		p2 := party2.NewParty2(params2, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+		var counter int32
+		c := new(container)
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				t.Fatalf("expected no error, got %v", err)
+			case result := <-resCh:
+				atomic.AddInt32(&counter, 1)
+
+				switch result.From() {
+				case lindell17.Party1:
+					res := result.(*party1.Result)
+					c.addParty1KeyMaterial(res.KeyMaterial)
+				case lindell17.Party2:
+					res := result.(*party2.Result)
+					c.addParty2KeyMaterial(res.KeyMaterial)
+				}
+
+				if atomic.LoadInt32(&counter) == 2 {
+					keys1 := c.p1KeyMaterial
+					keys2 := c.p2KeyMaterial
+
+					x1Dec, _ := cipher.Decrypt(keys1.Sk, keys2.X1Enc)
+					x1Rec := new(big.Int).SetBytes(x1Dec)
+
+					if keys1.X1.Cmp(x1Rec) != 0 {
+						t.Fatal("Key generation failed (x1 verification)")
+					}
+					if keys1.Q.Equal(keys2.Q) != true {
+						t.Fatal("Key generation failed (q verification)")
+					}
+
+					break coord
+				}
+			}
+		}
+	})
+
+	t.Run("Key Generation - Invalid (x1 with workers)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		params2 := party2.NewParams(secp256k1, rangeProofBits, nthRootProofBits).WithWorkers(4)
+
+		p1 := party1.NewParty1(p1Params, outCh, resCh)
+		p2 := party2.NewParty2(params2, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if msg.MessageId() == 3 {
+						// Parse old message.
+						msg := msg.(*messages.Message3)
+
+						// Compute new, random x1 and its encryption.
+						x1, _ := secp256k1.GetRandomScalar()
+						x1Enc, _ := cipher.Encrypt(msg.Pk, x1.Bytes())
+
+						// Replace existing message.
+						msg = messages.NewMessage3(msg.SessionId(), msg.Q1, msg.Pk, msg.PNthRoot, x1Enc, msg.PRange)
+
+						// Inject faulty message.
+						if _, err := p2.Process(msg); err != nil {
+							errCh <- err
+						}
+
+						break
+					}
+
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, party2.ErrInvalidRangeProof) {
+					t.Fatalf("want error %v, got %v", party2.ErrInvalidRangeProof, err)
+				}
+
+				break coord
+			}
+		}
+	})
+
 	t.Run("Key Generation - Invalid (Q1)", func(t *testing.T) {
 		t.Parallel()
 
@@ -370,6 +767,152 @@ func TestKeygen(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("Key Generation - Invalid (Paillier modulus too large)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		// Only accept Paillier moduli that are smaller than the one party 1 uses.
+		limits := lindell17.NewLimits(lindell17.DefaultMaxMessages, paillierBits/2)
+		params := party2.NewParams(secp256k1, rangeProofBits, nthRootProofBits).WithLimits(limits)
+
+		p1 := party1.NewParty1(p1Params, outCh, resCh)
+		p2 := party2.NewParty2(params, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, lindell17.ErrPaillierModulusTooLarge) {
+					t.Fatalf("want error %v, got %v", lindell17.ErrPaillierModulusTooLarge, err)
+				}
+
+				break coord
+			}
+		}
+	})
+
+	t.Run("KeyMaterial (redacted)", func(t *testing.T) {
+		t.Parallel()
+
+		sk, pk, _ := keys.GenerateKeys(paillierBits)
+		x1, _ := secp256k1.GetRandomScalar()
+		x2, _ := secp256k1.GetRandomScalar()
+		q, _ := secp256k1.ScalarMultiply(x1, secp256k1.G())
+		x1Enc, _ := cipher.Encrypt(pk, x1.Bytes())
+
+		km1 := party1.NewKeyMaterial(x1, sk, pk, q)
+		km2 := party2.NewKeyMaterial(x1Enc, x2, pk, q)
+
+		secrets := []string{
+			x1.String(), fmt.Sprintf("%x", x1),
+			x2.String(), fmt.Sprintf("%x", x2),
+			sk.PhiN.String(), fmt.Sprintf("%x", sk.PhiN),
+		}
+
+		var outputs []string
+		for _, km := range []any{km1, *km1, km2, *km2} {
+			for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+				outputs = append(outputs, fmt.Sprintf(verb, km))
+			}
+
+			bz, err := json.Marshal(km)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			outputs = append(outputs, string(bz))
+		}
+
+		for _, output := range outputs {
+			if !strings.Contains(output, "[REDACTED]") {
+				t.Fatalf("want output to be redacted, got %s", output)
+			}
+			for _, secret := range secrets {
+				if strings.Contains(output, secret) {
+					t.Fatalf("want output to not contain secret, got %s", output)
+				}
+			}
+		}
+	})
+
+	t.Run("KeyMaterial (destroy)", func(t *testing.T) {
+		t.Parallel()
+
+		sk, pk, _ := keys.GenerateKeys(paillierBits)
+		x1, _ := secp256k1.GetRandomScalar()
+		x2, _ := secp256k1.GetRandomScalar()
+		q, _ := secp256k1.ScalarMultiply(x1, secp256k1.G())
+		x1Enc, _ := cipher.Encrypt(pk, x1.Bytes())
+
+		km1 := party1.NewKeyMaterial(x1, sk, pk, q)
+		km2 := party2.NewKeyMaterial(x1Enc, x2, pk, q)
+
+		km1.Destroy()
+		km2.Destroy()
+
+		if km1.X1.Sign() != 0 || km1.Sk.PhiN.Sign() != 0 || km1.Sk.Mu.Sign() != 0 {
+			t.Error("Party 1's secrets weren't wiped")
+		}
+		if km2.X2.Sign() != 0 {
+			t.Error("Party 2's secrets weren't wiped")
+		}
+	})
+
+	t.Run("Process - Invalid (Foreign message type)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 4)
+
+		p1 := party1.NewParty1(p1Params, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, outCh, resCh)
+
+		tests := []struct {
+			party lindell17.Participant
+			msg   *foreignMessage
+			want  error
+		}{
+			{p1, &foreignMessage{lindell17.Keygen, lindell17.Party2, lindell17.Party1, 2}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.Keygen, lindell17.Party2, lindell17.Party1, 4}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.Keygen, lindell17.Party2, lindell17.Party1, 6}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.Keygen, lindell17.Party2, lindell17.Party1, 8}, lindell17.ErrUnknownMessage},
+			{p2, &foreignMessage{lindell17.Keygen, lindell17.Party1, lindell17.Party2, 1}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.Keygen, lindell17.Party1, lindell17.Party2, 3}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.Keygen, lindell17.Party1, lindell17.Party2, 5}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.Keygen, lindell17.Party1, lindell17.Party2, 7}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.Keygen, lindell17.Party1, lindell17.Party2, 8}, lindell17.ErrUnknownMessage},
+		}
+
+		for _, tt := range tests {
+			_, err := tt.party.Process(tt.msg)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("message id %d: want error %v, got %v", tt.msg.messageId, tt.want, err)
+			}
+		}
+	})
 }
 
 type container struct {
@@ -391,3 +934,80 @@ func (c *container) addParty2KeyMaterial(keyMaterial *party2.KeyMaterial) {
 
 	c.p2KeyMaterial = keyMaterial
 }
+
+func FuzzProcessParty1(f *testing.F) {
+	profile.Fuzz(f, lindell17.Party1, profile.Keygen(secp256k1, rangeProofBits, nthRootProofBits, paillierBits)...)
+}
+
+func FuzzProcessParty2(f *testing.F) {
+	profile.Fuzz(f, lindell17.Party2, profile.Keygen(secp256k1, rangeProofBits, nthRootProofBits, paillierBits)...)
+}
+
+// benchmarkPaillierBits are the Paillier key sizes the steps are benchmarked with.
+var benchmarkPaillierBits = []int{1024, 2048, 3072}
+
+func BenchmarkKeygen(b *testing.B) {
+	for _, bits := range benchmarkPaillierBits {
+		b.Run(fmt.Sprintf("%d", bits), func(b *testing.B) {
+
+			prof, err := profile.Run(profile.Keygen(secp256k1, rangeProofBits, nthRootProofBits, bits)...)
+			if err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+
+			for _, step := range prof.Steps {
+				b.Run(step.Name(), func(b *testing.B) {
+					prof.Benchmark(b, step)
+				})
+			}
+		})
+	}
+}
+
+// staticKeyProvider is a Paillier key provider that always returns the same
+// key pair.
+type staticKeyProvider struct {
+	sk *keys.PrivateKey
+	pk *keys.PublicKey
+}
+
+func (p *staticKeyProvider) PaillierKeys(bits int) (*keys.PrivateKey, *keys.PublicKey, error) {
+	return p.sk, p.pk, nil
+}
+
+// foreignMessage is a message type that isn't part of the protocol but
+// implements the message interface.
+type foreignMessage struct {
+	protocol  lindell17.Protocol
+	from      lindell17.Entity
+	to        lindell17.Entity
+	messageId int
+}
+
+func (m *foreignMessage) To() lindell17.Entity {
+	return m.to
+}
+
+func (m *foreignMessage) From() lindell17.Entity {
+	return m.from
+}
+
+func (m *foreignMessage) Protocol() lindell17.Protocol {
+	return m.protocol
+}
+
+func (m *foreignMessage) MessageId() int {
+	return m.messageId
+}
+
+func (m *foreignMessage) SessionId() string {
+	return strings.Repeat("0", lindell17.SessionIdLength)
+}
+
+func (m *foreignMessage) IsValid() bool {
+	return true
+}
+
+func (m *foreignMessage) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	return nil
+}
@@ -1,7 +1,10 @@
 package messages
 
 import (
+	"fmt"
+
 	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/keys"
@@ -57,10 +60,27 @@ func (m *Message3) SessionId() string {
 }
 
 func (m *Message3) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.Q1 != nil &&
 		m.Pk != nil &&
 		m.PNthRoot != nil &&
 		m.X1Enc != nil &&
 		m.PRange != nil
 }
+
+func (m *Message3) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	if err := lindell17.CheckPoint(curve, m.Q1); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+	if err := lindell17.CheckPaillierKey(m.Pk); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+
+	// X1Enc can only be checked once Pk is proven to be valid, which is why
+	// party 2 does so after verifying the Nth root proof.
+	return nil
+}
@@ -1,8 +1,10 @@
 package messages
 
 import (
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/messages"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message4 is the protocol's fourth message that is sent from party 2 to party 1.
@@ -43,6 +45,14 @@ func (m *Message4) SessionId() string {
 }
 
 func (m *Message4) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.Message1.IsValid()
 }
+
+func (m *Message4) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	return m.Message1.ValidateFor(curve, pk)
+}
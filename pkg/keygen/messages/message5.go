@@ -1,8 +1,10 @@
 package messages
 
 import (
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/messages"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message5 is the protocol's fifth message that is sent from party 1 to party 2.
@@ -43,6 +45,14 @@ func (m *Message5) SessionId() string {
 }
 
 func (m *Message5) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.Message2.IsValid()
 }
+
+func (m *Message5) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	return m.Message2.ValidateFor(curve, pk)
+}
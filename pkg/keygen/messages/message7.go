@@ -1,8 +1,10 @@
 package messages
 
 import (
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/messages"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message7 is the protocol's seventh message that is sent from party 1 to
@@ -44,6 +46,14 @@ func (m *Message7) SessionId() string {
 }
 
 func (m *Message7) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.Message4.IsValid()
 }
+
+func (m *Message7) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	return m.Message4.ValidateFor(curve, pk)
+}
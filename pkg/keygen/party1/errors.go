@@ -15,6 +15,10 @@ var (
 	ErrInvalidQ2DLKProof = fmt.Errorf("invalid Q2 DLK proof")
 	// ErrGeneratePaillierKeys is returned if the Paillier keys can't be generated.
 	ErrGeneratePaillierKeys = fmt.Errorf("unable to generate Paillier keys")
+	// ErrProvidePaillierKeys is returned if the key provider can't provide Paillier keys.
+	ErrProvidePaillierKeys = fmt.Errorf("unable to provide Paillier keys")
+	// ErrInvalidPaillierKeys is returned if the provided Paillier keys are invalid.
+	ErrInvalidPaillierKeys = fmt.Errorf("invalid Paillier keys")
 	// ErrGenerateNthRootProof is returned if the Nth root proof can't be generated.
 	ErrGenerateNthRootProof = fmt.Errorf("unable to generate Nth root proof")
 	// ErrEncryptX1 is returned if x1 can't be encrypted.
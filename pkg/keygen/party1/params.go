@@ -1,6 +1,12 @@
 package party1
 
-import "github.com/primefactor-io/ecc/pkg/weierstrass"
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
 
 // Params is an instance of parameters party 1 uses.
 type Params struct {
@@ -8,6 +14,12 @@ type Params struct {
 	rangeProofBits   int
 	nthRootProofBits int
 	paillierBits     int
+	limits           *lindell17.Limits
+	recorder         lindell17.Recorder
+	observer         lindell17.Observer
+	workers          int
+	keyProvider      lindell17.PaillierKeyProvider
+	rand             io.Reader
 }
 
 // NewParams creates a new instance of parameters party 1 uses.
@@ -17,5 +29,64 @@ func NewParams(curve weierstrass.Curve, rangeProofBits, nthRootProofBits, pailli
 		rangeProofBits:   rangeProofBits,
 		nthRootProofBits: nthRootProofBits,
 		paillierBits:     paillierBits,
+		limits:           lindell17.DefaultLimits(),
+		workers:          1,
+		rand:             rand.Reader,
+	}
+}
+
+// WithLimits sets the resource limits party 1 enforces on incoming messages.
+// A nil value restores the default limits.
+// Returns the params to allow chaining.
+func (p *Params) WithLimits(limits *lindell17.Limits) *Params {
+	if limits == nil {
+		limits = lindell17.DefaultLimits()
 	}
+
+	p.limits = limits
+
+	return p
+}
+
+// WithRecorder sets the recorder party 1 records its transcript with.
+// Returns the params to allow chaining.
+func (p *Params) WithRecorder(recorder lindell17.Recorder) *Params {
+	p.recorder = recorder
+
+	return p
+}
+
+// WithObserver sets the observer party 1 reports the events of its
+// protocol runs to.
+// Returns the params to allow chaining.
+func (p *Params) WithObserver(observer lindell17.Observer) *Params {
+	p.observer = observer
+
+	return p
+}
+
+// WithRandomness sets the source of randomness party 1 uses.
+// Returns the params to allow chaining.
+func (p *Params) WithRandomness(reader io.Reader) *Params {
+	p.rand = reader
+
+	return p
+}
+
+// WithKeyProvider sets the provider party 1 takes its Paillier key pair from
+// instead of generating it during the protocol run.
+// Returns the params to allow chaining.
+func (p *Params) WithKeyProvider(provider lindell17.PaillierKeyProvider) *Params {
+	p.keyProvider = provider
+
+	return p
+}
+
+// WithWorkers sets the maximum number of workers party 1 generates its proofs with
+// concurrently. A single worker (the default) runs them one after the other.
+// Returns the params to allow chaining.
+func (p *Params) WithWorkers(workers int) *Params {
+	p.workers = max(workers, 1)
+
+	return p
 }
@@ -1,9 +1,10 @@
 package party1
 
 import (
+	"fmt"
+	"io"
 	"math/big"
 
-	"github.com/primefactor-io/commitment/pkg/hash"
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	sProofs "github.com/primefactor-io/ecc/pkg/proofs"
 	"github.com/primefactor-io/ecc/pkg/weierstrass"
@@ -11,6 +12,7 @@ import (
 	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/prover"
 	"github.com/primefactor-io/lindell17/pkg/keygen/messages"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
 	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/keys"
@@ -32,6 +34,13 @@ type Party1 struct {
 	prover           *prover.Prover
 	proverOutCh      chan lindell17.Message
 	proverResCh      chan lindell17.Result
+	limits           *lindell17.Limits
+	recorder         lindell17.Recorder
+	tracer           *lindell17.Tracer
+	workers          int
+	keyProvider      lindell17.PaillierKeyProvider
+	rand             io.Reader
+	messages         int
 	state            lindell17.State
 	outCh            chan<- lindell17.Message
 	resCh            chan<- lindell17.Result
@@ -45,6 +54,12 @@ func NewParty1(params *Params, outCh chan<- lindell17.Message, resCh chan<- lind
 		rangeProofBits:   params.rangeProofBits,
 		nthRootProofBits: params.nthRootProofBits,
 		paillierBits:     params.paillierBits,
+		limits:           params.limits,
+		recorder:         params.recorder,
+		tracer:           lindell17.NewTracer(params.observer, lindell17.Keygen, lindell17.Party1),
+		workers:          params.workers,
+		keyProvider:      params.keyProvider,
+		rand:             params.rand,
 		state:            lindell17.Start,
 		outCh:            outCh,
 		resCh:            resCh,
@@ -54,7 +69,10 @@ func NewParty1(params *Params, outCh chan<- lindell17.Message, resCh chan<- lind
 // Start starts party 1 of the protocol.
 // Returns an error if the current state is invalid or starting the protocol
 // fails.
-func (p *Party1) Start() (bool, error) {
+func (p *Party1) Start() (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
 	// Validate state.
 	if p.state != lindell17.Start {
 		return false, lindell17.ErrInvalidState
@@ -62,22 +80,33 @@ func (p *Party1) Start() (bool, error) {
 
 	// Generate session id.
 	bits := 128
-	sid, err := utils.GenerateSessionId(bits)
+	sid, err := utils.GenerateSessionIdFromReader(p.rand, bits)
 	if err != nil {
-		return false, lindell17.ErrGenerateSessionId
+		return false, err
 	}
+	p.tracer.SetSessionId(sid)
 
 	// Transition to next state.
-	p.state = lindell17.Step1
+	p.setState(lindell17.Step1)
 
 	// Run step 1.
 	return p.step1(sid)
 }
 
 // Process processes an incoming protocol message.
-// Returns an error if the message was sent by the wrong sender, is invalid,
+// Returns an error if the session's message limit is exceeded, the message
+// can't be recorded or the message was sent by the wrong sender, is invalid,
 // unknown or not intended for the protocol / recipient.
-func (p *Party1) Process(msg lindell17.Message) (bool, error) {
+func (p *Party1) Process(msg lindell17.Message) (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
+	// Enforce the session's message limit.
+	p.messages++
+	if p.messages > p.limits.MaxMessages {
+		return false, lindell17.ErrTooManyMessages
+	}
+
 	// Check message protocol.
 	if msg.Protocol() != lindell17.Keygen {
 		return false, lindell17.ErrWrongProtocol
@@ -93,19 +122,41 @@ func (p *Party1) Process(msg lindell17.Message) (bool, error) {
 		return false, lindell17.ErrWrongRecipient
 	}
 
+	// Record inbound message.
+	if p.recorder != nil {
+		if err := p.recorder.RecordReceived(msg); err != nil {
+			return false, fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	// Trace inbound message.
+	p.tracer.Received(msg)
+
 	// Validate message.
-	if !msg.IsValid() {
-		return false, lindell17.ErrInvalidMessage
+	if err := msg.ValidateFor(p.curve, p.pk); err != nil {
+		return false, err
 	}
 
 	// Process message.
 	switch msg.MessageId() {
 	case 2:
-		return p.step2(msg.(*messages.Message2))
+		m, ok := msg.(*messages.Message2)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step2(m)
 	case 4:
-		return p.step3(msg.(*messages.Message4))
+		m, ok := msg.(*messages.Message4)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step3(m)
 	case 6:
-		return p.step4(msg.(*messages.Message6))
+		m, ok := msg.(*messages.Message6)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step4(m)
 	default:
 		return false, lindell17.ErrUnknownMessage
 	}
@@ -124,27 +175,27 @@ func (p *Party1) step1(sessionId string) (bool, error) {
 	q3 := new(big.Int).Div(p.curve.N(), big.NewInt(3)) // q / 3
 
 	// Sample the random scalar x1.
-	x1, err := p.curve.GetRandomScalar(q3)
+	x1, err := random.Scalar(p.rand, p.curve, q3)
 	if err != nil {
-		return false, ErrSampleScalarX1
+		return false, fmt.Errorf("%w: %w", ErrSampleScalarX1, err)
 	}
 
 	// Compute Q1 by multiplying x1 with the curve's generator.
 	q1, err := p.curve.ScalarMultiply(x1, p.curve.G()) // x1 * G
 	if err != nil {
-		return false, ErrComputeQ1
+		return false, fmt.Errorf("%w: %w", ErrComputeQ1, err)
 	}
 
 	// Commit to Q1.
-	cQ1, err := hash.Commit(q1.X.Bytes(), q1.Y.Bytes())
+	cQ1, err := random.Commit(p.rand, q1.X.Bytes(), q1.Y.Bytes())
 	if err != nil {
-		return false, ErrCommitToQ1
+		return false, fmt.Errorf("%w: %w", ErrCommitToQ1, err)
 	}
 
 	// Generate Q1 DLK proof.
-	pQ1, err := sProofs.GenerateDLKProof(p.curve, q1, x1)
+	pQ1, err := random.DLKProof(p.rand, p.curve, q1, x1)
 	if err != nil {
-		return false, ErrGenerateQ1DLKProof
+		return false, fmt.Errorf("%w: %w", ErrGenerateQ1DLKProof, err)
 	}
 
 	// Store x1 and Q1.
@@ -152,10 +203,12 @@ func (p *Party1) step1(sessionId string) (bool, error) {
 	p.q1 = q1
 
 	// Transition to next state.
-	p.state = lindell17.Step2
+	p.setState(lindell17.Step2)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage1(sessionId, cQ1, pQ1)
+	if err := p.send(messages.NewMessage1(sessionId, cQ1, pQ1)); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -173,7 +226,11 @@ func (p *Party1) step2(msg *messages.Message2) (bool, error) {
 
 	// Verify Q2 DLK proof.
 	isValid, err := sProofs.VerifyDLKProof(p.curve, msg.PQ2, msg.Q2)
-	if err != nil || !isValid {
+	p.tracer.Proof("Q2 DLK proof", err == nil && isValid)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidQ2DLKProof, err)
+	}
+	if !isValid {
 		return false, ErrInvalidQ2DLKProof
 	}
 
@@ -181,36 +238,72 @@ func (p *Party1) step2(msg *messages.Message2) (bool, error) {
 	q1 := p.q1
 
 	// Generate Paillier keys.
-	sk, pk, err := keys.GenerateKeys(p.paillierBits)
-	if err != nil {
-		return false, ErrGeneratePaillierKeys
-	}
-
-	// Generate Nth root proof.
-	pNthRoot, err := pProofs.GenerateNthRootProof(p.nthRootProofBits, pk.N)
+	sk, pk, err := p.paillierKeys()
 	if err != nil {
-		return false, ErrGenerateNthRootProof
+		return false, err
 	}
 
-	// Encrypt x1.
-	x1Enc, r, err := cipher.EncryptAndReturnNonce(pk, p.x1.Bytes())
+	// The proofs are generated concurrently if there are multiple workers, so
+	// each task draws its randomness from its own fork of the source.
+	rands, err := random.Fork(p.rand, 2)
 	if err != nil {
-		return false, ErrEncryptX1
+		return false, fmt.Errorf("%w: %w", ErrGenerateNthRootProof, err)
 	}
 
-	//  Generate range proof.
-	pRange, err := pProofs.GenerateRangeProof(p.rangeProofBits, pk, p.curve.N(), p.x1, r)
+	// The tasks keep running in the background if a task failed and the party
+	// is destroyed, so the encryption task works on its own copy of x1.
+	x1 := new(big.Int).Set(p.x1)
+
+	var pNthRoot *pProofs.NthRootProof
+	var x1Enc cipher.Ciphertext
+	var pRange *pProofs.RangeProof
+
+	err = utils.RunTasks(p.workers,
+		func() error {
+			// Generate Nth root proof.
+			var err error
+			pNthRoot, err = random.NthRootProof(rands[0], p.nthRootProofBits, pk.N)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrGenerateNthRootProof, err)
+			}
+
+			return nil
+		},
+		func() error {
+			defer utils.Wipe(x1)
+
+			// Encrypt x1.
+			var r *big.Int
+			var err error
+			x1Enc, r, err = random.Encrypt(rands[1], pk, x1.Bytes())
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrEncryptX1, err)
+			}
+			defer utils.Wipe(r)
+
+			//  Generate range proof.
+			pRange, err = random.RangeProof(rands[1], p.rangeProofBits, pk, p.curve.N(), x1, r)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrGenerateRangeProof, err)
+			}
+
+			return nil
+		},
+	)
 	if err != nil {
-		return false, ErrGenerateRangeProof
+		return false, err
 	}
 
 	// Initialize and start DLEnc proof prover.
 	p.proverOutCh = make(chan lindell17.Message, 1)
 	p.proverResCh = make(chan lindell17.Result, 1)
-	params := prover.NewParams(p.curve, sk, p.x1)
+	params := prover.NewParams(p.curve, sk, p.x1).WithLimits(p.limits).WithRandomness(p.rand)
 	p.prover = prover.NewProver(params, p.proverOutCh, p.proverResCh)
 	ok, err := p.prover.Start()
-	if !ok || err != nil {
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInitializeDLEncProofProver, err)
+	}
+	if !ok {
 		return false, ErrInitializeDLEncProofProver
 	}
 
@@ -222,10 +315,12 @@ func (p *Party1) step2(msg *messages.Message2) (bool, error) {
 	p.pk = pk
 
 	// Transition to next state.
-	p.state = lindell17.Step3
+	p.setState(lindell17.Step3)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage3(sid, q1, pk, pNthRoot, x1Enc, pRange)
+	if err := p.send(messages.NewMessage3(sid, q1, pk, pNthRoot, x1Enc, pRange)); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -243,7 +338,10 @@ func (p *Party1) step3(msg *messages.Message4) (bool, error) {
 
 	// Process incoming message.
 	ok, err := p.prover.Process(&msg.Message1)
-	if !ok || err != nil {
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrProcessDLEncProofMessage1, err)
+	}
+	if !ok {
 		return false, ErrProcessDLEncProofMessage1
 	}
 
@@ -251,10 +349,12 @@ func (p *Party1) step3(msg *messages.Message4) (bool, error) {
 	message := <-p.proverOutCh
 
 	// Transition to next state.
-	p.state = lindell17.Step4
+	p.setState(lindell17.Step4)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage5(sid, message.(*dlencproof.Message2))
+	if err := p.send(messages.NewMessage5(sid, message.(*dlencproof.Message2))); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -272,7 +372,10 @@ func (p *Party1) step4(msg *messages.Message6) (bool, error) {
 
 	// Process incoming message.
 	ok, err := p.prover.Process(&msg.Message3)
-	if !ok || err != nil {
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrProcessDLEncProofMessage3, err)
+	}
+	if !ok {
 		return false, ErrProcessDLEncProofMessage3
 	}
 
@@ -280,7 +383,9 @@ func (p *Party1) step4(msg *messages.Message6) (bool, error) {
 	message := <-p.proverOutCh
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage7(sid, message.(*dlencproof.Message4))
+	if err := p.send(messages.NewMessage7(sid, message.(*dlencproof.Message4))); err != nil {
+		return false, err
+	}
 
 	// Read prover result.
 	res := <-p.proverResCh
@@ -293,14 +398,110 @@ func (p *Party1) step4(msg *messages.Message6) (bool, error) {
 	// Compute Q by multiplying x1 with Q2.
 	q, err := p.curve.ScalarMultiply(p.x1, p.q2) // x1 * Q2
 	if err != nil {
-		return false, ErrComputeQ
+		return false, fmt.Errorf("%w: %w", ErrComputeQ, err)
 	}
 
 	// Create key material.
 	keyMaterial := NewKeyMaterial(p.x1, p.sk, p.pk, q)
 
 	// Send key material over result channel.
-	p.resCh <- NewResult(sid, keyMaterial)
+	if err := p.sendResult(NewResult(sid, keyMaterial)); err != nil {
+		return false, err
+	}
+
+	// The key material owns x1 and sk now.
+	p.x1 = nil
+	p.sk = nil
+	p.Destroy()
 
 	return true, nil
 }
+
+// paillierKeys takes a Paillier key pair from the key provider if one is set
+// and generates a fresh key pair otherwise.
+// Returns an error if the key pair can't be generated or the provided key pair
+// doesn't have the expected size.
+func (p *Party1) paillierKeys() (*keys.PrivateKey, *keys.PublicKey, error) {
+	if p.keyProvider == nil {
+		sk, pk, err := random.PaillierKeys(p.rand, p.paillierBits)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrGeneratePaillierKeys, err)
+		}
+
+		return sk, pk, nil
+	}
+
+	sk, pk, err := p.keyProvider.PaillierKeys(p.paillierBits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrProvidePaillierKeys, err)
+	}
+	if sk == nil || pk == nil || sk.N == nil || pk.N == nil || pk.N.BitLen() != p.paillierBits || sk.N.Cmp(pk.N) != 0 {
+		return nil, nil, ErrInvalidPaillierKeys
+	}
+
+	return sk, pk, nil
+}
+
+// Destroy wipes the secrets party 1 generated and didn't hand out in its
+// result yet and ends the protocol run, so that further calls fail with
+// lindell17.ErrInvalidState. Party 1 destroys itself once it sent its result
+// or a call failed, so Destroy only needs to be called to cancel a run.
+func (p *Party1) Destroy() {
+	utils.Wipe(p.x1)
+	utils.WipePaillierKey(p.sk)
+	if p.prover != nil {
+		p.prover.Destroy()
+	}
+
+	p.x1 = nil
+	p.sk = nil
+	p.prover = nil
+	p.state = lindell17.Destroyed
+	p.tracer.Destroyed()
+}
+
+// destroyOnError destroys party 1 if the call failed, since failed protocol
+// runs can't be resumed.
+func (p *Party1) destroyOnError(err *error) {
+	if *err != nil {
+		p.Destroy()
+	}
+}
+
+// setState transitions to the state and reports the transition.
+func (p *Party1) setState(state lindell17.State) {
+	p.state = state
+	p.tracer.Transition(state)
+}
+
+// send records the outbound message if a recorder is set, reports it to the
+// observer and sends it via the message channel.
+// Returns an error if the message can't be recorded.
+func (p *Party1) send(msg lindell17.Message) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordSent(msg); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Sent(msg)
+	p.outCh <- msg
+
+	return nil
+}
+
+// sendResult records the result if a recorder is set, reports it to the
+// observer and sends it via the result channel.
+// Returns an error if the result can't be recorded.
+func (p *Party1) sendResult(res lindell17.Result) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordResult(res); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Result(res)
+	p.resCh <- res
+
+	return nil
+}
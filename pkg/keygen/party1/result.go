@@ -1,10 +1,13 @@
 package party1
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/big"
 
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
@@ -36,8 +39,14 @@ func (r *Result) SessionId() string {
 	return r.Sid
 }
 
+// redacted replaces the secrets of the key material when it's formatted or
+// encoded as JSON.
+const redacted = "[REDACTED]"
+
 // KeyMaterial is an instance of party 1's key material which is the result of
-// running the key generation protocol.
+// running the key generation protocol. Its secrets are redacted when it's
+// formatted via the fmt package or encoded via encoding/json. The codec
+// encodes them, so that the key material can be persisted.
 type KeyMaterial struct {
 	// X1 is party 1's private key share.
 	X1 *big.Int
@@ -59,3 +68,49 @@ func NewKeyMaterial(x1 *big.Int, sk *keys.PrivateKey, pk *keys.PublicKey, q *ell
 		Q:  q,
 	}
 }
+
+// Destroy wipes the private key share and the Paillier private key. The key
+// material (and the params created from it) can't be used afterwards.
+func (km *KeyMaterial) Destroy() {
+	utils.Wipe(km.X1)
+	utils.WipePaillierKey(km.Sk)
+}
+
+// String returns the key material's public values and redacts its secrets.
+func (km KeyMaterial) String() string {
+	return fmt.Sprintf("{X1:%s Sk:%s Pk:%s Q:%s}", redacted, redacted, describePublicKey(km.Pk), describePoint(km.Q))
+}
+
+// GoString returns the key material's public values and redacts its secrets.
+func (km KeyMaterial) GoString() string {
+	return "party1.KeyMaterial" + km.String()
+}
+
+// MarshalJSON encodes the key material's public values as JSON and redacts
+// its secrets.
+func (km KeyMaterial) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		X1 string
+		Sk string
+		Pk *keys.PublicKey
+		Q  *elliptic.Point
+	}{redacted, redacted, km.Pk, km.Q})
+}
+
+// describePublicKey returns the size of the Paillier public key's modulus.
+func describePublicKey(pk *keys.PublicKey) string {
+	if pk == nil || pk.N == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("%d-bit", pk.N.BitLen())
+}
+
+// describePoint returns the hex-encoded coordinates of the point.
+func describePoint(q *elliptic.Point) string {
+	if q == nil || q.X == nil || q.Y == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("(%x, %x)", q.X, q.Y)
+}
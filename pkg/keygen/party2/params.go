@@ -1,12 +1,23 @@
 package party2
 
-import "github.com/primefactor-io/ecc/pkg/weierstrass"
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
 
 // Params is an instance of parameters party 2 uses.
 type Params struct {
 	curve            weierstrass.Curve
 	rangeProofBits   int
 	nthRootProofBits int
+	limits           *lindell17.Limits
+	recorder         lindell17.Recorder
+	observer         lindell17.Observer
+	workers          int
+	rand             io.Reader
 }
 
 // NewParams creates a new instance of parameters party 2 uses.
@@ -15,5 +26,55 @@ func NewParams(curve weierstrass.Curve, rangeProofBits, nthRootProofBits int) *P
 		curve:            curve,
 		rangeProofBits:   rangeProofBits,
 		nthRootProofBits: nthRootProofBits,
+		limits:           lindell17.DefaultLimits(),
+		workers:          1,
+		rand:             rand.Reader,
 	}
 }
+
+// WithLimits sets the resource limits party 2 enforces on incoming messages.
+// A nil value restores the default limits.
+// Returns the params to allow chaining.
+func (p *Params) WithLimits(limits *lindell17.Limits) *Params {
+	if limits == nil {
+		limits = lindell17.DefaultLimits()
+	}
+
+	p.limits = limits
+
+	return p
+}
+
+// WithRecorder sets the recorder party 2 records its transcript with.
+// Returns the params to allow chaining.
+func (p *Params) WithRecorder(recorder lindell17.Recorder) *Params {
+	p.recorder = recorder
+
+	return p
+}
+
+// WithObserver sets the observer party 2 reports the events of its
+// protocol runs to.
+// Returns the params to allow chaining.
+func (p *Params) WithObserver(observer lindell17.Observer) *Params {
+	p.observer = observer
+
+	return p
+}
+
+// WithRandomness sets the source of randomness party 2 uses.
+// Returns the params to allow chaining.
+func (p *Params) WithRandomness(reader io.Reader) *Params {
+	p.rand = reader
+
+	return p
+}
+
+// WithWorkers sets the maximum number of workers party 2 verifies the proofs it receives with
+// concurrently. A single worker (the default) runs them one after the other.
+// Returns the params to allow chaining.
+func (p *Params) WithWorkers(workers int) *Params {
+	p.workers = max(workers, 1)
+
+	return p
+}
@@ -1,6 +1,9 @@
 package party2
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/primefactor-io/commitment/pkg/hash"
@@ -11,6 +14,8 @@ import (
 	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/verifier"
 	"github.com/primefactor-io/lindell17/pkg/keygen/messages"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/keys"
 	pProofs "github.com/primefactor-io/paillier/pkg/proofs"
@@ -32,6 +37,12 @@ type Party2 struct {
 	verifier         *verifier.Verifier
 	verifierOutCh    chan lindell17.Message
 	verifierResCh    chan lindell17.Result
+	limits           *lindell17.Limits
+	recorder         lindell17.Recorder
+	tracer           *lindell17.Tracer
+	workers          int
+	rand             io.Reader
+	messages         int
 	state            lindell17.State
 	outCh            chan<- lindell17.Message
 	resCh            chan<- lindell17.Result
@@ -44,6 +55,11 @@ func NewParty2(params *Params, outCh chan<- lindell17.Message, resCh chan<- lind
 		curve:            params.curve,
 		rangeProofBits:   params.rangeProofBits,
 		nthRootProofBits: params.nthRootProofBits,
+		limits:           params.limits,
+		recorder:         params.recorder,
+		tracer:           lindell17.NewTracer(params.observer, lindell17.Keygen, lindell17.Party2),
+		workers:          params.workers,
+		rand:             params.rand,
 		state:            lindell17.Start,
 		outCh:            outCh,
 		resCh:            resCh,
@@ -53,22 +69,35 @@ func NewParty2(params *Params, outCh chan<- lindell17.Message, resCh chan<- lind
 // Start starts party 2 of the protocol.
 // Returns an error if the current state is invalid or starting the protocol
 // fails.
-func (p *Party2) Start() (bool, error) {
+func (p *Party2) Start() (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
 	// Validate state.
 	if p.state != lindell17.Start {
 		return false, lindell17.ErrInvalidState
 	}
 
 	// Transition to next state.
-	p.state = lindell17.Step1
+	p.setState(lindell17.Step1)
 
 	return true, nil
 }
 
 // Process processes an incoming protocol message.
-// Returns an error if the message was sent by the wrong sender, is invalid,
+// Returns an error if the session's message limit is exceeded, the message
+// can't be recorded or the message was sent by the wrong sender, is invalid,
 // unknown or not intended for the protocol / recipient.
-func (p *Party2) Process(msg lindell17.Message) (bool, error) {
+func (p *Party2) Process(msg lindell17.Message) (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
+	// Enforce the session's message limit.
+	p.messages++
+	if p.messages > p.limits.MaxMessages {
+		return false, lindell17.ErrTooManyMessages
+	}
+
 	// Check message protocol.
 	if msg.Protocol() != lindell17.Keygen {
 		return false, lindell17.ErrWrongProtocol
@@ -84,21 +113,47 @@ func (p *Party2) Process(msg lindell17.Message) (bool, error) {
 		return false, lindell17.ErrWrongRecipient
 	}
 
+	// Record inbound message.
+	if p.recorder != nil {
+		if err := p.recorder.RecordReceived(msg); err != nil {
+			return false, fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	// Trace inbound message.
+	p.tracer.Received(msg)
+
 	// Validate message.
-	if !msg.IsValid() {
-		return false, lindell17.ErrInvalidMessage
+	if err := msg.ValidateFor(p.curve, p.pk); err != nil {
+		return false, err
 	}
 
 	// Process message.
 	switch msg.MessageId() {
 	case 1:
-		return p.step1(msg.(*messages.Message1))
+		m, ok := msg.(*messages.Message1)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step1(m)
 	case 3:
-		return p.step2(msg.(*messages.Message3))
+		m, ok := msg.(*messages.Message3)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step2(m)
 	case 5:
-		return p.step3(msg.(*messages.Message5))
+		m, ok := msg.(*messages.Message5)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step3(m)
 	case 7:
-		return p.step4(msg.(*messages.Message7))
+		m, ok := msg.(*messages.Message7)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step4(m)
 	default:
 		return false, lindell17.ErrUnknownMessage
 	}
@@ -116,21 +171,21 @@ func (p *Party2) step1(msg *messages.Message1) (bool, error) {
 	}
 
 	// Sample the random scalar x2.
-	x2, err := p.curve.GetRandomScalar()
+	x2, err := random.Scalar(p.rand, p.curve)
 	if err != nil {
-		return false, ErrSampleScalarX2
+		return false, fmt.Errorf("%w: %w", ErrSampleScalarX2, err)
 	}
 
 	// Compute Q2 by multiplying x2 with the curve's generator.
 	q2, err := p.curve.ScalarMultiply(x2, p.curve.G()) // x2 * G
 	if err != nil {
-		return false, ErrComputeQ2
+		return false, fmt.Errorf("%w: %w", ErrComputeQ2, err)
 	}
 
 	// Generate Q2 DLK proof.
-	pQ2, err := sProofs.GenerateDLKProof(p.curve, q2, x2)
+	pQ2, err := random.DLKProof(p.rand, p.curve, q2, x2)
 	if err != nil {
-		return false, ErrGenerateQ2DLKProof
+		return false, fmt.Errorf("%w: %w", ErrGenerateQ2DLKProof, err)
 	}
 
 	// Store commitment to Q1 and Q1 DLK proof.
@@ -142,10 +197,12 @@ func (p *Party2) step1(msg *messages.Message1) (bool, error) {
 	p.q2 = q2
 
 	// Transition to next state.
-	p.state = lindell17.Step2
+	p.setState(lindell17.Step2)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage2(sid, q2, pQ2)
+	if err := p.send(messages.NewMessage2(sid, q2, pQ2)); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -161,37 +218,77 @@ func (p *Party2) step2(msg *messages.Message3) (bool, error) {
 		return false, lindell17.ErrInvalidState
 	}
 
-	// Verify commitment to Q1.
-	isValid := hash.Verify(p.cQ1, msg.Q1.X.Bytes(), msg.Q1.Y.Bytes())
-	if !isValid {
-		return false, ErrInvalidQ1Commitment
-	}
-
-	// Verify Q1 DLK proof.
-	isValid, err := sProofs.VerifyDLKProof(p.curve, p.pQ1, msg.Q1)
-	if err != nil || !isValid {
-		return false, ErrInvalidQ1DLKProof
+	// Enforce the session's Paillier modulus limit.
+	if err := p.limits.CheckPaillierKey(msg.Pk); err != nil {
+		return false, fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
 	}
 
-	// Verify Nth root proof.
-	isValid, err = pProofs.VerifyNthRootProof(msg.PNthRoot, p.nthRootProofBits, msg.Pk.N)
-	if err != nil || !isValid {
-		return false, ErrInvalidNthRootProof
-	}
-
-	// Verify range proof.
-	isValid, err = pProofs.VerifyRangeProof(msg.PRange, p.rangeProofBits, msg.Pk, p.curve.N(), msg.X1Enc)
-	if err != nil || !isValid {
-		return false, ErrInvalidRangeProof
+	// The checks are run concurrently if there are multiple workers. The
+	// outcomes are reported once the checks returned, since the remaining
+	// checks keep running in the background after a check failed.
+	err := utils.RunTasks(p.workers,
+		func() error {
+			// Verify commitment to Q1.
+			if !hash.Verify(p.cQ1, msg.Q1.X.Bytes(), msg.Q1.Y.Bytes()) {
+				return ErrInvalidQ1Commitment
+			}
+
+			// Verify Q1 DLK proof.
+			isValid, err := sProofs.VerifyDLKProof(p.curve, p.pQ1, msg.Q1)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidQ1DLKProof, err)
+			}
+			if !isValid {
+				return ErrInvalidQ1DLKProof
+			}
+
+			return nil
+		},
+		func() error {
+			// Verify Nth root proof.
+			isValid, err := pProofs.VerifyNthRootProof(msg.PNthRoot, p.nthRootProofBits, msg.Pk.N)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidNthRootProof, err)
+			}
+			if !isValid {
+				return ErrInvalidNthRootProof
+			}
+
+			return nil
+		},
+		func() error {
+			// Check encryption of x1.
+			if err := lindell17.CheckCiphertext(msg.Pk, msg.X1Enc); err != nil {
+				return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+			}
+
+			// Verify range proof.
+			isValid, err := pProofs.VerifyRangeProof(msg.PRange, p.rangeProofBits, msg.Pk, p.curve.N(), msg.X1Enc)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidRangeProof, err)
+			}
+			if !isValid {
+				return ErrInvalidRangeProof
+			}
+
+			return nil
+		},
+	)
+	p.traceProofs(err)
+	if err != nil {
+		return false, err
 	}
 
 	// Initialize and start DLEnc proof verifier.
 	p.verifierOutCh = make(chan lindell17.Message, 1)
 	p.verifierResCh = make(chan lindell17.Result, 1)
-	params := verifier.NewParams(p.curve, msg.Q1, msg.Pk, msg.X1Enc)
+	params := verifier.NewParams(p.curve, msg.Q1, msg.Pk, msg.X1Enc).WithLimits(p.limits).WithRandomness(p.rand)
 	p.verifier = verifier.NewVerifier(params, p.verifierOutCh, p.verifierResCh)
 	ok, err := p.verifier.Start()
-	if !ok || err != nil {
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInitializeDLEncProofVerifier, err)
+	}
+	if !ok {
 		return false, ErrInitializeDLEncProofVerifier
 	}
 
@@ -204,10 +301,12 @@ func (p *Party2) step2(msg *messages.Message3) (bool, error) {
 	message := <-p.verifierOutCh
 
 	// Transition to next state.
-	p.state = lindell17.Step3
+	p.setState(lindell17.Step3)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage4(sid, message.(*dlencproof.Message1))
+	if err := p.send(messages.NewMessage4(sid, message.(*dlencproof.Message1))); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -225,7 +324,10 @@ func (p *Party2) step3(msg *messages.Message5) (bool, error) {
 
 	// Process incoming message.
 	ok, err := p.verifier.Process(&msg.Message2)
-	if !ok || err != nil {
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrProcessDLEncProofMessage2, err)
+	}
+	if !ok {
 		return false, ErrProcessDLEncProofMessage2
 	}
 
@@ -233,10 +335,12 @@ func (p *Party2) step3(msg *messages.Message5) (bool, error) {
 	message := <-p.verifierOutCh
 
 	// Transition to next state.
-	p.state = lindell17.Step4
+	p.setState(lindell17.Step4)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage6(sid, message.(*dlencproof.Message3))
+	if err := p.send(messages.NewMessage6(sid, message.(*dlencproof.Message3))); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -254,13 +358,17 @@ func (p *Party2) step4(msg *messages.Message7) (bool, error) {
 
 	// Process incoming message.
 	ok, err := p.verifier.Process(&msg.Message4)
-	if !ok || err != nil {
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrProcessDLEncProofMessage4, err)
+	}
+	if !ok {
 		return false, ErrProcessDLEncProofMessage4
 	}
 
 	// Read verifier result.
 	res := <-p.verifierResCh
 	result := res.(*verifier.Result)
+	p.tracer.Proof("DLEnc proof", result.IsValid)
 
 	if !result.IsValid {
 		return false, ErrInvalidDLEncProof
@@ -269,14 +377,106 @@ func (p *Party2) step4(msg *messages.Message7) (bool, error) {
 	// Compute Q by multiplying x2 with Q1.
 	q, err := p.curve.ScalarMultiply(p.x2, p.q1) // x2 * Q1
 	if err != nil {
-		return false, ErrComputeQ
+		return false, fmt.Errorf("%w: %w", ErrComputeQ, err)
 	}
 
 	// Create key material.
 	keyMaterial := NewKeyMaterial(p.x1Enc, p.x2, p.pk, q)
 
 	// Send key material over result channel.
-	p.resCh <- NewResult(sid, keyMaterial)
+	if err := p.sendResult(NewResult(sid, keyMaterial)); err != nil {
+		return false, err
+	}
+
+	// The key material owns x2 now.
+	p.x2 = nil
+	p.Destroy()
 
 	return true, nil
 }
+
+// Destroy wipes the secrets party 2 generated and didn't hand out in its
+// result yet and ends the protocol run, so that further calls fail with
+// lindell17.ErrInvalidState. Party 2 destroys itself once it sent its result
+// or a call failed, so Destroy only needs to be called to cancel a run.
+func (p *Party2) Destroy() {
+	utils.Wipe(p.x2)
+	if p.verifier != nil {
+		p.verifier.Destroy()
+	}
+
+	p.x2 = nil
+	p.verifier = nil
+	p.state = lindell17.Destroyed
+	p.tracer.Destroyed()
+}
+
+// traceProofs reports the outcome of the checks of party 1's proofs given the
+// error they returned. If a check failed, only its proof is reported, since
+// the remaining checks may not have finished.
+func (p *Party2) traceProofs(err error) {
+	proofs := []struct {
+		name string
+		err  error
+	}{
+		{"Q1 commitment", ErrInvalidQ1Commitment},
+		{"Q1 DLK proof", ErrInvalidQ1DLKProof},
+		{"Nth root proof", ErrInvalidNthRootProof},
+		{"range proof", ErrInvalidRangeProof},
+	}
+
+	for _, proof := range proofs {
+		switch {
+		case err == nil:
+			p.tracer.Proof(proof.name, true)
+		case errors.Is(err, proof.err):
+			p.tracer.Proof(proof.name, false)
+		}
+	}
+}
+
+// destroyOnError destroys party 2 if the call failed, since failed protocol
+// runs can't be resumed.
+func (p *Party2) destroyOnError(err *error) {
+	if *err != nil {
+		p.Destroy()
+	}
+}
+
+// setState transitions to the state and reports the transition.
+func (p *Party2) setState(state lindell17.State) {
+	p.state = state
+	p.tracer.Transition(state)
+}
+
+// send records the outbound message if a recorder is set, reports it to the
+// observer and sends it via the message channel.
+// Returns an error if the message can't be recorded.
+func (p *Party2) send(msg lindell17.Message) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordSent(msg); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Sent(msg)
+	p.outCh <- msg
+
+	return nil
+}
+
+// sendResult records the result if a recorder is set, reports it to the
+// observer and sends it via the result channel.
+// Returns an error if the result can't be recorded.
+func (p *Party2) sendResult(res lindell17.Result) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordResult(res); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Result(res)
+	p.resCh <- res
+
+	return nil
+}
@@ -1,10 +1,13 @@
 package party2
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/big"
 
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/cipher"
 	"github.com/primefactor-io/paillier/pkg/keys"
 )
@@ -37,8 +40,14 @@ func (r *Result) SessionId() string {
 	return r.Sid
 }
 
+// redacted replaces the secrets of the key material when it's formatted or
+// encoded as JSON.
+const redacted = "[REDACTED]"
+
 // KeyMaterial is an instance of party 2's key material which is the result of
-// running the key generation protocol.
+// running the key generation protocol. Its secret is redacted when it's
+// formatted via the fmt package or encoded via encoding/json. The codec
+// encodes it, so that the key material can be persisted.
 type KeyMaterial struct {
 	// X1Enc is the encrypted private key share of party 1.
 	X1Enc cipher.Ciphertext
@@ -60,3 +69,48 @@ func NewKeyMaterial(x1Enc cipher.Ciphertext, x2 *big.Int, pk *keys.PublicKey, q
 		Q:     q,
 	}
 }
+
+// Destroy wipes the private key share. The key material (and the params
+// created from it) can't be used afterwards.
+func (km *KeyMaterial) Destroy() {
+	utils.Wipe(km.X2)
+}
+
+// String returns the key material's public values and redacts its secret.
+func (km KeyMaterial) String() string {
+	return fmt.Sprintf("{X1Enc:%d bytes X2:%s Pk:%s Q:%s}", len(km.X1Enc), redacted, describePublicKey(km.Pk), describePoint(km.Q))
+}
+
+// GoString returns the key material's public values and redacts its secret.
+func (km KeyMaterial) GoString() string {
+	return "party2.KeyMaterial" + km.String()
+}
+
+// MarshalJSON encodes the key material's public values as JSON and redacts
+// its secret.
+func (km KeyMaterial) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		X1Enc cipher.Ciphertext
+		X2    string
+		Pk    *keys.PublicKey
+		Q     *elliptic.Point
+	}{km.X1Enc, redacted, km.Pk, km.Q})
+}
+
+// describePublicKey returns the size of the Paillier public key's modulus.
+func describePublicKey(pk *keys.PublicKey) string {
+	if pk == nil || pk.N == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("%d-bit", pk.N.BitLen())
+}
+
+// describePoint returns the hex-encoded coordinates of the point.
+func describePoint(q *elliptic.Point) string {
+	if q == nil || q.X == nil || q.Y == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("(%x, %x)", q.X, q.Y)
+}
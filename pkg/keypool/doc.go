@@ -0,0 +1,21 @@
+/*
+Package keypool implements providers of pre-generated Paillier key pairs for
+party 1 of the key generation protocol (see lindell17.PaillierKeyProvider).
+
+Generating a Paillier key pair takes seconds for moduli of 2048 bits and more,
+and party 2 has to wait while party 1 generates it. A Pool generates key pairs
+in the background ahead of time, so that a key generation run only has to wait
+if the pool is empty. A List hands out key pairs that were generated earlier
+and stored on disk via Save and Load.
+
+The key pairs are generated via keys.GenerateKeys, which is equivalent to the
+way party 1 generates them itself (see random.PaillierKeys). A provider never
+hands out a key pair twice, as a Paillier key must not be shared by multiple
+key generation runs.
+
+Note that key files contain Paillier private keys and need to be protected
+like any other secret key material. A List only removes the key pairs it hands
+out from memory, so the remaining key pairs should be saved again (see
+List.Remaining) to avoid reusing a key pair after a restart.
+*/
+package keypool
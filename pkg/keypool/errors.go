@@ -0,0 +1,22 @@
+package keypool
+
+import "fmt"
+
+var (
+	// ErrKeySize is returned if a key pair of an unsupported size is requested.
+	ErrKeySize = fmt.Errorf("unsupported key size")
+	// ErrPoolClosed is returned if the pool is closed and empty.
+	ErrPoolClosed = fmt.Errorf("pool closed")
+	// ErrGenerateKeys is returned if the pool can't generate key pairs.
+	ErrGenerateKeys = fmt.Errorf("unable to generate key pairs")
+	// ErrNoKeys is returned if no key pair of the requested size is left.
+	ErrNoKeys = fmt.Errorf("no key pair left")
+	// ErrReadKeyFile is returned if the key file can't be read.
+	ErrReadKeyFile = fmt.Errorf("unable to read key file")
+	// ErrWriteKeyFile is returned if the key file can't be written.
+	ErrWriteKeyFile = fmt.Errorf("unable to write key file")
+	// ErrInvalidKeyFile is returned if the key file's content is invalid.
+	ErrInvalidKeyFile = fmt.Errorf("invalid key file")
+	// ErrInvalidKey is returned if a stored private key is inconsistent.
+	ErrInvalidKey = fmt.Errorf("invalid private key")
+)
@@ -0,0 +1,189 @@
+package keypool_test
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/primefactor-io/lindell17/pkg/keypool"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+const paillierBits = 512
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Pool (valid)", func(t *testing.T) {
+		t.Parallel()
+
+		pool := keypool.NewPool(paillierBits, 2).WithWorkers(2)
+		pool.Start()
+		defer pool.Close()
+
+		seen := make(map[string]bool)
+		for i := 0; i < 3; i++ {
+			sk, pk, err := pool.PaillierKeys(paillierBits)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if pk.N.BitLen() != paillierBits {
+				t.Fatalf("want %d bits, got %d", paillierBits, pk.N.BitLen())
+			}
+			if sk.N.Cmp(pk.N) != 0 {
+				t.Fatal("Private and public key don't match")
+			}
+			if seen[pk.N.String()] {
+				t.Fatal("Key pair was handed out twice")
+			}
+			seen[pk.N.String()] = true
+		}
+	})
+
+	t.Run("Pool - Invalid (Key size)", func(t *testing.T) {
+		t.Parallel()
+
+		pool := keypool.NewPool(paillierBits, 1)
+		defer pool.Close()
+
+		_, _, err := pool.PaillierKeys(2 * paillierBits)
+
+		if !errors.Is(err, keypool.ErrKeySize) {
+			t.Errorf("want error %v, got %v", keypool.ErrKeySize, err)
+		}
+	})
+
+	t.Run("Pool - Invalid (Closed)", func(t *testing.T) {
+		t.Parallel()
+
+		pool := keypool.NewPool(paillierBits, 1)
+		pool.Close()
+
+		_, _, err := pool.PaillierKeys(paillierBits)
+
+		if !errors.Is(err, keypool.ErrPoolClosed) {
+			t.Errorf("want error %v, got %v", keypool.ErrPoolClosed, err)
+		}
+	})
+
+	t.Run("Pool - Invalid (Key generation)", func(t *testing.T) {
+		t.Parallel()
+
+		// Primes of 0 bits can't be generated.
+		pool := keypool.NewPool(1, 1)
+		defer pool.Close()
+
+		_, _, err := pool.PaillierKeys(1)
+
+		if !errors.Is(err, keypool.ErrGenerateKeys) {
+			t.Errorf("want error %v, got %v", keypool.ErrGenerateKeys, err)
+		}
+	})
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	sk1, _, _ := keys.GenerateKeys(paillierBits)
+	sk2, _, _ := keys.GenerateKeys(2 * paillierBits)
+
+	t.Run("Save / Load", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "keys.json")
+		if err := keypool.Save(path, sk1, sk2); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Fatalf("want permissions %v, got %v", os.FileMode(0o600), info.Mode().Perm())
+		}
+
+		list, err := keypool.Load(path)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		sk, pk, err := list.PaillierKeys(2 * paillierBits)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !sk.Equal(sk2) || !pk.Equal(keys.DerivePublicKey(sk2)) {
+			t.Fatal("Loaded key pair doesn't match the stored one")
+		}
+
+		if _, _, err := list.PaillierKeys(2 * paillierBits); !errors.Is(err, keypool.ErrNoKeys) {
+			t.Fatalf("want error %v, got %v", keypool.ErrNoKeys, err)
+		}
+
+		remaining := list.Remaining()
+		if len(remaining) != 1 || !remaining[0].Equal(sk1) {
+			t.Fatal("Remaining keys don't match")
+		}
+	})
+
+	t.Run("Load - Invalid (Inconsistent key)", func(t *testing.T) {
+		t.Parallel()
+
+		// Replace phi(N) so that the key can't decrypt.
+		invalid := *sk1
+		invalid.PhiN = new(big.Int).Sub(sk1.PhiN, big.NewInt(2))
+
+		path := filepath.Join(t.TempDir(), "keys.json")
+		if err := keypool.Save(path, &invalid); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		_, err := keypool.Load(path)
+
+		if !errors.Is(err, keypool.ErrInvalidKeyFile) {
+			t.Errorf("want error %v, got %v", keypool.ErrInvalidKeyFile, err)
+		}
+	})
+
+	t.Run("Load - Invalid (Version)", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "keys.json")
+		if err := os.WriteFile(path, []byte(`{"version":2,"keys":[]}`), 0o600); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		_, err := keypool.Load(path)
+
+		if !errors.Is(err, keypool.ErrInvalidKeyFile) {
+			t.Errorf("want error %v, got %v", keypool.ErrInvalidKeyFile, err)
+		}
+	})
+
+	t.Run("List (concurrent use)", func(t *testing.T) {
+		t.Parallel()
+
+		list := keypool.NewList(sk1)
+
+		errCh := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				_, _, err := list.PaillierKeys(paillierBits)
+				errCh <- err
+			}()
+		}
+
+		var failures int
+		for i := 0; i < 2; i++ {
+			if err := <-errCh; errors.Is(err, keypool.ErrNoKeys) {
+				failures++
+			}
+		}
+		if failures != 1 {
+			t.Fatalf("want 1 failure, got %d", failures)
+		}
+	})
+}
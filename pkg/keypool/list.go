@@ -0,0 +1,170 @@
+package keypool
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// keyFileVersion is the version of the key file format.
+const keyFileVersion = 1
+
+// keyFile is the JSON representation of a key file.
+type keyFile struct {
+	Version int          `json:"version"`
+	Keys    []keyFileKey `json:"keys"`
+}
+
+// keyFileKey is the JSON representation of a Paillier private key. The other
+// values of the key are derived from N and phi(N).
+type keyFileKey struct {
+	N    *big.Int `json:"n"`
+	PhiN *big.Int `json:"phiN"`
+}
+
+// List is an instance of a list of pre-generated Paillier key pairs. Every
+// key pair is handed out once. It's safe for concurrent use.
+type List struct {
+	mu   sync.Mutex
+	keys []*keys.PrivateKey
+}
+
+// NewList creates a new instance of a list that hands out the given private
+// keys in order.
+func NewList(sks ...*keys.PrivateKey) *List {
+	return &List{
+		keys: append([]*keys.PrivateKey(nil), sks...),
+	}
+}
+
+// Load loads the private keys that are stored in the key file.
+// Returns an error if the file can't be read or contains invalid keys.
+func Load(path string) (*List, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadKeyFile, err)
+	}
+
+	f := new(keyFile)
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKeyFile, err)
+	}
+	if f.Version != keyFileVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidKeyFile, f.Version)
+	}
+
+	sks := make([]*keys.PrivateKey, len(f.Keys))
+	for i, k := range f.Keys {
+		sk, err := privateKey(k.N, k.PhiN)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %d: %w", ErrInvalidKeyFile, i, err)
+		}
+		sks[i] = sk
+	}
+
+	return NewList(sks...), nil
+}
+
+// Save stores the private keys in the key file which is only readable by the
+// owner.
+// Returns an error if the file can't be written.
+func Save(path string, sks ...*keys.PrivateKey) error {
+	f := &keyFile{
+		Version: keyFileVersion,
+		Keys:    make([]keyFileKey, len(sks)),
+	}
+	for i, sk := range sks {
+		f.Keys[i] = keyFileKey{N: sk.N, PhiN: sk.PhiN}
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWriteKeyFile, err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("%w: %w", ErrWriteKeyFile, err)
+	}
+
+	return nil
+}
+
+// Len returns the number of key pairs that are left.
+func (l *List) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.keys)
+}
+
+// Remaining returns the private keys that haven't been handed out yet.
+func (l *List) Remaining() []*keys.PrivateKey {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]*keys.PrivateKey(nil), l.keys...)
+}
+
+// PaillierKeys takes the first key pair whose modulus has the given number of
+// bits from the list.
+// Returns an error if no such key pair is left.
+func (l *List) PaillierKeys(bits int) (*keys.PrivateKey, *keys.PublicKey, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, sk := range l.keys {
+		if sk.N.BitLen() != bits {
+			continue
+		}
+
+		l.keys = append(l.keys[:i], l.keys[i+1:]...)
+
+		return sk, keys.DerivePublicKey(sk), nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: %d bits", ErrNoKeys, bits)
+}
+
+// privateKey derives the private key from N and phi(N) and checks that the
+// key decrypts what it encrypts.
+// Returns an error if the values don't form a valid private key.
+func privateKey(n, phiN *big.Int) (*keys.PrivateKey, error) {
+	if n == nil || phiN == nil || n.Sign() <= 0 || phiN.Sign() <= 0 || phiN.Cmp(n) >= 0 {
+		return nil, ErrInvalidKey
+	}
+
+	mu := new(big.Int).ModInverse(phiN, n) // phiN^-1 mod n
+	if mu == nil {
+		return nil, ErrInvalidKey
+	}
+
+	nn := new(big.Int).Mul(n, n) // n^2
+
+	sk := keys.NewPrivateKey(n, phiN, mu, nn)
+	pk := keys.DerivePublicKey(sk)
+
+	// Check that a random plaintext survives an encryption round trip.
+	m, err := rand.Int(rand.Reader, n)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := cipher.Encrypt(pk, m.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+	plaintext, err := cipher.Decrypt(sk, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+	if new(big.Int).SetBytes(plaintext).Cmp(m) != 0 {
+		return nil, ErrInvalidKey
+	}
+
+	return sk, nil
+}
@@ -0,0 +1,134 @@
+package keypool
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// keyPair is a Paillier key pair.
+type keyPair struct {
+	sk *keys.PrivateKey
+	pk *keys.PublicKey
+}
+
+// Pool is an instance of a pool that generates Paillier key pairs of a fixed
+// size in the background. It's safe for concurrent use.
+type Pool struct {
+	bits    int
+	workers int
+	keys    chan keyPair
+	stop    chan struct{}
+	start   sync.Once
+	close   sync.Once
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	err     error
+}
+
+// NewPool creates a new instance of a pool that keeps up to size key pairs
+// whose moduli have the given number of bits. The pool generates key pairs
+// with a single worker.
+func NewPool(bits, size int) *Pool {
+	return &Pool{
+		bits:    bits,
+		workers: 1,
+		keys:    make(chan keyPair, size),
+		stop:    make(chan struct{}),
+	}
+}
+
+// WithWorkers sets the number of workers that generate key pairs concurrently.
+// Returns the pool to allow chaining.
+func (p *Pool) WithWorkers(workers int) *Pool {
+	p.workers = max(workers, 1)
+
+	return p
+}
+
+// Start starts the workers which fill the pool in the background. Subsequent
+// calls have no effect.
+func (p *Pool) Start() {
+	p.start.Do(func() {
+		p.wg.Add(p.workers)
+		for i := 0; i < p.workers; i++ {
+			go p.work()
+		}
+
+		// Close the key channel once every worker stopped, so that callers
+		// waiting for a key pair don't block forever.
+		go func() {
+			p.wg.Wait()
+			close(p.keys)
+		}()
+	})
+}
+
+// Close stops the workers and waits until they're done. Key pairs that were
+// generated before the pool was closed can still be taken.
+func (p *Pool) Close() {
+	p.close.Do(func() {
+		close(p.stop)
+	})
+	p.wg.Wait()
+}
+
+// Len returns the number of key pairs that are ready to be taken.
+func (p *Pool) Len() int {
+	return len(p.keys)
+}
+
+// PaillierKeys takes a key pair from the pool and blocks until one is
+// available if the pool is empty.
+// Returns an error if the key size isn't the pool's key size, the pool is
+// closed and empty or the workers can't generate key pairs.
+func (p *Pool) PaillierKeys(bits int) (*keys.PrivateKey, *keys.PublicKey, error) {
+	if bits != p.bits {
+		return nil, nil, fmt.Errorf("%w: %d (pool generates %d bits)", ErrKeySize, bits, p.bits)
+	}
+
+	p.Start()
+
+	kp, ok := <-p.keys
+	if !ok {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if p.err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrGenerateKeys, p.err)
+		}
+
+		return nil, nil, ErrPoolClosed
+	}
+
+	return kp.sk, kp.pk, nil
+}
+
+// work generates key pairs until the pool is closed or key generation fails.
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		sk, pk, err := keys.GenerateKeys(p.bits)
+		if err != nil {
+			p.mu.Lock()
+			p.err = err
+			p.mu.Unlock()
+
+			return
+		}
+
+		select {
+		case p.keys <- keyPair{sk: sk, pk: pk}:
+		case <-p.stop:
+			return
+		}
+	}
+}
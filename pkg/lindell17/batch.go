@@ -0,0 +1,78 @@
+package lindell17
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ItemError is an error that occurred while processing a single item of a
+// batch.
+type ItemError struct {
+	// Index is the item's index in the batch.
+	Index int
+	// Err is the error that occurred.
+	Err error
+}
+
+// NewItemError creates a new instance of an error that occurred while
+// processing the batch's item with the given index.
+func NewItemError(index int, err error) *ItemError {
+	return &ItemError{
+		Index: index,
+		Err:   err,
+	}
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError is an error that reports every item of a batch that failed. The
+// batch's other items succeeded.
+type BatchError struct {
+	// Errors are the errors of the failed items, ordered by their index.
+	Errors []*ItemError
+}
+
+// NewBatchError creates a new instance of an error that reports the failed
+// items of a batch. The errors are indexed by the items' indices and nil for
+// items that succeeded.
+// Returns nil if no item failed.
+func NewBatchError(errs []error) *BatchError {
+	var itemErrs []*ItemError
+	for i, err := range errs {
+		if err != nil {
+			itemErrs = append(itemErrs, NewItemError(i, err))
+		}
+	}
+
+	if len(itemErrs) == 0 {
+		return nil
+	}
+
+	return &BatchError{
+		Errors: itemErrs,
+	}
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d batch item(s) failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+
+	return errs
+}
@@ -17,4 +17,24 @@ var (
 	ErrWrongRecipient = fmt.Errorf("wrong recipient")
 	// ErrWrongProtocol is returned if the protocol is wrong.
 	ErrWrongProtocol = fmt.Errorf("wrong protocol")
+	// ErrRecordTranscript is returned if a message or result can't be recorded.
+	ErrRecordTranscript = fmt.Errorf("unable to record transcript")
+	// ErrTooManyMessages is returned if the session's message limit is exceeded.
+	ErrTooManyMessages = fmt.Errorf("too many messages")
+	// ErrInvalidPoint is returned if a point isn't on the curve or is the point at infinity.
+	ErrInvalidPoint = fmt.Errorf("invalid point (not on curve or point at infinity)")
+	// ErrScalarOutOfRange is returned if a scalar is out of range.
+	ErrScalarOutOfRange = fmt.Errorf("scalar out of range")
+	// ErrCiphertextOutOfRange is returned if a ciphertext isn't in the range 0 < c < N^2.
+	ErrCiphertextOutOfRange = fmt.Errorf("ciphertext out of range (0 < c < N^2)")
+	// ErrCiphertextNotUnit is returned if a ciphertext isn't a unit in Z*_{N^2}.
+	ErrCiphertextNotUnit = fmt.Errorf("ciphertext not a unit (gcd(c, N) != 1)")
+	// ErrInvalidPaillierKey is returned if a Paillier public key is malformed.
+	ErrInvalidPaillierKey = fmt.Errorf("invalid Paillier public key")
+	// ErrPaillierModulusTooLarge is returned if a Paillier modulus exceeds the configured limit.
+	ErrPaillierModulusTooLarge = fmt.Errorf("Paillier modulus too large")
+	// ErrEvaluatePolicy is returned if the signing policy can't be evaluated.
+	ErrEvaluatePolicy = fmt.Errorf("unable to evaluate signing policy")
+	// ErrPolicyDenied is returned if the signing policy denies the request.
+	ErrPolicyDenied = fmt.Errorf("signing request denied by policy")
 )
@@ -0,0 +1,33 @@
+package lindell17
+
+// DefaultMaxMessages is the default maximum number of messages a party
+// processes per session.
+const DefaultMaxMessages = 16
+
+// DefaultMaxPaillierBits is the default maximum bit length of a peer's
+// Paillier modulus N.
+const DefaultMaxPaillierBits = 4096
+
+// Limits is an instance of resource limits a party enforces on incoming
+// messages before running any expensive computation.
+type Limits struct {
+	// MaxMessages is the maximum number of messages a party processes per
+	// session.
+	MaxMessages int
+	// MaxPaillierBits is the maximum bit length of a peer's Paillier modulus N.
+	MaxPaillierBits int
+}
+
+// NewLimits creates a new instance of resource limits.
+func NewLimits(maxMessages, maxPaillierBits int) *Limits {
+	return &Limits{
+		MaxMessages:     maxMessages,
+		MaxPaillierBits: maxPaillierBits,
+	}
+}
+
+// DefaultLimits creates a new instance of resource limits that uses the
+// default values.
+func DefaultLimits() *Limits {
+	return NewLimits(DefaultMaxMessages, DefaultMaxPaillierBits)
+}
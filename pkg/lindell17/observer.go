@@ -0,0 +1,281 @@
+package lindell17
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// EventKind indicates the kind of an event of a party's protocol run.
+type EventKind int
+
+const (
+	// EventTransition is reported when a party transitions to another state.
+	EventTransition EventKind = iota
+	// EventSent is reported when a party sends a message.
+	EventSent
+	// EventReceived is reported when a party accepts an incoming message for
+	// processing.
+	EventReceived
+	// EventProof is reported when a party verified a proof, a commitment or
+	// a signature.
+	EventProof
+	// EventResult is reported when a party emits its result.
+	EventResult
+	// EventError is reported when a party's Start or Process call fails.
+	EventError
+	// EventStep is reported when a party's Start or Process call returns.
+	EventStep
+	// EventOperation is reported when a party finished a timed operation,
+	// e.g. a Paillier decryption.
+	EventOperation
+	// EventFallback is reported when a party falls back to a slower
+	// implementation of an operation, e.g. to the regular Paillier decryption
+	// if the CRT decryption isn't available.
+	EventFallback
+	// EventDestroyed is reported when a party is destroyed. If it's destroyed
+	// during its Start or Process call, it's reported once the call returned.
+	EventDestroyed
+)
+
+// String returns the name of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case EventTransition:
+		return "transition"
+	case EventSent:
+		return "sent"
+	case EventReceived:
+		return "received"
+	case EventProof:
+		return "proof"
+	case EventResult:
+		return "result"
+	case EventError:
+		return "error"
+	case EventStep:
+		return "step"
+	case EventOperation:
+		return "operation"
+	case EventFallback:
+		return "fallback"
+	case EventDestroyed:
+		return "destroyed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is an instance of an event of a party's protocol run. Events only
+// carry identifiers and outcomes, never secret material.
+type Event struct {
+	// Kind is the event's kind.
+	Kind EventKind
+	// RunId identifies the party's protocol run within the process. It's set
+	// before the session id is known.
+	RunId uint64
+	// SessionId is the protocol run's session id, which both parties share.
+	// It's empty until the party generated or received it.
+	SessionId string
+	// Protocol is the protocol the party runs.
+	Protocol Protocol
+	// Entity is the party.
+	Entity Entity
+	// State is the party's state after the event. For EventStep, it's the
+	// state the call started in.
+	State State
+	// MessageId is the id of the sent or received message (EventSent and
+	// EventReceived only).
+	MessageId int
+	// Proof is the name of the verified proof (EventProof only).
+	Proof string
+	// Valid is set if the proof is valid (EventProof only).
+	Valid bool
+	// Err is the error the call failed with (EventError and EventStep only) or
+	// the reason of the fallback (EventFallback only).
+	Err error
+	// Operation is the name of the timed operation (EventOperation only) or of
+	// the operation that fell back (EventFallback only).
+	Operation string
+	// Duration is the duration of the call or operation (EventStep and
+	// EventOperation only).
+	Duration time.Duration
+}
+
+// Observer is an interface that observers of protocol runs need to
+// implement. Observers need to be safe for concurrent use, since parties may
+// verify proofs concurrently.
+type Observer interface {
+	// Observe is called for every event of a party's protocol run.
+	Observe(event *Event)
+}
+
+// ObserverFunc is an adapter to allow the use of ordinary functions as
+// observers.
+type ObserverFunc func(event *Event)
+
+// Observe calls f(event).
+func (f ObserverFunc) Observe(event *Event) {
+	f(event)
+}
+
+// runIds is the counter the ids of protocol runs are taken from.
+var runIds atomic.Uint64
+
+// Tracer is an instance of a helper that reports the events of a party's
+// protocol run to the party's observer. Its methods do nothing if the
+// observer is nil.
+type Tracer struct {
+	observer  Observer
+	runId     uint64
+	protocol  Protocol
+	entity    Entity
+	sessionId string
+	state     State
+	calling   bool
+	destroyed bool
+}
+
+// NewTracer creates a new instance of a tracer that reports the events of the
+// entity's run of the protocol to the observer (which may be nil).
+func NewTracer(observer Observer, protocol Protocol, entity Entity) *Tracer {
+	t := &Tracer{
+		observer: observer,
+		protocol: protocol,
+		entity:   entity,
+		state:    Start,
+	}
+	if observer != nil {
+		t.runId = runIds.Add(1)
+	}
+
+	return t
+}
+
+// SetSessionId sets the session id of the protocol run once the party
+// generated it.
+func (t *Tracer) SetSessionId(sessionId string) {
+	t.sessionId = sessionId
+}
+
+// Transition reports the party's transition to the state.
+func (t *Tracer) Transition(state State) {
+	t.state = state
+	t.observe(&Event{Kind: EventTransition})
+}
+
+// Sent reports the message the party sent.
+func (t *Tracer) Sent(msg Message) {
+	t.sessionId = msg.SessionId()
+	t.observe(&Event{Kind: EventSent, MessageId: msg.MessageId()})
+}
+
+// Received reports the message the party accepted for processing.
+func (t *Tracer) Received(msg Message) {
+	t.sessionId = msg.SessionId()
+	t.observe(&Event{Kind: EventReceived, MessageId: msg.MessageId()})
+}
+
+// Proof reports the outcome of the verification of the named proof.
+func (t *Tracer) Proof(name string, valid bool) {
+	t.observe(&Event{Kind: EventProof, Proof: name, Valid: valid})
+}
+
+// Result reports the result the party emitted.
+func (t *Tracer) Result(res Result) {
+	t.sessionId = res.SessionId()
+	t.observe(&Event{Kind: EventResult})
+}
+
+// Error reports the error a Start or Process call failed with (if not nil).
+func (t *Tracer) Error(err error) {
+	if err != nil {
+		t.observe(&Event{Kind: EventError, Err: err})
+	}
+}
+
+// Call is an instance of a party's Start or Process call that is being
+// timed.
+type Call struct {
+	start time.Time
+	state State
+}
+
+// Begin returns the call that starts now. Parties time their Start and
+// Process calls via defer p.tracer.End(p.tracer.Begin(), &err).
+func (t *Tracer) Begin() Call {
+	if t.observer == nil {
+		return Call{}
+	}
+
+	t.calling = true
+
+	return Call{
+		start: time.Now(),
+		state: t.state,
+	}
+}
+
+// End reports the call's error (if not nil) and its duration.
+func (t *Tracer) End(call Call, err *error) {
+	if t.observer == nil {
+		return
+	}
+
+	t.Error(*err)
+	t.observeIn(&Event{Kind: EventStep, Err: *err, Duration: time.Since(call.start)}, call.state)
+
+	t.calling = false
+	if t.destroyed {
+		t.destroyed = false
+		t.observe(&Event{Kind: EventDestroyed})
+	}
+}
+
+// Destroyed reports that the party was destroyed. If it's destroyed during a
+// call, the event is reported once the call ended.
+func (t *Tracer) Destroyed() {
+	if t.observer == nil {
+		return
+	}
+
+	if t.calling {
+		t.destroyed = true
+		return
+	}
+
+	t.observe(&Event{Kind: EventDestroyed})
+}
+
+// Operation reports the duration of the named operation that started at the
+// given time.
+func (t *Tracer) Operation(name string, start time.Time) {
+	t.observe(&Event{Kind: EventOperation, Operation: name, Duration: time.Since(start)})
+}
+
+// Fallback reports that the named operation falls back to a slower
+// implementation for the given reason.
+func (t *Tracer) Fallback(name string, reason error) {
+	t.observe(&Event{Kind: EventFallback, Operation: name, Err: reason})
+}
+
+// observe completes the event with the run's data and the current state and
+// passes it to the observer.
+func (t *Tracer) observe(event *Event) {
+	t.observeIn(event, t.state)
+}
+
+// observeIn completes the event with the run's data and the given state and
+// passes it to the observer.
+func (t *Tracer) observeIn(event *Event, state State) {
+	if t.observer == nil {
+		return
+	}
+
+	event.RunId = t.runId
+	event.SessionId = t.sessionId
+	event.Protocol = t.protocol
+	event.Entity = t.entity
+	event.State = state
+
+	t.observer.Observe(event)
+}
@@ -0,0 +1,141 @@
+package lindell17
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+)
+
+// Payload is an instance of the structured data a hash was computed from,
+// e.g. a decoded transaction. It's provided by the requester of a signature
+// and only as trustworthy as the policy's check that it matches the hash.
+type Payload struct {
+	// Destination is the address the funds are sent to.
+	Destination string
+	// Amount is the amount that is sent.
+	Amount *big.Int
+	// Data is the encoded transaction the hash was computed from.
+	Data []byte
+}
+
+// SigningRequest is an instance of a request to sign a hash that party 2
+// evaluates its policy on.
+type SigningRequest struct {
+	// Protocol is the signing protocol (Sign, Adaptor or BatchSign).
+	Protocol Protocol
+	// KeyID identifies the key that signs.
+	KeyID string
+	// Hash is the hash to sign.
+	Hash []byte
+	// Payload is the optional structured data the hash was computed from.
+	Payload *Payload
+	// Statement is the statement an adaptor signature is bound to (Adaptor
+	// requests only).
+	Statement *adaptor.Statement
+}
+
+// NewSigningRequest creates a new instance of a signing request.
+func NewSigningRequest(protocol Protocol, keyID string, hash []byte, payload *Payload) *SigningRequest {
+	return &SigningRequest{
+		Protocol: protocol,
+		KeyID:    keyID,
+		Hash:     hash,
+		Payload:  payload,
+	}
+}
+
+// WithStatement sets the statement the adaptor signature is bound to.
+// Returns the request to allow chaining.
+func (r *SigningRequest) WithStatement(statement *adaptor.Statement) *SigningRequest {
+	r.Statement = statement
+
+	return r
+}
+
+// Decision is an instance of a policy's decision on a signing request.
+type Decision struct {
+	// Allow is set if the request may be signed.
+	Allow bool
+	// Reason explains the decision.
+	Reason string
+}
+
+// Allow creates a new instance of a decision that allows a request.
+func Allow() *Decision {
+	return &Decision{Allow: true}
+}
+
+// Deny creates a new instance of a decision that denies a request for the
+// given reason.
+func Deny(reason string) *Decision {
+	return &Decision{Reason: reason}
+}
+
+// Policy is an interface that signing policies need to implement. Party 2 of
+// the sign, adaptor and batch sign protocols evaluates its policy before it
+// contributes to a signature (in batches on all items, see EnforceBatch).
+type Policy interface {
+	// Evaluate decides whether the request may be signed.
+	Evaluate(req *SigningRequest) (*Decision, error)
+}
+
+// BatchPolicy is an interface that signing policies implement to evaluate the
+// items of a batch as a whole, so that the requests of a denied batch aren't
+// recorded by stateful rules (e.g. velocity limits).
+type BatchPolicy interface {
+	// EvaluateBatch decides whether all of the requests may be signed. It
+	// returns the index of the first request that isn't allowed along with
+	// the decision on it. The requests are only recorded if all of them are
+	// allowed.
+	EvaluateBatch(reqs []*SigningRequest) (int, *Decision, error)
+}
+
+// Enforce evaluates the policy on the request.
+// Returns an error if the policy can't be evaluated or denies the request.
+func Enforce(policy Policy, req *SigningRequest) error {
+	decision, err := policy.Evaluate(req)
+
+	return checkDecision(decision, err)
+}
+
+// EnforceBatch evaluates the policy on the requests of a batch. Policies that
+// don't implement BatchPolicy evaluate the requests one by one, so the
+// requests before a denied one count as allowed.
+// Returns an ItemError if the policy can't be evaluated or denies a request.
+func EnforceBatch(policy Policy, reqs []*SigningRequest) error {
+	batchPolicy, ok := policy.(BatchPolicy)
+	if !ok {
+		for i, req := range reqs {
+			if err := Enforce(policy, req); err != nil {
+				return NewItemError(i, err)
+			}
+		}
+
+		return nil
+	}
+
+	i, decision, err := batchPolicy.EvaluateBatch(reqs)
+	if err := checkDecision(decision, err); err != nil {
+		return NewItemError(i, err)
+	}
+
+	return nil
+}
+
+// checkDecision checks the decision a policy made.
+// Returns an error if the policy couldn't be evaluated or denied the request.
+func checkDecision(decision *Decision, err error) error {
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrEvaluatePolicy, err)
+	}
+	if decision == nil || !decision.Allow {
+		reason := "no reason given"
+		if decision != nil && decision.Reason != "" {
+			reason = decision.Reason
+		}
+		return fmt.Errorf("%w: %s", ErrPolicyDenied, reason)
+	}
+
+	return nil
+}
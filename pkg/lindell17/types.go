@@ -1,5 +1,13 @@
 package lindell17
 
+import (
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// SessionIdLength is the number of characters a session id has.
+const SessionIdLength = 64
+
 // Message is an interface that all protocol messages need to implement.
 type Message interface {
 	// To returns the entity the message should be sent to.
@@ -12,8 +20,48 @@ type Message interface {
 	MessageId() int
 	// SessionId returns the protocol run's session id.
 	SessionId() string
-	// IsValid checks if the message and its content are valid.
+	// IsValid checks if the message is well-formed, i.e. if all its fields are
+	// set and the session id has the expected format.
 	IsValid() bool
+	// ValidateFor checks if the message's content is valid for the given curve
+	// and Paillier public key. Points need to lie on the curve, scalars need to
+	// be in range and ciphertexts need to be units in Z*_{N^2}.
+	// Returns an error if the message or its content is invalid.
+	ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error
+}
+
+// Participant is an interface that all protocol parties implement.
+type Participant interface {
+	// Start starts the party's part of the protocol.
+	Start() (bool, error)
+	// Process processes an incoming protocol message.
+	Process(msg Message) (bool, error)
+}
+
+// Destroyer is an interface that protocol parties implement which hold
+// secrets. Parties destroy themselves once they sent their result or a call
+// failed.
+type Destroyer interface {
+	// Destroy wipes the party's secrets and ends its protocol run.
+	Destroy()
+}
+
+// Recorder is an interface that protocol transcript recorders need to implement.
+type Recorder interface {
+	// RecordReceived records a message the party received.
+	RecordReceived(msg Message) error
+	// RecordSent records a message the party sent.
+	RecordSent(msg Message) error
+	// RecordResult records the party's result.
+	RecordResult(res Result) error
+}
+
+// PaillierKeyProvider is an interface that providers of pre-generated Paillier
+// key pairs need to implement.
+type PaillierKeyProvider interface {
+	// PaillierKeys returns a Paillier key pair whose modulus has the given
+	// number of bits. A key pair must never be returned twice.
+	PaillierKeys(bits int) (*keys.PrivateKey, *keys.PublicKey, error)
 }
 
 // Result is an interface that all protocol results need to implement.
@@ -38,8 +86,28 @@ const (
 	Sign
 	// Adaptor is the protocol to generate adaptor signatures.
 	Adaptor
+	// BatchSign is the protocol to generate signatures for a batch of hashes.
+	BatchSign
 )
 
+// String returns the name of the protocol.
+func (p Protocol) String() string {
+	switch p {
+	case DLEncProof:
+		return "dlenc_proof"
+	case Keygen:
+		return "keygen"
+	case Sign:
+		return "sign"
+	case Adaptor:
+		return "adaptor"
+	case BatchSign:
+		return "batchsign"
+	default:
+		return "unknown"
+	}
+}
+
 // Entity is used to indicate a protocol's entity.
 type Entity int
 
@@ -54,6 +122,22 @@ const (
 	Verifier
 )
 
+// String returns the name of the entity.
+func (e Entity) String() string {
+	switch e {
+	case Party1:
+		return "party1"
+	case Party2:
+		return "party2"
+	case Prover:
+		return "prover"
+	case Verifier:
+		return "verifier"
+	default:
+		return "unknown"
+	}
+}
+
 // State is used to indicate an internal state.
 type State int
 
@@ -68,4 +152,27 @@ const (
 	Step3
 	// Step4 is the fourth state.
 	Step4
+	// Destroyed is the final state of an instance whose protocol run ended
+	// and whose secrets were wiped.
+	Destroyed
 )
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case Start:
+		return "start"
+	case Step1:
+		return "step1"
+	case Step2:
+		return "step2"
+	case Step3:
+		return "step3"
+	case Step4:
+		return "step4"
+	case Destroyed:
+		return "destroyed"
+	default:
+		return "unknown"
+	}
+}
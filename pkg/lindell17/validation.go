@@ -0,0 +1,125 @@
+package lindell17
+
+import (
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// CheckPoint checks that the point is set, lies on the curve and isn't the
+// point at infinity.
+// Returns an error if the point is invalid.
+func CheckPoint(curve weierstrass.Curve, point *elliptic.Point) error {
+	if point == nil || point.X == nil || point.Y == nil {
+		return ErrInvalidPoint
+	}
+
+	// The point at infinity is encoded as (x=0, y=0).
+	if point.X.Sign() == 0 && point.Y.Sign() == 0 {
+		return ErrInvalidPoint
+	}
+
+	// Coordinates need to be elements of the underlying field.
+	if point.X.Sign() < 0 || point.X.Cmp(curve.P()) >= 0 ||
+		point.Y.Sign() < 0 || point.Y.Cmp(curve.P()) >= 0 {
+		return ErrInvalidPoint
+	}
+
+	if !curve.IsOnCurve(point) {
+		return ErrInvalidPoint
+	}
+
+	return nil
+}
+
+// CheckScalar checks that the scalar is set and in the range min <= x < max.
+// Returns an error if the scalar is out of range.
+func CheckScalar(scalar, min, max *big.Int) error {
+	if scalar == nil || scalar.Cmp(min) < 0 || scalar.Cmp(max) >= 0 {
+		return ErrScalarOutOfRange
+	}
+
+	return nil
+}
+
+// IsValidSessionId checks that the session id has the format session ids are
+// generated in (a hex-encoded SHA-256 hash).
+func IsValidSessionId(sid string) bool {
+	if len(sid) != SessionIdLength {
+		return false
+	}
+
+	// Only lowercase hex characters are allowed.
+	for _, c := range sid {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckCiphertext checks that the ciphertext is in the range 0 < c < N^2 and
+// that it's a unit in Z*_{N^2}, i.e. gcd(c, N) = 1.
+// Returns an error if the ciphertext is invalid.
+func CheckCiphertext(pk *keys.PublicKey, ciphertext cipher.Ciphertext) error {
+	if pk == nil || pk.N == nil || pk.NN == nil {
+		return ErrInvalidPaillierKey
+	}
+
+	// Reject oversized encodings before interpreting them as a number.
+	if len(ciphertext) > (pk.NN.BitLen()+7)/8 {
+		return ErrCiphertextOutOfRange
+	}
+
+	c := new(big.Int).SetBytes(ciphertext)
+	if c.Sign() <= 0 || c.Cmp(pk.NN) >= 0 {
+		return ErrCiphertextOutOfRange
+	}
+
+	gcd := new(big.Int).GCD(nil, nil, c, pk.N)
+	if gcd.Cmp(big.NewInt(1)) != 0 {
+		return ErrCiphertextNotUnit
+	}
+
+	return nil
+}
+
+// CheckPaillierKey checks that the Paillier public key is well-formed.
+// Returns an error if the public key is invalid.
+func CheckPaillierKey(pk *keys.PublicKey) error {
+	if pk == nil || pk.N == nil || pk.G == nil || pk.NN == nil {
+		return ErrInvalidPaillierKey
+	}
+
+	// N needs to be an odd number > 1.
+	if pk.N.Cmp(big.NewInt(1)) <= 0 || pk.N.Bit(0) != 1 {
+		return ErrInvalidPaillierKey
+	}
+
+	g := new(big.Int).Add(pk.N, big.NewInt(1)) // N + 1
+	nn := new(big.Int).Mul(pk.N, pk.N)         // N^2
+	if pk.G.Cmp(g) != 0 || pk.NN.Cmp(nn) != 0 {
+		return ErrInvalidPaillierKey
+	}
+
+	return nil
+}
+
+// CheckPaillierKey checks that the Paillier public key is well-formed and that
+// its modulus doesn't exceed the configured limit.
+// Returns an error if the public key is invalid or too large.
+func (l *Limits) CheckPaillierKey(pk *keys.PublicKey) error {
+	if pk == nil || pk.N == nil {
+		return ErrInvalidPaillierKey
+	}
+
+	if pk.N.BitLen() > l.MaxPaillierBits {
+		return ErrPaillierModulusTooLarge
+	}
+
+	return CheckPaillierKey(pk)
+}
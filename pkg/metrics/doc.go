@@ -0,0 +1,32 @@
+/*
+Package metrics implements an observer of protocol runs (see
+lindell17.Observer) that aggregates the runs' events into Prometheus metrics
+and exposes them in the Prometheus text format.
+
+A Metrics instance is set via the parties' params' WithObserver methods (or
+the co-signer's WithObserver method) and served as the scrape endpoint:
+
+	m := metrics.NewMetrics().WithStaleAfter(5 * time.Minute)
+	params := party2.NewParams(curve, pk, x1Enc, x2).WithObserver(m)
+	http.Handle("/metrics", m)
+
+The following metrics are exposed, labeled with the protocol and the entity:
+
+	lindell17_runs_started_total           protocol runs started
+	lindell17_runs_completed_total         protocol runs that emitted their result
+	lindell17_runs_aborted_total           protocol runs that failed, by reason
+	lindell17_runs_active                  protocol runs in progress
+	lindell17_step_duration_seconds        duration of the Start and Process calls, by step
+	lindell17_operation_duration_seconds   duration of timed operations, by operation
+
+A run is tracked from the party's Start call on and ends with the call in
+which the party emits its result or fails. The abort reasons are derived from
+the lindell17 sentinel errors (see Reason). Runs that a peer abandons never
+fail, so they are counted as aborted (with the reason "abandoned") once the
+party is destroyed (e.g. because its session timed out) or once they're
+stale, if WithStaleAfter is set.
+
+Timed operations currently cover party 1's Paillier decryptions
+("paillier_decryption").
+*/
+package metrics
@@ -0,0 +1,215 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// DefaultBuckets are the upper bounds (in seconds) of the buckets of the
+// duration histograms. They range from a millisecond for the cheap steps to
+// 30 seconds for the generation of Paillier keys.
+var DefaultBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// party identifies the protocol and the entity of runs.
+type party struct {
+	protocol lindell17.Protocol
+	entity   lindell17.Entity
+}
+
+// abortKey identifies the aborted runs of a party by reason.
+type abortKey struct {
+	party
+	reason string
+}
+
+// stepKey identifies the steps of a party's runs.
+type stepKey struct {
+	party
+	state lindell17.State
+}
+
+// operationKey identifies the timed operations of a party's runs.
+type operationKey struct {
+	party
+	operation string
+}
+
+// run is the state of a tracked protocol run.
+type run struct {
+	party
+	lastSeen     time.Time
+	invalidProof bool
+	// reason is set once the run failed.
+	reason string
+	// done is set once the run emitted its result or failed. The run ends
+	// with the call it happened in.
+	done bool
+}
+
+// Metrics is an instance of an observer that aggregates the events of
+// protocol runs into Prometheus metrics. It serves them in the Prometheus
+// text format via ServeHTTP. It's safe for concurrent use.
+type Metrics struct {
+	mu         sync.Mutex
+	clock      func() time.Time
+	staleAfter time.Duration
+	buckets    []float64
+	runs       map[uint64]*run
+	started    map[party]uint64
+	completed  map[party]uint64
+	aborted    map[abortKey]uint64
+	steps      map[stepKey]*histogram
+	operations map[operationKey]*histogram
+}
+
+// NewMetrics creates a new instance of an observer that aggregates the events
+// of protocol runs into Prometheus metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		clock:      time.Now,
+		buckets:    DefaultBuckets,
+		runs:       make(map[uint64]*run),
+		started:    make(map[party]uint64),
+		completed:  make(map[party]uint64),
+		aborted:    make(map[abortKey]uint64),
+		steps:      make(map[stepKey]*histogram),
+		operations: make(map[operationKey]*histogram),
+	}
+}
+
+// WithStaleAfter sets the duration without events after which runs are
+// counted as aborted with the reason "abandoned". Zero (the default) keeps
+// runs active until they end.
+// Returns the metrics to allow chaining.
+func (m *Metrics) WithStaleAfter(d time.Duration) *Metrics {
+	m.staleAfter = d
+
+	return m
+}
+
+// WithClock sets the function the metrics get the current time from.
+// Returns the metrics to allow chaining.
+func (m *Metrics) WithClock(clock func() time.Time) *Metrics {
+	m.clock = clock
+
+	return m
+}
+
+// WithBuckets sets the upper bounds (in seconds, ascending) of the buckets of
+// the duration histograms. It needs to be called before the first event is
+// observed.
+// Returns the metrics to allow chaining.
+func (m *Metrics) WithBuckets(buckets ...float64) *Metrics {
+	m.buckets = buckets
+
+	return m
+}
+
+// Observe aggregates the event. Runs are tracked from their first event in
+// the Start state or their first transition on, so that calls on parties
+// that already ended aren't counted as new runs. Runs that are destroyed
+// before they ended are counted as aborted with the reason "abandoned".
+func (m *Metrics) Observe(event *lindell17.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock()
+	p := party{event.Protocol, event.Entity}
+
+	r, ok := m.runs[event.RunId]
+	if !ok {
+		if event.Kind == lindell17.EventDestroyed {
+			return
+		}
+		if event.State != lindell17.Start && event.Kind != lindell17.EventTransition {
+			return
+		}
+		r = &run{party: p}
+		m.runs[event.RunId] = r
+		m.started[p]++
+	}
+	r.lastSeen = now
+
+	switch event.Kind {
+	case lindell17.EventProof:
+		if !event.Valid {
+			r.invalidProof = true
+		}
+	case lindell17.EventResult:
+		if !r.done {
+			r.done = true
+			m.completed[p]++
+		}
+	case lindell17.EventError:
+		if !r.done {
+			r.done = true
+			r.reason = Reason(event.Err)
+			if r.reason == ReasonOther && r.invalidProof {
+				r.reason = ReasonInvalidProof
+			}
+			m.aborted[abortKey{p, r.reason}]++
+		}
+	case lindell17.EventOperation:
+		observeDuration(m.operations, operationKey{p, event.Operation}, m.buckets, event.Duration)
+	case lindell17.EventStep:
+		observeDuration(m.steps, stepKey{p, event.State}, m.buckets, event.Duration)
+		if r.done {
+			delete(m.runs, event.RunId)
+		}
+	case lindell17.EventDestroyed:
+		if !r.done {
+			m.aborted[abortKey{p, ReasonAbandoned}]++
+		}
+		delete(m.runs, event.RunId)
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	_, _ = m.WriteTo(w)
+}
+
+// observeDuration adds the duration to the key's histogram, which is created
+// with the buckets if it doesn't exist yet.
+func observeDuration[K comparable](histograms map[K]*histogram, key K, buckets []float64, d time.Duration) {
+	h, ok := histograms[key]
+	if !ok {
+		h = newHistogram(buckets)
+		histograms[key] = h
+	}
+
+	h.observe(d.Seconds())
+}
+
+// expire counts the stale runs as aborted. It needs to be called with the
+// lock held.
+func (m *Metrics) expire() {
+	if m.staleAfter <= 0 {
+		return
+	}
+
+	now := m.clock()
+	for id, r := range m.runs {
+		if now.Sub(r.lastSeen) < m.staleAfter {
+			continue
+		}
+		if !r.done {
+			m.aborted[abortKey{r.party, ReasonAbandoned}]++
+		}
+		delete(m.runs, id)
+	}
+}
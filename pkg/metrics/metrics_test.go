@@ -0,0 +1,275 @@
+package metrics_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/metrics"
+	"github.com/primefactor-io/lindell17/pkg/policy"
+	"github.com/primefactor-io/lindell17/pkg/sign/party1"
+	"github.com/primefactor-io/lindell17/pkg/sign/party2"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	pKeys "github.com/primefactor-io/paillier/pkg/keys"
+)
+
+var errRun = errors.New("run failed")
+
+var secp256k1 = curves.Secp256k1
+
+// clock is a settable clock.
+type clock struct {
+	now time.Time
+}
+
+// scrape scrapes the metrics via HTTP and returns the samples by series.
+func scrape(t *testing.T, m *metrics.Metrics) map[string]string {
+	t.Helper()
+
+	server := httptest.NewServer(m)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != metrics.ContentType {
+		t.Errorf("want content type %q, got %q", metrics.ContentType, ct)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	samples := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndexByte(line, ' ')
+		if i < 0 {
+			t.Fatalf("invalid sample %q", line)
+		}
+		samples[line[:i]] = line[i+1:]
+	}
+
+	return samples
+}
+
+// expect checks the values of the series.
+func expect(t *testing.T, samples map[string]string, want map[string]string) {
+	t.Helper()
+
+	for series, value := range want {
+		if samples[series] != value {
+			t.Errorf("%s: want %q, got %q", series, value, samples[series])
+		}
+	}
+}
+
+// sign runs the sign protocol with the parties and returns party 2's Start
+// error, if any.
+func sign(t *testing.T, p1 *party1.Party1, p2 *party2.Party2, outCh chan lindell17.Message, resCh chan lindell17.Result) error {
+	t.Helper()
+
+	if _, err := p2.Start(); err != nil {
+		return err
+	}
+	if _, err := p1.Start(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for len(resCh) < 2 {
+		msg := <-outCh
+		var err error
+		switch msg.To() {
+		case lindell17.Party1:
+			_, err = p1.Process(msg)
+		case lindell17.Party2:
+			_, err = p2.Process(msg)
+		}
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	return nil
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Sign (scrape)", func(t *testing.T) {
+		t.Parallel()
+
+		q3 := new(big.Int).Div(secp256k1.N(), big.NewInt(3))
+		sk, pk, _ := pKeys.GenerateKeys(1024)
+		x1, _ := secp256k1.GetRandomScalar(q3)
+		x1Enc, _ := cipher.Encrypt(pk, x1.Bytes())
+		x2, _ := secp256k1.GetRandomScalar()
+		q2, _ := secp256k1.ScalarMultiply(x2, secp256k1.G())
+		q, _ := secp256k1.ScalarMultiply(x1, q2)
+
+		checksum := sha256.Sum256([]byte("Hello World"))
+		hash := checksum[:]
+
+		m := metrics.NewMetrics()
+		p1Params := party1.NewParams(secp256k1, sk, q).WithObserver(m)
+		p2Params := party2.NewParams(secp256k1, pk, x1Enc, x2).WithObserver(m)
+
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, outCh, resCh)
+		if err := sign(t, p1, p2, outCh, resCh); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// Calls on parties that ended don't start runs.
+		if _, err := p2.Start(); !errors.Is(err, lindell17.ErrInvalidState) {
+			t.Errorf("want error %v, got %v", lindell17.ErrInvalidState, err)
+		}
+
+		engine := policy.NewEngine(policy.Destinations("bc1qallowed"))
+		deniedParams := party2.NewParams(secp256k1, pk, x1Enc, x2).
+			WithPolicy(engine, "key").
+			WithPayload(&lindell17.Payload{Destination: "bc1qunknown"}).
+			WithObserver(m)
+		denied := party2.NewParty2(deniedParams, hash, outCh, resCh)
+		if err := sign(t, nil, denied, outCh, resCh); !errors.Is(err, lindell17.ErrPolicyDenied) {
+			t.Errorf("want error %v, got %v", lindell17.ErrPolicyDenied, err)
+		}
+
+		p1Labels := `protocol="sign",entity="party1"`
+		p2Labels := `protocol="sign",entity="party2"`
+		expect(t, scrape(t, m), map[string]string{
+			"lindell17_runs_started_total{" + p1Labels + "}":                                               "1",
+			"lindell17_runs_started_total{" + p2Labels + "}":                                               "2",
+			"lindell17_runs_completed_total{" + p1Labels + "}":                                             "1",
+			"lindell17_runs_completed_total{" + p2Labels + "}":                                             "1",
+			"lindell17_runs_aborted_total{" + p2Labels + `,reason="policy_denied"}`:                        "1",
+			"lindell17_runs_active{" + p1Labels + "}":                                                      "0",
+			"lindell17_runs_active{" + p2Labels + "}":                                                      "0",
+			"lindell17_step_duration_seconds_count{" + p1Labels + `,step="start"}`:                         "1",
+			"lindell17_step_duration_seconds_count{" + p1Labels + `,step="step3"}`:                         "1",
+			"lindell17_step_duration_seconds_count{" + p2Labels + `,step="start"}`:                         "2",
+			"lindell17_step_duration_seconds_bucket{" + p2Labels + `,step="start",le="+Inf"}`:              "2",
+			"lindell17_operation_duration_seconds_count{" + p1Labels + `,operation="paillier_decryption"}`: "1",
+		})
+	})
+
+	t.Run("Abort reasons", func(t *testing.T) {
+		t.Parallel()
+
+		m := metrics.NewMetrics()
+
+		// fail runs a party that fails in its first Process call.
+		fail := func(err error, invalidProof bool) {
+			tracer := lindell17.NewTracer(m, lindell17.Keygen, lindell17.Party2)
+
+			var callErr error
+			tracer.End(tracer.Begin(), &callErr)
+			tracer.Transition(lindell17.Step1)
+
+			call := tracer.Begin()
+			if invalidProof {
+				tracer.Proof("DLEnc proof", false)
+			}
+			tracer.End(call, &err)
+		}
+
+		fail(fmt.Errorf("%w: %w", lindell17.ErrTooManyMessages, errRun), false)
+		fail(lindell17.ErrCiphertextNotUnit, false)
+		fail(lindell17.ErrInvalidPoint, true)
+		fail(errRun, true)
+		fail(errRun, false)
+
+		labels := `protocol="keygen",entity="party2"`
+		expect(t, scrape(t, m), map[string]string{
+			"lindell17_runs_started_total{" + labels + "}":                            "5",
+			"lindell17_runs_aborted_total{" + labels + `,reason="too_many_messages"}`: "1",
+			"lindell17_runs_aborted_total{" + labels + `,reason="invalid_content"}`:   "2",
+			"lindell17_runs_aborted_total{" + labels + `,reason="invalid_proof"}`:     "1",
+			"lindell17_runs_aborted_total{" + labels + `,reason="other"}`:             "1",
+			"lindell17_runs_active{" + labels + "}":                                   "0",
+		})
+	})
+
+	t.Run("Abandoned runs", func(t *testing.T) {
+		t.Parallel()
+
+		c := &clock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		m := metrics.NewMetrics().
+			WithStaleAfter(time.Minute).
+			WithClock(func() time.Time { return c.now })
+
+		tracer := lindell17.NewTracer(m, lindell17.Adaptor, lindell17.Party1)
+		var err error
+		call := tracer.Begin()
+		tracer.Transition(lindell17.Step1)
+		tracer.End(call, &err)
+
+		labels := `protocol="adaptor",entity="party1"`
+		expect(t, scrape(t, m), map[string]string{
+			"lindell17_runs_active{" + labels + "}": "1",
+		})
+
+		c.now = c.now.Add(time.Minute)
+		expect(t, scrape(t, m), map[string]string{
+			"lindell17_runs_active{" + labels + "}":                           "0",
+			"lindell17_runs_aborted_total{" + labels + `,reason="abandoned"}`: "1",
+		})
+
+		// Later calls of the abandoned run aren't counted.
+		tracer.Error(lindell17.ErrUnknownMessage)
+		expect(t, scrape(t, m), map[string]string{
+			"lindell17_runs_started_total{" + labels + "}":                          "1",
+			"lindell17_runs_aborted_total{" + labels + `,reason="unknown_message"}`: "",
+		})
+
+		// Runs that are destroyed before they ended are abandoned right away.
+		destroyed := lindell17.NewTracer(m, lindell17.Adaptor, lindell17.Party1)
+		call = destroyed.Begin()
+		destroyed.Transition(lindell17.Step1)
+		destroyed.End(call, &err)
+		destroyed.Destroyed()
+		expect(t, scrape(t, m), map[string]string{
+			"lindell17_runs_started_total{" + labels + "}":                    "2",
+			"lindell17_runs_active{" + labels + "}":                           "0",
+			"lindell17_runs_aborted_total{" + labels + `,reason="abandoned"}`: "2",
+		})
+	})
+
+	t.Run("Reason", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			err  error
+			want string
+		}{
+			{lindell17.ErrInvalidState, "invalid_state"},
+			{fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, errRun), "record_transcript"},
+			{fmt.Errorf("%w: %w", lindell17.ErrPolicyDenied, errRun), "policy_denied"},
+			{lindell17.ErrPaillierModulusTooLarge, "invalid_paillier_key"},
+			{errRun, metrics.ReasonOther},
+		}
+
+		for _, tc := range cases {
+			if got := metrics.Reason(tc.err); got != tc.want {
+				t.Errorf("%v: want reason %q, got %q", tc.err, tc.want, got)
+			}
+		}
+	})
+}
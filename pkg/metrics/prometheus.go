@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the Prometheus text format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// histogram is an instance of a histogram with cumulative buckets.
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// newHistogram creates a new instance of a histogram with the buckets' upper
+// bounds.
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+// observe adds the value to the histogram.
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// sample is a sample of a metric.
+type sample struct {
+	labels string
+	value  float64
+}
+
+// WriteTo writes the metrics in the Prometheus text format to the writer.
+// Stale runs are counted as aborted first.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	m.expire()
+
+	var buf bytes.Buffer
+
+	var started, completed, active []sample
+	activeRuns := make(map[party]int)
+	for _, r := range m.runs {
+		if !r.done {
+			activeRuns[r.party]++
+		}
+	}
+	for p, n := range m.started {
+		started = append(started, sample{p.labels(), float64(n)})
+		completed = append(completed, sample{p.labels(), float64(m.completed[p])})
+		active = append(active, sample{p.labels(), float64(activeRuns[p])})
+	}
+	writeSamples(&buf, "lindell17_runs_started_total", "counter", "Number of protocol runs started.", started)
+	writeSamples(&buf, "lindell17_runs_completed_total", "counter", "Number of protocol runs that emitted their result.", completed)
+
+	var aborted []sample
+	for k, n := range m.aborted {
+		aborted = append(aborted, sample{k.labels("reason", k.reason), float64(n)})
+	}
+	writeSamples(&buf, "lindell17_runs_aborted_total", "counter", "Number of protocol runs that failed or were abandoned, by reason.", aborted)
+	writeSamples(&buf, "lindell17_runs_active", "gauge", "Number of protocol runs in progress.", active)
+
+	steps := make(map[string]*histogram, len(m.steps))
+	for k, h := range m.steps {
+		steps[k.labels("step", k.state.String())] = h
+	}
+	writeHistograms(&buf, "lindell17_step_duration_seconds", "Duration of the parties' Start and Process calls, by the step they started in.", steps)
+
+	operations := make(map[string]*histogram, len(m.operations))
+	for k, h := range m.operations {
+		operations[k.labels("operation", k.operation)] = h
+	}
+	writeHistograms(&buf, "lindell17_operation_duration_seconds", "Duration of timed operations, e.g. Paillier decryptions.", operations)
+
+	m.mu.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+// labels returns the label set of the party with the additional label pairs.
+func (p party) labels(pairs ...string) string {
+	return formatLabels(append([]string{"protocol", p.protocol.String(), "entity", p.entity.String()}, pairs...)...)
+}
+
+// writeSamples writes the metric's samples sorted by their labels.
+func writeSamples(buf *bytes.Buffer, name, typ, help string, samples []sample) {
+	writeHeader(buf, name, typ, help)
+
+	slices.SortFunc(samples, func(a, b sample) int {
+		return strings.Compare(a.labels, b.labels)
+	})
+	for _, s := range samples {
+		fmt.Fprintf(buf, "%s%s %s\n", name, s.labels, formatFloat(s.value))
+	}
+}
+
+// writeHistograms writes the histograms sorted by their labels.
+func writeHistograms(buf *bytes.Buffer, name, help string, histograms map[string]*histogram) {
+	writeHeader(buf, name, "histogram", help)
+
+	keys := make([]string, 0, len(histograms))
+	for k := range histograms {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, labels := range keys {
+		h := histograms[labels]
+		// Append the bucket's upper bound to the label set.
+		prefix := strings.TrimSuffix(labels, "}") + ","
+		for i, bound := range h.bounds {
+			fmt.Fprintf(buf, "%s_bucket%sle=%q} %d\n", name, prefix, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(buf, "%s_bucket%sle=\"+Inf\"} %d\n", name, prefix, h.count)
+		fmt.Fprintf(buf, "%s_sum%s %s\n", name, labels, formatFloat(h.sum))
+		fmt.Fprintf(buf, "%s_count%s %d\n", name, labels, h.count)
+	}
+}
+
+// writeHeader writes the metric's HELP and TYPE lines.
+func writeHeader(buf *bytes.Buffer, name, typ, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// formatLabels returns the label set of the name-value pairs, e.g.
+// {protocol="sign",entity="party2"}.
+func formatLabels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+// labelEscaper escapes label values as the text format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes the label value.
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+// formatFloat formats the value as the text format requires.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"errors"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+const (
+	// ReasonInvalidProof is the reason of runs that failed after a proof,
+	// commitment or signature didn't verify.
+	ReasonInvalidProof = "invalid_proof"
+	// ReasonAbandoned is the reason of runs that became stale.
+	ReasonAbandoned = "abandoned"
+	// ReasonOther is the reason of runs that failed with an unknown error.
+	ReasonOther = "other"
+)
+
+// reasons maps the sentinel errors to the reasons of aborted runs.
+var reasons = []struct {
+	err    error
+	reason string
+}{
+	{lindell17.ErrGenerateSessionId, "session_id"},
+	{lindell17.ErrInvalidState, "invalid_state"},
+	{lindell17.ErrUnknownMessage, "unknown_message"},
+	{lindell17.ErrInvalidMessage, "invalid_message"},
+	{lindell17.ErrWrongSender, "wrong_sender"},
+	{lindell17.ErrWrongRecipient, "wrong_recipient"},
+	{lindell17.ErrWrongProtocol, "wrong_protocol"},
+	{lindell17.ErrRecordTranscript, "record_transcript"},
+	{lindell17.ErrTooManyMessages, "too_many_messages"},
+	{lindell17.ErrInvalidPoint, "invalid_content"},
+	{lindell17.ErrScalarOutOfRange, "invalid_content"},
+	{lindell17.ErrCiphertextOutOfRange, "invalid_content"},
+	{lindell17.ErrCiphertextNotUnit, "invalid_content"},
+	{lindell17.ErrInvalidPaillierKey, "invalid_paillier_key"},
+	{lindell17.ErrPaillierModulusTooLarge, "invalid_paillier_key"},
+	{lindell17.ErrEvaluatePolicy, "policy_error"},
+	{lindell17.ErrPolicyDenied, "policy_denied"},
+}
+
+// Reason returns the reason of a run that failed with the error. Errors that
+// don't wrap one of the lindell17 sentinel errors are reported as
+// ReasonOther.
+func Reason(err error) string {
+	for _, r := range reasons {
+		if errors.Is(err, r.err) {
+			return r.reason
+		}
+	}
+
+	return ReasonOther
+}
@@ -0,0 +1,34 @@
+package observe
+
+import "github.com/primefactor-io/lindell17/pkg/lindell17"
+
+// multi is an instance of an observer that passes events to several
+// observers.
+type multi []lindell17.Observer
+
+// All returns an observer that passes every event to the observers in order.
+// Nil observers are skipped and nil is returned if none remain.
+func All(observers ...lindell17.Observer) lindell17.Observer {
+	var m multi
+	for _, o := range observers {
+		if o != nil {
+			m = append(m, o)
+		}
+	}
+
+	switch len(m) {
+	case 0:
+		return nil
+	case 1:
+		return m[0]
+	default:
+		return m
+	}
+}
+
+// Observe passes the event to the observers.
+func (m multi) Observe(event *lindell17.Event) {
+	for _, o := range m {
+		o.Observe(event)
+	}
+}
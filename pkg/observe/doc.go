@@ -0,0 +1,42 @@
+/*
+Package observe implements observers of protocol runs (see
+lindell17.Observer) that log the events via log/slog or trace every protocol
+run as a span.
+
+Parties report their state transitions, the messages they send and receive,
+the outcomes of the proofs they verify, their results, their errors and their
+destruction to the observer that is set via their params' WithObserver
+methods. Events carry the session id, the protocol, the entity and the state,
+but never secret material, so that they can be logged safely.
+
+	logger := observe.NewLogger(slog.Default())
+	params := party2.NewParams(curve, pk, x1Enc, x2).WithObserver(logger)
+
+Several observers are combined via All.
+
+Spans are started via a SpanStarter, so that any tracing system can be used
+without this module depending on it. Both parties tag their spans with the
+shared session id, which allows to correlate the spans of a signing session
+across processes. An OpenTelemetry tracer can be adapted as follows:
+
+	type otelSpan struct{ span trace.Span }
+
+	func (s otelSpan) AddEvent(name string, attrs ...slog.Attr) {
+		s.span.AddEvent(name, trace.WithAttributes(toOtel(attrs)...))
+	}
+	func (s otelSpan) SetAttributes(attrs ...slog.Attr) { s.span.SetAttributes(toOtel(attrs)...) }
+	func (s otelSpan) RecordError(err error) {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	func (s otelSpan) End() { s.span.End() }
+
+	spans := observe.NewSpans(func(name string, attrs ...slog.Attr) observe.Span {
+		_, span := tracer.Start(ctx, name, trace.WithAttributes(toOtel(attrs)...))
+		return otelSpan{span}
+	})
+
+where toOtel converts the attributes, e.g. via attribute.String(a.Key,
+a.Value.String()).
+*/
+package observe
@@ -0,0 +1,101 @@
+package observe
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// Logger is an instance of an observer that logs the events of protocol runs
+// via log/slog. Transitions, messages, steps, operations and valid proofs are
+// logged at debug level, results at info level, invalid proofs at warn level and errors at
+// error level.
+type Logger struct {
+	logger *slog.Logger
+}
+
+// NewLogger creates a new instance of an observer that logs via the logger.
+func NewLogger(logger *slog.Logger) *Logger {
+	return &Logger{
+		logger: logger,
+	}
+}
+
+// Observe logs the event.
+func (l *Logger) Observe(event *lindell17.Event) {
+	level := slog.LevelDebug
+	msg := ""
+	switch event.Kind {
+	case lindell17.EventTransition:
+		msg = "state transition"
+	case lindell17.EventSent:
+		msg = "message sent"
+	case lindell17.EventReceived:
+		msg = "message received"
+	case lindell17.EventProof:
+		msg = "proof verified"
+		if !event.Valid {
+			level = slog.LevelWarn
+			msg = "proof invalid"
+		}
+	case lindell17.EventStep:
+		msg = "step completed"
+	case lindell17.EventOperation:
+		msg = "operation completed"
+	case lindell17.EventFallback:
+		level = slog.LevelWarn
+		msg = "operation fell back"
+	case lindell17.EventDestroyed:
+		msg = "party destroyed"
+	case lindell17.EventResult:
+		level = slog.LevelInfo
+		msg = "result emitted"
+	case lindell17.EventError:
+		level = slog.LevelError
+		msg = "protocol run failed"
+	default:
+		msg = event.Kind.String()
+	}
+
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	l.logger.LogAttrs(ctx, level, msg, Attrs(event)...)
+}
+
+// Attrs returns the attributes of the event.
+func Attrs(event *lindell17.Event) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.Uint64("run_id", event.RunId),
+		slog.String("session_id", event.SessionId),
+		slog.String("protocol", event.Protocol.String()),
+		slog.String("entity", event.Entity.String()),
+		slog.String("state", event.State.String()),
+	}
+
+	switch event.Kind {
+	case lindell17.EventSent, lindell17.EventReceived:
+		attrs = append(attrs, slog.Int("message_id", event.MessageId))
+	case lindell17.EventProof:
+		attrs = append(attrs, slog.String("proof", event.Proof), slog.Bool("valid", event.Valid))
+	case lindell17.EventStep:
+		attrs = append(attrs, slog.Duration("duration", event.Duration))
+		if event.Err != nil {
+			attrs = append(attrs, slog.String("error", event.Err.Error()))
+		}
+	case lindell17.EventOperation:
+		attrs = append(attrs, slog.String("operation", event.Operation), slog.Duration("duration", event.Duration))
+	case lindell17.EventFallback:
+		attrs = append(attrs, slog.String("operation", event.Operation))
+		if event.Err != nil {
+			attrs = append(attrs, slog.String("error", event.Err.Error()))
+		}
+	case lindell17.EventError:
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+
+	return attrs
+}
@@ -0,0 +1,203 @@
+package observe_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/observe"
+	"github.com/primefactor-io/lindell17/pkg/sign/messages"
+)
+
+var errRun = errors.New("run failed")
+
+var sid = strings.Repeat("ab", lindell17.SessionIdLength/2)
+
+// result is a minimal protocol result.
+type result struct{}
+
+func (result) From() lindell17.Entity       { return lindell17.Party2 }
+func (result) Protocol() lindell17.Protocol { return lindell17.Sign }
+func (result) SessionId() string            { return sid }
+
+// span records the calls of a span.
+type span struct {
+	mu    *sync.Mutex
+	calls *[]string
+	name  string
+}
+
+func (s *span) record(format string, args ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	*s.calls = append(*s.calls, s.name+" "+fmt.Sprintf(format, args...))
+}
+
+func (s *span) AddEvent(name string, attrs ...slog.Attr) { s.record("event %s", name) }
+func (s *span) SetAttributes(attrs ...slog.Attr)         { s.record("attrs %v", attrs) }
+func (s *span) RecordError(err error)                    { s.record("error %v", err) }
+func (s *span) End()                                     { s.record("end") }
+
+func TestObserve(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Logger", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		tracer := lindell17.NewTracer(observe.NewLogger(logger), lindell17.Sign, lindell17.Party2)
+
+		tracer.Transition(lindell17.Step1)
+		tracer.Received(&messages.Message1{Sid: sid})
+		tracer.Proof("R1 DLK proof", false)
+		tracer.Result(result{})
+		tracer.Error(nil)
+		tracer.Error(errRun)
+
+		var lines []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			entry := make(map[string]any)
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			lines = append(lines, entry)
+		}
+
+		want := []struct {
+			level string
+			msg   string
+		}{
+			{"DEBUG", "state transition"},
+			{"DEBUG", "message received"},
+			{"WARN", "proof invalid"},
+			{"INFO", "result emitted"},
+			{"ERROR", "protocol run failed"},
+		}
+		if len(lines) != len(want) {
+			t.Fatalf("want %d log entries, got %d", len(want), len(lines))
+		}
+		for i, w := range want {
+			if lines[i]["level"] != w.level || lines[i]["msg"] != w.msg {
+				t.Errorf("want %s %q, got %v %v", w.level, w.msg, lines[i]["level"], lines[i]["msg"])
+			}
+			if lines[i]["protocol"] != "sign" || lines[i]["entity"] != "party2" {
+				t.Errorf("expected protocol and entity, got %v", lines[i])
+			}
+		}
+
+		if lines[0]["session_id"] != "" || lines[1]["session_id"] != sid {
+			t.Errorf("expected session id once it's received, got %v / %v", lines[0]["session_id"], lines[1]["session_id"])
+		}
+		if lines[1]["message_id"] != float64(1) || lines[1]["state"] != "step1" {
+			t.Errorf("expected message id and state, got %v", lines[1])
+		}
+		if lines[2]["proof"] != "R1 DLK proof" || lines[2]["valid"] != false {
+			t.Errorf("expected proof outcome, got %v", lines[2])
+		}
+		if lines[4]["error"] != errRun.Error() {
+			t.Errorf("expected error, got %v", lines[4])
+		}
+	})
+
+	t.Run("Spans", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		var calls []string
+		spans := observe.NewSpans(func(name string, attrs ...slog.Attr) observe.Span {
+			s := &span{mu: &mu, calls: &calls, name: name}
+			s.record("start %v", attrs)
+			return s
+		})
+
+		p1 := lindell17.NewTracer(spans, lindell17.Sign, lindell17.Party1)
+		p2 := lindell17.NewTracer(spans, lindell17.Sign, lindell17.Party2)
+
+		// Parties time their calls, which end the spans.
+		var err error
+		call := p2.Begin()
+		p2.Transition(lindell17.Step1)
+		p2.Received(&messages.Message1{Sid: sid})
+		p2.End(call, &err)
+
+		err = errRun
+		p1.End(p1.Begin(), &err)
+
+		err = nil
+		call = p2.Begin()
+		p2.Result(result{})
+		p2.End(call, &err)
+
+		want := []string{
+			"lindell17.sign.party2 start [protocol=sign entity=party2]",
+			"lindell17.sign.party2 event transition",
+			"lindell17.sign.party2 attrs [session_id=" + sid + "]",
+			"lindell17.sign.party2 event received",
+			"lindell17.sign.party2 event step",
+			"lindell17.sign.party1 start [protocol=sign entity=party1]",
+			"lindell17.sign.party1 event error",
+			"lindell17.sign.party1 error run failed",
+			"lindell17.sign.party1 event step",
+			"lindell17.sign.party1 end",
+			"lindell17.sign.party2 event result",
+			"lindell17.sign.party2 event step",
+			"lindell17.sign.party2 end",
+		}
+		if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+			t.Errorf("want calls\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(calls, "\n"))
+		}
+	})
+
+	t.Run("Spans (destroyed)", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		var calls []string
+		spans := observe.NewSpans(func(name string, attrs ...slog.Attr) observe.Span {
+			s := &span{mu: &mu, calls: &calls, name: name}
+			s.record("start %v", attrs)
+			return s
+		})
+
+		p1 := lindell17.NewTracer(spans, lindell17.Sign, lindell17.Party1)
+		p2 := lindell17.NewTracer(spans, lindell17.Sign, lindell17.Party2)
+
+		// Party 2 is abandoned after its first step and destroyed.
+		var err error
+		call := p2.Begin()
+		p2.Transition(lindell17.Step1)
+		p2.End(call, &err)
+		p2.Destroyed()
+
+		// Party 1 is destroyed during its failed call, which ends its span
+		// first.
+		err = errRun
+		call = p1.Begin()
+		p1.Destroyed()
+		p1.End(call, &err)
+
+		want := []string{
+			"lindell17.sign.party2 start [protocol=sign entity=party2]",
+			"lindell17.sign.party2 event transition",
+			"lindell17.sign.party2 event step",
+			"lindell17.sign.party2 event destroyed",
+			"lindell17.sign.party2 end",
+			"lindell17.sign.party1 start [protocol=sign entity=party1]",
+			"lindell17.sign.party1 event error",
+			"lindell17.sign.party1 error run failed",
+			"lindell17.sign.party1 event step",
+			"lindell17.sign.party1 end",
+		}
+		if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+			t.Errorf("want calls\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(calls, "\n"))
+		}
+	})
+}
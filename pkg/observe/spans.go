@@ -0,0 +1,99 @@
+package observe
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// Span is an interface that spans of tracing systems need to implement.
+type Span interface {
+	// AddEvent adds the named event with the attributes to the span.
+	AddEvent(name string, attrs ...slog.Attr)
+	// SetAttributes sets the attributes of the span.
+	SetAttributes(attrs ...slog.Attr)
+	// RecordError records the error and marks the span as failed.
+	RecordError(err error)
+	// End ends the span.
+	End()
+}
+
+// SpanStarter is a function that starts a span with the name and the
+// attributes.
+type SpanStarter func(name string, attrs ...slog.Attr) Span
+
+// Spans is an instance of an observer that traces every protocol run of a
+// party as a span. A span is named after the protocol and the entity (e.g.
+// lindell17.sign.party2), every event is added to it and it ends with the
+// step in which the party emits its result or fails, or once the party is
+// destroyed (e.g. because its session timed out).
+type Spans struct {
+	start SpanStarter
+	mu    sync.Mutex
+	runs  map[uint64]*run
+}
+
+// run is the span of a protocol run.
+type run struct {
+	span      Span
+	sessionId string
+	done      bool
+}
+
+// NewSpans creates a new instance of an observer that starts the spans via
+// the starter.
+func NewSpans(start SpanStarter) *Spans {
+	return &Spans{
+		start: start,
+		runs:  make(map[uint64]*run),
+	}
+}
+
+// Observe adds the event to the span of its protocol run. The span is started
+// on the run's first event.
+func (s *Spans) Observe(event *lindell17.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.runs[event.RunId]
+	if !ok {
+		// The span of a run that already ended isn't started again.
+		if event.Kind == lindell17.EventDestroyed {
+			return
+		}
+
+		name := "lindell17." + event.Protocol.String() + "." + event.Entity.String()
+		r = &run{
+			span: s.start(name,
+				slog.String("protocol", event.Protocol.String()),
+				slog.String("entity", event.Entity.String()),
+			),
+		}
+		s.runs[event.RunId] = r
+	}
+
+	// The session id becomes known during the run.
+	if event.SessionId != r.sessionId {
+		r.sessionId = event.SessionId
+		r.span.SetAttributes(slog.String("session_id", event.SessionId))
+	}
+
+	r.span.AddEvent(event.Kind.String(), Attrs(event)...)
+
+	switch event.Kind {
+	case lindell17.EventResult:
+		r.done = true
+	case lindell17.EventError:
+		r.span.RecordError(event.Err)
+		r.done = true
+	case lindell17.EventStep:
+		if r.done {
+			r.span.End()
+			delete(s.runs, event.RunId)
+		}
+	case lindell17.EventDestroyed:
+		r.span.End()
+		delete(s.runs, event.RunId)
+	}
+}
@@ -0,0 +1,23 @@
+/*
+Package policy implements a rules engine for signing policies (see
+lindell17.Policy) which party 2 of the sign, adaptor and batch sign protocols
+evaluates before it contributes to a signature.
+
+An Engine allows a signing request only if all of its rules allow it. The
+rules cover allowlisted destinations, maximum amounts, per-key velocity limits
+and time windows. Custom rules can be added via RuleFunc.
+
+Destination and amount rules rely on the request's payload, which is provided
+by the requester. The PayloadHash rule checks that the hash to sign was
+computed from the payload's data. Without it (or a custom rule that decodes
+the data) a requester can pair an allowed payload with any hash. Note that the
+engine doesn't decode transactions, so the payload's destination and amount
+need to be extracted from the data by a custom rule if they can't be trusted.
+
+Velocity limits count the requests the engine allowed, even if the protocol
+run fails afterwards, and are kept in memory only. The items of a batch are
+evaluated as a whole (see Engine.EvaluateBatch): each item counts towards the
+limits of the following ones, and the items are only counted if all of them
+are allowed. Custom stateful rules implement BatchRule to do the same.
+*/
+package policy
@@ -0,0 +1,323 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// Rule is an interface that the rules of an engine need to implement.
+type Rule interface {
+	// Evaluate decides whether the request may be signed at the given time.
+	Evaluate(req *lindell17.SigningRequest, now time.Time) (*lindell17.Decision, error)
+}
+
+// Committer is an interface that stateful rules implement to record the
+// requests the engine allowed.
+type Committer interface {
+	// Commit records that the request was allowed at the given time.
+	Commit(req *lindell17.SigningRequest, now time.Time)
+}
+
+// BatchRule is an interface that stateful rules implement to evaluate a
+// request of a batch together with the batch's previous requests, which
+// aren't committed yet.
+type BatchRule interface {
+	// EvaluateInBatch decides whether the request may be signed at the given
+	// time after the previous requests of its batch.
+	EvaluateInBatch(req *lindell17.SigningRequest, previous []*lindell17.SigningRequest, now time.Time) (*lindell17.Decision, error)
+}
+
+// RuleFunc is a function that is used as a rule.
+type RuleFunc func(req *lindell17.SigningRequest, now time.Time) (*lindell17.Decision, error)
+
+// Evaluate calls the function.
+func (f RuleFunc) Evaluate(req *lindell17.SigningRequest, now time.Time) (*lindell17.Decision, error) {
+	return f(req, now)
+}
+
+// Engine is an instance of a signing policy that allows a request only if
+// all of its rules allow it. It's safe for concurrent use.
+type Engine struct {
+	mu    sync.Mutex
+	rules []Rule
+	clock func() time.Time
+}
+
+// NewEngine creates a new instance of a signing policy with the given rules.
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{
+		rules: rules,
+		clock: time.Now,
+	}
+}
+
+// WithClock sets the function the engine gets the current time from.
+// Returns the engine to allow chaining.
+func (e *Engine) WithClock(clock func() time.Time) *Engine {
+	e.clock = clock
+
+	return e
+}
+
+// Evaluate evaluates the rules in order and returns the first denial. If all
+// rules allow the request, it's committed to the stateful rules.
+// Returns an error if a rule can't be evaluated.
+func (e *Engine) Evaluate(req *lindell17.SigningRequest) (*lindell17.Decision, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.clock()
+
+	decision, err := e.evaluate(req, nil, now)
+	if err != nil || decision == nil || !decision.Allow {
+		return decision, err
+	}
+
+	e.commit(req, now)
+
+	return decision, nil
+}
+
+// EvaluateBatch evaluates the rules on the requests in order and returns the
+// first denial along with the index of the denied request. Rules that
+// implement BatchRule take the batch's previous requests into account. The
+// requests are only committed to the stateful rules if all of them are
+// allowed.
+// Returns an error if a rule can't be evaluated.
+func (e *Engine) EvaluateBatch(reqs []*lindell17.SigningRequest) (int, *lindell17.Decision, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.clock()
+
+	for i, req := range reqs {
+		decision, err := e.evaluate(req, reqs[:i], now)
+		if err != nil || decision == nil || !decision.Allow {
+			return i, decision, err
+		}
+	}
+
+	for _, req := range reqs {
+		e.commit(req, now)
+	}
+
+	return 0, lindell17.Allow(), nil
+}
+
+// evaluate evaluates the rules in order on the request that follows the
+// previous requests of its batch and returns the first denial. It needs to be
+// called with the lock held.
+// Returns an error if a rule can't be evaluated.
+func (e *Engine) evaluate(req *lindell17.SigningRequest, previous []*lindell17.SigningRequest, now time.Time) (*lindell17.Decision, error) {
+	for _, rule := range e.rules {
+		var decision *lindell17.Decision
+		var err error
+		if b, ok := rule.(BatchRule); ok {
+			decision, err = b.EvaluateInBatch(req, previous, now)
+		} else {
+			decision, err = rule.Evaluate(req, now)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if decision == nil || !decision.Allow {
+			return decision, nil
+		}
+	}
+
+	return lindell17.Allow(), nil
+}
+
+// commit commits the request to the stateful rules. It needs to be called
+// with the lock held.
+func (e *Engine) commit(req *lindell17.SigningRequest, now time.Time) {
+	for _, rule := range e.rules {
+		if c, ok := rule.(Committer); ok {
+			c.Commit(req, now)
+		}
+	}
+}
+
+// Destinations creates a rule that only allows requests whose payload sends
+// to one of the given destinations.
+func Destinations(destinations ...string) Rule {
+	return RuleFunc(func(req *lindell17.SigningRequest, _ time.Time) (*lindell17.Decision, error) {
+		if req.Payload == nil || req.Payload.Destination == "" {
+			return lindell17.Deny("missing destination"), nil
+		}
+		if !slices.Contains(destinations, req.Payload.Destination) {
+			return lindell17.Deny(fmt.Sprintf("destination %q isn't allowlisted", req.Payload.Destination)), nil
+		}
+
+		return lindell17.Allow(), nil
+	})
+}
+
+// MaxAmount creates a rule that only allows requests whose payload sends at
+// most the given amount.
+func MaxAmount(amount *big.Int) Rule {
+	return RuleFunc(func(req *lindell17.SigningRequest, _ time.Time) (*lindell17.Decision, error) {
+		if req.Payload == nil || req.Payload.Amount == nil {
+			return lindell17.Deny("missing amount"), nil
+		}
+		if req.Payload.Amount.Sign() < 0 {
+			return lindell17.Deny("negative amount"), nil
+		}
+		if req.Payload.Amount.Cmp(amount) > 0 {
+			return lindell17.Deny(fmt.Sprintf("amount %s exceeds maximum of %s", req.Payload.Amount, amount)), nil
+		}
+
+		return lindell17.Allow(), nil
+	})
+}
+
+// PayloadHash creates a rule that only allows requests whose hash is the
+// hash of the payload's data, computed via the given function.
+func PayloadHash(hash func(data []byte) []byte) Rule {
+	return RuleFunc(func(req *lindell17.SigningRequest, _ time.Time) (*lindell17.Decision, error) {
+		if req.Payload == nil || req.Payload.Data == nil {
+			return lindell17.Deny("missing payload data"), nil
+		}
+		if !bytes.Equal(hash(req.Payload.Data), req.Hash) {
+			return lindell17.Deny("hash doesn't match payload data"), nil
+		}
+
+		return lindell17.Allow(), nil
+	})
+}
+
+// TimeWindow creates a rule that only allows requests between the start and
+// the end time of day (as offsets from midnight in the given location) on the
+// given weekdays (every day if none are given). A window whose end is before
+// its start spans midnight.
+func TimeWindow(start, end time.Duration, loc *time.Location, weekdays ...time.Weekday) Rule {
+	return RuleFunc(func(_ *lindell17.SigningRequest, now time.Time) (*lindell17.Decision, error) {
+		now = now.In(loc)
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		offset := now.Sub(midnight)
+
+		var inWindow bool
+		if start <= end {
+			inWindow = offset >= start && offset < end
+		} else {
+			inWindow = offset >= start || offset < end
+		}
+		if !inWindow {
+			return lindell17.Deny(fmt.Sprintf("outside of signing hours at %s", now.Format("15:04 MST"))), nil
+		}
+
+		if len(weekdays) != 0 && !slices.Contains(weekdays, now.Weekday()) {
+			return lindell17.Deny(fmt.Sprintf("no signing on %s", now.Weekday())), nil
+		}
+
+		return lindell17.Allow(), nil
+	})
+}
+
+// usage is a request that a velocity rule recorded.
+type usage struct {
+	at     time.Time
+	amount *big.Int
+}
+
+// VelocityRule is an instance of a rule that limits the number of requests
+// and the total amount per key within a sliding time window. It's only safe
+// for concurrent use as a rule of an engine.
+type VelocityRule struct {
+	window    time.Duration
+	maxCount  int
+	maxAmount *big.Int
+	usages    map[string][]usage
+}
+
+// Velocity creates a new instance of a rule that allows at most maxCount
+// requests and a total amount of maxAmount per key within the window. A
+// maxCount of 0 and a nil maxAmount disable the respective limit.
+func Velocity(window time.Duration, maxCount int, maxAmount *big.Int) *VelocityRule {
+	return &VelocityRule{
+		window:    window,
+		maxCount:  maxCount,
+		maxAmount: maxAmount,
+		usages:    make(map[string][]usage),
+	}
+}
+
+// Evaluate checks whether the request stays within the key's limits.
+func (v *VelocityRule) Evaluate(req *lindell17.SigningRequest, now time.Time) (*lindell17.Decision, error) {
+	return v.EvaluateInBatch(req, nil, now)
+}
+
+// EvaluateInBatch checks whether the request stays within the key's limits
+// after the previous requests of its batch.
+func (v *VelocityRule) EvaluateInBatch(req *lindell17.SigningRequest, previous []*lindell17.SigningRequest, now time.Time) (*lindell17.Decision, error) {
+	usages := v.prune(req.KeyID, now)
+	for _, prev := range previous {
+		if prev.KeyID == req.KeyID {
+			usages = append(slices.Clip(usages), usageOf(prev, now))
+		}
+	}
+
+	if v.maxCount > 0 && len(usages) >= v.maxCount {
+		return lindell17.Deny(fmt.Sprintf("more than %d signatures within %s", v.maxCount, v.window)), nil
+	}
+
+	if v.maxAmount != nil {
+		if req.Payload == nil || req.Payload.Amount == nil {
+			return lindell17.Deny("missing amount"), nil
+		}
+		if req.Payload.Amount.Sign() < 0 {
+			return lindell17.Deny("negative amount"), nil
+		}
+
+		total := new(big.Int).Set(req.Payload.Amount)
+		for _, u := range usages {
+			total.Add(total, u.amount)
+		}
+		if total.Cmp(v.maxAmount) > 0 {
+			return lindell17.Deny(fmt.Sprintf("total amount exceeds %s within %s", v.maxAmount, v.window)), nil
+		}
+	}
+
+	return lindell17.Allow(), nil
+}
+
+// Commit records the request for the key.
+func (v *VelocityRule) Commit(req *lindell17.SigningRequest, now time.Time) {
+	v.usages[req.KeyID] = append(v.prune(req.KeyID, now), usageOf(req, now))
+}
+
+// usageOf creates the usage that records the request at the given time.
+func usageOf(req *lindell17.SigningRequest, now time.Time) usage {
+	amount := new(big.Int)
+	if req.Payload != nil && req.Payload.Amount != nil {
+		amount.Set(req.Payload.Amount)
+	}
+
+	return usage{at: now, amount: amount}
+}
+
+// prune removes the key's requests that left the window and returns the
+// remaining ones.
+func (v *VelocityRule) prune(keyID string, now time.Time) []usage {
+	usages := v.usages[keyID]
+
+	i := 0
+	for i < len(usages) && now.Sub(usages[i].at) >= v.window {
+		i++
+	}
+	usages = usages[i:]
+
+	if len(usages) == 0 {
+		delete(v.usages, keyID)
+		return nil
+	}
+	v.usages[keyID] = usages
+
+	return usages
+}
@@ -0,0 +1,268 @@
+package policy_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/policy"
+)
+
+var errRule = errors.New("rule failed")
+
+// clock is a settable clock.
+type clock struct {
+	now time.Time
+}
+
+// request creates a signing request for the key that sends the amount to the
+// destination.
+func request(keyID, destination string, amount int64) *lindell17.SigningRequest {
+	payload := &lindell17.Payload{Destination: destination, Amount: big.NewInt(amount)}
+	checksum := sha256.Sum256([]byte("Hello World"))
+
+	return lindell17.NewSigningRequest(lindell17.Sign, keyID, checksum[:], payload)
+}
+
+// evaluate evaluates the request and fails the test if the engine returns an
+// error.
+func evaluate(t *testing.T, engine *policy.Engine, req *lindell17.SigningRequest) *lindell17.Decision {
+	t.Helper()
+
+	decision, err := engine.Evaluate(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return decision
+}
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Destinations", func(t *testing.T) {
+		t.Parallel()
+
+		engine := policy.NewEngine(policy.Destinations("bc1qa", "bc1qb"))
+
+		if d := evaluate(t, engine, request("key", "bc1qb", 1)); !d.Allow {
+			t.Errorf("expected request to be allowed, got %q", d.Reason)
+		}
+		if d := evaluate(t, engine, request("key", "bc1qc", 1)); d.Allow || !strings.Contains(d.Reason, "bc1qc") {
+			t.Errorf("expected request to be denied, got %+v", d)
+		}
+
+		req := request("key", "", 1)
+		req.Payload = nil
+		if d := evaluate(t, engine, req); d.Allow {
+			t.Error("expected request without payload to be denied")
+		}
+	})
+
+	t.Run("MaxAmount", func(t *testing.T) {
+		t.Parallel()
+
+		engine := policy.NewEngine(policy.MaxAmount(big.NewInt(100)))
+
+		if d := evaluate(t, engine, request("key", "bc1qa", 100)); !d.Allow {
+			t.Errorf("expected request to be allowed, got %q", d.Reason)
+		}
+		if d := evaluate(t, engine, request("key", "bc1qa", 101)); d.Allow {
+			t.Error("expected request to be denied")
+		}
+		if d := evaluate(t, engine, request("key", "bc1qa", -1)); d.Allow {
+			t.Error("expected request with negative amount to be denied")
+		}
+	})
+
+	t.Run("PayloadHash", func(t *testing.T) {
+		t.Parallel()
+
+		sha := func(data []byte) []byte {
+			checksum := sha256.Sum256(data)
+			return checksum[:]
+		}
+		engine := policy.NewEngine(policy.PayloadHash(sha))
+
+		req := request("key", "bc1qa", 1)
+		req.Payload.Data = []byte("Hello World")
+		if d := evaluate(t, engine, req); !d.Allow {
+			t.Errorf("expected request to be allowed, got %q", d.Reason)
+		}
+
+		req.Payload.Data = []byte("Hello Mallory")
+		if d := evaluate(t, engine, req); d.Allow {
+			t.Error("expected request with mismatching data to be denied")
+		}
+	})
+
+	t.Run("TimeWindow", func(t *testing.T) {
+		t.Parallel()
+
+		c := &clock{}
+		office := policy.TimeWindow(9*time.Hour, 17*time.Hour, time.UTC, time.Monday, time.Friday)
+		night := policy.TimeWindow(22*time.Hour, 6*time.Hour, time.UTC)
+
+		cases := []struct {
+			name   string
+			rule   policy.Rule
+			now    time.Time
+			allow  bool
+			reason string
+		}{
+			{"Office hours (Monday 09:00)", office, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), true, ""},
+			{"Office hours (Friday 16:59)", office, time.Date(2024, 1, 5, 16, 59, 0, 0, time.UTC), true, ""},
+			{"Office hours (Monday 17:00)", office, time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC), false, "signing hours"},
+			{"Office hours (Tuesday 10:00)", office, time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), false, "Tuesday"},
+			{"Night (23:00)", night, time.Date(2024, 1, 2, 23, 0, 0, 0, time.UTC), true, ""},
+			{"Night (05:59)", night, time.Date(2024, 1, 2, 5, 59, 0, 0, time.UTC), true, ""},
+			{"Night (12:00)", night, time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC), false, "signing hours"},
+		}
+
+		for _, tc := range cases {
+			c.now = tc.now
+			engine := policy.NewEngine(tc.rule).WithClock(func() time.Time { return c.now })
+
+			d := evaluate(t, engine, request("key", "bc1qa", 1))
+			if d.Allow != tc.allow || !strings.Contains(d.Reason, tc.reason) {
+				t.Errorf("%s: want allow %v (%q), got %+v", tc.name, tc.allow, tc.reason, d)
+			}
+		}
+	})
+
+	t.Run("Velocity", func(t *testing.T) {
+		t.Parallel()
+
+		c := &clock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		engine := policy.NewEngine(policy.Velocity(time.Hour, 2, big.NewInt(100))).
+			WithClock(func() time.Time { return c.now })
+
+		if d := evaluate(t, engine, request("key1", "bc1qa", 60)); !d.Allow {
+			t.Errorf("expected request to be allowed, got %q", d.Reason)
+		}
+
+		// The total amount would exceed the limit.
+		if d := evaluate(t, engine, request("key1", "bc1qa", 41)); d.Allow {
+			t.Error("expected request exceeding the amount limit to be denied")
+		}
+
+		// Denied requests don't count.
+		if d := evaluate(t, engine, request("key1", "bc1qa", 40)); !d.Allow {
+			t.Errorf("expected request to be allowed, got %q", d.Reason)
+		}
+
+		// The count limit is reached.
+		if d := evaluate(t, engine, request("key1", "bc1qa", 0)); d.Allow {
+			t.Error("expected request exceeding the count limit to be denied")
+		}
+
+		// Limits are per key.
+		if d := evaluate(t, engine, request("key2", "bc1qa", 100)); !d.Allow {
+			t.Errorf("expected request for another key to be allowed, got %q", d.Reason)
+		}
+
+		// The window slides.
+		c.now = c.now.Add(time.Hour)
+		if d := evaluate(t, engine, request("key1", "bc1qa", 100)); !d.Allow {
+			t.Errorf("expected request after the window to be allowed, got %q", d.Reason)
+		}
+	})
+
+	t.Run("Engine (first denial wins)", func(t *testing.T) {
+		t.Parallel()
+
+		velocity := policy.Velocity(time.Hour, 1, nil)
+		engine := policy.NewEngine(velocity, policy.Destinations("bc1qa"))
+
+		// The denial by a later rule isn't committed to the velocity rule.
+		if d := evaluate(t, engine, request("key", "bc1qb", 1)); d.Allow || !strings.Contains(d.Reason, "allowlisted") {
+			t.Errorf("expected request to be denied, got %+v", d)
+		}
+		if d := evaluate(t, engine, request("key", "bc1qa", 1)); !d.Allow {
+			t.Errorf("expected request to be allowed, got %q", d.Reason)
+		}
+		if d := evaluate(t, engine, request("key", "bc1qa", 1)); d.Allow || !strings.Contains(d.Reason, "signatures") {
+			t.Errorf("expected request to be denied, got %+v", d)
+		}
+	})
+
+	t.Run("Engine (batch)", func(t *testing.T) {
+		t.Parallel()
+
+		engine := policy.NewEngine(policy.Velocity(time.Hour, 3, big.NewInt(100)))
+
+		// The batch's previous requests count towards the limits.
+		batch := []*lindell17.SigningRequest{
+			request("key", "bc1qa", 40),
+			request("key", "bc1qa", 40),
+			request("key", "bc1qa", 40),
+		}
+		i, d, err := engine.EvaluateBatch(batch)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if d.Allow || i != 2 {
+			t.Errorf("expected request 2 to be denied, got request %d (%+v)", i, d)
+		}
+
+		// Requests of a denied batch aren't committed.
+		i, d, err = engine.EvaluateBatch(batch[:2])
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !d.Allow {
+			t.Errorf("expected batch to be allowed, got request %d denied (%q)", i, d.Reason)
+		}
+
+		// Requests of an allowed batch are.
+		if d := evaluate(t, engine, request("key", "bc1qa", 30)); d.Allow {
+			t.Error("expected request exceeding the amount limit to be denied")
+		}
+		if d := evaluate(t, engine, request("key", "bc1qa", 20)); !d.Allow {
+			t.Errorf("expected request to be allowed, got %q", d.Reason)
+		}
+	})
+
+	t.Run("EnforceBatch - Invalid (denied)", func(t *testing.T) {
+		t.Parallel()
+
+		engine := policy.NewEngine(policy.Destinations("bc1qa"))
+		reqs := []*lindell17.SigningRequest{request("key", "bc1qa", 1), request("key", "bc1qb", 1)}
+
+		err := lindell17.EnforceBatch(engine, reqs)
+
+		var itemErr *lindell17.ItemError
+		if !errors.Is(err, lindell17.ErrPolicyDenied) || !errors.As(err, &itemErr) || itemErr.Index != 1 {
+			t.Errorf("want error %v for item 1, got %v", lindell17.ErrPolicyDenied, err)
+		}
+	})
+
+	t.Run("Engine - Invalid (rule error)", func(t *testing.T) {
+		t.Parallel()
+
+		failing := policy.RuleFunc(func(*lindell17.SigningRequest, time.Time) (*lindell17.Decision, error) {
+			return nil, errRule
+		})
+		engine := policy.NewEngine(failing)
+
+		err := lindell17.Enforce(engine, request("key", "bc1qa", 1))
+		if !errors.Is(err, lindell17.ErrEvaluatePolicy) || !errors.Is(err, errRule) {
+			t.Errorf("want error %v, got %v", lindell17.ErrEvaluatePolicy, err)
+		}
+	})
+
+	t.Run("Enforce - Invalid (denied)", func(t *testing.T) {
+		t.Parallel()
+
+		engine := policy.NewEngine(policy.Destinations("bc1qa"))
+
+		err := lindell17.Enforce(engine, request("key", "bc1qb", 1))
+		if !errors.Is(err, lindell17.ErrPolicyDenied) || !strings.Contains(err.Error(), "bc1qb") {
+			t.Errorf("want error %v, got %v", lindell17.ErrPolicyDenied, err)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package profile
+
+import "testing"
+
+// Benchmark benchmarks the step. Only the step itself is timed, the party is
+// prepared via Prepare before every iteration with the timer stopped.
+func (p *Profile) Benchmark(b *testing.B, step *Step) {
+	b.Helper()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		run, err := p.Prepare(step)
+		if err != nil {
+			b.Fatalf("expected no error, got %v", err)
+		}
+		b.StartTimer()
+
+		if err := run(); err != nil {
+			b.Fatalf("expected no error, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Package profile implements per-step latency profiling of the protocols.
+
+Run runs a protocol honestly and measures the duration of every Start and
+Process call of every party. Each call is a Step of the protocol run. Keygen,
+Sign, Adaptor and DLEncProof return the parties of the respective protocol for
+the given parameters and key material.
+
+The parties draw their randomness from recording tapes (see random.Tape), so
+that Prepare can reproduce a party's state right before any of its steps: it
+creates a fresh party, replays the recorded random inputs and feeds it the
+messages it received before the step. The step then runs with fresh
+randomness. This allows benchmarking a single step without timing the steps
+that lead up to it, which is what Benchmark does. The protocol packages'
+benchmarks use it for several Paillier key sizes, e.g.
+
+	go test ./pkg/sign -run '^$' -bench 'Sign/2048/'
+
+The profile command (cmd/profile) prints a table with the latency of every
+step which can be used to size hardware and to spot performance regressions.
+
+Fuzz uses the same replay to fuzz the Process method of one of the parties in
+every state it passes through during an honest run. The protocol packages'
+fuzz tests use it for every recipient, e.g.
+
+	go test ./pkg/sign -run '^$' -fuzz 'FuzzProcessParty2$'
+
+Note that Prepare re-runs the party's previous steps, so preparing a late step
+of a protocol (e.g. of key generation) takes a while even though the recorded
+Paillier key is replayed instead of being generated again.
+*/
+package profile
@@ -0,0 +1,20 @@
+package profile
+
+import "fmt"
+
+var (
+	// ErrRunProtocol is returned if a party fails during a protocol run.
+	ErrRunProtocol = fmt.Errorf("unable to run protocol")
+	// ErrStalled is returned if the protocol run can't make progress.
+	ErrStalled = fmt.Errorf("protocol run stalled")
+	// ErrUnknownParty is returned if a message is addressed to an unknown party.
+	ErrUnknownParty = fmt.Errorf("unknown party")
+	// ErrUnknownStep is returned if a step isn't part of the profile.
+	ErrUnknownStep = fmt.Errorf("unknown step")
+	// ErrPrepareStep is returned if a party's state before a step can't be
+	// reproduced.
+	ErrPrepareStep = fmt.Errorf("unable to prepare step")
+	// ErrMissingKeyMaterial is returned if a profile lacks the key material of
+	// a key generation run.
+	ErrMissingKeyMaterial = fmt.Errorf("missing key material")
+)
@@ -0,0 +1,70 @@
+package profile
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+)
+
+// Fuzz fuzzes the Process method of the recipient's party. The parties are
+// run honestly once and the corpus is seeded with the messages the recipient
+// processed. An input consists of the index of one of the recipient's states
+// (before each message it processed and after the run) and an encoded
+// message. The recipient is brought into the state by replaying its random
+// inputs and messages and then processes the decoded message. Errors are
+// expected, panics aren't.
+func Fuzz(f *testing.F, recipient lindell17.Entity, parties ...Party) {
+	f.Helper()
+
+	p, err := Run(parties...)
+	if err != nil {
+		f.Fatalf("expected no error, got %v", err)
+	}
+
+	// states are the indexes of the steps before which the recipient is
+	// fuzzed, the last one is the end of the run.
+	var states []int
+	for i, step := range p.Steps {
+		if step.Entity == recipient && step.Message != nil {
+			states = append(states, i)
+		}
+	}
+	if len(states) == 0 {
+		f.Fatalf("want %s to process messages, got none", entityName(recipient))
+	}
+
+	for i, index := range states {
+		data, err := codec.MarshalMessage(p.Steps[index].Message)
+		if err != nil {
+			f.Fatalf("expected no error, got %v", err)
+		}
+		f.Add(uint8(i), data)
+
+		// Deliver the last message again once the run ended.
+		if i == len(states)-1 {
+			f.Add(uint8(i+1), data)
+		}
+	}
+	states = append(states, len(p.Steps))
+
+	f.Fuzz(func(t *testing.T, state uint8, data []byte) {
+		msg, err := codec.UnmarshalMessage(data)
+		if err != nil {
+			return
+		}
+
+		// The recipient replays all of its random inputs, so that it doesn't
+		// need to generate expensive ones (e.g. Paillier keys) again if it
+		// processes the message of the honest run.
+		tape := random.NewReplayTape(p.draws[recipient]).WithFallback(rand.Reader)
+		run, err := p.replay(recipient, tape, states[int(state)%len(states)])
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		_ = run(&Step{Entity: recipient, Message: msg})
+	})
+}
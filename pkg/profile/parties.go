@@ -0,0 +1,108 @@
+package profile
+
+import (
+	"io"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	aParty1 "github.com/primefactor-io/lindell17/pkg/adaptor/party1"
+	aParty2 "github.com/primefactor-io/lindell17/pkg/adaptor/party2"
+	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/prover"
+	"github.com/primefactor-io/lindell17/pkg/dlenc_proof/verifier"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	sParty1 "github.com/primefactor-io/lindell17/pkg/sign/party1"
+	sParty2 "github.com/primefactor-io/lindell17/pkg/sign/party2"
+)
+
+// Keygen returns the parties of a key generation run in which party 1
+// generates a Paillier key with the given number of bits.
+func Keygen(curve weierstrass.Curve, rangeProofBits, nthRootProofBits, paillierBits int) []Party {
+	return []Party{
+		{
+			Entity: lindell17.Party1,
+			New: func(rand io.Reader, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+				params := kParty1.NewParams(curve, rangeProofBits, nthRootProofBits, paillierBits).WithRandomness(rand)
+				return kParty1.NewParty1(params, outCh, resCh)
+			},
+		},
+		{
+			Entity: lindell17.Party2,
+			New: func(rand io.Reader, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+				params := kParty2.NewParams(curve, rangeProofBits, nthRootProofBits).WithRandomness(rand)
+				return kParty2.NewParty2(params, outCh, resCh)
+			},
+		},
+	}
+}
+
+// Sign returns the parties of a signing run for the given key material and
+// hash.
+func Sign(curve weierstrass.Curve, km1 *kParty1.KeyMaterial, km2 *kParty2.KeyMaterial, hash []byte) []Party {
+	return []Party{
+		{
+			Entity: lindell17.Party1,
+			New: func(rand io.Reader, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+				params := sParty1.NewParams(curve, km1.Sk, km1.Q).WithRandomness(rand)
+				return sParty1.NewParty1(params, hash, outCh, resCh)
+			},
+		},
+		{
+			Entity: lindell17.Party2,
+			New: func(rand io.Reader, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+				params := sParty2.NewParams(curve, km2.Pk, km2.X1Enc, km2.X2).WithRandomness(rand)
+				return sParty2.NewParty2(params, hash, outCh, resCh)
+			},
+		},
+	}
+}
+
+// Adaptor returns the parties of an adaptor signing run for the given key
+// material, hash and statement.
+func Adaptor(curve weierstrass.Curve, km1 *kParty1.KeyMaterial, km2 *kParty2.KeyMaterial, hash []byte, stmt *adaptor.Statement, pStmt *proofs.DLKProof) []Party {
+	return []Party{
+		{
+			Entity: lindell17.Party1,
+			New: func(rand io.Reader, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+				params := aParty1.NewParams(curve, km1.Sk, km1.Q).WithRandomness(rand)
+				return aParty1.NewParty1(params, hash, stmt, pStmt, outCh, resCh)
+			},
+		},
+		{
+			Entity: lindell17.Party2,
+			New: func(rand io.Reader, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+				params := aParty2.NewParams(curve, km2.Pk, km2.Q, km2.X1Enc, km2.X2).WithRandomness(rand)
+				return aParty2.NewParty2(params, hash, stmt, pStmt, outCh, resCh)
+			},
+		},
+	}
+}
+
+// DLEncProof returns the parties of a DLEnc proof run in which party 1 proves
+// that the ciphertext of the key material encrypts its private key share.
+// Returns an error if party 1's public key share can't be computed.
+func DLEncProof(curve weierstrass.Curve, km1 *kParty1.KeyMaterial, km2 *kParty2.KeyMaterial) ([]Party, error) {
+	q1, err := curve.ScalarMultiply(km1.X1, curve.G()) // x1 * G
+	if err != nil {
+		return nil, err
+	}
+
+	return []Party{
+		{
+			Entity: lindell17.Prover,
+			New: func(rand io.Reader, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+				params := prover.NewParams(curve, km1.Sk, km1.X1).WithRandomness(rand)
+				return prover.NewProver(params, outCh, resCh)
+			},
+		},
+		{
+			Entity: lindell17.Verifier,
+			New: func(rand io.Reader, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+				params := verifier.NewParams(curve, q1, km2.Pk, km2.X1Enc).WithRandomness(rand)
+				return verifier.NewVerifier(params, outCh, resCh)
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,255 @@
+package profile
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+)
+
+// channelSize is the capacity of the channels parties send via. A single call
+// sends at most one message and one result.
+const channelSize = 4
+
+// Factory creates a fresh party that draws its randomness from the given
+// reader and sends its messages and result via the given channels.
+type Factory func(rand io.Reader, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant
+
+// Party is a party of a protocol run.
+type Party struct {
+	// Entity is the party's entity.
+	Entity lindell17.Entity
+	// New creates a fresh instance of the party.
+	New Factory
+}
+
+// Step is a single Start or Process call of a party.
+type Step struct {
+	// Entity is the party that ran the step.
+	Entity lindell17.Entity
+	// Message is the message the party processed (nil for Start).
+	Message lindell17.Message
+	// Duration is the time the call took.
+	Duration time.Duration
+	// draws is the number of random inputs the party drew before the step.
+	draws int
+}
+
+// Name returns the step's name which consists of the party's name and the
+// call, e.g. "Party1/Start" or "Party2/Message3".
+func (s *Step) Name() string {
+	call := "Start"
+	if s.Message != nil {
+		call = fmt.Sprintf("Message%d", s.Message.MessageId())
+	}
+
+	return entityName(s.Entity) + "/" + call
+}
+
+// Profile is an instance of a profiled protocol run.
+type Profile struct {
+	// Steps are the steps of the protocol run in the order in which they ran.
+	Steps []*Step
+	// Results are the results the parties produced.
+	Results []lindell17.Result
+	parties map[lindell17.Entity]Factory
+	draws   map[lindell17.Entity][]random.Draw
+}
+
+// Run runs the protocol with the given parties and measures the duration of
+// every step. Parties are started in the given order and messages are
+// delivered in the order in which they were sent.
+// Returns an error if a party fails or the protocol run stalls.
+func Run(parties ...Party) (*Profile, error) {
+	p := &Profile{
+		parties: make(map[lindell17.Entity]Factory),
+		draws:   make(map[lindell17.Entity][]random.Draw),
+	}
+
+	outCh := make(chan lindell17.Message, channelSize)
+	resCh := make(chan lindell17.Result, channelSize)
+
+	tapes := make(map[lindell17.Entity]*random.Tape)
+	instances := make(map[lindell17.Entity]lindell17.Participant)
+	for _, party := range parties {
+		tape := random.NewRecordingTape(rand.Reader)
+
+		p.parties[party.Entity] = party.New
+		tapes[party.Entity] = tape
+		instances[party.Entity] = party.New(tape, outCh, resCh)
+	}
+
+	var queue []lindell17.Message
+
+	// run runs the step and collects the messages and results it produced.
+	run := func(step *Step) error {
+		step.draws = len(tapes[step.Entity].Draws())
+
+		start := time.Now()
+		err := call(instances[step.Entity], step)
+		step.Duration = time.Since(start)
+
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrRunProtocol, err)
+		}
+		p.Steps = append(p.Steps, step)
+
+		for {
+			select {
+			case msg := <-outCh:
+				queue = append(queue, msg)
+			case res := <-resCh:
+				p.Results = append(p.Results, res)
+			default:
+				return nil
+			}
+		}
+	}
+
+	for _, party := range parties {
+		if err := run(&Step{Entity: party.Entity}); err != nil {
+			return nil, err
+		}
+	}
+
+	for len(p.Results) < len(parties) {
+		if len(queue) == 0 {
+			return nil, ErrStalled
+		}
+
+		var msg lindell17.Message
+		msg, queue = queue[0], queue[1:]
+
+		if _, ok := instances[msg.To()]; !ok {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownParty, msg.To())
+		}
+
+		if err := run(&Step{Entity: msg.To(), Message: msg}); err != nil {
+			return nil, err
+		}
+	}
+
+	for entity, tape := range tapes {
+		p.draws[entity] = tape.Draws()
+	}
+
+	return p, nil
+}
+
+// Prepare creates a fresh instance of the step's party and brings it into the
+// state it was in right before the step by replaying its random inputs and
+// the messages it processed.
+// Returns a function that runs the step (once) or an error if the step isn't
+// part of the profile or the party's state can't be reproduced.
+func (p *Profile) Prepare(step *Step) (func() error, error) {
+	index := slices.Index(p.Steps, step)
+	if index < 0 {
+		return nil, ErrUnknownStep
+	}
+
+	tape := random.NewReplayTape(p.draws[step.Entity][:step.draws]).WithFallback(rand.Reader)
+	run, err := p.replay(step.Entity, tape, index)
+	if err != nil {
+		return nil, err
+	}
+
+	if tape.Remaining() != 0 {
+		return nil, fmt.Errorf("%w: %d unused random inputs", ErrPrepareStep, tape.Remaining())
+	}
+
+	return func() error {
+		return run(step)
+	}, nil
+}
+
+// replay creates a fresh instance of the entity's party that draws its
+// randomness from the tape and feeds it the messages it processed in the
+// profile's first n steps.
+// Returns a function that runs a step on the party and discards the messages
+// and results it produced, or an error if a step fails.
+func (p *Profile) replay(entity lindell17.Entity, tape *random.Tape, n int) (func(*Step) error, error) {
+	outCh := make(chan lindell17.Message, channelSize)
+	resCh := make(chan lindell17.Result, channelSize)
+
+	party := p.parties[entity](tape, outCh, resCh)
+
+	// run runs the step and discards the messages and results it produced.
+	run := func(step *Step) error {
+		err := call(party, step)
+
+		for {
+			select {
+			case <-outCh:
+			case <-resCh:
+			default:
+				return err
+			}
+		}
+	}
+
+	for _, s := range p.Steps[:n] {
+		if s.Entity != entity {
+			continue
+		}
+		if err := run(s); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrPrepareStep, err)
+		}
+	}
+
+	return run, nil
+}
+
+// KeyMaterial returns the key material of a key generation run.
+// Returns an error if the profile lacks the key material of either party.
+func (p *Profile) KeyMaterial() (*kParty1.KeyMaterial, *kParty2.KeyMaterial, error) {
+	var km1 *kParty1.KeyMaterial
+	var km2 *kParty2.KeyMaterial
+
+	for _, res := range p.Results {
+		switch res := res.(type) {
+		case *kParty1.Result:
+			km1 = res.KeyMaterial
+		case *kParty2.Result:
+			km2 = res.KeyMaterial
+		}
+	}
+
+	if km1 == nil || km2 == nil {
+		return nil, nil, ErrMissingKeyMaterial
+	}
+
+	return km1, km2, nil
+}
+
+// call runs the step's Start or Process call on the party.
+func call(party lindell17.Participant, step *Step) error {
+	if step.Message == nil {
+		_, err := party.Start()
+		return err
+	}
+
+	_, err := party.Process(step.Message)
+	return err
+}
+
+// entityName returns the entity's name.
+func entityName(entity lindell17.Entity) string {
+	switch entity {
+	case lindell17.Party1:
+		return "Party1"
+	case lindell17.Party2:
+		return "Party2"
+	case lindell17.Prover:
+		return "Prover"
+	case lindell17.Verifier:
+		return "Verifier"
+	default:
+		return fmt.Sprintf("Entity%d", entity)
+	}
+}
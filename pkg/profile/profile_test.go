@@ -0,0 +1,129 @@
+package profile_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/profile"
+)
+
+const paillierBits = 1024
+const rangeProofBits = 40
+const nthRootProofBits = 128
+
+var secp256k1 = curves.Secp256k1
+
+func TestProfile(t *testing.T) {
+	t.Parallel()
+
+	keygen, err := profile.Run(profile.Keygen(secp256k1, rangeProofBits, nthRootProofBits, paillierBits)...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	km1, km2, err := keygen.KeyMaterial()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	t.Run("Run", func(t *testing.T) {
+		t.Parallel()
+
+		// Key generation consists of 2 Start calls and 7 messages.
+		if len(keygen.Steps) != 9 {
+			t.Fatalf("want 9 steps, got %d", len(keygen.Steps))
+		}
+		if len(keygen.Results) != 2 {
+			t.Fatalf("want 2 results, got %d", len(keygen.Results))
+		}
+
+		names := make(map[string]bool)
+		for _, step := range keygen.Steps {
+			if step.Duration <= 0 {
+				t.Errorf("step %s: duration not measured", step.Name())
+			}
+			if names[step.Name()] {
+				t.Errorf("step %s: name isn't unique", step.Name())
+			}
+			names[step.Name()] = true
+		}
+
+		if !names["Party1/Start"] || !names["Party2/Message7"] {
+			t.Fatal("Steps lack the first or last call")
+		}
+	})
+
+	t.Run("Prepare", func(t *testing.T) {
+		t.Parallel()
+
+		checksum := sha256.Sum256([]byte("Hello World"))
+
+		sign, err := profile.Run(profile.Sign(secp256k1, km1, km2, checksum[:])...)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		for _, step := range sign.Steps {
+			run, err := sign.Prepare(step)
+			if err != nil {
+				t.Fatalf("step %s: expected no error, got %v", step.Name(), err)
+			}
+			if err := run(); err != nil {
+				t.Fatalf("step %s: expected no error, got %v", step.Name(), err)
+			}
+		}
+	})
+
+	t.Run("Prepare (Key Generation)", func(t *testing.T) {
+		t.Parallel()
+
+		// Replaying the recorded prime numbers reproduces party 1's Paillier key.
+		step := keygen.Steps[len(keygen.Steps)-1]
+		run, err := keygen.Prepare(step)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := run(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Prepare - Invalid (Unknown step)", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := keygen.Prepare(&profile.Step{Entity: lindell17.Party1})
+
+		if !errors.Is(err, profile.ErrUnknownStep) {
+			t.Errorf("want error %v, got %v", profile.ErrUnknownStep, err)
+		}
+	})
+
+	t.Run("Adaptor / DLEnc Proof", func(t *testing.T) {
+		t.Parallel()
+
+		checksum := sha256.Sum256([]byte("Hello World"))
+
+		wit, stmt, _ := adaptor.GenerateHardRelation(secp256k1)
+		pStmt, _ := proofs.GenerateDLKProof(secp256k1, (*elliptic.Point)(stmt), (*big.Int)(wit))
+
+		if _, err := profile.Run(profile.Adaptor(secp256k1, km1, km2, checksum[:], stmt, pStmt)...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		parties, err := profile.DLEncProof(secp256k1, km1, km2)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if _, err := profile.Run(parties...); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,27 @@
+/*
+Package random implements the randomized building blocks the protocols use
+(scalar sampling, commitments, proofs, Paillier key generation and encryption)
+on top of a caller-provided source of randomness.
+
+The ecc, commitment and paillier libraries always draw their randomness from
+crypto/rand, so this package mirrors their implementations. The resulting
+commitments, proofs, keys and ciphertexts are interchangeable with the ones the
+libraries produce and are verified by the libraries' verification functions.
+Since every random input comes from the source, a deterministic source (see
+NewSeededReader) makes a protocol run deterministic. Concurrent tasks draw
+from forks of the source (see Fork), so their order doesn't matter.
+
+Note that some of the constructions (e.g. rejection sampling and prime
+generation) consume a variable amount of randomness, so a deterministic reader
+needs to be able to provide an unbounded stream of bytes.
+
+A Tape records the random inputs (integers, Paillier keys and raw bytes) drawn
+from it and can replay them in order, which fixes a party's random inputs
+independently of how they're sampled (e.g. for known-answer test vectors).
+Paillier keys are recorded as values rather than the prime numbers' random
+bytes, since their sampling consumes a lot of randomness.
+A replay tape with a fallback continues with fresh random inputs once the
+recorded ones are used up, which reproduces a party's state up to a given
+point of a protocol run.
+*/
+package random
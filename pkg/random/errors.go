@@ -0,0 +1,27 @@
+package random
+
+import "fmt"
+
+var (
+	// ErrSampleInt is returned if a random integer can't be sampled.
+	ErrSampleInt = fmt.Errorf("unable to sample random integer")
+	// ErrSampleBytes is returned if random bytes can't be sampled.
+	ErrSampleBytes = fmt.Errorf("unable to sample random bytes")
+	// ErrInvalidBits is returned if the requested number of bits is invalid.
+	ErrInvalidBits = fmt.Errorf("invalid number of bits")
+	// ErrEqualPrimeNumbers is returned if the sampled prime numbers are equal.
+	ErrEqualPrimeNumbers = fmt.Errorf("prime numbers p and q are equal")
+	// ErrInvalidPoint is returned if the point doesn't match the scalar.
+	ErrInvalidPoint = fmt.Errorf("point doesn't match scalar times generator")
+	// ErrComputePoint is returned if a point can't be computed.
+	ErrComputePoint = fmt.Errorf("unable to compute point")
+	// ErrEncrypt is returned if a plaintext can't be encrypted.
+	ErrEncrypt = fmt.Errorf("unable to encrypt plaintext")
+	// ErrInvalidWitness is returned if the range proof witness isn't in range.
+	ErrInvalidWitness = fmt.Errorf("witness not in range")
+	// ErrTapeExhausted is returned if a replay tape has no random inputs left.
+	ErrTapeExhausted = fmt.Errorf("tape exhausted")
+	// ErrTapeMismatch is returned if the next random input on a replay tape
+	// doesn't match the requested one.
+	ErrTapeMismatch = fmt.Errorf("tape mismatch")
+)
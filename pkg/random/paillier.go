@@ -0,0 +1,75 @@
+package random
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// PaillierKeys generates a Paillier key pair whose modulus has the given number
+// of bits. It's the equivalent of keys.GenerateKeys.
+// If the reader is a Tape, the tape records the key pair rather than the prime
+// numbers' random bytes, so that replays don't depend on how they're sampled.
+// Returns an error if the key generation fails.
+func PaillierKeys(reader io.Reader, bits int) (*keys.PrivateKey, *keys.PublicKey, error) {
+	if tape, ok := reader.(*Tape); ok {
+		return tape.paillierKeys(bits)
+	}
+
+	return generatePaillierKeys(reader, bits)
+}
+
+// generatePaillierKeys generates a Paillier key pair from two random prime
+// numbers of half the modulus' number of bits.
+// Returns an error if the prime numbers can't be sampled or are equal.
+func generatePaillierKeys(reader io.Reader, bits int) (*keys.PrivateKey, *keys.PublicKey, error) {
+	// Prime numbers p and q should have roughly the same size.
+	primeBits := bits / 2
+
+	p, err := Prime(reader, primeBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, err := Prime(reader, primeBits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.Cmp(q) == 0 {
+		return nil, nil, ErrEqualPrimeNumbers
+	}
+
+	n := new(big.Int).Mul(p, q) // p * q
+
+	pMinusOne := new(big.Int).Sub(p, big.NewInt(1)) // p - 1
+	qMinusOne := new(big.Int).Sub(q, big.NewInt(1)) // q - 1
+
+	phiN := new(big.Int).Mul(pMinusOne, qMinusOne) // (p - 1) * (q - 1)
+	mu := new(big.Int).ModInverse(phiN, n)         // phiN^-1 mod n
+
+	nn := new(big.Int).Mul(n, n) // n^2
+
+	sk := keys.NewPrivateKey(n, phiN, mu, nn)
+	pk := keys.DerivePublicKey(sk)
+
+	return sk, pk, nil
+}
+
+// Encrypt encrypts the plaintext and returns the ciphertext as well as the
+// nonce that was used. It's the equivalent of cipher.EncryptAndReturnNonce.
+// Returns an error if the encryption fails.
+func Encrypt(reader io.Reader, pk *keys.PublicKey, plaintext cipher.Plaintext) (cipher.Ciphertext, *big.Int, error) {
+	nonce, err := Int(reader, pk.N)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ciphertext, err := cipher.EncryptWithCustomNonce(pk, nonce, plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ciphertext, nonce, nil
+}
@@ -0,0 +1,241 @@
+package random
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/schnorr"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/codec/opaque"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+	pProofs "github.com/primefactor-io/paillier/pkg/proofs"
+	pUtils "github.com/primefactor-io/paillier/pkg/utils"
+)
+
+// DLKProof generates a discrete logarithm knowledge proof which proves that one
+// knows the scalar for which point = scalar * G. It's the equivalent of
+// proofs.GenerateDLKProof.
+// Returns an error if the point doesn't match the scalar or the proof
+// generation fails.
+func DLKProof(reader io.Reader, curve weierstrass.Curve, point *elliptic.Point, scalar *big.Int) (*proofs.DLKProof, error) {
+	result, err := curve.ScalarMultiply(scalar, curve.G()) // scalar * G
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrComputePoint, err)
+	}
+	if !result.Equal(point) {
+		return nil, ErrInvalidPoint
+	}
+
+	// Sample nonce k.
+	k, err := Scalar(reader, curve)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute R.
+	r, err := curve.ScalarMultiply(k, curve.G()) // k * G
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrComputePoint, err)
+	}
+
+	// The proof is a Schnorr signature over the hash of the point.
+	var pointBytes []byte
+	pointBytes = append(pointBytes, point.X.Bytes()...)
+	pointBytes = append(pointBytes, point.Y.Bytes()...)
+	pointHash := sha256.Sum256(pointBytes)
+
+	// Compute e.
+	var bz []byte
+	bz = append(bz, point.X.Bytes()...)
+	bz = append(bz, point.Y.Bytes()...)
+	bz = append(bz, r.X.Bytes()...)
+	bz = append(bz, r.Y.Bytes()...)
+	bz = append(bz, pointHash[:]...)
+	checksum := sha256.Sum256(bz)
+	e := new(big.Int).SetBytes(checksum[:])
+
+	// Compute s.
+	in1 := new(big.Int).Mul(e, scalar)    // e * x
+	in2 := new(big.Int).Add(k, in1)       // k + (e * x)
+	s := new(big.Int).Mod(in2, curve.N()) // k + (e * x) mod n
+
+	return proofs.NewDLKProof(schnorr.NewSignature(e, s)), nil
+}
+
+// DLEqProof generates a discrete logarithm equality proof which proves that
+// X = x * G and Z = x * Y. It's the equivalent of proofs.GenerateDLEqProof.
+// Returns an error if the proof generation fails.
+func DLEqProof(reader io.Reader, curve weierstrass.Curve, G, X, Y, Z *elliptic.Point, x *big.Int) (*proofs.DLEqProof, error) {
+	// Sample nonce a.
+	a, err := Scalar(reader, curve)
+	if err != nil {
+		return nil, err
+	}
+
+	aG, err := curve.ScalarMultiply(a, G) // a * G
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrComputePoint, err)
+	}
+	aY, err := curve.ScalarMultiply(a, Y) // a * Y
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrComputePoint, err)
+	}
+
+	// Compute b.
+	magic := sha256.Sum256([]byte("DLEQ"))
+	bz := append(magic[:], magic[:]...)
+	for _, point := range []*elliptic.Point{G, X, Y, Z, aG, aY} {
+		bz = append(bz, point.X.Bytes()...)
+		bz = append(bz, point.Y.Bytes()...)
+	}
+	checksum := sha256.Sum256(bz)
+	b := new(big.Int).SetBytes(checksum[:])
+
+	// Compute c.
+	in1 := new(big.Int).Mul(b, x)         // b * x
+	in2 := new(big.Int).Add(a, in1)       // a + (b * x)
+	c := new(big.Int).Mod(in2, curve.N()) // a + (b * x) mod n
+
+	return proofs.NewDLEqProof(b, c), nil
+}
+
+// NthRootProof generates an Nth Root knowledge proof which proves that given
+// x = y^N mod N^2 one knows an Nth root of x. It's the equivalent of
+// proofs.GenerateNthRootProof.
+// Returns an error if the proof generation fails.
+func NthRootProof(reader io.Reader, bits int, n *big.Int) (*pProofs.NthRootProof, error) {
+	nn := new(big.Int).Mul(n, n) // n^2
+
+	// Sample v and compute u.
+	v, err := Int(reader, nn)
+	if err != nil {
+		return nil, err
+	}
+	u := new(big.Int).Exp(v, n, nn) // v^n mod n^2
+
+	// Sample r and compute a.
+	r, err := Int(reader, nn)
+	if err != nil {
+		return nil, err
+	}
+	a := new(big.Int).Exp(r, n, nn) // r^n mod n^2
+
+	// Compute challenge e.
+	eBytes, err := pUtils.GenerateRandomBytesSeeded(a.Bytes(), bits)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSampleBytes, err)
+	}
+	e := new(big.Int).SetBytes(eBytes)
+
+	// Compute z.
+	in1 := new(big.Int).Exp(v, e, nn) // v^e mod n^2
+	in2 := new(big.Int).Mul(r, in1)   // r * v^e
+	z := new(big.Int).Mod(in2, nn)    // r * v^e mod n^2
+
+	return pProofs.NewNthRootProof(u, a, z), nil
+}
+
+// RangeProof generates a Range proof which proves that x is an element of
+// {0, ..., q / 3} where c = enc_pk(x) with nonce r. It's the equivalent of
+// proofs.GenerateRangeProof.
+// Returns an error if the proof generation fails.
+func RangeProof(reader io.Reader, bits int, pk *keys.PublicKey, q, x, r *big.Int) (*pProofs.RangeProof, error) {
+	l := new(big.Int).Div(q, big.NewInt(3))  // q / 3
+	l2 := new(big.Int).Mul(big.NewInt(2), l) // 2 * l
+
+	// Sample the bits that decide whether w1 and w2 are switched.
+	switchBytes := make([]byte, (bits+7)/8)
+	if _, err := io.ReadFull(reader, switchBytes); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSampleBytes, err)
+	}
+
+	type randomnessPair struct{ w1, r1, w2, r2 *big.Int }
+	randomnessPairs := make([]randomnessPair, bits)
+	ciphertextPairs := make([]opaque.RangeCiphertextPair, bits)
+
+	for i := range bits {
+		// Sample w1 in [l, 2l) and derive w2 = w1 - l.
+		w1, err := Int(reader, l)
+		if err != nil {
+			return nil, err
+		}
+		w1 = new(big.Int).Add(w1, l)
+		w2 := new(big.Int).Sub(w1, l)
+
+		// Sample nonces r1 and r2.
+		r1, err := Int(reader, pk.N)
+		if err != nil {
+			return nil, err
+		}
+		r2, err := Int(reader, pk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		// Switch w1 and w2 with probability 1 / 2.
+		pair := randomnessPair{w1, r1, w2, r2}
+		if pUtils.BytesToBit(switchBytes, i) == 1 {
+			pair = randomnessPair{w2, r2, w1, r1}
+		}
+		randomnessPairs[i] = pair
+
+		c1, err := cipher.EncryptWithCustomNonce(pk, pair.r1, pair.w1.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrEncrypt, err)
+		}
+		c2, err := cipher.EncryptWithCustomNonce(pk, pair.r2, pair.w2.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrEncrypt, err)
+		}
+		ciphertextPairs[i] = opaque.RangeCiphertextPair{C1: c1, C2: c2}
+	}
+
+	// Derive the challenge via the Fiat-Shamir transform.
+	var seed []byte
+	seed = append(seed, pk.N.Bytes()...)
+	seed = append(seed, pk.G.Bytes()...)
+	seed = append(seed, pk.NN.Bytes()...)
+	for _, pair := range ciphertextPairs {
+		seed = append(seed, pair.C1...)
+		seed = append(seed, pair.C2...)
+	}
+	e, err := pUtils.GenerateRandomBytesSeeded(seed, bits)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSampleBytes, err)
+	}
+
+	proofPairs := make([]opaque.RangeProofPair, bits)
+	for i, pair := range randomnessPairs {
+		if pUtils.BytesToBit(e, i) == 0 {
+			proofPairs[i] = opaque.RangeProofPair{J: 0, W1: pair.w1, R1: pair.r1, W2: pair.w2, R2: pair.r2}
+			continue
+		}
+
+		xw1 := new(big.Int).Add(x, pair.w1) // x + w1
+		xw2 := new(big.Int).Add(x, pair.w2) // x + w2
+
+		// Exactly one of x + w1 and x + w2 needs to be an element of {l, ..., 2l}.
+		isXW1Valid := xw1.Cmp(l) >= 0 && xw1.Cmp(l2) < 0
+		isXW2Valid := xw2.Cmp(l) >= 0 && xw2.Cmp(l2) < 0
+
+		switch {
+		case isXW1Valid && !isXW2Valid:
+			in1 := new(big.Int).Mul(r, pair.r1) // r * r1
+			rr1 := new(big.Int).Mod(in1, pk.N)  // r * r1 mod n
+			proofPairs[i] = opaque.RangeProofPair{J: 1, W1: xw1, R1: rr1}
+		case isXW2Valid && !isXW1Valid:
+			in1 := new(big.Int).Mul(r, pair.r2) // r * r2
+			rr2 := new(big.Int).Mod(in1, pk.N)  // r * r2 mod n
+			proofPairs[i] = opaque.RangeProofPair{J: 2, W2: xw2, R2: rr2}
+		default:
+			return nil, ErrInvalidWitness
+		}
+	}
+
+	return opaque.NewRangeProof(proofPairs, ciphertextPairs)
+}
@@ -0,0 +1,148 @@
+package random
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/primefactor-io/commitment/pkg/hash"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+)
+
+// primalityRounds is the number of Miller-Rabin rounds used to test primality.
+const primalityRounds = 20
+
+// Int samples a uniformly random integer in the range [0, max).
+// Returns an error if the integer can't be sampled.
+func Int(reader io.Reader, max *big.Int) (*big.Int, error) {
+	var x *big.Int
+	var err error
+	if tape, ok := reader.(*Tape); ok {
+		x, err = tape.int(max)
+	} else {
+		x, err = rand.Int(reader, max)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSampleInt, err)
+	}
+
+	return x, nil
+}
+
+// Scalar samples a random scalar in the range [2, max) where max defaults to
+// the curve's order n. It's the equivalent of curve.GetRandomScalar.
+// Returns an error if the scalar can't be sampled.
+func Scalar(reader io.Reader, curve weierstrass.Curve, max ...*big.Int) (*big.Int, error) {
+	upper := curve.N()
+	if len(max) > 0 {
+		upper = max[0]
+	}
+
+	// Rejection sampling, see: https://stackoverflow.com/a/23650312
+	for {
+		x, err := Int(reader, upper)
+		if err != nil {
+			return nil, err
+		}
+
+		if x.Cmp(big.NewInt(1)) > 0 {
+			return x, nil
+		}
+	}
+}
+
+// Prime samples a random prime number with the given number of bits. The two
+// most significant bits are set so that the product of two such primes has
+// exactly twice the number of bits.
+// Returns an error if the prime number can't be sampled.
+func Prime(reader io.Reader, bits int) (*big.Int, error) {
+	if bits < 2 {
+		return nil, ErrInvalidBits
+	}
+
+	bz := make([]byte, (bits+7)/8)
+	excess := uint(len(bz)*8 - bits)
+
+	for {
+		if _, err := io.ReadFull(reader, bz); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSampleBytes, err)
+		}
+
+		// Clear excess bits, set the top two bits and make the candidate odd.
+		bz[0] &= byte(0xff >> excess)
+		p := new(big.Int).SetBytes(bz)
+		p.SetBit(p, bits-1, 1)
+		p.SetBit(p, bits-2, 1)
+		p.SetBit(p, 0, 1)
+
+		if p.ProbablyPrime(primalityRounds) {
+			return p, nil
+		}
+	}
+}
+
+// Commit creates a commitment that commits to arbitrary data. It's the
+// equivalent of hash.Commit.
+// Returns an error if the nonce can't be sampled.
+func Commit(reader io.Reader, data ...[]byte) (*hash.Commitment, error) {
+	var nonce [32]byte
+	if _, err := io.ReadFull(reader, nonce[:]); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSampleBytes, err)
+	}
+
+	var bz []byte
+	for _, d := range data {
+		bz = append(bz, d...)
+	}
+	bz = append(bz, nonce[:]...)
+
+	return hash.NewCommitment(sha256.Sum256(bz), nonce), nil
+}
+
+// NewSeededReader creates a deterministic source of randomness that derives an
+// unbounded stream of bytes from the seed via AES-256 in CTR mode.
+// It's meant for known-answer tests and replays and must not be used to
+// generate production keys.
+func NewSeededReader(seed []byte) io.Reader {
+	key := sha256.Sum256(seed)
+
+	// The key has a valid AES-256 size, so creating the block can't fail.
+	block, _ := aes.NewCipher(key[:])
+	iv := make([]byte, aes.BlockSize)
+
+	return &cipher.StreamReader{
+		S: cipher.NewCTR(block, iv),
+		R: zeroReader{},
+	}
+}
+
+// Fork derives n independent sources of randomness from the reader so that
+// concurrent tasks can draw their randomness without sharing a reader. The
+// seeds are drawn from the reader in order, so a deterministic reader leads to
+// deterministic sources no matter in which order the tasks run.
+// Returns an error if the seeds can't be sampled.
+func Fork(reader io.Reader, n int) ([]io.Reader, error) {
+	readers := make([]io.Reader, n)
+	for i := range readers {
+		seed := make([]byte, 32)
+		if _, err := io.ReadFull(reader, seed); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSampleBytes, err)
+		}
+		readers[i] = NewSeededReader(seed)
+	}
+
+	return readers, nil
+}
+
+// zeroReader is a reader that returns an infinite stream of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+
+	return len(p), nil
+}
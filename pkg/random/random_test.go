@@ -0,0 +1,331 @@
+package random_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/commitment/pkg/hash"
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
+	pProofs "github.com/primefactor-io/paillier/pkg/proofs"
+)
+
+var secp256k1 = curves.Secp256k1
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NewSeededReader", func(t *testing.T) {
+		t.Parallel()
+
+		bz1 := make([]byte, 64)
+		bz2 := make([]byte, 64)
+		bz3 := make([]byte, 64)
+
+		random.NewSeededReader([]byte("seed")).Read(bz1)
+		random.NewSeededReader([]byte("seed")).Read(bz2)
+		random.NewSeededReader([]byte("other seed")).Read(bz3)
+
+		if !bytes.Equal(bz1, bz2) {
+			t.Fatal("Seeded reader isn't deterministic")
+		}
+		if bytes.Equal(bz1, bz3) {
+			t.Fatal("Seeded reader ignores seed")
+		}
+	})
+
+	t.Run("Fork", func(t *testing.T) {
+		t.Parallel()
+
+		readers1, err := random.Fork(random.NewSeededReader([]byte("seed")), 2)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		readers2, _ := random.Fork(random.NewSeededReader([]byte("seed")), 2)
+
+		bz := make([][]byte, 4)
+		for i := range bz {
+			bz[i] = make([]byte, 64)
+		}
+
+		// The forked readers don't depend on the order they're read in.
+		readers1[1].Read(bz[0])
+		readers1[0].Read(bz[1])
+		readers2[0].Read(bz[2])
+		readers2[1].Read(bz[3])
+
+		if !bytes.Equal(bz[0], bz[3]) || !bytes.Equal(bz[1], bz[2]) {
+			t.Fatal("Forked readers aren't deterministic")
+		}
+		if bytes.Equal(bz[0], bz[1]) {
+			t.Fatal("Forked readers aren't independent")
+		}
+	})
+
+	t.Run("Scalar", func(t *testing.T) {
+		t.Parallel()
+
+		x, _ := random.Scalar(rand.Reader, secp256k1)
+
+		if x.Sign() <= 0 || x.Cmp(secp256k1.N()) >= 0 {
+			t.Error("Scalar sampling failed")
+		}
+	})
+
+	t.Run("Encrypt", func(t *testing.T) {
+		t.Parallel()
+
+		sk, pk, _ := keys.GenerateKeys(512)
+
+		plaintext := []byte("Hello World")
+		ciphertext, nonce, err := random.Encrypt(rand.Reader, pk, plaintext)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// The nonce reproduces the ciphertext.
+		expected, _ := cipher.EncryptWithCustomNonce(pk, nonce, plaintext)
+		if !bytes.Equal(ciphertext, expected) {
+			t.Fatal("Nonce doesn't match the ciphertext")
+		}
+
+		decrypted, _ := cipher.Decrypt(sk, ciphertext)
+		if !bytes.Equal(plaintext, decrypted) {
+			t.Fatal("Decryption failed")
+		}
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte("Hello World")
+		commitment, err := random.Commit(rand.Reader, data)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if !hash.Verify(commitment, data) {
+			t.Error("Commitment verification failed")
+		}
+	})
+
+	t.Run("DLKProof", func(t *testing.T) {
+		t.Parallel()
+
+		x, _ := random.Scalar(rand.Reader, secp256k1)
+		point, _ := secp256k1.ScalarMultiply(x, secp256k1.G())
+
+		proof, err := random.DLKProof(rand.Reader, secp256k1, point, x)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		isValid, _ := proofs.VerifyDLKProof(secp256k1, proof, point)
+
+		if !isValid {
+			t.Error("DLK proof verification failed")
+		}
+	})
+
+	t.Run("DLKProof - Invalid (Point)", func(t *testing.T) {
+		t.Parallel()
+
+		x, _ := random.Scalar(rand.Reader, secp256k1)
+
+		_, err := random.DLKProof(rand.Reader, secp256k1, secp256k1.G(), x)
+		if !errors.Is(err, random.ErrInvalidPoint) {
+			t.Fatalf("want error %v, got %v", random.ErrInvalidPoint, err)
+		}
+	})
+
+	t.Run("DLEqProof", func(t *testing.T) {
+		t.Parallel()
+
+		x, _ := random.Scalar(rand.Reader, secp256k1)
+		y, _ := random.Scalar(rand.Reader, secp256k1)
+		g := secp256k1.G()
+		X, _ := secp256k1.ScalarMultiply(x, g)
+		Y, _ := secp256k1.ScalarMultiply(y, g)
+		Z, _ := secp256k1.ScalarMultiply(x, Y)
+
+		proof, err := random.DLEqProof(rand.Reader, secp256k1, g, X, Y, Z, x)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		isValid, _ := proofs.VerifyDLEqProof(secp256k1, proof, g, X, Y, Z)
+
+		if !isValid {
+			t.Error("DLEq proof verification failed")
+		}
+	})
+
+	t.Run("PaillierKeys / NthRootProof / RangeProof", func(t *testing.T) {
+		t.Parallel()
+
+		reader := random.NewSeededReader([]byte("paillier"))
+		sk, pk, err := random.PaillierKeys(reader, 1024)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if pk.N.BitLen() != 1024 {
+			t.Fatalf("want 1024 bit modulus, got %d", pk.N.BitLen())
+		}
+
+		// Keys are interchangeable with the ones the library generates.
+		plaintext := []byte("Hello World")
+		ciphertext, _, _ := random.Encrypt(reader, pk, plaintext)
+		decrypted, _ := cipher.Decrypt(sk, ciphertext)
+
+		if !bytes.Equal(plaintext, decrypted) {
+			t.Fatal("Decryption failed")
+		}
+
+		pNthRoot, err := random.NthRootProof(reader, 128, pk.N)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		isValid, _ := pProofs.VerifyNthRootProof(pNthRoot, 128, pk.N)
+
+		if !isValid {
+			t.Fatal("Nth root proof verification failed")
+		}
+
+		// x should be in the range x >= 0 and x < q / 3.
+		x, _ := random.Int(reader, secp256k1.N())
+		x.Rsh(x, 2)
+		xEnc, r, _ := random.Encrypt(reader, pk, x.Bytes())
+
+		pRange, err := random.RangeProof(reader, 40, pk, secp256k1.N(), x, r)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		isValid, _ = pProofs.VerifyRangeProof(pRange, 40, pk, secp256k1.N(), xEnc)
+
+		if !isValid {
+			t.Fatal("Range proof verification failed")
+		}
+	})
+
+	t.Run("Seeded reader (deterministic)", func(t *testing.T) {
+		t.Parallel()
+
+		// generate returns the encodings of the values generated from a
+		// reader with the given seed.
+		generate := func(seed string) [][]byte {
+			reader := random.NewSeededReader([]byte(seed))
+
+			x, _ := random.Scalar(reader, secp256k1)
+			point, _ := secp256k1.ScalarMultiply(x, secp256k1.G())
+			sk, pk, _ := random.PaillierKeys(reader, 512)
+			commitment, _ := random.Commit(reader, []byte("Hello World"))
+			pDLK, _ := random.DLKProof(reader, secp256k1, point, x)
+			pNthRoot, _ := random.NthRootProof(reader, 40, pk.N)
+			xEnc, r, _ := random.Encrypt(reader, pk, big.NewInt(1).Bytes())
+			pRange, _ := random.RangeProof(reader, 40, pk, secp256k1.N(), big.NewInt(1), r)
+
+			encodings := [][]byte{xEnc}
+			for _, value := range []any{sk, commitment, pDLK, pNthRoot, pRange} {
+				bz, err := codec.MarshalValue(value)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				encodings = append(encodings, bz)
+			}
+			return encodings
+		}
+
+		run1 := generate("seed")
+		run2 := generate("seed")
+		run3 := generate("other seed")
+
+		for i := range run1 {
+			if !bytes.Equal(run1[i], run2[i]) {
+				t.Fatalf("Value %d isn't deterministic", i)
+			}
+			if bytes.Equal(run1[i], run3[i]) {
+				t.Fatalf("Value %d ignores the seed", i)
+			}
+		}
+	})
+
+	t.Run("Tape (record / replay)", func(t *testing.T) {
+		t.Parallel()
+
+		recording := random.NewRecordingTape(rand.Reader)
+
+		x1, _ := random.Scalar(recording, secp256k1)
+		sk1, _, _ := random.PaillierKeys(recording, 512)
+		bz1 := make([]byte, 16)
+		recording.Read(bz1)
+
+		replay := random.NewReplayTape(recording.Draws())
+
+		x2, _ := random.Scalar(replay, secp256k1)
+		sk2, pk2, _ := random.PaillierKeys(replay, 512)
+		bz2 := make([]byte, 16)
+		replay.Read(bz2)
+
+		if x1.Cmp(x2) != 0 || !sk1.Equal(sk2) || !bytes.Equal(bz1, bz2) {
+			t.Fatal("Replay doesn't reproduce the recorded random inputs")
+		}
+		if replay.Remaining() != 0 {
+			t.Fatal("Replay didn't use all random inputs")
+		}
+		if !pk2.Equal(keys.DerivePublicKey(sk1)) {
+			t.Fatal("Replayed public key doesn't match the private key")
+		}
+
+		if _, err := random.Scalar(replay, secp256k1); !errors.Is(err, random.ErrTapeExhausted) {
+			t.Fatalf("expected error %v, got %v", random.ErrTapeExhausted, err)
+		}
+	})
+
+	t.Run("Tape (fallback)", func(t *testing.T) {
+		t.Parallel()
+
+		recording := random.NewRecordingTape(rand.Reader)
+		x1, _ := random.Scalar(recording, secp256k1)
+
+		replay := random.NewReplayTape(recording.Draws()).WithFallback(rand.Reader)
+
+		x2, err := random.Scalar(replay, secp256k1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if x1.Cmp(x2) != 0 {
+			t.Fatal("Replay doesn't reproduce the recorded random input")
+		}
+
+		if _, err := random.Scalar(replay, secp256k1); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, _, err := random.PaillierKeys(replay, 512); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := replay.Read(make([]byte, 16)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(replay.Draws()) != 1 {
+			t.Fatal("Fallback random inputs were recorded")
+		}
+	})
+
+	t.Run("Tape (mismatch)", func(t *testing.T) {
+		t.Parallel()
+
+		recording := random.NewRecordingTape(rand.Reader)
+		random.PaillierKeys(recording, 512)
+
+		replay := random.NewReplayTape(recording.Draws())
+
+		if _, err := random.Scalar(replay, secp256k1); !errors.Is(err, random.ErrTapeMismatch) {
+			t.Fatalf("expected error %v, got %v", random.ErrTapeMismatch, err)
+		}
+	})
+}
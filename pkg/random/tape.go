@@ -0,0 +1,204 @@
+package random
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/primefactor-io/paillier/pkg/keys"
+)
+
+// DrawKind is the kind of a random input that was drawn from a tape.
+type DrawKind string
+
+const (
+	// IntDraw is a uniformly random integer sampled via Int (or Scalar).
+	IntDraw DrawKind = "int"
+	// PaillierKeyDraw is a Paillier private key generated via PaillierKeys.
+	PaillierKeyDraw DrawKind = "paillier key"
+	// BytesDraw is a slice of random bytes read directly from the tape.
+	BytesDraw DrawKind = "bytes"
+)
+
+// Draw is a single random input that was drawn from a tape.
+type Draw struct {
+	Kind  DrawKind
+	Value []byte
+}
+
+// Tape is a source of randomness that either records every random input that
+// is drawn from it or replays previously recorded random inputs in order.
+// Integers and Paillier keys are recorded as values rather than the bytes they
+// were derived from, so that a replay fixes the protocol's random inputs
+// independently of how they're sampled.
+type Tape struct {
+	source   io.Reader
+	fallback io.Reader
+	draws    []Draw
+	pos      int
+}
+
+// NewRecordingTape creates a tape that draws random inputs from the source and
+// records them.
+func NewRecordingTape(source io.Reader) *Tape {
+	return &Tape{source: source}
+}
+
+// NewReplayTape creates a tape that replays the given random inputs in order.
+func NewReplayTape(draws []Draw) *Tape {
+	return &Tape{draws: draws}
+}
+
+// WithFallback sets the source random inputs are drawn from once the replay
+// tape is exhausted. Inputs drawn from the fallback aren't recorded.
+// Returns the tape to allow chaining.
+func (t *Tape) WithFallback(fallback io.Reader) *Tape {
+	t.fallback = fallback
+
+	return t
+}
+
+// Draws returns the random inputs that were recorded or replayed so far.
+func (t *Tape) Draws() []Draw {
+	if t.source == nil {
+		return t.draws[:t.pos]
+	}
+
+	return t.draws
+}
+
+// Remaining returns the number of random inputs that haven't been replayed yet.
+func (t *Tape) Remaining() int {
+	if t.source != nil {
+		return 0
+	}
+
+	return len(t.draws) - t.pos
+}
+
+// Read fills p with random bytes.
+// Returns an error if the bytes can't be read from the source or the next
+// random input on the replay tape doesn't consist of exactly len(p) bytes.
+func (t *Tape) Read(p []byte) (int, error) {
+	if t.source != nil {
+		if _, err := io.ReadFull(t.source, p); err != nil {
+			return 0, err
+		}
+		t.draws = append(t.draws, Draw{Kind: BytesDraw, Value: append([]byte(nil), p...)})
+
+		return len(p), nil
+	}
+	if t.exhausted() {
+		return io.ReadFull(t.fallback, p)
+	}
+
+	draw, err := t.next(BytesDraw)
+	if err != nil {
+		return 0, err
+	}
+	if len(draw.Value) != len(p) {
+		return 0, fmt.Errorf("%w: expected %d bytes, got %d", ErrTapeMismatch, len(p), len(draw.Value))
+	}
+
+	return copy(p, draw.Value), nil
+}
+
+// int draws a uniformly random integer in the range [0, max).
+// Returns an error if the integer can't be sampled or the replayed integer
+// isn't in range.
+func (t *Tape) int(max *big.Int) (*big.Int, error) {
+	if t.source != nil {
+		x, err := rand.Int(t.source, max)
+		if err != nil {
+			return nil, err
+		}
+		t.draws = append(t.draws, Draw{Kind: IntDraw, Value: x.Bytes()})
+
+		return x, nil
+	}
+	if t.exhausted() {
+		return rand.Int(t.fallback, max)
+	}
+
+	draw, err := t.next(IntDraw)
+	if err != nil {
+		return nil, err
+	}
+
+	x := new(big.Int).SetBytes(draw.Value)
+	if x.Cmp(max) >= 0 {
+		return nil, fmt.Errorf("%w: integer out of range", ErrTapeMismatch)
+	}
+
+	return x, nil
+}
+
+// paillierKeys generates a Paillier key pair whose modulus has the given number
+// of bits. The private key is recorded as N followed by phi(N), both padded to
+// the modulus' byte length.
+// Returns an error if the key pair can't be generated or the replayed value
+// isn't a private key with a modulus of the given number of bits.
+func (t *Tape) paillierKeys(bits int) (*keys.PrivateKey, *keys.PublicKey, error) {
+	if t.source != nil {
+		sk, pk, err := generatePaillierKeys(t.source, bits)
+		if err != nil {
+			return nil, nil, err
+		}
+		size := (bits + 7) / 8
+		value := append(sk.N.FillBytes(make([]byte, size)), sk.PhiN.FillBytes(make([]byte, size))...)
+		t.draws = append(t.draws, Draw{Kind: PaillierKeyDraw, Value: value})
+
+		return sk, pk, nil
+	}
+	if t.exhausted() {
+		return generatePaillierKeys(t.fallback, bits)
+	}
+
+	draw, err := t.next(PaillierKeyDraw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size := (bits + 7) / 8
+	if len(draw.Value) != 2*size {
+		return nil, nil, fmt.Errorf("%w: invalid Paillier key", ErrTapeMismatch)
+	}
+
+	n := new(big.Int).SetBytes(draw.Value[:size])
+	phiN := new(big.Int).SetBytes(draw.Value[size:])
+	mu := new(big.Int).ModInverse(phiN, n) // phiN^-1 mod n
+	if n.BitLen() != bits || mu == nil {
+		return nil, nil, fmt.Errorf("%w: invalid Paillier key", ErrTapeMismatch)
+	}
+
+	nn := new(big.Int).Mul(n, n) // n^2
+
+	sk := keys.NewPrivateKey(n, phiN, mu, nn)
+	pk := keys.DerivePublicKey(sk)
+
+	return sk, pk, nil
+}
+
+// exhausted checks if the replay tape is exhausted and random inputs should be
+// drawn from the fallback.
+func (t *Tape) exhausted() bool {
+	return t.fallback != nil && t.pos >= len(t.draws)
+}
+
+// next returns the next random input on the replay tape.
+// Returns an error if the tape is exhausted or the next random input has a
+// different kind.
+func (t *Tape) next(kind DrawKind) (Draw, error) {
+	if t.pos >= len(t.draws) {
+		return Draw{}, ErrTapeExhausted
+	}
+
+	draw := t.draws[t.pos]
+	if draw.Kind != kind {
+		return Draw{}, fmt.Errorf("%w: expected %s, got %s", ErrTapeMismatch, kind, draw.Kind)
+	}
+	t.pos++
+
+	return draw, nil
+}
@@ -0,0 +1,20 @@
+/*
+Package scalar implements constant-time arithmetic modulo the order of an
+elliptic curve, e.g. the orders of secp256k1 and P-256.
+
+The operations of math/big take time that depends on the values they operate
+on (e.g. Mul and Mod depend on the operands' lengths, ModInverse runs the
+extended Euclidean algorithm and Cmp returns early). The protocol parties
+compute with secret values such as the nonces k1 and k2, the key share x2 and
+the decrypted s', so they use a Field instead which stores scalars as four
+64-bit limbs in Montgomery form and runs every operation in time that only
+depends on the field's order. Conditions on secret values are returned as 0 or
+1 (see Scalar.IsZero and Scalar.IsHigh) and consumed via Scalar.Select, so that
+no secret-dependent branch is needed, e.g. to choose the low s value of an
+ECDSA signature (see Scalar.SetLowS).
+
+Values that leave a Field (see Scalar.BigInt) are handled by math/big again
+which is fine for public values such as a signature's s value and unavoidable
+for values that go into a Paillier encryption or a scalar multiplication.
+*/
+package scalar
@@ -0,0 +1,9 @@
+package scalar
+
+import "fmt"
+
+var (
+	// ErrInvalidOrder is returned if the order isn't an odd number greater than
+	// 1 with at most 256 bits.
+	ErrInvalidOrder = fmt.Errorf("invalid order")
+)
@@ -0,0 +1,378 @@
+package scalar
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// limbs is the number of 64-bit limbs of an element.
+const limbs = 4
+
+// size is the byte length of an element.
+const size = limbs * 8
+
+// element is a 256-bit number with little-endian limbs.
+type element [limbs]uint64
+
+// Field is an instance of the field of scalars modulo a curve's order.
+type Field struct {
+	// n is the order.
+	n element
+	// nInv is -n^-1 mod 2^64.
+	nInv uint64
+	// rr is R^2 mod n with R = 2^256.
+	rr element
+	// one is R mod n, i.e. 1 in Montgomery form.
+	one element
+	// half is (n - 1) / 2.
+	half element
+	// exp is n - 2, the exponent of the inversion via Fermat's little theorem.
+	exp element
+	// byteLen is the byte length of the order.
+	byteLen int
+}
+
+// NewField creates a new instance of the field of scalars modulo the order n,
+// e.g. curve.N().
+// Returns an error if the order isn't an odd number greater than 1 with at most
+// 256 bits.
+func NewField(n *big.Int) (*Field, error) {
+	if n.Sign() <= 0 || n.Bit(0) == 0 || n.BitLen() < 2 || n.BitLen() > limbs*64 {
+		return nil, ErrInvalidOrder
+	}
+
+	// The order is public, so the constants can be computed via math/big.
+	r := new(big.Int).Lsh(big.NewInt(1), limbs*64)
+	w := new(big.Int).Lsh(big.NewInt(1), 64)
+	nInv := new(big.Int).ModInverse(new(big.Int).Mod(n, w), w)
+
+	return &Field{
+		n:       fromBigInt(n),
+		nInv:    -nInv.Uint64(),
+		rr:      fromBigInt(new(big.Int).Exp(r, big.NewInt(2), n)),
+		one:     fromBigInt(new(big.Int).Mod(r, n)),
+		half:    fromBigInt(new(big.Int).Rsh(n, 1)),
+		exp:     fromBigInt(new(big.Int).Sub(n, big.NewInt(2))),
+		byteLen: (n.BitLen() + 7) / 8,
+	}, nil
+}
+
+// NewScalar creates a new scalar of the field which is set to 0.
+func (f *Field) NewScalar() *Scalar {
+	return &Scalar{field: f}
+}
+
+// Scalar is an instance of a scalar of a field. All of its operations run in
+// constant time. Scalars of different fields must not be mixed.
+type Scalar struct {
+	field *Field
+	// v is the scalar in Montgomery form (v = x * R mod n).
+	v element
+}
+
+// Set sets z to x.
+// Returns z to allow chaining.
+func (z *Scalar) Set(x *Scalar) *Scalar {
+	z.v = x.v
+
+	return z
+}
+
+// SetBytes sets z to the big-endian encoded value modulo the order. The value
+// may have any length, e.g. a decrypted Paillier plaintext.
+// Returns z to allow chaining.
+func (z *Scalar) SetBytes(bz []byte) *Scalar {
+	f := z.field
+
+	var acc, c element
+	var buf [size]byte
+
+	// Reduce the value chunk by chunk via Horner's method where every chunk c is
+	// less than R, so that c * R^2 * R^-1 = c * R mod n is c in Montgomery form.
+	for len(bz) > 0 {
+		k := len(bz) % size
+		if k == 0 {
+			k = size
+		}
+
+		clear(buf[:])
+		copy(buf[size-k:], bz[:k])
+		bz = bz[k:]
+
+		c = fromBytes(&buf)
+		c = f.mul(&c, &f.rr)
+		acc = f.mul(&acc, &f.rr) // acc * R
+		acc = f.add(&acc, &c)
+	}
+
+	z.v = acc
+	clear(buf[:])
+	clear(c[:])
+
+	return z
+}
+
+// SetInt sets z to the non-negative value x modulo the order.
+// Returns z to allow chaining.
+func (z *Scalar) SetInt(x *big.Int) *Scalar {
+	// Encode x with a fixed length, so that its leading zeros aren't stripped.
+	buf := x.FillBytes(make([]byte, max(size, (x.BitLen()+7)/8)))
+	z.SetBytes(buf)
+	clear(buf)
+
+	return z
+}
+
+// Add sets z to x + y mod n.
+// Returns z to allow chaining.
+func (z *Scalar) Add(x, y *Scalar) *Scalar {
+	z.v = z.field.add(&x.v, &y.v)
+
+	return z
+}
+
+// Sub sets z to x - y mod n.
+// Returns z to allow chaining.
+func (z *Scalar) Sub(x, y *Scalar) *Scalar {
+	z.v = z.field.sub(&x.v, &y.v)
+
+	return z
+}
+
+// Negate sets z to -x mod n.
+// Returns z to allow chaining.
+func (z *Scalar) Negate(x *Scalar) *Scalar {
+	var zero element
+	z.v = z.field.sub(&zero, &x.v)
+
+	return z
+}
+
+// Mul sets z to x * y mod n.
+// Returns z to allow chaining.
+func (z *Scalar) Mul(x, y *Scalar) *Scalar {
+	z.v = z.field.mul(&x.v, &y.v)
+
+	return z
+}
+
+// Invert sets z to x^-1 mod n via Fermat's little theorem, so the order must
+// be prime. z is set to 0 if x is 0 (see IsZero).
+// Returns z to allow chaining.
+func (z *Scalar) Invert(x *Scalar) *Scalar {
+	f := z.field
+
+	// x^(n - 2) via square and multiply. The branch only depends on the public
+	// exponent.
+	r := f.one
+	for i := limbs*64 - 1; i >= 0; i-- {
+		r = f.mul(&r, &r)
+		if (f.exp[i/64]>>(i%64))&1 == 1 {
+			r = f.mul(&r, &x.v)
+		}
+	}
+
+	z.v = r
+
+	return z
+}
+
+// Select sets z to x if cond is 1 and to y if cond is 0.
+// Returns z to allow chaining.
+func (z *Scalar) Select(x, y *Scalar, cond int) *Scalar {
+	mask := -uint64(cond)
+	for i := range z.v {
+		z.v[i] = (x.v[i] & mask) | (y.v[i] &^ mask)
+	}
+
+	return z
+}
+
+// SetLowS sets z to x or -x mod n, whichever is at most (n - 1) / 2, which is
+// the low s value of an ECDSA signature.
+// Returns 1 if x was negated (which flips the signature's recovery id v) and 0
+// otherwise.
+func (z *Scalar) SetLowS(x *Scalar) int {
+	high := x.IsHigh()
+	neg := z.field.NewScalar().Negate(x)
+	z.Select(neg, x, high)
+	neg.Wipe()
+
+	return high
+}
+
+// IsZero returns 1 if z is 0 and 0 otherwise.
+func (z *Scalar) IsZero() int {
+	// 0 is the only value whose Montgomery form is 0.
+	var acc uint64
+	for _, limb := range z.v {
+		acc |= limb
+	}
+
+	return int(1 ^ ((acc | -acc) >> 63))
+}
+
+// IsHigh returns 1 if z is greater than (n - 1) / 2 and 0 otherwise.
+func (z *Scalar) IsHigh() int {
+	x := z.field.fromMontgomery(&z.v)
+	_, borrow := sub(&z.field.half, &x)
+	clear(x[:])
+
+	return int(borrow)
+}
+
+// Bytes returns the big-endian encoding of z which has the byte length of the
+// order.
+func (z *Scalar) Bytes() []byte {
+	x := z.field.fromMontgomery(&z.v)
+
+	var buf [size]byte
+	for i, limb := range x {
+		binary.BigEndian.PutUint64(buf[size-(i+1)*8:], limb)
+	}
+	clear(x[:])
+
+	out := make([]byte, z.field.byteLen)
+	copy(out, buf[size-z.field.byteLen:])
+	clear(buf[:])
+
+	return out
+}
+
+// BigInt returns z as a big integer. Note that math/big isn't constant time,
+// so the big integer should only be used for public values or where math/big
+// can't be avoided.
+func (z *Scalar) BigInt() *big.Int {
+	bz := z.Bytes()
+	x := new(big.Int).SetBytes(bz)
+	clear(bz)
+
+	return x
+}
+
+// Wipe sets z to 0.
+func (z *Scalar) Wipe() {
+	clear(z.v[:])
+}
+
+// mul returns a * b * R^-1 mod n via the Montgomery multiplication (CIOS).
+// The result is fully reduced if a * b < n * R, e.g. if a < R and b < n.
+func (f *Field) mul(a, b *element) element {
+	var t [limbs + 2]uint64
+
+	for i := range limbs {
+		// t = t + a * b_i
+		var c uint64
+		for j := range limbs {
+			hi, lo := bits.Mul64(a[j], b[i])
+			lo, cc := bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		t[limbs], c = bits.Add64(t[limbs], c, 0)
+		t[limbs+1] = c
+
+		// t = (t + m * n) / 2^64 with m = t_0 * -n^-1 mod 2^64
+		m := t[0] * f.nInv
+		hi, lo := bits.Mul64(m, f.n[0])
+		_, cc := bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < limbs; j++ {
+			hi, lo = bits.Mul64(m, f.n[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[limbs-1], cc = bits.Add64(t[limbs], c, 0)
+		t[limbs] = t[limbs+1] + cc
+	}
+
+	// t < 2n, so a single conditional subtraction reduces it.
+	x := element(t[:limbs])
+	res := f.reduce(&x, t[limbs])
+	clear(t[:])
+	clear(x[:])
+
+	return res
+}
+
+// add returns a + b mod n.
+func (f *Field) add(a, b *element) element {
+	var x element
+	var carry uint64
+	for i := range limbs {
+		x[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+
+	return f.reduce(&x, carry)
+}
+
+// sub returns a - b mod n.
+func (f *Field) sub(a, b *element) element {
+	x, borrow := sub(a, b)
+
+	// Add n back if the subtraction borrowed.
+	mask := -borrow
+	var carry uint64
+	for i := range limbs {
+		x[i], carry = bits.Add64(x[i], f.n[i]&mask, carry)
+	}
+
+	return x
+}
+
+// reduce returns hi * 2^256 + x mod n for values less than 2n.
+func (f *Field) reduce(x *element, hi uint64) element {
+	d, borrow := sub(x, &f.n)
+
+	// Subtract n if the value has a 257th bit or is at least n.
+	mask := -(hi | (borrow ^ 1))
+	var res element
+	for i := range limbs {
+		res[i] = (d[i] & mask) | (x[i] &^ mask)
+	}
+
+	return res
+}
+
+// fromMontgomery returns x * R^-1 mod n.
+func (f *Field) fromMontgomery(x *element) element {
+	one := element{1}
+
+	return f.mul(x, &one)
+}
+
+// sub returns a - b and the borrow.
+func sub(a, b *element) (element, uint64) {
+	var x element
+	var borrow uint64
+	for i := range limbs {
+		x[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+
+	return x, borrow
+}
+
+// fromBytes returns the element the big-endian bytes encode.
+func fromBytes(buf *[size]byte) element {
+	var x element
+	for i := range limbs {
+		x[i] = binary.BigEndian.Uint64(buf[size-(i+1)*8:])
+	}
+
+	return x
+}
+
+// fromBigInt returns the element of the public value x which has at most 256
+// bits.
+func fromBigInt(x *big.Int) element {
+	var buf [size]byte
+	x.FillBytes(buf[:])
+
+	return fromBytes(&buf)
+}
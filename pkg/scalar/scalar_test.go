@@ -0,0 +1,199 @@
+package scalar_test
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/lindell17/pkg/scalar"
+)
+
+var orders = []struct {
+	name string
+	n    *big.Int
+}{
+	{"secp256k1", curves.Secp256k1.N()},
+	{"P-256", elliptic.P256().Params().N},
+}
+
+func TestScalar(t *testing.T) {
+	t.Parallel()
+
+	for _, order := range orders {
+		n := order.n
+
+		// values returns random values modulo n and the edge cases.
+		values := func() []*big.Int {
+			nMinusOne := new(big.Int).Sub(n, big.NewInt(1))
+			half := new(big.Int).Rsh(n, 1)
+			xs := []*big.Int{big.NewInt(0), big.NewInt(1), half, new(big.Int).Add(half, big.NewInt(1)), nMinusOne}
+			for range 32 {
+				x, _ := rand.Int(rand.Reader, n)
+				xs = append(xs, x)
+			}
+			return xs
+		}
+
+		t.Run(fmt.Sprintf("Arithmetic (%s)", order.name), func(t *testing.T) {
+			t.Parallel()
+
+			f, err := scalar.NewField(n)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			xs := values()
+			for i, x := range xs {
+				y := xs[(i+1)%len(xs)]
+				sx := f.NewScalar().SetInt(x)
+				sy := f.NewScalar().SetInt(y)
+
+				tests := []struct {
+					name string
+					got  *scalar.Scalar
+					want *big.Int
+				}{
+					{"Add", f.NewScalar().Add(sx, sy), new(big.Int).Add(x, y)},
+					{"Sub", f.NewScalar().Sub(sx, sy), new(big.Int).Sub(x, y)},
+					{"Negate", f.NewScalar().Negate(sx), new(big.Int).Neg(x)},
+					{"Mul", f.NewScalar().Mul(sx, sy), new(big.Int).Mul(x, y)},
+				}
+				if x.Sign() != 0 {
+					tests = append(tests, struct {
+						name string
+						got  *scalar.Scalar
+						want *big.Int
+					}{"Invert", f.NewScalar().Invert(sx), new(big.Int).ModInverse(x, n)})
+				}
+
+				for _, tt := range tests {
+					want := new(big.Int).Mod(tt.want, n)
+					if tt.got.BigInt().Cmp(want) != 0 {
+						t.Fatalf("%s(%x, %x): want %x, got %x", tt.name, x, y, want, tt.got.BigInt())
+					}
+				}
+			}
+		})
+
+		t.Run(fmt.Sprintf("SetBytes (%s)", order.name), func(t *testing.T) {
+			t.Parallel()
+
+			f, _ := scalar.NewField(n)
+
+			// Values with up to 4 times the order's length (e.g. a decrypted s').
+			for _, length := range []int{0, 1, 31, 32, 33, 64, 65, 100, 128} {
+				bz := make([]byte, length)
+				rand.Read(bz)
+
+				want := new(big.Int).Mod(new(big.Int).SetBytes(bz), n)
+				got := f.NewScalar().SetBytes(bz)
+
+				if got.BigInt().Cmp(want) != 0 {
+					t.Fatalf("length %d: want %x, got %x", length, want, got.BigInt())
+				}
+				if !bytes.Equal(got.Bytes(), want.FillBytes(make([]byte, 32))) {
+					t.Fatalf("length %d: want bytes %x, got %x", length, want, got.Bytes())
+				}
+			}
+
+			// n itself is reduced to 0.
+			if f.NewScalar().SetInt(n).IsZero() != 1 {
+				t.Fatal("want n to be reduced to 0")
+			}
+		})
+
+		t.Run(fmt.Sprintf("SetLowS (%s)", order.name), func(t *testing.T) {
+			t.Parallel()
+
+			f, _ := scalar.NewField(n)
+			half := new(big.Int).Rsh(n, 1)
+
+			for _, x := range values() {
+				s := f.NewScalar()
+				flipped := s.SetLowS(f.NewScalar().SetInt(x))
+
+				want, wantFlipped := x, 0
+				if x.Cmp(half) > 0 {
+					want, wantFlipped = new(big.Int).Sub(n, x), 1
+				}
+
+				if s.BigInt().Cmp(want) != 0 {
+					t.Fatalf("SetLowS(%x): want %x, got %x", x, want, s.BigInt())
+				}
+				if flipped != wantFlipped {
+					t.Fatalf("SetLowS(%x): want flipped %d, got %d", x, wantFlipped, flipped)
+				}
+			}
+		})
+	}
+
+	t.Run("IsZero / Select / Wipe", func(t *testing.T) {
+		t.Parallel()
+
+		f, _ := scalar.NewField(curves.Secp256k1.N())
+		x := f.NewScalar().SetInt(big.NewInt(7))
+		y := f.NewScalar().SetInt(big.NewInt(9))
+
+		if f.NewScalar().IsZero() != 1 || x.IsZero() != 0 {
+			t.Fatal("IsZero failed")
+		}
+		if f.NewScalar().Select(x, y, 1).BigInt().Int64() != 7 {
+			t.Fatal("Select failed (cond = 1)")
+		}
+		if f.NewScalar().Select(x, y, 0).BigInt().Int64() != 9 {
+			t.Fatal("Select failed (cond = 0)")
+		}
+		if f.NewScalar().Invert(f.NewScalar()).IsZero() != 1 {
+			t.Fatal("want the inverse of 0 to be 0")
+		}
+
+		x.Wipe()
+		if x.IsZero() != 1 {
+			t.Fatal("Wipe failed")
+		}
+	})
+
+	t.Run("NewField - Invalid (Order)", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := []*big.Int{
+			big.NewInt(-7),
+			big.NewInt(0),
+			big.NewInt(1),
+			big.NewInt(10),
+			new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+		}
+
+		for _, n := range invalid {
+			_, err := scalar.NewField(n)
+
+			if !errors.Is(err, scalar.ErrInvalidOrder) {
+				t.Errorf("order %v: want error %v, got %v", n, scalar.ErrInvalidOrder, err)
+			}
+		}
+	})
+}
+
+func BenchmarkInvert(b *testing.B) {
+	n := curves.Secp256k1.N()
+	f, _ := scalar.NewField(n)
+	x, _ := rand.Int(rand.Reader, n)
+	sx := f.NewScalar().SetInt(x)
+
+	b.Run("math/big", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			new(big.Int).ModInverse(x, n)
+		}
+	})
+
+	b.Run("scalar", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			f.NewScalar().Invert(sx)
+		}
+	})
+}
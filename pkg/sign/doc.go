@@ -1,5 +1,9 @@
 /*
 Package sign implements the interactive signing protocol as described
 in section "Protocol 3.2" of the paper https://eprint.iacr.org/2017/552.pdf.
+
+Both parties wipe their nonces once they sent their result or a call failed,
+so protocol runs can't be resumed or retried. Destroy cancels a run. The
+params' Destroy method wipes the long-term secrets they hold.
 */
 package sign
@@ -3,7 +3,9 @@ package messages
 import (
 	"github.com/primefactor-io/commitment/pkg/hash"
 	"github.com/primefactor-io/ecc/pkg/proofs"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message1 is the protocol's first message that is sent from party 1 to party 2.
@@ -46,7 +48,15 @@ func (m *Message1) SessionId() string {
 }
 
 func (m *Message1) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.CR1 != nil &&
 		m.PR1 != nil
 }
+
+func (m *Message1) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	return nil
+}
@@ -1,10 +1,13 @@
 package messages
 
 import (
+	"fmt"
 	"math/big"
 
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
 	"github.com/primefactor-io/paillier/pkg/cipher"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // Message4 is the protocol's fourth message that is sent from part 2 to party 1.
@@ -47,7 +50,22 @@ func (m *Message4) SessionId() string {
 }
 
 func (m *Message4) IsValid() bool {
-	return m.Sid != "" &&
+	return lindell17.IsValidSessionId(m.Sid) &&
 		m.R != nil &&
 		m.Ciphertext != nil
 }
+
+func (m *Message4) ValidateFor(curve weierstrass.Curve, pk *keys.PublicKey) error {
+	if !m.IsValid() {
+		return lindell17.ErrInvalidMessage
+	}
+
+	if err := lindell17.CheckScalar(m.R, big.NewInt(1), curve.N()); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+	if err := lindell17.CheckCiphertext(pk, m.Ciphertext); err != nil {
+		return fmt.Errorf("%w: %w", lindell17.ErrInvalidMessage, err)
+	}
+
+	return nil
+}
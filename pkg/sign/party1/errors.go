@@ -21,6 +21,12 @@ var (
 	ErrInvalidR = fmt.Errorf("recomputed r doesn't equal r of partial signature")
 	// ErrDecryptCiphertext is returned if the ciphertext can't be decrypted.
 	ErrDecryptCiphertext = fmt.Errorf("unable to decrypt ciphertext")
+	// ErrZeroSPrime is returned if the decrypted s' is 0 mod q.
+	ErrZeroSPrime = fmt.Errorf("invalid ciphertext (s' = 0 mod q)")
+	// ErrInvertNonceK1 is returned if the nonce k1 can't be inverted.
+	ErrInvertNonceK1 = fmt.Errorf("unable to invert nonce k1")
+	// ErrComputeS is returned if s can't be computed.
+	ErrComputeS = fmt.Errorf("unable to compute s")
 	// ErrInvalidSignature is returned if the signature is invalid.
 	ErrInvalidSignature = fmt.Errorf("invalid signature")
 )
@@ -1,15 +1,20 @@
 package party1
 
 import (
+	"fmt"
+	"io"
 	"math/big"
+	"time"
 
-	"github.com/primefactor-io/commitment/pkg/hash"
 	"github.com/primefactor-io/ecc/pkg/ecdsa"
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	"github.com/primefactor-io/ecc/pkg/keys"
 	"github.com/primefactor-io/ecc/pkg/proofs"
 	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/crt"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/scalar"
 	"github.com/primefactor-io/lindell17/pkg/sign/messages"
 	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/cipher"
@@ -18,36 +23,56 @@ import (
 
 // Party1 is an instance of party 1 that participates in the signing protocol.
 type Party1 struct {
-	curve   weierstrass.Curve
-	sk      *pKeys.PrivateKey
-	qShared *elliptic.Point
-	hash    []byte
-	k1      *big.Int
-	r1      *elliptic.Point
-	r2      *elliptic.Point
-	state   lindell17.State
-	outCh   chan<- lindell17.Message
-	resCh   chan<- lindell17.Result
+	curve     weierstrass.Curve
+	field     *scalar.Field
+	sk        *pKeys.PrivateKey
+	decrypter *crt.Decrypter
+	crtErr    error
+	pk        *pKeys.PublicKey
+	qShared   *elliptic.Point
+	hash      []byte
+	k1        *big.Int
+	r1        *elliptic.Point
+	r2        *elliptic.Point
+	limits    *lindell17.Limits
+	recorder  lindell17.Recorder
+	tracer    *lindell17.Tracer
+	rand      io.Reader
+	messages  int
+	state     lindell17.State
+	outCh     chan<- lindell17.Message
+	resCh     chan<- lindell17.Result
 }
 
 // NewParty1 creates a new instance of party 1 that participates in the signing
 // protocol.
 func NewParty1(params *Params, hash []byte, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) *Party1 {
 	return &Party1{
-		curve:   params.curve,
-		sk:      params.sk,
-		qShared: params.qShared,
-		hash:    hash,
-		state:   lindell17.Start,
-		outCh:   outCh,
-		resCh:   resCh,
+		curve:     params.curve,
+		field:     params.field,
+		sk:        params.sk,
+		decrypter: params.decrypter,
+		crtErr:    params.crtErr,
+		pk:        pKeys.DerivePublicKey(params.sk),
+		qShared:   params.qShared,
+		hash:      hash,
+		limits:    params.limits,
+		recorder:  params.recorder,
+		tracer:    lindell17.NewTracer(params.observer, lindell17.Sign, lindell17.Party1),
+		rand:      params.rand,
+		state:     lindell17.Start,
+		outCh:     outCh,
+		resCh:     resCh,
 	}
 }
 
 // Start starts party 1 of the protocol.
 // Returns an error if the current state is invalid, the hash has an invalid
 // length or starting the protocol fails.
-func (p *Party1) Start() (bool, error) {
+func (p *Party1) Start() (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
 	// Validate state.
 	if p.state != lindell17.Start {
 		return false, lindell17.ErrInvalidState
@@ -55,10 +80,11 @@ func (p *Party1) Start() (bool, error) {
 
 	// Generate session id.
 	bits := 128
-	sid, err := utils.GenerateSessionId(bits)
+	sid, err := utils.GenerateSessionIdFromReader(p.rand, bits)
 	if err != nil {
-		return false, lindell17.ErrGenerateSessionId
+		return false, err
 	}
+	p.tracer.SetSessionId(sid)
 
 	// Check if hash has length of 256 bits.
 	if len(p.hash) != utils.HashLength {
@@ -66,16 +92,26 @@ func (p *Party1) Start() (bool, error) {
 	}
 
 	// Transition to next state.
-	p.state = lindell17.Step1
+	p.setState(lindell17.Step1)
 
 	// Run step 1.
 	return p.step1(sid)
 }
 
 // Process processes an incoming protocol message.
-// Returns an error if the message was sent by the wrong sender, is invalid,
+// Returns an error if the session's message limit is exceeded, the message
+// can't be recorded or the message was sent by the wrong sender, is invalid,
 // unknown or not intended for the protocol / recipient.
-func (p *Party1) Process(msg lindell17.Message) (bool, error) {
+func (p *Party1) Process(msg lindell17.Message) (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
+	// Enforce the session's message limit.
+	p.messages++
+	if p.messages > p.limits.MaxMessages {
+		return false, lindell17.ErrTooManyMessages
+	}
+
 	// Check message protocol.
 	if msg.Protocol() != lindell17.Sign {
 		return false, lindell17.ErrWrongProtocol
@@ -91,17 +127,35 @@ func (p *Party1) Process(msg lindell17.Message) (bool, error) {
 		return false, lindell17.ErrWrongRecipient
 	}
 
+	// Record inbound message.
+	if p.recorder != nil {
+		if err := p.recorder.RecordReceived(msg); err != nil {
+			return false, fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	// Trace inbound message.
+	p.tracer.Received(msg)
+
 	// Validate message.
-	if !msg.IsValid() {
-		return false, lindell17.ErrInvalidMessage
+	if err := msg.ValidateFor(p.curve, p.pk); err != nil {
+		return false, err
 	}
 
 	// Process message.
 	switch msg.MessageId() {
 	case 2:
-		return p.step2(msg.(*messages.Message2))
+		m, ok := msg.(*messages.Message2)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step2(m)
 	case 4:
-		return p.step3(msg.(*messages.Message4))
+		m, ok := msg.(*messages.Message4)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step3(m)
 	default:
 		return false, lindell17.ErrUnknownMessage
 	}
@@ -117,27 +171,27 @@ func (p *Party1) step1(sessionId string) (bool, error) {
 	}
 
 	// Sample random partial nonce k1.
-	k1, err := p.curve.GetRandomScalar()
+	k1, err := random.Scalar(p.rand, p.curve)
 	if err != nil {
-		return false, ErrSampleNonceK1
+		return false, fmt.Errorf("%w: %w", ErrSampleNonceK1, err)
 	}
 
 	// Compute R1.
 	r1, err := p.curve.ScalarMultiply(k1, p.curve.G()) // k1 * G
 	if err != nil {
-		return false, ErrComputeR1
+		return false, fmt.Errorf("%w: %w", ErrComputeR1, err)
 	}
 
 	// Commit to R1.
-	cR1, err := hash.Commit(r1.X.Bytes(), r1.Y.Bytes())
+	cR1, err := random.Commit(p.rand, r1.X.Bytes(), r1.Y.Bytes())
 	if err != nil {
-		return false, ErrCommitToR1
+		return false, fmt.Errorf("%w: %w", ErrCommitToR1, err)
 	}
 
 	// Generate R1 DLK proof.
-	pR1, err := proofs.GenerateDLKProof(p.curve, r1, k1)
+	pR1, err := random.DLKProof(p.rand, p.curve, r1, k1)
 	if err != nil {
-		return false, ErrGenerateR1DLKProof
+		return false, fmt.Errorf("%w: %w", ErrGenerateR1DLKProof, err)
 	}
 
 	// Store k1 and R1.
@@ -145,10 +199,12 @@ func (p *Party1) step1(sessionId string) (bool, error) {
 	p.r1 = r1
 
 	// Transition to next state.
-	p.state = lindell17.Step2
+	p.setState(lindell17.Step2)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage1(sessionId, cR1, pR1)
+	if err := p.send(messages.NewMessage1(sessionId, cR1, pR1)); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -166,7 +222,11 @@ func (p *Party1) step2(msg *messages.Message2) (bool, error) {
 
 	// Verify R2 DLK proof.
 	isValid, err := proofs.VerifyDLKProof(p.curve, msg.PR2, msg.R2)
-	if err != nil || !isValid {
+	p.tracer.Proof("R2 DLK proof", err == nil && isValid)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidR2DLKProof, err)
+	}
+	if !isValid {
 		return false, ErrInvalidR2DLKProof
 	}
 
@@ -177,10 +237,12 @@ func (p *Party1) step2(msg *messages.Message2) (bool, error) {
 	p.r2 = msg.R2
 
 	// Transition to next state.
-	p.state = lindell17.Step3
+	p.setState(lindell17.Step3)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage3(sid, r1)
+	if err := p.send(messages.NewMessage3(sid, r1)); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -199,7 +261,7 @@ func (p *Party1) step3(msg *messages.Message4) (bool, error) {
 	// Derive shared point R.
 	rP, err := p.curve.ScalarMultiply(p.k1, p.r2) // k1 * R2
 	if err != nil {
-		return false, ErrComputeR
+		return false, fmt.Errorf("%w: %w", ErrComputeR, err)
 	}
 
 	// Compute r.
@@ -211,43 +273,145 @@ func (p *Party1) step3(msg *messages.Message4) (bool, error) {
 	}
 
 	// Compute s.
-	sPrime, err := cipher.Decrypt(p.sk, msg.Ciphertext)
+	sPrime, err := p.decrypt(msg.Ciphertext)
 	if err != nil {
-		return false, ErrDecryptCiphertext
+		return false, fmt.Errorf("%w: %w", ErrDecryptCiphertext, err)
 	}
 
 	// Compute v.
-	v := new(big.Int).And(rP.Y, big.NewInt(1)) // R_y & 1
+	v := int(rP.Y.Bit(0)) // R_y & 1
 
-	// Turn decrypted ciphertext into big int.
-	in1 := new(big.Int).SetBytes(sPrime)              // s'
-	in2 := new(big.Int).ModInverse(p.k1, p.curve.N()) // k1^-1 mod q
-	in3 := new(big.Int).Mul(in1, in2)                 // s' * k1^-1
-	s1 := new(big.Int).Mod(in3, p.curve.N())          // s' * k1^-1 mod q
-	s2 := new(big.Int).Sub(p.curve.N(), s1)           // q - (s' * k1^-1 mod q)
+	// Compute secret-dependent values in constant time.
+	field := p.field
+	if field == nil {
+		return false, fmt.Errorf("%w: %w", ErrComputeS, scalar.ErrInvalidOrder)
+	}
 
-	// s = min(s1, s2).
-	// Ensures that s is always smaller than half of the curve.
-	s := s1
-	if s2.Cmp(s1) < 0 {
-		s = s2
+	// Turn decrypted ciphertext into scalar.
+	in1 := field.NewScalar().SetBytes(sPrime) // s' mod q
+	defer in1.Wipe()
+	utils.WipeBytes(sPrime)
 
-		// Invert v.
-		v = v.Xor(v, big.NewInt(1)) // v ^ 1
+	// Ensure that s' isn't 0 mod q (which would result in s = 0).
+	if in1.IsZero() == 1 {
+		return false, ErrZeroSPrime
 	}
 
+	// Invert k1.
+	k1 := field.NewScalar().SetInt(p.k1)
+	defer k1.Wipe()
+	if k1.IsZero() == 1 {
+		return false, ErrInvertNonceK1
+	}
+	in2 := field.NewScalar().Invert(k1) // k1^-1 mod q
+	defer in2.Wipe()
+
+	s1 := field.NewScalar().Mul(in1, in2) // s' * k1^-1 mod q
+
+	// s = min(s1, q - s1).
+	// Ensures that s is always smaller than half of the curve. v is inverted if
+	// s1 is negated. Both happen without branching on s1.
+	s := field.NewScalar()
+	v ^= s.SetLowS(s1)
+	s1.Wipe()
+
 	// Create signature.
-	signature := ecdsa.NewSignature(r, s, v)
+	signature := ecdsa.NewSignature(r, s.BigInt(), big.NewInt(int64(v)))
 
 	// Verify signature.
 	pk := (*keys.PublicKey)(p.qShared)
 	isValid, err := ecdsa.Verify(p.curve, pk, p.hash, signature)
-	if err != nil || !isValid {
+	p.tracer.Proof("signature", err == nil && isValid)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if !isValid {
 		return false, ErrInvalidSignature
 	}
 
 	// Send signature over result channel.
-	p.resCh <- NewResult(sid, signature)
+	if err := p.sendResult(NewResult(sid, signature)); err != nil {
+		return false, err
+	}
+
+	// Wipe k1.
+	p.Destroy()
 
 	return true, nil
 }
+
+// decrypt decrypts the ciphertext via the Chinese Remainder Theorem if the
+// decrypter is available and via the regular decryption otherwise. The
+// decryption time and the fallback are reported to the observer.
+// Returns an error if the ciphertext can't be decrypted.
+func (p *Party1) decrypt(ciphertext cipher.Ciphertext) (cipher.Plaintext, error) {
+	defer p.tracer.Operation("paillier_decryption", time.Now())
+
+	if p.decrypter != nil {
+		return p.decrypter.Decrypt(ciphertext)
+	}
+
+	p.tracer.Fallback("paillier_decryption", p.crtErr)
+
+	return cipher.Decrypt(p.sk, ciphertext)
+}
+
+// Destroy wipes party 1's ephemeral secrets, drops its references to the
+// long-term secrets and ends the protocol run, so that further calls fail with
+// lindell17.ErrInvalidState. Party 1 destroys itself once it sent its result
+// or a call failed, so Destroy only needs to be called to cancel a run.
+func (p *Party1) Destroy() {
+	utils.Wipe(p.k1)
+
+	p.k1 = nil
+	p.sk = nil
+	p.decrypter = nil
+	p.state = lindell17.Destroyed
+	p.tracer.Destroyed()
+}
+
+// destroyOnError destroys party 1 if the call failed, since failed protocol
+// runs can't be resumed.
+func (p *Party1) destroyOnError(err *error) {
+	if *err != nil {
+		p.Destroy()
+	}
+}
+
+// setState transitions to the state and reports the transition.
+func (p *Party1) setState(state lindell17.State) {
+	p.state = state
+	p.tracer.Transition(state)
+}
+
+// send records the outbound message if a recorder is set, reports it to the
+// observer and sends it via the message channel.
+// Returns an error if the message can't be recorded.
+func (p *Party1) send(msg lindell17.Message) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordSent(msg); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Sent(msg)
+	p.outCh <- msg
+
+	return nil
+}
+
+// sendResult records the result if a recorder is set, reports it to the
+// observer and sends it via the result channel.
+// Returns an error if the result can't be recorded.
+func (p *Party1) sendResult(res lindell17.Result) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordResult(res); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Result(res)
+	p.resCh <- res
+
+	return nil
+}
@@ -19,6 +19,8 @@ var (
 	ErrComputeR = fmt.Errorf("unable to compute R")
 	// ErrSampleP is returned if p can't be sampled.
 	ErrSampleP = fmt.Errorf("unable to sample random p")
+	// ErrInvertNonceK2 is returned if the nonce k2 can't be inverted.
+	ErrInvertNonceK2 = fmt.Errorf("unable to invert nonce k2")
 	// ErrComputeC1 is returned if c1 can't be computed.
 	ErrComputeC1 = fmt.Errorf("unable to compute c1")
 	// ErrInvalidGCD is returned if the GCD is invalid.
@@ -1,13 +1,16 @@
 package party2
 
 import (
-	"crypto/rand"
+	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/primefactor-io/commitment/pkg/hash"
 	"github.com/primefactor-io/ecc/pkg/proofs"
 	"github.com/primefactor-io/ecc/pkg/weierstrass"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/scalar"
 	"github.com/primefactor-io/lindell17/pkg/sign/messages"
 	"github.com/primefactor-io/lindell17/pkg/utils"
 	"github.com/primefactor-io/paillier/pkg/cipher"
@@ -17,38 +20,59 @@ import (
 
 // Party2 is an instance of party 2 that participates in the signing protocol.
 type Party2 struct {
-	curve weierstrass.Curve
-	pk    *keys.PublicKey
-	x1Enc cipher.Ciphertext
-	x2    *big.Int
-	hash  []byte
-	k2    *big.Int
-	cR1   *hash.Commitment
-	pR1   *proofs.DLKProof
-	state lindell17.State
-	outCh chan<- lindell17.Message
-	resCh chan<- lindell17.Result
+	curve    weierstrass.Curve
+	field    *scalar.Field
+	pk       *keys.PublicKey
+	x1Enc    cipher.Ciphertext
+	x2       *big.Int
+	hash     []byte
+	k2       *big.Int
+	cR1      *hash.Commitment
+	pR1      *proofs.DLKProof
+	limits   *lindell17.Limits
+	recorder lindell17.Recorder
+	tracer   *lindell17.Tracer
+	rand     io.Reader
+	policy   lindell17.Policy
+	keyID    string
+	payload  *lindell17.Payload
+	messages int
+	state    lindell17.State
+	outCh    chan<- lindell17.Message
+	resCh    chan<- lindell17.Result
 }
 
 // NewParty2 creates a new instance of party 2 that participates in the signing
 // protocol.
 func NewParty2(params *Params, hash []byte, outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) *Party2 {
 	return &Party2{
-		curve: params.curve,
-		pk:    params.pk,
-		x1Enc: params.x1Enc,
-		x2:    params.x2,
-		hash:  hash,
-		state: lindell17.Start,
-		outCh: outCh,
-		resCh: resCh,
+		curve:    params.curve,
+		field:    params.field,
+		pk:       params.pk,
+		x1Enc:    params.x1Enc,
+		x2:       params.x2,
+		hash:     hash,
+		limits:   params.limits,
+		recorder: params.recorder,
+		tracer:   lindell17.NewTracer(params.observer, lindell17.Sign, lindell17.Party2),
+		rand:     params.rand,
+		policy:   params.policy,
+		keyID:    params.keyID,
+		payload:  params.payload,
+		state:    lindell17.Start,
+		outCh:    outCh,
+		resCh:    resCh,
 	}
 }
 
 // Start starts party 2 of the protocol.
 // Returns an error if the current state is invalid, the hash has an invalid
-// length or starting the protocol fails.
-func (p *Party2) Start() (bool, error) {
+// length, the signing policy denies the request or starting the protocol
+// fails.
+func (p *Party2) Start() (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
 	// Validate state.
 	if p.state != lindell17.Start {
 		return false, lindell17.ErrInvalidState
@@ -59,16 +83,34 @@ func (p *Party2) Start() (bool, error) {
 		return false, ErrInvalidHashLength
 	}
 
+	// Enforce signing policy.
+	if p.policy != nil {
+		req := lindell17.NewSigningRequest(lindell17.Sign, p.keyID, p.hash, p.payload)
+		if err := lindell17.Enforce(p.policy, req); err != nil {
+			return false, err
+		}
+	}
+
 	// Transition to next state.
-	p.state = lindell17.Step1
+	p.setState(lindell17.Step1)
 
 	return true, nil
 }
 
 // Process processes an incoming protocol message.
-// Returns an error if the message was sent by the wrong sender, is invalid,
+// Returns an error if the session's message limit is exceeded, the message
+// can't be recorded or the message was sent by the wrong sender, is invalid,
 // unknown or not intended for the protocol / recipient.
-func (p *Party2) Process(msg lindell17.Message) (bool, error) {
+func (p *Party2) Process(msg lindell17.Message) (ok bool, err error) {
+	defer p.tracer.End(p.tracer.Begin(), &err)
+	defer p.destroyOnError(&err)
+
+	// Enforce the session's message limit.
+	p.messages++
+	if p.messages > p.limits.MaxMessages {
+		return false, lindell17.ErrTooManyMessages
+	}
+
 	// Check message protocol.
 	if msg.Protocol() != lindell17.Sign {
 		return false, lindell17.ErrWrongProtocol
@@ -84,17 +126,35 @@ func (p *Party2) Process(msg lindell17.Message) (bool, error) {
 		return false, lindell17.ErrWrongRecipient
 	}
 
+	// Record inbound message.
+	if p.recorder != nil {
+		if err := p.recorder.RecordReceived(msg); err != nil {
+			return false, fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	// Trace inbound message.
+	p.tracer.Received(msg)
+
 	// Validate message.
-	if !msg.IsValid() {
-		return false, lindell17.ErrInvalidMessage
+	if err := msg.ValidateFor(p.curve, p.pk); err != nil {
+		return false, err
 	}
 
 	// Process message.
 	switch msg.MessageId() {
 	case 1:
-		return p.step1(msg.(*messages.Message1))
+		m, ok := msg.(*messages.Message1)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step1(m)
 	case 3:
-		return p.step2(msg.(*messages.Message3))
+		m, ok := msg.(*messages.Message3)
+		if !ok {
+			return false, lindell17.ErrInvalidMessage
+		}
+		return p.step2(m)
 	default:
 		return false, lindell17.ErrUnknownMessage
 	}
@@ -112,21 +172,21 @@ func (p *Party2) step1(msg *messages.Message1) (bool, error) {
 	}
 
 	// Sample random partial nonce k2.
-	k2, err := p.curve.GetRandomScalar()
+	k2, err := random.Scalar(p.rand, p.curve)
 	if err != nil {
-		return false, ErrSampleNonceK2
+		return false, fmt.Errorf("%w: %w", ErrSampleNonceK2, err)
 	}
 
 	// Compute R2.
 	r2, err := p.curve.ScalarMultiply(k2, p.curve.G()) // k2 * G
 	if err != nil {
-		return false, ErrComputeR2
+		return false, fmt.Errorf("%w: %w", ErrComputeR2, err)
 	}
 
 	// Generate R2 DLK proof.
-	pR2, err := proofs.GenerateDLKProof(p.curve, r2, k2)
+	pR2, err := random.DLKProof(p.rand, p.curve, r2, k2)
 	if err != nil {
-		return false, ErrGenerateR2DLKProof
+		return false, fmt.Errorf("%w: %w", ErrGenerateR2DLKProof, err)
 	}
 
 	// Store commitment to R1 and R1 DLK proof.
@@ -137,10 +197,12 @@ func (p *Party2) step1(msg *messages.Message1) (bool, error) {
 	p.k2 = k2
 
 	// Transition to next state.
-	p.state = lindell17.Step2
+	p.setState(lindell17.Step2)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage2(sid, r2, pR2)
+	if err := p.send(messages.NewMessage2(sid, r2, pR2)); err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
@@ -158,20 +220,25 @@ func (p *Party2) step2(msg *messages.Message3) (bool, error) {
 
 	// Verify commitment to R1.
 	isValid := hash.Verify(p.cR1, msg.R1.X.Bytes(), msg.R1.Y.Bytes())
+	p.tracer.Proof("R1 commitment", isValid)
 	if !isValid {
 		return false, ErrInvalidR1Commitment
 	}
 
 	// Verify R1 DLK proof.
 	isValid, err := proofs.VerifyDLKProof(p.curve, p.pR1, msg.R1)
-	if err != nil || !isValid {
+	p.tracer.Proof("R1 DLK proof", err == nil && isValid)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrInvalidR1DLKProof, err)
+	}
+	if !isValid {
 		return false, ErrInvalidR1DLKProof
 	}
 
 	// Derive shared point R.
 	rP, err := p.curve.ScalarMultiply(p.k2, msg.R1) // k2 * R1
 	if err != nil {
-		return false, ErrComputeR
+		return false, fmt.Errorf("%w: %w", ErrComputeR, err)
 	}
 
 	// Compute r.
@@ -179,25 +246,40 @@ func (p *Party2) step2(msg *messages.Message3) (bool, error) {
 
 	// Sample random p from Z_q^2.
 	qq := new(big.Int).Mul(p.curve.N(), p.curve.N()) // q^2
-	randP, err := rand.Int(rand.Reader, qq)
+	randP, err := random.Int(p.rand, qq)
 	if err != nil {
-		return false, ErrSampleP
+		return false, fmt.Errorf("%w: %w", ErrSampleP, err)
 	}
 
 	// Turn hash into big integer.
 	z := new(big.Int).SetBytes(p.hash)
 
+	// Compute secret-dependent values in constant time.
+	field := p.field
+	if field == nil {
+		return false, fmt.Errorf("%w: %w", ErrComputeC1, scalar.ErrInvalidOrder)
+	}
+
 	// Invert k2.
-	k2Inv := new(big.Int).ModInverse(p.k2, p.curve.N()) // k2^-1 mod q
+	k2 := field.NewScalar().SetInt(p.k2)
+	defer k2.Wipe()
+	if k2.IsZero() == 1 {
+		return false, ErrInvertNonceK2
+	}
+	k2Inv := field.NewScalar().Invert(k2) // k2^-1 mod q
+	defer k2Inv.Wipe()
 
 	// Compute c1.
-	in1 := new(big.Int).Mul(z, k2Inv)           // z * k2^-1
-	in2 := new(big.Int).Mod(in1, p.curve.N())   // z * k2^-1 mod q
+	in1 := field.NewScalar().Mul(field.NewScalar().SetInt(z), k2Inv) // z * k2^-1 mod q
+	defer in1.Wipe()
+	// The Paillier encryption needs big integers.
+	in2 := in1.BigInt()
 	in3 := new(big.Int).Mul(randP, p.curve.N()) // p * q
 	res := new(big.Int).Add(in2, in3)           // (z * k2^-1 mod q) + (p * q)
-	c1, nonce, err := cipher.EncryptAndReturnNonce(p.pk, res.Bytes())
+	defer utils.Wipe(randP, in2, in3, res)
+	c1, nonce, err := random.Encrypt(p.rand, p.pk, res.Bytes())
 	if err != nil {
-		return false, ErrComputeC1
+		return false, fmt.Errorf("%w: %w", ErrComputeC1, err)
 	}
 
 	// Ensure that gcd(nonce, N) = 1.
@@ -205,26 +287,99 @@ func (p *Party2) step2(msg *messages.Message3) (bool, error) {
 	if gcd.Cmp(big.NewInt(1)) != 0 {
 		return false, ErrInvalidGCD
 	}
+	defer utils.Wipe(nonce)
 
 	// Compute v.
-	in4 := new(big.Int).Mul(r, k2Inv)       // r * k2^-1
-	in5 := new(big.Int).Mul(in4, p.x2)      // r * k2^-1 * x2
-	v := new(big.Int).Mod(in5, p.curve.N()) // r * k2^-1 * x2 mod q
+	x2 := field.NewScalar().SetInt(p.x2)
+	defer x2.Wipe()
+	in4 := field.NewScalar().Mul(field.NewScalar().SetInt(r), k2Inv) // r * k2^-1 mod q
+	in5 := field.NewScalar().Mul(in4, x2)                            // r * k2^-1 * x2 mod q
+	defer in4.Wipe()
+	defer in5.Wipe()
+	// The homomorphic multiplication needs a big integer.
+	v := in5.BigInt()
+	defer utils.Wipe(v)
 
 	// Compute c2.
 	c2, err := homomorphic.MultiplyPlaintextValue(p.pk, p.x1Enc, v.Bytes())
 	if err != nil {
-		return false, ErrComputeC2
+		return false, fmt.Errorf("%w: %w", ErrComputeC2, err)
 	}
 
 	// Compute c3.
 	c3 := homomorphic.AddPlaintextValues(p.pk, c1, c2)
 
 	// Send outbound message.
-	p.outCh <- messages.NewMessage4(sid, r, c3)
+	if err := p.send(messages.NewMessage4(sid, r, c3)); err != nil {
+		return false, err
+	}
 
 	// Send partial signature over result channel.
-	p.resCh <- NewResult(sid, r, c3)
+	if err := p.sendResult(NewResult(sid, r, c3)); err != nil {
+		return false, err
+	}
+
+	// Wipe k2.
+	p.Destroy()
 
 	return true, nil
 }
+
+// Destroy wipes party 2's ephemeral secrets, drops its references to the
+// long-term secrets and ends the protocol run, so that further calls fail with
+// lindell17.ErrInvalidState. Party 2 destroys itself once it sent its result
+// or a call failed, so Destroy only needs to be called to cancel a run.
+func (p *Party2) Destroy() {
+	utils.Wipe(p.k2)
+
+	p.k2 = nil
+	p.x2 = nil
+	p.state = lindell17.Destroyed
+	p.tracer.Destroyed()
+}
+
+// destroyOnError destroys party 2 if the call failed, since failed protocol
+// runs can't be resumed.
+func (p *Party2) destroyOnError(err *error) {
+	if *err != nil {
+		p.Destroy()
+	}
+}
+
+// setState transitions to the state and reports the transition.
+func (p *Party2) setState(state lindell17.State) {
+	p.state = state
+	p.tracer.Transition(state)
+}
+
+// send records the outbound message if a recorder is set, reports it to the
+// observer and sends it via the message channel.
+// Returns an error if the message can't be recorded.
+func (p *Party2) send(msg lindell17.Message) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordSent(msg); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Sent(msg)
+	p.outCh <- msg
+
+	return nil
+}
+
+// sendResult records the result if a recorder is set, reports it to the
+// observer and sends it via the result channel.
+// Returns an error if the result can't be recorded.
+func (p *Party2) sendResult(res lindell17.Result) error {
+	if p.recorder != nil {
+		if err := p.recorder.RecordResult(res); err != nil {
+			return fmt.Errorf("%w: %w", lindell17.ErrRecordTranscript, err)
+		}
+	}
+
+	p.tracer.Result(res)
+	p.resCh <- res
+
+	return nil
+}
@@ -3,7 +3,9 @@ package sign_test
 import (
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -12,7 +14,14 @@ import (
 	"github.com/primefactor-io/ecc/pkg/ecdsa"
 	"github.com/primefactor-io/ecc/pkg/elliptic"
 	"github.com/primefactor-io/ecc/pkg/keys"
+	"github.com/primefactor-io/ecc/pkg/weierstrass"
+	"github.com/primefactor-io/lindell17/pkg/crt"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/policy"
+	"github.com/primefactor-io/lindell17/pkg/profile"
+	"github.com/primefactor-io/lindell17/pkg/sign/messages"
 	"github.com/primefactor-io/lindell17/pkg/sign/party1"
 	"github.com/primefactor-io/lindell17/pkg/sign/party2"
 	"github.com/primefactor-io/paillier/pkg/cipher"
@@ -21,7 +30,19 @@ import (
 
 var p1Params *party1.Params
 var p2Params *party2.Params
+var p2AllowedParams *party2.Params
+var p2DeniedParams *party2.Params
+var p2LimitedParams *party2.Params
+var p2NilLimitsParams *party2.Params
+var p1ObservedParams *party1.Params
+var p2ObservedParams *party2.Params
+var observed *collector
+var p1FallbackParams *party1.Params
+var fallbackObserved *collector
 var qShared *elliptic.Point
+var paillierPk *pKeys.PublicKey
+var keyMaterial1 *kParty1.KeyMaterial
+var keyMaterial2 *kParty2.KeyMaterial
 
 var secp256k1 = curves.Secp256k1
 
@@ -30,6 +51,7 @@ func TestMain(m *testing.M) {
 	q3 := new(big.Int).Div(secp256k1.N(), big.NewInt(3)) // q / 3
 
 	sk, pk, _ := pKeys.GenerateKeys(1024)
+	paillierPk = pk
 
 	x1, _ := secp256k1.GetRandomScalar(q3)
 	x1Enc, _ := cipher.Encrypt(pk, x1.Bytes())
@@ -42,6 +64,32 @@ func TestMain(m *testing.M) {
 
 	p1Params = party1.NewParams(secp256k1, sk, qShared)
 	p2Params = party2.NewParams(secp256k1, pk, x1Enc, x2)
+	keyMaterial1 = kParty1.NewKeyMaterial(x1, sk, pk, qShared)
+	keyMaterial2 = kParty2.NewKeyMaterial(x1Enc, x2, pk, qShared)
+
+	engine := policy.NewEngine(policy.Destinations("bc1qallowed"))
+	p2AllowedParams = party2.NewParams(secp256k1, pk, x1Enc, x2).
+		WithPolicy(engine, "key").
+		WithPayload(&lindell17.Payload{Destination: "bc1qallowed"})
+	p2DeniedParams = party2.NewParams(secp256k1, pk, x1Enc, x2).
+		WithPolicy(engine, "key").
+		WithPayload(&lindell17.Payload{Destination: "bc1qunknown"})
+
+	limits := lindell17.NewLimits(2, lindell17.DefaultMaxPaillierBits)
+	p2LimitedParams = party2.NewParams(secp256k1, pk, x1Enc, x2).WithLimits(limits)
+	p2NilLimitsParams = party2.NewParams(secp256k1, pk, x1Enc, x2).WithLimits(nil)
+
+	observed = new(collector)
+	p1ObservedParams = party1.NewParams(secp256k1, sk, qShared).WithObserver(observed)
+	p2ObservedParams = party2.NewParams(secp256k1, pk, x1Enc, x2).WithObserver(observed)
+
+	// A multiple of phi(N) still decrypts, but doesn't factor N, so party 1
+	// can't use the CRT decryption.
+	phiN := new(big.Int).Lsh(sk.PhiN, 1)      // 2 * phi(N)
+	mu := new(big.Int).ModInverse(phiN, sk.N) // (2 * phi(N))^-1 mod N
+	fallbackSk := pKeys.NewPrivateKey(sk.N, phiN, mu, sk.NN)
+	fallbackObserved = new(collector)
+	p1FallbackParams = party1.NewParams(secp256k1, fallbackSk, qShared).WithObserver(fallbackObserved)
 
 	m.Run()
 }
@@ -281,6 +329,483 @@ func TestSignVerify(t *testing.T) {
 		}
 	})
 
+	t.Run("Sign - Invalid (Ciphertext out of range)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if msg.MessageId() == 4 {
+						// Parse old message.
+						msg := msg.(*messages.Message4)
+
+						sid := msg.SessionId()
+						r := msg.R
+
+						// Compute ciphertext that exceeds N^2.
+						ciphertext := new(big.Int).Add(paillierPk.NN, big.NewInt(1)).Bytes()
+
+						// Replace existing message.
+						msg = messages.NewMessage4(sid, r, ciphertext)
+
+						// Inject faulty message.
+						if _, err := p1.Process(msg); err != nil {
+							errCh <- err
+						}
+
+						break
+					}
+
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, lindell17.ErrInvalidMessage) {
+					t.Fatalf("want error %v, got %v", lindell17.ErrInvalidMessage, err)
+				}
+				if !errors.Is(err, lindell17.ErrCiphertextOutOfRange) {
+					t.Fatalf("want error %v, got %v", lindell17.ErrCiphertextOutOfRange, err)
+				}
+
+				break coord
+			case <-resCh:
+			}
+		}
+	})
+
+	t.Run("Sign - Invalid (Ciphertext not a unit)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if msg.MessageId() == 4 {
+						// Parse old message.
+						msg := msg.(*messages.Message4)
+
+						sid := msg.SessionId()
+						r := msg.R
+
+						// Use N as ciphertext which isn't a unit in Z*_{N^2}.
+						ciphertext := paillierPk.N.Bytes()
+
+						// Replace existing message.
+						msg = messages.NewMessage4(sid, r, ciphertext)
+
+						// Inject faulty message.
+						if _, err := p1.Process(msg); err != nil {
+							errCh <- err
+						}
+
+						break
+					}
+
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, lindell17.ErrInvalidMessage) {
+					t.Fatalf("want error %v, got %v", lindell17.ErrInvalidMessage, err)
+				}
+				if !errors.Is(err, lindell17.ErrCiphertextNotUnit) {
+					t.Fatalf("want error %v, got %v", lindell17.ErrCiphertextNotUnit, err)
+				}
+
+				break coord
+			case <-resCh:
+			}
+		}
+	})
+
+	t.Run("Sign - Invalid (s' = 0 mod q)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if msg.MessageId() == 4 {
+						// Parse old message.
+						msg := msg.(*messages.Message4)
+
+						sid := msg.SessionId()
+						r := msg.R
+
+						// Encrypt q so that s' = 0 mod q.
+						ciphertext, _ := cipher.Encrypt(paillierPk, secp256k1.N().Bytes())
+
+						// Replace existing message.
+						msg = messages.NewMessage4(sid, r, ciphertext)
+
+						// Inject faulty message.
+						if _, err := p1.Process(msg); err != nil {
+							errCh <- err
+						}
+
+						break
+					}
+
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, party1.ErrZeroSPrime) {
+					t.Fatalf("want error %v, got %v", party1.ErrZeroSPrime, err)
+				}
+
+				break coord
+			case <-resCh:
+			}
+		}
+	})
+
+	t.Run("Sign - Invalid (R out of range)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if msg.MessageId() == 4 {
+						// Parse old message.
+						msg := msg.(*messages.Message4)
+
+						sid := msg.SessionId()
+						ciphertext := msg.Ciphertext
+
+						// Use r = n which isn't a valid scalar.
+						r := secp256k1.N()
+
+						// Replace existing message.
+						msg = messages.NewMessage4(sid, r, ciphertext)
+
+						// Inject faulty message.
+						if _, err := p1.Process(msg); err != nil {
+							errCh <- err
+						}
+
+						break
+					}
+
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, lindell17.ErrInvalidMessage) {
+					t.Fatalf("want error %v, got %v", lindell17.ErrInvalidMessage, err)
+				}
+				if !errors.Is(err, lindell17.ErrScalarOutOfRange) {
+					t.Fatalf("want error %v, got %v", lindell17.ErrScalarOutOfRange, err)
+				}
+
+				break coord
+			case <-resCh:
+			}
+		}
+	})
+
+	t.Run("Sign - Invalid (Session id format)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if msg.MessageId() == 4 {
+						// Parse old message.
+						msg := msg.(*messages.Message4)
+
+						r := msg.R
+						ciphertext := msg.Ciphertext
+
+						// Use a session id that doesn't have the expected format.
+						sid := strings.ToUpper(msg.SessionId())
+
+						// Replace existing message.
+						msg = messages.NewMessage4(sid, r, ciphertext)
+
+						// Inject faulty message.
+						if _, err := p1.Process(msg); err != nil {
+							errCh <- err
+						}
+
+						break
+					}
+
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, lindell17.ErrInvalidMessage) {
+					t.Fatalf("want error %v, got %v", lindell17.ErrInvalidMessage, err)
+				}
+
+				break coord
+			case <-resCh:
+			}
+		}
+	})
+
+	t.Run("Sign - Invalid (R2 not on curve)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if msg.MessageId() == 2 {
+						// Parse old message.
+						msg := msg.(*messages.Message2)
+
+						sid := msg.SessionId()
+						pR2 := msg.PR2
+
+						// Use a point that isn't on the curve.
+						r2 := elliptic.NewPoint(big.NewInt(1), big.NewInt(1))
+
+						// Replace existing message.
+						msg = messages.NewMessage2(sid, r2, pR2)
+
+						// Inject faulty message.
+						if _, err := p1.Process(msg); err != nil {
+							errCh <- err
+						}
+
+						break
+					}
+
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, lindell17.ErrInvalidPoint) {
+					t.Fatalf("want error %v, got %v", lindell17.ErrInvalidPoint, err)
+				}
+
+				break coord
+			}
+		}
+	})
+
+	t.Run("Party2 - Process - Invalid (Too many messages)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 1)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2LimitedParams, hash, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if _, err := p2.Start(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		msg := <-outCh
+
+		// Replay the same message until the limit is exceeded.
+		var err error
+		for range 3 {
+			_, err = p2.Process(msg)
+		}
+
+		if !errors.Is(err, lindell17.ErrTooManyMessages) {
+			t.Errorf("want error %v, got %v", lindell17.ErrTooManyMessages, err)
+		}
+	})
+
+	t.Run("Party2 - Process (Nil limits)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 1)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2NilLimitsParams, hash, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if _, err := p2.Start(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// Party 2 falls back to the default limits.
+		if _, err := p2.Process(<-outCh); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
 	t.Run("Party1 - Start - Invalid (Hash length)", func(t *testing.T) {
 		t.Parallel()
 
@@ -324,6 +849,304 @@ func TestSignVerify(t *testing.T) {
 			t.Errorf("want error %v, got %v", party2.ErrInvalidHashLength, err)
 		}
 	})
+
+	t.Run("Party1 - Start - Invalid (Destroyed)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p1.Destroy()
+
+		_, err := p1.Start()
+
+		if !errors.Is(err, lindell17.ErrInvalidState) {
+			t.Errorf("want error %v, got %v", lindell17.ErrInvalidState, err)
+		}
+	})
+
+	t.Run("Party2 - Start - Invalid (Failed run)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		// Add one additional byte.
+		hash = append(hash, 0x1)
+
+		p2 := party2.NewParty2(p2Params, hash, outCh, resCh)
+
+		// The failed call destroys party 2, so the run can't be retried.
+		p2.Start()
+		_, err := p2.Start()
+
+		if !errors.Is(err, lindell17.ErrInvalidState) {
+			t.Errorf("want error %v, got %v", lindell17.ErrInvalidState, err)
+		}
+	})
+
+	t.Run("Party2 - Start (Policy allowed)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p2 := party2.NewParty2(p2AllowedParams, hash, outCh, resCh)
+
+		_, err := p2.Start()
+
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Party2 - Start - Invalid (Policy denied)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 1)
+		resCh := make(chan lindell17.Result, 1)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p2 := party2.NewParty2(p2DeniedParams, hash, outCh, resCh)
+
+		_, err := p2.Start()
+
+		if !errors.Is(err, lindell17.ErrPolicyDenied) {
+			t.Errorf("want error %v, got %v", lindell17.ErrPolicyDenied, err)
+		}
+		if len(outCh) != 0 {
+			t.Error("expected no message to be sent")
+		}
+	})
+
+	t.Run("Sign (CRT fallback)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1FallbackParams, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := p2.Start(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		var signature *ecdsa.Signature
+		for signature == nil {
+			select {
+			case msg := <-outCh:
+				var err error
+				switch msg.To() {
+				case lindell17.Party1:
+					_, err = p1.Process(msg)
+				case lindell17.Party2:
+					_, err = p2.Process(msg)
+				}
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			case res := <-resCh:
+				if res, ok := res.(*party1.Result); ok {
+					signature = res.Signature
+				}
+			}
+		}
+
+		pk := (*keys.PublicKey)(qShared)
+		if isValid, _ := ecdsa.Verify(secp256k1, pk, hash, signature); !isValid {
+			t.Error("Signature is invalid")
+		}
+
+		var fallbacks int
+		for _, e := range fallbackObserved.list() {
+			if e.Kind != lindell17.EventFallback {
+				continue
+			}
+			fallbacks++
+			if e.Operation != "paillier_decryption" {
+				t.Errorf("want operation paillier_decryption, got %s", e.Operation)
+			}
+			if !errors.Is(e.Err, crt.ErrFactorModulus) {
+				t.Errorf("want error %v, got %v", crt.ErrFactorModulus, e.Err)
+			}
+		}
+		if fallbacks != 1 {
+			t.Errorf("want 1 fallback, got %d", fallbacks)
+		}
+	})
+
+	t.Run("Sign (Observed)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1ObservedParams, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2ObservedParams, hash, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := p2.Start(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		var first lindell17.Message
+		for len(resCh) < 2 {
+			msg := <-outCh
+			var err error
+			switch msg.To() {
+			case lindell17.Party1:
+				_, err = p1.Process(msg)
+			case lindell17.Party2:
+				_, err = p2.Process(msg)
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if first == nil {
+				first = msg
+			}
+		}
+
+		// Replaying the first message fails.
+		if _, err := p2.Process(first); !errors.Is(err, lindell17.ErrInvalidState) {
+			t.Errorf("want error %v, got %v", lindell17.ErrInvalidState, err)
+		}
+
+		events := observed.list()
+		sid := first.SessionId()
+
+		var trace []string
+		for _, e := range events {
+			if e.SessionId != "" && e.SessionId != sid {
+				t.Errorf("want session id %s, got %s", sid, e.SessionId)
+			}
+			if e.Protocol != lindell17.Sign {
+				t.Errorf("want protocol %v, got %v", lindell17.Sign, e.Protocol)
+			}
+
+			var entry string
+			switch e.Kind {
+			case lindell17.EventTransition:
+				entry = fmt.Sprintf("%v %v", e.Entity, e.State)
+			case lindell17.EventSent, lindell17.EventReceived:
+				entry = fmt.Sprintf("%v %v %d", e.Entity, e.Kind, e.MessageId)
+			case lindell17.EventProof:
+				entry = fmt.Sprintf("%v %s %v", e.Entity, e.Proof, e.Valid)
+			case lindell17.EventStep:
+				entry = fmt.Sprintf("%v step in %v", e.Entity, e.State)
+			case lindell17.EventOperation:
+				entry = fmt.Sprintf("%v %s", e.Entity, e.Operation)
+			case lindell17.EventError:
+				entry = fmt.Sprintf("%v error", e.Entity)
+			default:
+				entry = fmt.Sprintf("%v %v", e.Entity, e.Kind)
+			}
+			trace = append(trace, entry)
+		}
+
+		want := []string{
+			"party1 step1",
+			"party1 step2",
+			"party1 sent 1",
+			"party1 step in start",
+			"party2 step1",
+			"party2 step in start",
+			"party2 received 1",
+			"party2 step2",
+			"party2 sent 2",
+			"party2 step in step1",
+			"party1 received 2",
+			"party1 R2 DLK proof true",
+			"party1 step3",
+			"party1 sent 3",
+			"party1 step in step2",
+			"party2 received 3",
+			"party2 R1 commitment true",
+			"party2 R1 DLK proof true",
+			"party2 sent 4",
+			"party2 result",
+			"party2 step in step2",
+			"party2 destroyed",
+			"party1 received 4",
+			"party1 paillier_decryption",
+			"party1 signature true",
+			"party1 result",
+			"party1 step in step3",
+			"party1 destroyed",
+			"party2 received 1",
+			"party2 error",
+			"party2 step in step2",
+			"party2 destroyed",
+		}
+		if strings.Join(trace, "\n") != strings.Join(want, "\n") {
+			t.Errorf("want events\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(trace, "\n"))
+		}
+	})
+
+	t.Run("Process - Invalid (Foreign message type)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 4)
+
+		message := []byte("Hello World")
+		checksum := sha256.Sum256(message)
+		hash := checksum[:]
+
+		p1 := party1.NewParty1(p1Params, hash, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, hash, outCh, resCh)
+
+		tests := []struct {
+			party lindell17.Participant
+			msg   *foreignMessage
+			want  error
+		}{
+			{p1, &foreignMessage{lindell17.Sign, lindell17.Party2, lindell17.Party1, 2}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.Sign, lindell17.Party2, lindell17.Party1, 4}, lindell17.ErrInvalidMessage},
+			{p1, &foreignMessage{lindell17.Sign, lindell17.Party2, lindell17.Party1, 5}, lindell17.ErrUnknownMessage},
+			{p2, &foreignMessage{lindell17.Sign, lindell17.Party1, lindell17.Party2, 1}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.Sign, lindell17.Party1, lindell17.Party2, 3}, lindell17.ErrInvalidMessage},
+			{p2, &foreignMessage{lindell17.Sign, lindell17.Party1, lindell17.Party2, 5}, lindell17.ErrUnknownMessage},
+		}
+
+		for _, tt := range tests {
+			_, err := tt.party.Process(tt.msg)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("message id %d: want error %v, got %v", tt.msg.messageId, tt.want, err)
+			}
+		}
+	})
 }
 
 type container struct {
@@ -347,3 +1170,114 @@ func (c *container) addParty2ResultData(r *big.Int, ciphertext cipher.Ciphertext
 	c.r = r
 	c.ciphertext = ciphertext
 }
+
+func FuzzProcessParty1(f *testing.F) {
+	checksum := sha256.Sum256([]byte("Hello World"))
+
+	profile.Fuzz(f, lindell17.Party1, profile.Sign(secp256k1, keyMaterial1, keyMaterial2, checksum[:])...)
+}
+
+func FuzzProcessParty2(f *testing.F) {
+	checksum := sha256.Sum256([]byte("Hello World"))
+
+	profile.Fuzz(f, lindell17.Party2, profile.Sign(secp256k1, keyMaterial1, keyMaterial2, checksum[:])...)
+}
+
+// benchmarkPaillierBits are the Paillier key sizes the steps are benchmarked with.
+var benchmarkPaillierBits = []int{1024, 2048, 3072}
+
+func BenchmarkSign(b *testing.B) {
+	for _, bits := range benchmarkPaillierBits {
+		b.Run(fmt.Sprintf("%d", bits), func(b *testing.B) {
+			km1, km2 := benchmarkKeyMaterial(b, bits)
+
+			checksum := sha256.Sum256([]byte("Hello World"))
+
+			prof, err := profile.Run(profile.Sign(secp256k1, km1, km2, checksum[:])...)
+			if err != nil {
+				b.Fatalf("expected no error, got %v", err)
+			}
+
+			for _, step := range prof.Steps {
+				b.Run(step.Name(), func(b *testing.B) {
+					prof.Benchmark(b, step)
+				})
+			}
+		})
+	}
+}
+
+// benchmarkKeyMaterial runs key generation with a Paillier key of the given
+// size.
+func benchmarkKeyMaterial(b *testing.B, bits int) (*kParty1.KeyMaterial, *kParty2.KeyMaterial) {
+	b.Helper()
+
+	prof, err := profile.Run(profile.Keygen(secp256k1, 40, 128, bits)...)
+	if err != nil {
+		b.Fatalf("expected no error, got %v", err)
+	}
+
+	km1, km2, err := prof.KeyMaterial()
+	if err != nil {
+		b.Fatalf("expected no error, got %v", err)
+	}
+
+	return km1, km2
+}
+
+// collector is an observer that collects the events.
+type collector struct {
+	mu     sync.Mutex
+	events []lindell17.Event
+}
+
+func (c *collector) Observe(event *lindell17.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.events = append(c.events, *event)
+}
+
+func (c *collector) list() []lindell17.Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]lindell17.Event(nil), c.events...)
+}
+
+// foreignMessage is a message type that isn't part of the protocol but
+// implements the message interface.
+type foreignMessage struct {
+	protocol  lindell17.Protocol
+	from      lindell17.Entity
+	to        lindell17.Entity
+	messageId int
+}
+
+func (m *foreignMessage) To() lindell17.Entity {
+	return m.to
+}
+
+func (m *foreignMessage) From() lindell17.Entity {
+	return m.from
+}
+
+func (m *foreignMessage) Protocol() lindell17.Protocol {
+	return m.protocol
+}
+
+func (m *foreignMessage) MessageId() int {
+	return m.messageId
+}
+
+func (m *foreignMessage) SessionId() string {
+	return strings.Repeat("0", lindell17.SessionIdLength)
+}
+
+func (m *foreignMessage) IsValid() bool {
+	return true
+}
+
+func (m *foreignMessage) ValidateFor(curve weierstrass.Curve, pk *pKeys.PublicKey) error {
+	return nil
+}
@@ -0,0 +1,24 @@
+/*
+Package transcript implements append-only protocol transcripts that can be used
+for audits and incident response.
+
+A Recorder records every message a party sends and receives as well as the
+party's result. Every entry contains a timestamp, a SHA-256 digest of the
+encoded message or result and a hash that chains it to the previous entry, so
+that modifications of a transcript can be detected.
+
+Replay feeds the received messages of a transcript into a fresh party and
+reports where the party's behavior diverges from the recorded one. Messages
+and results only match bit for bit if the party is given the same secret
+inputs and a source of randomness (see the parties' WithRandomness) that
+provides the same bytes as the one it used when the transcript was recorded,
+e.g. a reader with the same seed (see random.NewSeededReader). Every random
+input of a party, including its commitments' and proofs' nonces and its
+Paillier key, is drawn from that source. Replaying a party that drew its
+randomness from crypto/rand reports a divergence for every message it sent and
+may fail once it receives messages that depend on the ones it sent before.
+
+Note that a transcript contains the party's result which, depending on the
+protocol, includes secret key material.
+*/
+package transcript
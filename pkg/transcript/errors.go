@@ -0,0 +1,16 @@
+package transcript
+
+import "fmt"
+
+var (
+	// ErrInvalidTranscript is returned if the transcript is malformed or was modified.
+	ErrInvalidTranscript = fmt.Errorf("invalid transcript")
+	// ErrEncodeEntry is returned if a message or result can't be encoded.
+	ErrEncodeEntry = fmt.Errorf("unable to encode entry")
+	// ErrDecodeEntry is returned if a message can't be decoded.
+	ErrDecodeEntry = fmt.Errorf("unable to decode entry")
+	// ErrWriteTranscript is returned if the transcript can't be written.
+	ErrWriteTranscript = fmt.Errorf("unable to write transcript")
+	// ErrReadTranscript is returned if the transcript can't be read.
+	ErrReadTranscript = fmt.Errorf("unable to read transcript")
+)
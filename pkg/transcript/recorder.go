@@ -0,0 +1,106 @@
+package transcript
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// Recorder is an instance of a recorder that records a party's transcript.
+// It's safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	entity  lindell17.Entity
+	entries []*Entry
+}
+
+// NewRecorder creates a new instance of a recorder that records the transcript
+// of the given party.
+func NewRecorder(entity lindell17.Entity) *Recorder {
+	return &Recorder{
+		entity: entity,
+	}
+}
+
+// RecordReceived records a message the party received.
+// Returns an error if the message can't be encoded.
+func (r *Recorder) RecordReceived(msg lindell17.Message) error {
+	return r.recordMessage(Received, msg)
+}
+
+// RecordSent records a message the party sent.
+// Returns an error if the message can't be encoded.
+func (r *Recorder) RecordSent(msg lindell17.Message) error {
+	return r.recordMessage(Sent, msg)
+}
+
+// RecordResult records the party's result.
+// Returns an error if the result can't be encoded.
+func (r *Recorder) RecordResult(res lindell17.Result) error {
+	data, err := codec.MarshalResult(res)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrEncodeEntry, err)
+	}
+
+	r.append(Output, res.Protocol(), 0, res.SessionId(), data)
+
+	return nil
+}
+
+// Transcript returns a copy of the transcript that was recorded so far.
+func (r *Recorder) Transcript() *Transcript {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]*Entry, len(r.entries))
+	for i, entry := range r.entries {
+		e := *entry
+		entries[i] = &e
+	}
+
+	return &Transcript{
+		Entity:  r.entity,
+		Entries: entries,
+	}
+}
+
+// recordMessage records a message of the given kind.
+// Returns an error if the message can't be encoded.
+func (r *Recorder) recordMessage(kind Kind, msg lindell17.Message) error {
+	data, err := codec.MarshalMessage(msg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrEncodeEntry, err)
+	}
+
+	r.append(kind, msg.Protocol(), msg.MessageId(), msg.SessionId(), data)
+
+	return nil
+}
+
+// append appends a new entry to the transcript.
+func (r *Recorder) append(kind Kind, protocol lindell17.Protocol, messageId int, sid string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := &Entry{
+		Seq:       len(r.entries),
+		Time:      time.Now().UTC(),
+		Kind:      kind,
+		Protocol:  protocol,
+		MessageId: messageId,
+		SessionId: sid,
+		Digest:    digest(data),
+		Data:      data,
+	}
+
+	prev := ""
+	if len(r.entries) > 0 {
+		prev = r.entries[len(r.entries)-1].Chain
+	}
+	entry.Chain = chain(prev, entry)
+
+	r.entries = append(r.entries, entry)
+}
@@ -0,0 +1,121 @@
+package transcript
+
+import (
+	"fmt"
+
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// Divergence describes a recorded entry the replay didn't reproduce.
+type Divergence struct {
+	// Seq is the sequence number of the recorded entry.
+	Seq int
+	// Kind is the kind of the recorded entry.
+	Kind Kind
+	// Want is the digest of the recorded data.
+	Want string
+	// Got is the digest of the replayed data (empty if nothing was produced).
+	Got string
+}
+
+// Report is an instance of a replay report.
+type Report struct {
+	// Replayed is the number of entries that were replayed.
+	Replayed int
+	// Divergences are the entries the replay didn't reproduce.
+	Divergences []*Divergence
+	// Err is the error the party returned (nil if the party didn't fail).
+	Err error
+	// ErrSeq is the sequence number of the entry the party failed at (-1 if
+	// the party failed when being started).
+	ErrSeq int
+}
+
+// Reproduced returns true if every recorded message and result was reproduced
+// bit for bit, which requires the party to draw the same randomness it drew
+// when the transcript was recorded.
+func (r *Report) Reproduced() bool {
+	return len(r.Divergences) == 0
+}
+
+// Replay starts the party and feeds it the messages it received according to
+// the transcript. The messages and the result the party produces are compared
+// with the recorded ones. Replaying stops once the party returns an error,
+// which is reported alongside the entry it failed at.
+// The party needs to be freshly created with the given channels which need to
+// be buffered so that the party doesn't block when sending.
+// Returns an error if the transcript is invalid or can't be decoded.
+func Replay(t *Transcript, party lindell17.Participant, outCh <-chan lindell17.Message, resCh <-chan lindell17.Result) (*Report, error) {
+	if err := t.Verify(); err != nil {
+		return nil, err
+	}
+
+	report := &Report{ErrSeq: -1}
+
+	if _, err := party.Start(); err != nil {
+		report.Err = err
+
+		return report, nil
+	}
+
+	for _, entry := range t.Entries {
+		report.Replayed++
+
+		switch entry.Kind {
+		case Sent:
+			var got string
+			select {
+			case msg := <-outCh:
+				data, err := codec.MarshalMessage(msg)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrEncodeEntry, err)
+				}
+				got = digest(data)
+			default:
+			}
+			report.compare(entry, got)
+		case Output:
+			var got string
+			select {
+			case res := <-resCh:
+				data, err := codec.MarshalResult(res)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrEncodeEntry, err)
+				}
+				got = digest(data)
+			default:
+			}
+			report.compare(entry, got)
+		case Received:
+			msg, err := codec.UnmarshalMessage(entry.Data)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrDecodeEntry, err)
+			}
+
+			if _, err := party.Process(msg); err != nil {
+				report.Err = err
+				report.ErrSeq = entry.Seq
+
+				return report, nil
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown entry kind %q", ErrInvalidTranscript, entry.Kind)
+		}
+	}
+
+	return report, nil
+}
+
+// compare records a divergence if the replayed digest doesn't match the
+// recorded one.
+func (r *Report) compare(entry *Entry, got string) {
+	if entry.Digest != got {
+		r.Divergences = append(r.Divergences, &Divergence{
+			Seq:  entry.Seq,
+			Kind: entry.Kind,
+			Want: entry.Digest,
+			Got:  got,
+		})
+	}
+}
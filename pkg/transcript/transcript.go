@@ -0,0 +1,127 @@
+package transcript
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// Kind indicates the kind of a transcript entry.
+type Kind string
+
+const (
+	// Sent is the kind of an entry that records a message the party sent.
+	Sent Kind = "sent"
+	// Received is the kind of an entry that records a message the party received.
+	Received Kind = "received"
+	// Output is the kind of an entry that records the party's result.
+	Output Kind = "result"
+)
+
+// Entry is an instance of a transcript entry.
+type Entry struct {
+	// Seq is the entry's sequence number.
+	Seq int `json:"seq"`
+	// Time is the time the entry was recorded at.
+	Time time.Time `json:"time"`
+	// Kind is the entry's kind.
+	Kind Kind `json:"kind"`
+	// Protocol is the protocol the message or result belongs to.
+	Protocol lindell17.Protocol `json:"protocol"`
+	// MessageId is the message's id (0 for results).
+	MessageId int `json:"messageId"`
+	// SessionId is the session id of the message or result.
+	SessionId string `json:"sid"`
+	// Digest is the hex-encoded SHA-256 digest of the data.
+	Digest string `json:"digest"`
+	// Chain is the hex-encoded hash that chains the entry to the previous one.
+	Chain string `json:"chain"`
+	// Data is the encoded message or result.
+	Data json.RawMessage `json:"data"`
+}
+
+// Transcript is an instance of a party's protocol transcript.
+type Transcript struct {
+	// Entity is the party the transcript belongs to.
+	Entity lindell17.Entity `json:"entity"`
+	// Entries are the transcript's entries in the order they were recorded.
+	Entries []*Entry `json:"entries"`
+}
+
+// Verify checks that the entries are numbered consecutively and that their
+// digests and hash chain are intact.
+// Returns an error if the transcript is malformed or was modified.
+func (t *Transcript) Verify() error {
+	prev := ""
+	for i, entry := range t.Entries {
+		if entry == nil || entry.Seq != i {
+			return fmt.Errorf("%w: entry %d out of sequence", ErrInvalidTranscript, i)
+		}
+
+		if entry.Digest != digest(entry.Data) {
+			return fmt.Errorf("%w: entry %d has invalid digest", ErrInvalidTranscript, i)
+		}
+
+		if entry.Chain != chain(prev, entry) {
+			return fmt.Errorf("%w: entry %d has invalid chain hash", ErrInvalidTranscript, i)
+		}
+
+		prev = entry.Chain
+	}
+
+	return nil
+}
+
+// Write writes the transcript as JSON.
+// Returns an error if the transcript can't be written.
+func (t *Transcript) Write(w io.Writer) error {
+	// The entries' data is written as is since the digests are computed over it.
+	if err := json.NewEncoder(w).Encode(t); err != nil {
+		return fmt.Errorf("%w: %w", ErrWriteTranscript, err)
+	}
+
+	return nil
+}
+
+// Read reads a transcript that was written via Write and verifies it.
+// Returns an error if the transcript can't be read or is invalid.
+func Read(r io.Reader) (*Transcript, error) {
+	var t Transcript
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadTranscript, err)
+	}
+
+	if err := t.Verify(); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// digest computes the hex-encoded SHA-256 digest of the data.
+func digest(data []byte) string {
+	checksum := sha256.Sum256(data)
+
+	return hex.EncodeToString(checksum[:])
+}
+
+// chain computes the hash that chains the entry to the previous one.
+func chain(prev string, entry *Entry) string {
+	header := fmt.Sprintf("%s|%d|%s|%s|%d|%d|%s|%s",
+		prev,
+		entry.Seq,
+		entry.Time.UTC().Format(time.RFC3339Nano),
+		entry.Kind,
+		entry.Protocol,
+		entry.MessageId,
+		entry.SessionId,
+		entry.Digest,
+	)
+
+	return digest([]byte(header))
+}
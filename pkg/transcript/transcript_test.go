@@ -0,0 +1,326 @@
+package transcript_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/lindell17/pkg/keygen/messages"
+	"github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	"github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	"github.com/primefactor-io/lindell17/pkg/transcript"
+)
+
+const paillierBits = 1024
+const rangeProofBits = 40
+const nthRootProofBits = 128
+
+var secp256k1 = curves.Secp256k1
+
+func TestTranscript(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Record / Write / Read (valid)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		r1 := transcript.NewRecorder(lindell17.Party1)
+		r2 := transcript.NewRecorder(lindell17.Party2)
+
+		p1Params := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithRecorder(r1)
+		p2Params := party2.NewParams(secp256k1, rangeProofBits, nthRootProofBits).WithRecorder(r2)
+
+		p1 := party1.NewParty1(p1Params, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+		var counter int
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				t.Fatalf("expected no error, got %v", err)
+			case <-resCh:
+				counter++
+
+				if counter == 2 {
+					break coord
+				}
+			}
+		}
+
+		// Party 1 sends messages 1, 3, 5 and 7, receives messages 2, 4 and 6
+		// and outputs its result.
+		var buf bytes.Buffer
+		if err := r1.Transcript().Write(&buf); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		tr, err := transcript.Read(&buf)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		kinds := []transcript.Kind{
+			transcript.Sent,
+			transcript.Received,
+			transcript.Sent,
+			transcript.Received,
+			transcript.Sent,
+			transcript.Received,
+			transcript.Sent,
+			transcript.Output,
+		}
+
+		if len(tr.Entries) != len(kinds) {
+			t.Fatalf("want %d entries, got %d", len(kinds), len(tr.Entries))
+		}
+
+		for i, kind := range kinds {
+			if tr.Entries[i].Kind != kind {
+				t.Fatalf("want entry %d to be of kind %q, got %q", i, kind, tr.Entries[i].Kind)
+			}
+		}
+
+		// Both parties need to agree on the exchanged messages.
+		tr2 := r2.Transcript()
+		if tr.Entries[0].Digest != tr2.Entries[0].Digest {
+			t.Fatal("Transcript digests of message 1 don't match")
+		}
+	})
+
+	t.Run("Verify - Invalid (Modified entry)", func(t *testing.T) {
+		t.Parallel()
+
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		r1 := transcript.NewRecorder(lindell17.Party1)
+		p1Params := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithRecorder(r1)
+		p1 := party1.NewParty1(p1Params, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		tr := r1.Transcript()
+		if err := tr.Verify(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// Modify the recorded message.
+		tr.Entries[0].SessionId = "modified"
+
+		if err := tr.Verify(); !errors.Is(err, transcript.ErrInvalidTranscript) {
+			t.Fatalf("want error %v, got %v", transcript.ErrInvalidTranscript, err)
+		}
+	})
+
+	t.Run("Replay - Failure (Q1)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		r2 := transcript.NewRecorder(lindell17.Party2)
+
+		p1Params := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits)
+		p2Params := party2.NewParams(secp256k1, rangeProofBits, nthRootProofBits).WithRecorder(r2).WithRandomness(random.NewSeededReader([]byte("party 2")))
+
+		p1 := party1.NewParty1(p1Params, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if msg.MessageId() == 3 {
+						// Parse old message.
+						msg := msg.(*messages.Message3)
+
+						// Compute new, random Q1.
+						x1, _ := secp256k1.GetRandomScalar()
+						q1, _ := secp256k1.ScalarMultiply(x1, secp256k1.G())
+
+						// Replace existing message.
+						msg = messages.NewMessage3(msg.Sid, q1, msg.Pk, msg.PNthRoot, msg.X1Enc, msg.PRange)
+
+						// Inject faulty message.
+						if _, err := p2.Process(msg); err != nil {
+							errCh <- err
+						}
+
+						break
+					}
+
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				if !errors.Is(err, party2.ErrInvalidQ1Commitment) {
+					t.Fatalf("want error %v, got %v", party2.ErrInvalidQ1Commitment, err)
+				}
+
+				break coord
+			}
+		}
+
+		// Replay party 2's transcript with a fresh instance of party 2.
+		replayOutCh := make(chan lindell17.Message, 2)
+		replayResCh := make(chan lindell17.Result, 2)
+
+		replayParams := party2.NewParams(secp256k1, rangeProofBits, nthRootProofBits).WithRandomness(random.NewSeededReader([]byte("party 2")))
+		replayP2 := party2.NewParty2(replayParams, replayOutCh, replayResCh)
+
+		tr := r2.Transcript()
+
+		report, err := transcript.Replay(tr, replayP2, replayOutCh, replayResCh)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if !errors.Is(report.Err, party2.ErrInvalidQ1Commitment) {
+			t.Fatalf("want error %v, got %v", party2.ErrInvalidQ1Commitment, report.Err)
+		}
+
+		// The faulty message 3 is the last entry of party 2's transcript.
+		if report.ErrSeq != len(tr.Entries)-1 {
+			t.Fatalf("want failure at entry %d, got %d", len(tr.Entries)-1, report.ErrSeq)
+		}
+
+		// The replay uses the same randomness, so message 2 is reproduced.
+		if !report.Reproduced() {
+			t.Fatalf("want no divergences, got %d", len(report.Divergences))
+		}
+	})
+
+	t.Run("Replay (Reproduced)", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+		outCh := make(chan lindell17.Message, 2)
+		resCh := make(chan lindell17.Result, 2)
+
+		r1 := transcript.NewRecorder(lindell17.Party1)
+
+		p1Params := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithRecorder(r1).WithRandomness(random.NewSeededReader([]byte("party 1")))
+		p2Params := party2.NewParams(secp256k1, rangeProofBits, nthRootProofBits).WithRandomness(random.NewSeededReader([]byte("party 2")))
+
+		p1 := party1.NewParty1(p1Params, outCh, resCh)
+		p2 := party2.NewParty2(p2Params, outCh, resCh)
+
+		if _, err := p1.Start(); err != nil {
+			errCh <- err
+		}
+
+		if _, err := p2.Start(); err != nil {
+			errCh <- err
+		}
+
+		var counter int
+
+	coord:
+		for {
+			select {
+			case msg := <-outCh:
+				switch msg.To() {
+				case lindell17.Party1:
+					if _, err := p1.Process(msg); err != nil {
+						errCh <- err
+					}
+				case lindell17.Party2:
+					if _, err := p2.Process(msg); err != nil {
+						errCh <- err
+					}
+				}
+			case err := <-errCh:
+				t.Fatalf("expected no error, got %v", err)
+			case <-resCh:
+				counter++
+
+				if counter == 2 {
+					break coord
+				}
+			}
+		}
+
+		tr := r1.Transcript()
+
+		// replay replays party 1's transcript with a fresh instance of party 1
+		// that draws its randomness from a reader with the given seed.
+		replay := func(seed string) *transcript.Report {
+			replayOutCh := make(chan lindell17.Message, 2)
+			replayResCh := make(chan lindell17.Result, 2)
+
+			replayParams := party1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithRandomness(random.NewSeededReader([]byte(seed)))
+			replayP1 := party1.NewParty1(replayParams, replayOutCh, replayResCh)
+
+			report, err := transcript.Replay(tr, replayP1, replayOutCh, replayResCh)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			return report
+		}
+
+		// Every message and the result are reproduced with the same randomness.
+		report := replay("party 1")
+		if report.Err != nil {
+			t.Fatalf("expected no error, got %v", report.Err)
+		}
+		if report.Replayed != len(tr.Entries) {
+			t.Fatalf("want %d replayed entries, got %d", len(tr.Entries), report.Replayed)
+		}
+		if !report.Reproduced() {
+			t.Fatalf("want no divergences, got %d", len(report.Divergences))
+		}
+
+		// Other randomness results in a different message 1 (and a Paillier
+		// key that doesn't match party 2's messages).
+		report = replay("other seed")
+		if report.Reproduced() || report.Divergences[0].Seq != 0 {
+			t.Fatal("Replay with other randomness didn't diverge at message 1")
+		}
+	})
+}
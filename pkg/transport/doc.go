@@ -0,0 +1,19 @@
+/*
+Package transport implements a transport that carries protocol messages
+between two parties over a TCP connection.
+
+One party listens for the connection (see Listen) while the other one connects
+to it (see Dial). The connection is independent of the parties' roles, i.e.
+party 1 can listen as well as connect. Every message is encoded via the codec
+(see package codec) and written as a frame that starts with the encoding's
+length as a 4-byte big-endian integer. Frames that exceed the maximum message
+size are rejected before their payload is read.
+
+Run drives a party over a connection: it starts the party, sends the messages
+the party emits, processes the messages the peer sends and returns the party's
+result once it's available.
+
+Note that the transport doesn't authenticate or encrypt the connection. It
+needs to be run over a trusted network or a secure tunnel.
+*/
+package transport
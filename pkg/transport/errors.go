@@ -0,0 +1,18 @@
+package transport
+
+import "fmt"
+
+var (
+	// ErrListen is returned if the transport can't listen for or accept a connection.
+	ErrListen = fmt.Errorf("unable to accept connection")
+	// ErrDial is returned if the transport can't connect to the peer.
+	ErrDial = fmt.Errorf("unable to connect to peer")
+	// ErrSend is returned if a message can't be sent.
+	ErrSend = fmt.Errorf("unable to send message")
+	// ErrReceive is returned if a message can't be received.
+	ErrReceive = fmt.Errorf("unable to receive message")
+	// ErrMessageTooLarge is returned if a message exceeds the maximum message size.
+	ErrMessageTooLarge = fmt.Errorf("message too large")
+	// ErrRunProtocol is returned if the party fails during the protocol run.
+	ErrRunProtocol = fmt.Errorf("unable to run protocol")
+)
@@ -0,0 +1,239 @@
+package transport
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+)
+
+// DefaultMaxMessageSize is the default maximum size of an encoded message in
+// bytes.
+const DefaultMaxMessageSize = 4 << 20
+
+// DefaultTimeout is the default time the transport waits for the peer's next
+// message. Key generation with large Paillier keys can take minutes.
+const DefaultTimeout = 10 * time.Minute
+
+// dialInterval is the time between two attempts to connect to the peer.
+const dialInterval = 100 * time.Millisecond
+
+// Conn is an instance of a connection to the peer that carries protocol
+// messages. It's safe for concurrent use by one sender and one receiver.
+type Conn struct {
+	conn           net.Conn
+	reader         *bufio.Reader
+	sendMu         sync.Mutex
+	timeout        time.Duration
+	maxMessageSize int
+}
+
+// NewConn creates a new instance of a connection that carries protocol
+// messages over the given network connection.
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{
+		conn:           conn,
+		reader:         bufio.NewReader(conn),
+		timeout:        DefaultTimeout,
+		maxMessageSize: DefaultMaxMessageSize,
+	}
+}
+
+// Listen listens on the given address and accepts a single connection.
+// Returns an error if the address can't be listened on or the connection
+// can't be accepted.
+func Listen(addr string) (*Conn, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListen, err)
+	}
+	defer listener.Close()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrListen, err)
+	}
+
+	return NewConn(conn), nil
+}
+
+// Dial connects to the peer at the given address. It retries until the wait
+// time elapsed, so that the peer can start listening after the dialing party
+// started.
+// Returns an error if the connection can't be established in time.
+func Dial(addr string, wait time.Duration) (*Conn, error) {
+	deadline := time.Now().Add(wait)
+
+	for {
+		conn, err := net.DialTimeout("tcp", addr, dialInterval)
+		if err == nil {
+			return NewConn(conn), nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %w", ErrDial, err)
+		}
+
+		time.Sleep(dialInterval)
+	}
+}
+
+// WithTimeout sets the time the connection waits for the peer's next message.
+// A timeout of 0 disables the timeout.
+// Returns the connection to allow chaining.
+func (c *Conn) WithTimeout(timeout time.Duration) *Conn {
+	c.timeout = timeout
+
+	return c
+}
+
+// WithMaxMessageSize sets the maximum size of an encoded message in bytes.
+// Returns the connection to allow chaining.
+func (c *Conn) WithMaxMessageSize(size int) *Conn {
+	c.maxMessageSize = size
+
+	return c
+}
+
+// Send encodes the message and sends it to the peer.
+// Returns an error if the message can't be encoded, is too large or can't be
+// written.
+func (c *Conn) Send(msg lindell17.Message) error {
+	data, err := codec.MarshalMessage(msg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSend, err)
+	}
+	if len(data) > c.maxMessageSize {
+		return fmt.Errorf("%w: %w (%d bytes)", ErrSend, ErrMessageTooLarge, len(data))
+	}
+
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if err := c.setDeadline(c.conn.SetWriteDeadline); err != nil {
+		return fmt.Errorf("%w: %w", ErrSend, err)
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		return fmt.Errorf("%w: %w", ErrSend, err)
+	}
+
+	return nil
+}
+
+// Receive waits for the peer's next message and decodes it.
+// Returns an error if the timeout elapsed or the message can't be read, is
+// too large or can't be decoded.
+func (c *Conn) Receive() (lindell17.Message, error) {
+	if err := c.setDeadline(c.conn.SetReadDeadline); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReceive, err)
+	}
+
+	var header [4]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReceive, err)
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if uint64(size) > uint64(c.maxMessageSize) {
+		return nil, fmt.Errorf("%w: %w (%d bytes)", ErrReceive, ErrMessageTooLarge, size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(c.reader, data); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReceive, err)
+	}
+
+	msg, err := codec.UnmarshalMessage(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReceive, err)
+	}
+
+	return msg, nil
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// setDeadline sets the deadline of the next read or write via the given
+// setter if a timeout is set.
+func (c *Conn) setDeadline(set func(time.Time) error) error {
+	if c.timeout == 0 {
+		return set(time.Time{})
+	}
+
+	return set(time.Now().Add(c.timeout))
+}
+
+// Factory creates a party that sends its messages via the message channel and
+// its result via the result channel.
+type Factory func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant
+
+// Run runs the party the factory creates over the connection. It starts the
+// party, sends every message the party emits to the peer and processes the
+// peer's messages until the party emitted its result.
+// Returns the party's result or an error if the party fails or a message
+// can't be sent or received. If the party fails after it emitted its result
+// (e.g. a batch signing run with failed items), the result is returned
+// together with the error.
+func Run(conn *Conn, newParty Factory) (lindell17.Result, error) {
+	outCh := make(chan lindell17.Message, 8)
+	resCh := make(chan lindell17.Result, 1)
+
+	party := newParty(outCh, resCh)
+
+	_, err := party.Start()
+
+	for {
+		// Send the messages the party emitted, even if it failed afterwards,
+		// so that the peer can finish as well.
+		if sendErr := flush(conn, outCh); sendErr != nil {
+			return nil, sendErr
+		}
+
+		var res lindell17.Result
+		select {
+		case res = <-resCh:
+		default:
+		}
+
+		if err != nil {
+			return res, fmt.Errorf("%w: %w", ErrRunProtocol, err)
+		}
+		if res != nil {
+			return res, nil
+		}
+
+		msg, recvErr := conn.Receive()
+		if recvErr != nil {
+			return nil, recvErr
+		}
+
+		_, err = party.Process(msg)
+	}
+}
+
+// flush sends every message in the channel to the peer.
+// Returns an error if a message can't be sent.
+func flush(conn *Conn, outCh <-chan lindell17.Message) error {
+	for {
+		select {
+		case msg := <-outCh:
+			if err := conn.Send(msg); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
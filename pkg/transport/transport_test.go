@@ -0,0 +1,214 @@
+package transport_test
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/ecc/pkg/curves"
+	"github.com/primefactor-io/ecc/pkg/ecdsa"
+	"github.com/primefactor-io/ecc/pkg/elliptic"
+	"github.com/primefactor-io/ecc/pkg/keys"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/sign/messages"
+	"github.com/primefactor-io/lindell17/pkg/sign/party1"
+	"github.com/primefactor-io/lindell17/pkg/sign/party2"
+	"github.com/primefactor-io/lindell17/pkg/transport"
+	"github.com/primefactor-io/paillier/pkg/cipher"
+	pKeys "github.com/primefactor-io/paillier/pkg/keys"
+)
+
+var p1Params *party1.Params
+var p2Params *party2.Params
+var qShared *elliptic.Point
+
+var secp256k1 = curves.Secp256k1
+
+func TestMain(m *testing.M) {
+	// x1 should be in the range x1 >= 0 and x1 < q / 3.
+	q3 := new(big.Int).Div(secp256k1.N(), big.NewInt(3)) // q / 3
+
+	sk, pk, _ := pKeys.GenerateKeys(1024)
+
+	x1, _ := secp256k1.GetRandomScalar(q3)
+	x1Enc, _ := cipher.Encrypt(pk, x1.Bytes())
+
+	x2, _ := secp256k1.GetRandomScalar()
+	q2, _ := secp256k1.ScalarMultiply(x2, secp256k1.G())
+
+	// Note that x1 * Q2 = x2 * Q1.
+	qShared, _ = secp256k1.ScalarMultiply(x1, q2)
+
+	p1Params = party1.NewParams(secp256k1, sk, qShared)
+	p2Params = party2.NewParams(secp256k1, pk, x1Enc, x2)
+
+	m.Run()
+}
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Run (valid)", func(t *testing.T) {
+		t.Parallel()
+
+		checksum := sha256.Sum256([]byte("Hello World"))
+		hash := checksum[:]
+
+		c1, c2 := net.Pipe()
+		conn1 := transport.NewConn(c1)
+		conn2 := transport.NewConn(c2)
+		defer conn1.Close()
+		defer conn2.Close()
+
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := transport.Run(conn2, func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+				return party2.NewParty2(p2Params, hash, outCh, resCh)
+			})
+			errCh <- err
+		}()
+
+		res, err := transport.Run(conn1, func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return party1.NewParty1(p1Params, hash, outCh, resCh)
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if err := <-errCh; err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		signature := res.(*party1.Result).Signature
+		isValid, _ := ecdsa.Verify(secp256k1, (*keys.PublicKey)(qShared), hash, signature)
+		if !isValid {
+			t.Fatal("Signature verification failed")
+		}
+	})
+
+	t.Run("Run - Invalid (Party fails)", func(t *testing.T) {
+		t.Parallel()
+
+		checksum1 := sha256.Sum256([]byte("Hello World"))
+		checksum2 := sha256.Sum256([]byte("Goodbye World"))
+
+		c1, c2 := net.Pipe()
+		conn1 := transport.NewConn(c1)
+		conn2 := transport.NewConn(c2)
+		defer conn1.Close()
+		defer conn2.Close()
+
+		go func() {
+			transport.Run(conn2, func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+				return party2.NewParty2(p2Params, checksum2[:], outCh, resCh)
+			})
+		}()
+
+		_, err := transport.Run(conn1, func(outCh chan<- lindell17.Message, resCh chan<- lindell17.Result) lindell17.Participant {
+			return party1.NewParty1(p1Params, checksum1[:], outCh, resCh)
+		})
+
+		if !errors.Is(err, transport.ErrRunProtocol) || !errors.Is(err, party1.ErrInvalidSignature) {
+			t.Errorf("want error %v, got %v", party1.ErrInvalidSignature, err)
+		}
+	})
+
+	t.Run("Listen / Dial", func(t *testing.T) {
+		t.Parallel()
+
+		// Reserve a free port.
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		addr := listener.Addr().String()
+		listener.Close()
+
+		// Dial before the peer listens.
+		connCh := make(chan *transport.Conn, 1)
+		errCh := make(chan error, 1)
+		go func() {
+			conn, err := transport.Dial(addr, 5*time.Second)
+			errCh <- err
+			connCh <- conn
+		}()
+
+		time.Sleep(200 * time.Millisecond)
+
+		conn1, err := transport.Listen(addr)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		defer conn1.Close()
+
+		if err := <-errCh; err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		conn2 := <-connCh
+		defer conn2.Close()
+
+		sid := strings.Repeat("0", lindell17.SessionIdLength)
+		if err := conn2.Send(messages.NewMessage3(sid, secp256k1.G())); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		msg, err := conn1.Receive()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if m, ok := msg.(*messages.Message3); !ok || !m.R1.Equal(secp256k1.G()) {
+			t.Fatal("Received message doesn't match the sent one")
+		}
+	})
+
+	t.Run("Dial - Invalid (No peer)", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := transport.Dial("127.0.0.1:1", 0)
+
+		if !errors.Is(err, transport.ErrDial) {
+			t.Errorf("want error %v, got %v", transport.ErrDial, err)
+		}
+	})
+
+	t.Run("Receive - Invalid (Message too large)", func(t *testing.T) {
+		t.Parallel()
+
+		c1, c2 := net.Pipe()
+		conn := transport.NewConn(c1).WithMaxMessageSize(1024)
+		defer conn.Close()
+		defer c2.Close()
+
+		go func() {
+			var header [4]byte
+			binary.BigEndian.PutUint32(header[:], 1025)
+			c2.Write(header[:])
+		}()
+
+		_, err := conn.Receive()
+
+		if !errors.Is(err, transport.ErrMessageTooLarge) {
+			t.Errorf("want error %v, got %v", transport.ErrMessageTooLarge, err)
+		}
+	})
+
+	t.Run("Receive - Invalid (Timeout)", func(t *testing.T) {
+		t.Parallel()
+
+		c1, c2 := net.Pipe()
+		conn := transport.NewConn(c1).WithTimeout(50 * time.Millisecond)
+		defer conn.Close()
+		defer c2.Close()
+
+		_, err := conn.Receive()
+
+		if !errors.Is(err, transport.ErrReceive) || !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("want error %v, got %v", os.ErrDeadlineExceeded, err)
+		}
+	})
+}
@@ -4,10 +4,12 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"io"
 	"math/big"
 
 	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/paillier/pkg/keys"
 )
 
 // HashLength is the number of bytes a 256 bit hash has.
@@ -16,9 +18,16 @@ const HashLength = 32
 // GenerateSessionId generates a session id that's used in protocol messages
 // to group messages belonging to the same session.
 func GenerateSessionId(bits int) (string, error) {
-	bz, err := GenerateRandomBytes(bits)
+	return GenerateSessionIdFromReader(rand.Reader, bits)
+}
+
+// GenerateSessionIdFromReader generates a session id using the given source of
+// randomness.
+// Returns an error if the session id can't be generated.
+func GenerateSessionIdFromReader(reader io.Reader, bits int) (string, error) {
+	bz, err := GenerateRandomBytesFromReader(reader, bits)
 	if err != nil {
-		return "", lindell17.ErrGenerateSessionId
+		return "", fmt.Errorf("%w: %w", lindell17.ErrGenerateSessionId, err)
 	}
 
 	checksum := sha256.Sum256(bz)
@@ -31,13 +40,20 @@ func GenerateSessionId(bits int) (string, error) {
 // desired bits.
 // Returns an error if the random bytes can't be generated.
 func GenerateRandomBytes(bits int) ([]byte, error) {
+	return GenerateRandomBytesFromReader(rand.Reader, bits)
+}
+
+// GenerateRandomBytesFromReader generates a byte slice that contains the number
+// of desired bits using the given source of randomness.
+// Returns an error if the random bytes can't be generated.
+func GenerateRandomBytesFromReader(reader io.Reader, bits int) ([]byte, error) {
 	numBytes := (bits + 7) / 8
 	randBytes := make([]byte, numBytes)
 
 	// Sample a slice of random bytes.
-	_, err := io.ReadFull(rand.Reader, randBytes)
+	_, err := io.ReadFull(reader, randBytes)
 	if err != nil {
-		return nil, ErrGenerateRandomBytes
+		return nil, fmt.Errorf("%w: %w", ErrGenerateRandomBytes, err)
 	}
 
 	return randBytes, nil
@@ -74,3 +90,96 @@ func ExtendedEuclidean(a *big.Int, b *big.Int) (*big.Int, *big.Int, *big.Int) {
 
 	return gcd, x, y
 }
+
+// RunTasks runs the tasks with at most the given number of concurrent workers
+// and returns the first error a task returns. Once a task failed, the tasks
+// that haven't been started yet are skipped and RunTasks returns without
+// waiting for the tasks that are still running. Their results are discarded,
+// so tasks must not share state with the caller that's used after an error.
+// With a single worker the tasks run one after the other in the given order.
+func RunTasks(workers int, tasks ...func() error) error {
+	if workers <= 1 {
+		for _, task := range tasks {
+			if err := task(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// The channel is buffered so that tasks that finish after RunTasks
+	// returned don't block.
+	results := make(chan error, len(tasks))
+	running := 0
+
+	for _, task := range tasks {
+		// Collect the results of finished tasks and wait for a free worker.
+		for running > 0 {
+			select {
+			case err := <-results:
+				running--
+				if err != nil {
+					return err
+				}
+				continue
+			default:
+			}
+
+			if running < workers {
+				break
+			}
+
+			running--
+			if err := <-results; err != nil {
+				return err
+			}
+		}
+
+		running++
+		go func(task func() error) {
+			results <- task()
+		}(task)
+	}
+
+	for ; running > 0; running-- {
+		if err := <-results; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Wipe overwrites the limbs of the integers with zeros and sets them to 0.
+// Nil integers are skipped. Wiping is best effort, since the runtime may have
+// copied the limbs before (e.g. when an integer grew) and intermediate values
+// of computations aren't covered.
+func Wipe(xs ...*big.Int) {
+	for _, x := range xs {
+		if x == nil {
+			continue
+		}
+
+		limbs := x.Bits()
+		clear(limbs[:cap(limbs)])
+		x.SetInt64(0)
+	}
+}
+
+// WipeBytes overwrites the byte slices with zeros.
+func WipeBytes(bzs ...[]byte) {
+	for _, bz := range bzs {
+		clear(bz)
+	}
+}
+
+// WipePaillierKey overwrites the secret values phi(N) and mu of the Paillier
+// private key. The modulus is kept, since it's shared with the public key.
+func WipePaillierKey(sk *keys.PrivateKey) {
+	if sk == nil {
+		return
+	}
+
+	Wipe(sk.PhiN, sk.Mu)
+}
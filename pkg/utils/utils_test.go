@@ -1,8 +1,11 @@
 package utils_test
 
 import (
+	"errors"
 	"math/big"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/primefactor-io/lindell17/pkg/utils"
 )
@@ -54,4 +57,101 @@ func TestUtils(t *testing.T) {
 		}
 	})
 
+	t.Run("Wipe", func(t *testing.T) {
+		t.Parallel()
+
+		x, _ := new(big.Int).SetString("deadbeefdeadbeefdeadbeefdeadbeefdeadbeef", 16)
+		limbs := x.Bits()
+
+		utils.Wipe(x, nil)
+
+		if x.Sign() != 0 {
+			t.Errorf("want x to be 0, got %v", x)
+		}
+		for i, limb := range limbs[:cap(limbs)] {
+			if limb != 0 {
+				t.Fatalf("want limb %d to be wiped, got %x", i, limb)
+			}
+		}
+	})
+
+	t.Run("RunTasks", func(t *testing.T) {
+		t.Parallel()
+
+		for _, workers := range []int{1, 3} {
+			var counter atomic.Int32
+			tasks := make([]func() error, 8)
+			for i := range tasks {
+				tasks[i] = func() error {
+					counter.Add(1)
+					return nil
+				}
+			}
+
+			if err := utils.RunTasks(workers, tasks...); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if counter.Load() != 8 {
+				t.Fatalf("want 8 tasks to run, got %d", counter.Load())
+			}
+		}
+	})
+
+	t.Run("RunTasks - Invalid (Task fails)", func(t *testing.T) {
+		t.Parallel()
+
+		errTask := errors.New("task failed")
+
+		var counter atomic.Int32
+		tasks := []func() error{
+			// Keep the first worker busy until the second task failed.
+			func() error {
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			},
+			func() error {
+				return errTask
+			},
+		}
+		for range 6 {
+			tasks = append(tasks, func() error {
+				counter.Add(1)
+				return nil
+			})
+		}
+
+		err := utils.RunTasks(2, tasks...)
+
+		if !errors.Is(err, errTask) {
+			t.Fatalf("want error %v, got %v", errTask, err)
+		}
+		if counter.Load() != 0 {
+			t.Fatalf("want remaining tasks to be skipped, got %d tasks", counter.Load())
+		}
+	})
+
+	t.Run("RunTasks - Invalid (Task fails while others run)", func(t *testing.T) {
+		t.Parallel()
+
+		errTask := errors.New("task failed")
+
+		// The first task doesn't finish before RunTasks returned.
+		release := make(chan struct{})
+		defer close(release)
+
+		err := utils.RunTasks(2,
+			func() error {
+				<-release
+				return nil
+			},
+			func() error {
+				return errTask
+			},
+		)
+
+		if !errors.Is(err, errTask) {
+			t.Fatalf("want error %v, got %v", errTask, err)
+		}
+	})
+
 }
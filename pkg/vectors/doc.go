@@ -0,0 +1,28 @@
+/*
+Package vectors contains known-answer test vectors for the key generation,
+signing and adaptor signature protocols.
+
+The vectors are stored as JSON files in the package's testdata directory. Each
+vector fixes every random input the parties draw (e.g. the secret shares x1 and
+x2, the nonces k1 and k2, the Paillier key, the DLEnc proof values a and b, the
+masking value p and the commitments' and proofs' nonces) in the order in which
+they're drawn and records the steps of the protocol run. A step is a Start or
+Process call of a party and lists the messages and results the party is
+expected to produce, so a change of any message fails the vector.
+
+Every random input has a kind:
+
+  - "int" is a uniformly random integer (big-endian, hex encoded).
+  - "paillier key" is a Paillier private key (N followed by phi(N), both
+    big-endian and padded to the modulus' length, hex encoded).
+  - "bytes" is a slice of random bytes (hex encoded), e.g. a commitment nonce
+    or the seed of a source of randomness that concurrent tasks draw from
+    (see random.Fork).
+
+Messages and results are encoded with the codec package. The signing and
+adaptor signature vectors use the key material of the key generation vector.
+
+Run "go test ./pkg/vectors -update" to regenerate the vectors after an
+intentional change to the protocols.
+*/
+package vectors
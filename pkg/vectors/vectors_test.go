@@ -0,0 +1,399 @@
+package vectors_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/primefactor-io/ecc/pkg/adaptor"
+	"github.com/primefactor-io/ecc/pkg/curves"
+	aParty1 "github.com/primefactor-io/lindell17/pkg/adaptor/party1"
+	aParty2 "github.com/primefactor-io/lindell17/pkg/adaptor/party2"
+	"github.com/primefactor-io/lindell17/pkg/codec"
+	kParty1 "github.com/primefactor-io/lindell17/pkg/keygen/party1"
+	kParty2 "github.com/primefactor-io/lindell17/pkg/keygen/party2"
+	"github.com/primefactor-io/lindell17/pkg/lindell17"
+	"github.com/primefactor-io/lindell17/pkg/random"
+	sParty1 "github.com/primefactor-io/lindell17/pkg/sign/party1"
+	sParty2 "github.com/primefactor-io/lindell17/pkg/sign/party2"
+)
+
+const paillierBits = 1024
+const rangeProofBits = 40
+const nthRootProofBits = 128
+
+var update = flag.Bool("update", false, "regenerate the test vectors in testdata")
+
+var secp256k1 = curves.Secp256k1
+
+// vector is a known-answer test vector.
+type vector struct {
+	Protocol string `json:"protocol"`
+	Curve    string `json:"curve"`
+	Hash     string `json:"hash,omitempty"`
+	Setup    []draw `json:"setup,omitempty"`
+	Party1   []draw `json:"party1"`
+	Party2   []draw `json:"party2"`
+	Steps    []step `json:"steps"`
+}
+
+// step is a Start or Process call of a party and the messages and results the
+// party produced.
+type step struct {
+	Party    string            `json:"party"`
+	Message  int               `json:"message,omitempty"`
+	Messages []json.RawMessage `json:"messages,omitempty"`
+	Results  []json.RawMessage `json:"results,omitempty"`
+}
+
+// draw is a random input of a test vector.
+type draw struct {
+	Label string          `json:"label,omitempty"`
+	Kind  random.DrawKind `json:"kind"`
+	Value string          `json:"value"`
+}
+
+// labels names the notable random inputs by their position on a party's tape.
+var labels = map[string]map[int]string{
+	"keygen/party1": {
+		0: "session id", 1: "x1", 2: "Q1 commitment nonce", 3: "Q1 DLK proof nonce", 4: "Paillier key",
+		5: "Nth root proof seed", 6: "x1 encryption and range proof seed", 7: "DLEnc Q^ commitment nonce",
+	},
+	"keygen/party2": {
+		0: "x2", 1: "Q2 DLK proof nonce", 2: "DLEnc session id", 3: "DLEnc a", 4: "DLEnc b",
+		5: "DLEnc a and b commitment nonce", 6: "DLEnc b encryption nonce",
+	},
+	"sign/party1": {
+		0: "session id", 1: "k1", 2: "R1 commitment nonce", 3: "R1 DLK proof nonce",
+	},
+	"sign/party2": {
+		0: "k2", 1: "R2 DLK proof nonce", 2: "masking p", 3: "c1 encryption nonce",
+	},
+	"adaptor/setup": {
+		0: "witness y", 1: "statement DLK proof nonce",
+	},
+	"adaptor/party1": {
+		0: "k1", 1: "R1 DLK proof nonce", 2: "K1 DLEq proof nonce",
+	},
+	"adaptor/party2": {
+		0: "session id", 1: "k2", 2: "R2 commitment nonce", 3: "R2 DLK proof nonce", 4: "R2' commitment nonce",
+		5: "K2 DLEq proof nonce", 6: "masking p", 7: "c1 encryption nonce",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	// The signing and adaptor signature vectors depend on the key material of
+	// the key generation vector, so the vectors are processed sequentially.
+
+	t.Run("Key Generation", func(t *testing.T) {
+		v, tapes := load(t, "keygen")
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 4)
+
+		p1Params := kParty1.NewParams(secp256k1, rangeProofBits, nthRootProofBits, paillierBits).WithRandomness(tapes[1])
+		p2Params := kParty2.NewParams(secp256k1, rangeProofBits, nthRootProofBits).WithRandomness(tapes[2])
+
+		p1 := kParty1.NewParty1(p1Params, outCh, resCh)
+		p2 := kParty2.NewParty2(p2Params, outCh, resCh)
+
+		steps := run(t, p1, p2, outCh, resCh)
+
+		check(t, v, tapes, steps)
+	})
+
+	t.Run("Signing", func(t *testing.T) {
+		v, tapes := load(t, "sign")
+		km1, km2 := keyMaterial(t)
+
+		hash := sha256.Sum256([]byte("Hello World"))
+		v.Hash = hex.EncodeToString(hash[:])
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 4)
+
+		p1Params := sParty1.NewParams(secp256k1, km1.Sk, km1.Q).WithRandomness(tapes[1])
+		p2Params := sParty2.NewParams(secp256k1, km2.Pk, km2.X1Enc, km2.X2).WithRandomness(tapes[2])
+
+		p1 := sParty1.NewParty1(p1Params, hash[:], outCh, resCh)
+		p2 := sParty2.NewParty2(p2Params, hash[:], outCh, resCh)
+
+		steps := run(t, p1, p2, outCh, resCh)
+
+		check(t, v, tapes, steps)
+	})
+
+	t.Run("Adaptor Signature", func(t *testing.T) {
+		v, tapes := load(t, "adaptor")
+		km1, km2 := keyMaterial(t)
+
+		hash := sha256.Sum256([]byte("Hello World"))
+		v.Hash = hex.EncodeToString(hash[:])
+
+		// Derive the hard relation and the statement's DLK proof.
+		wit, err := random.Scalar(tapes[0], secp256k1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		y, err := secp256k1.ScalarMultiply(wit, secp256k1.G())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		pStmt, err := random.DLKProof(tapes[0], secp256k1, y, wit)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		stmt := (*adaptor.Statement)(y)
+
+		outCh := make(chan lindell17.Message, 4)
+		resCh := make(chan lindell17.Result, 4)
+
+		p1Params := aParty1.NewParams(secp256k1, km1.Sk, km1.Q).WithRandomness(tapes[1])
+		p2Params := aParty2.NewParams(secp256k1, km2.Pk, km2.Q, km2.X1Enc, km2.X2).WithRandomness(tapes[2])
+
+		p1 := aParty1.NewParty1(p1Params, hash[:], stmt, pStmt, outCh, resCh)
+		p2 := aParty2.NewParty2(p2Params, hash[:], stmt, pStmt, outCh, resCh)
+
+		steps := run(t, p1, p2, outCh, resCh)
+
+		check(t, v, tapes, steps)
+	})
+}
+
+// load reads the test vector of the protocol and creates the tapes for the
+// setup (index 0) and the two parties (index 1 and 2). When updating, the
+// tapes record random inputs drawn from seeded readers instead.
+func load(t *testing.T, protocol string) (*vector, [3]*random.Tape) {
+	t.Helper()
+
+	var tapes [3]*random.Tape
+
+	if *update {
+		for i, name := range []string{"setup", "party1", "party2"} {
+			tapes[i] = random.NewRecordingTape(random.NewSeededReader([]byte(protocol + "/" + name)))
+		}
+
+		return &vector{Protocol: protocol, Curve: "secp256k1"}, tapes
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata", protocol+".json"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	v := new(vector)
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for i, draws := range [][]draw{v.Setup, v.Party1, v.Party2} {
+		decoded := make([]random.Draw, len(draws))
+		for j, d := range draws {
+			value, err := hex.DecodeString(d.Value)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			decoded[j] = random.Draw{Kind: d.Kind, Value: value}
+		}
+		tapes[i] = random.NewReplayTape(decoded)
+	}
+
+	return v, tapes
+}
+
+// keyMaterial decodes the key material of the key generation vector.
+func keyMaterial(t *testing.T) (*kParty1.KeyMaterial, *kParty2.KeyMaterial) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "keygen.json"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	v := new(vector)
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var km1 *kParty1.KeyMaterial
+	var km2 *kParty2.KeyMaterial
+
+	for _, step := range v.Steps {
+		for _, data := range step.Results {
+			res, err := codec.UnmarshalResult(data)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			switch res := res.(type) {
+			case *kParty1.Result:
+				km1 = res.KeyMaterial
+			case *kParty2.Result:
+				km2 = res.KeyMaterial
+			}
+		}
+	}
+
+	if km1 == nil || km2 == nil {
+		t.Fatal("key generation vector lacks key material")
+	}
+
+	return km1, km2
+}
+
+// run runs the protocol with deterministic message delivery and returns its
+// steps, i.e. the messages and results of every call in the order in which
+// the calls were made.
+func run(t *testing.T, p1, p2 lindell17.Participant, outCh chan lindell17.Message, resCh chan lindell17.Result) []step {
+	t.Helper()
+
+	var steps []step
+	var queue []lindell17.Message
+
+	// call makes the call and records the messages and results it produced.
+	call := func(party lindell17.Entity, msg lindell17.Message, fn func() (bool, error)) {
+		if _, err := fn(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		s := step{Party: party.String()}
+		if msg != nil {
+			s.Message = msg.MessageId()
+		}
+
+		for {
+			select {
+			case out := <-outCh:
+				data, err := codec.MarshalMessage(out)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				s.Messages = append(s.Messages, data)
+				queue = append(queue, out)
+				continue
+			case res := <-resCh:
+				data, err := codec.MarshalResult(res)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				s.Results = append(s.Results, data)
+				continue
+			default:
+			}
+			break
+		}
+
+		steps = append(steps, s)
+	}
+
+	call(lindell17.Party1, nil, p1.Start)
+	call(lindell17.Party2, nil, p2.Start)
+
+	for len(queue) > 0 {
+		msg := queue[0]
+		queue = queue[1:]
+
+		switch msg.To() {
+		case lindell17.Party1:
+			call(lindell17.Party1, msg, func() (bool, error) { return p1.Process(msg) })
+		case lindell17.Party2:
+			call(lindell17.Party2, msg, func() (bool, error) { return p2.Process(msg) })
+		}
+	}
+
+	return steps
+}
+
+// check compares the steps to the ones of the test vector, i.e. every message
+// and result, and ensures that all random inputs were used. When updating, it
+// writes the test vector instead.
+func check(t *testing.T, v *vector, tapes [3]*random.Tape, steps []step) {
+	t.Helper()
+
+	if *update {
+		v.Setup = encodeDraws(v.Protocol+"/setup", tapes[0].Draws())
+		v.Party1 = encodeDraws(v.Protocol+"/party1", tapes[1].Draws())
+		v.Party2 = encodeDraws(v.Protocol+"/party2", tapes[2].Draws())
+		v.Steps = steps
+
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		data = append(data, '\n')
+
+		if err := os.WriteFile(filepath.Join("testdata", v.Protocol+".json"), data, 0o644); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		return
+	}
+
+	for i, tape := range tapes {
+		if tape.Remaining() != 0 {
+			t.Fatalf("tape %d has %d unused random inputs", i, tape.Remaining())
+		}
+	}
+
+	if len(steps) != len(v.Steps) {
+		t.Fatalf("expected %d steps, got %d", len(v.Steps), len(steps))
+	}
+	for i, got := range steps {
+		want := v.Steps[i]
+
+		if got.Party != want.Party || got.Message != want.Message {
+			t.Fatalf("step %d: expected %s processing message %d, got %s processing message %d", i, want.Party, want.Message, got.Party, got.Message)
+		}
+		if len(got.Messages) != len(want.Messages) {
+			t.Fatalf("step %d: expected %d messages, got %d", i, len(want.Messages), len(got.Messages))
+		}
+		for j := range got.Messages {
+			if !equalJSON(t, want.Messages[j], got.Messages[j]) {
+				t.Fatalf("step %d: message %d doesn't match", i, j)
+			}
+		}
+		if len(got.Results) != len(want.Results) {
+			t.Fatalf("step %d: expected %d results, got %d", i, len(want.Results), len(got.Results))
+		}
+		for j := range got.Results {
+			if !equalJSON(t, want.Results[j], got.Results[j]) {
+				t.Fatalf("step %d: result %d doesn't match", i, j)
+			}
+		}
+	}
+}
+
+// encodeDraws encodes the random inputs of a tape and labels the notable ones.
+func encodeDraws(tape string, draws []random.Draw) []draw {
+	encoded := make([]draw, len(draws))
+	for i, d := range draws {
+		encoded[i] = draw{
+			Label: labels[tape][i],
+			Kind:  d.Kind,
+			Value: hex.EncodeToString(d.Value),
+		}
+	}
+
+	return encoded
+}
+
+// equalJSON reports whether the two JSON documents are equal regardless of
+// their formatting.
+func equalJSON(t *testing.T, a, b json.RawMessage) bool {
+	t.Helper()
+
+	var bufA, bufB bytes.Buffer
+	if err := json.Compact(&bufA, a); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := json.Compact(&bufB, b); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return bytes.Equal(bufA.Bytes(), bufB.Bytes())
+}